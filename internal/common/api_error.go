@@ -0,0 +1,313 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	ogen "github.com/ogen-go/ogen/validate"
+	client "github.com/sacloud/api-client-go"
+	"github.com/sacloud/iaas-api-go"
+)
+
+// RequestIDHeader はAPIのレスポンスに含まれるリクエストIDのヘッダー
+const RequestIDHeader = "X-Request-ID"
+
+// エラーレスポンスのボディとして読み込む最大サイズ
+const maxErrorBodySize = 64 * 1024
+
+// APIErrorDetail はAPIエラーの調査に必要な情報。認証情報やリクエストボディなどの機密情報は含めない
+type APIErrorDetail struct {
+	StatusCode int
+	Method     string
+	Path       string // クエリ文字列は含めない
+	RequestID  string
+	ErrorCode  string
+	Message    string
+	Fields     []APIFieldError // エラーレスポンスにフィールド単位のエラーが含まれる場合のみ設定される
+}
+
+func (d *APIErrorDetail) String() string {
+	var lines []string
+	if d.StatusCode > 0 {
+		lines = append(lines, fmt.Sprintf("status: %d %s", d.StatusCode, http.StatusText(d.StatusCode)))
+	}
+	if d.ErrorCode != "" {
+		lines = append(lines, "error code: "+d.ErrorCode)
+	}
+	if d.Message != "" {
+		lines = append(lines, "message: "+d.Message)
+	}
+	for _, f := range d.Fields {
+		lines = append(lines, fmt.Sprintf("field %s: %s", f.Field, f.Message))
+	}
+	if d.RequestID != "" {
+		lines = append(lines, "request ID: "+d.RequestID)
+	}
+	if d.Path != "" {
+		lines = append(lines, strings.TrimSpace("endpoint: "+d.Method+" "+d.Path))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// apiErrorCapture はAPIクライアントのトランスポートが記録したエラーレスポンスを保持する
+type apiErrorCapture struct {
+	mu     sync.Mutex
+	detail *APIErrorDetail
+}
+
+type apiErrorCaptureKey struct{}
+
+// WithAPIErrorCapture はエラーレスポンスのリクエストIDやエンドポイントを記録するためのcontextを返す。
+// CRUDの先頭でこれを呼び出し、以降のAPI呼び出しのエラーはAddAPIErrorでdiagnosticsに追加すること。
+// レスポンスに含まれる未知の列挙値もあわせて記録する(WithUnknownEnumCapture)
+func WithAPIErrorCapture(ctx context.Context) context.Context {
+	ctx = WithUnknownEnumCapture(ctx)
+	if _, ok := ctx.Value(apiErrorCaptureKey{}).(*apiErrorCapture); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, apiErrorCaptureKey{}, &apiErrorCapture{})
+}
+
+// WithoutAPIErrorCapture はAPIエラーを記録しないcontextを返す。
+// エラーの原因を調べるための補助的なAPI呼び出しで、記録済みのエラーが上書きされたり消えたりしないようにするために利用する
+func WithoutAPIErrorCapture(ctx context.Context) context.Context {
+	return context.WithValue(ctx, apiErrorCaptureKey{}, nil)
+}
+
+func capturedAPIError(ctx context.Context) *APIErrorDetail {
+	c, ok := ctx.Value(apiErrorCaptureKey{}).(*apiErrorCapture)
+	if !ok {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.detail == nil {
+		return nil
+	}
+	d := *c.detail
+	return &d
+}
+
+// apiErrorRecorder はエラーレスポンスの情報をリクエストのcontextのapiErrorCaptureに記録するhttp.RoundTripper
+type apiErrorRecorder struct {
+	transport http.RoundTripper
+}
+
+func (r *apiErrorRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := r.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	c, ok := req.Context().Value(apiErrorCaptureKey{}).(*apiErrorCapture)
+	if !ok {
+		return resp, nil
+	}
+	if resp.StatusCode < http.StatusBadRequest {
+		// リトライなどで成功した場合は以前のエラーを残さない
+		c.mu.Lock()
+		c.detail = nil
+		c.mu.Unlock()
+		return resp, nil
+	}
+
+	body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+	if readErr != nil {
+		body = nil
+	}
+
+	detail := &APIErrorDetail{
+		StatusCode: resp.StatusCode,
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		RequestID:  resp.Header.Get(RequestIDHeader),
+	}
+	detail.ErrorCode, detail.Message, detail.Fields = parseErrorBody(body)
+
+	c.mu.Lock()
+	c.detail = detail
+	c.mu.Unlock()
+	return resp, nil
+}
+
+// parseErrorBody はエラーレスポンスのボディからエラーコードとメッセージ、フィールド単位のエラーを取り出す。
+// フィールド単位のエラーは{"field": ...}もしくは{"errors": [{"field": ..., "message": ...}]}の形式に対応する
+func parseErrorBody(body []byte) (code, message string, fields []APIFieldError) {
+	type fieldError struct {
+		Field   string `json:"field"`
+		Name    string `json:"name"`
+		Message string `json:"message"`
+		Detail  string `json:"detail"`
+	}
+	var v struct {
+		ErrorCode    string       `json:"error_code"`
+		ErrorMessage string       `json:"error_msg"`
+		Code         string       `json:"code"`
+		Message      string       `json:"message"`
+		Detail       string       `json:"detail"`
+		Field        string       `json:"field"`
+		Errors       []fieldError `json:"errors"`
+	}
+	if len(body) == 0 || json.Unmarshal(body, &v) != nil {
+		return "", "", nil
+	}
+
+	code = firstNonEmpty(v.ErrorCode, v.Code)
+	message = firstNonEmpty(v.ErrorMessage, v.Message, v.Detail)
+	if v.Field != "" {
+		fields = append(fields, APIFieldError{Field: v.Field, Message: message})
+	}
+	for _, e := range v.Errors {
+		if name := firstNonEmpty(e.Field, e.Name); name != "" {
+			fields = append(fields, APIFieldError{Field: name, Message: firstNonEmpty(e.Message, e.Detail, message)})
+		}
+	}
+	return code, message, fields
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// APIErrorDetails はエラーとcontextに記録されたエラーレスポンスからAPIErrorDetailを組み立てる。APIエラーでない場合はnilを返す
+func APIErrorDetails(ctx context.Context, err error) *APIErrorDetail {
+	var (
+		statusCode int
+		errorCode  string
+		message    string
+		serial     string
+	)
+	var iaasErr iaas.APIError
+	var apiErr *client.APIError
+	switch {
+	case errors.As(err, &iaasErr):
+		statusCode = iaasErr.ResponseCode()
+		errorCode = iaasErr.Code()
+		message = iaasErr.Message()
+		serial = iaasErr.Serial()
+	case errors.As(err, &apiErr) && apiErr.Code > 0:
+		statusCode = apiErr.Code
+		message = apiErr.Message
+	default:
+		statusCode = APIStatusCode(err)
+	}
+
+	detail := capturedAPIError(ctx)
+	// 記録されたレスポンスのステータスコードがエラーと異なる場合は別のAPI呼び出しのものとみなす
+	if detail == nil || (statusCode > 0 && detail.StatusCode != statusCode) {
+		detail = &APIErrorDetail{StatusCode: statusCode}
+	}
+	// api-client-goのAPIErrorのメッセージはステータスコードの説明のため、レスポンスボディのものを優先する
+	detail.ErrorCode = firstNonEmpty(detail.ErrorCode, errorCode)
+	detail.Message = firstNonEmpty(detail.Message, message)
+	detail.RequestID = firstNonEmpty(detail.RequestID, serial)
+
+	if detail.StatusCode == 0 && detail.Path == "" {
+		return nil
+	}
+	return detail
+}
+
+// APIStatusCode はAPIエラーのHTTPステータスコードを返す。APIエラーでない場合は0を返す
+func APIStatusCode(err error) int {
+	var iaasErr iaas.APIError
+	if errors.As(err, &iaasErr) {
+		return iaasErr.ResponseCode()
+	}
+	var apiErr *client.APIError
+	if errors.As(err, &apiErr) && apiErr.Code > 0 {
+		return apiErr.Code
+	}
+	var unexpected *ogen.UnexpectedStatusCodeError
+	if errors.As(err, &unexpected) {
+		return unexpected.StatusCode
+	}
+	return 0
+}
+
+// AddAPIError はAPIエラーのステータスコード、エラーコード、メッセージ、リクエストID、エンドポイントを詳細に含めたエラーをdiagsに追加する
+// ctxがキャンセルされている場合はAddCanceledErrorでキャンセルされたことを表すdiagnosticを追加する
+func AddAPIError(ctx context.Context, diags *diag.Diagnostics, summary string, err error) {
+	if IsCanceled(ctx, err) {
+		AddCanceledError(diags, summary, err)
+		return
+	}
+	diags.AddError(summary, FormatAPIError(ctx, err))
+}
+
+// FormatAPIError はAddAPIErrorで利用するdiagnosticの詳細を返す
+func FormatAPIError(ctx context.Context, err error) string {
+	detail := APIErrorDetails(ctx, err)
+	if detail == nil {
+		return err.Error()
+	}
+	if hint := authErrorHint(detail); hint != "" {
+		return err.Error() + "\n\n" + hint + "\n\n" + detail.String()
+	}
+	return err.Error() + "\n\n" + detail.String()
+}
+
+// authErrorHint は認証エラー(401)と権限不足(403)を区別して対処方法を示すメッセージを返す。それ以外のステータスコードの場合は空文字を返す
+func authErrorHint(d *APIErrorDetail) string {
+	service, operation := apiOperation(d.Method, d.Path)
+	switch d.StatusCode {
+	case http.StatusUnauthorized:
+		return fmt.Sprintf("%s API rejected the credentials for %s; check token/secret.", service, operation)
+	case http.StatusForbidden:
+		return fmt.Sprintf("The credentials are valid but lack permission for %s on %s API; check the permissions of the API key.", operation, service)
+	}
+	return ""
+}
+
+// apiOperation はリクエストのメソッドとパスからサービス名と操作名を返す。
+// パスはゾーンとAPIのバージョンを含む(/{zone}/api/cloud/1.1/{service}/...)ため、サービス以降を操作名とする
+func apiOperation(method, reqPath string) (service, operation string) {
+	const apiPrefix = "/api/cloud/1.1/"
+	if i := strings.Index(reqPath, apiPrefix); i >= 0 {
+		reqPath = reqPath[i+len(apiPrefix)-1:]
+	}
+	service, _, _ = strings.Cut(strings.TrimPrefix(reqPath, "/"), "/")
+	if service == "" {
+		service = "SakuraCloud"
+	}
+	operation = strings.TrimSpace(method + " " + reqPath)
+	if operation == "" {
+		operation = "the operation"
+	}
+	return service, operation
+}
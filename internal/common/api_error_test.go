@@ -0,0 +1,184 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	ogen "github.com/ogen-go/ogen/validate"
+	"github.com/sacloud/iaas-api-go"
+	kmsapi "github.com/sacloud/kms-api-go/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddAPIError(t *testing.T) {
+	const (
+		token     = "test-access-token"
+		secret    = "test-access-token-secret"
+		plainKey  = "test-plain-key"
+		requestID = "req-0123456789"
+	)
+
+	expects := []struct {
+		status int
+		code   string
+		msg    string
+	}{
+		{status: http.StatusBadRequest, code: "bad_request", msg: "invalid parameter"},
+		{status: http.StatusForbidden, code: "forbidden", msg: "permission denied"},
+		{status: http.StatusNotFound, code: "not_found", msg: "key is not found"},
+		{status: http.StatusConflict, code: "conflict", msg: "key is in use"},
+		{status: http.StatusTooManyRequests, code: "too_many_requests", msg: "rate limit exceeded"},
+		{status: http.StatusInternalServerError, code: "internal_error", msg: "internal server error"},
+	}
+
+	for _, tc := range expects {
+		t.Run(fmt.Sprintf("%d", tc.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set(RequestIDHeader, requestID)
+				// 429はリトライされるため、待機せずにリトライさせる
+				w.Header().Set(RetryAfterHeader, "0")
+				w.WriteHeader(tc.status)
+				fmt.Fprintf(w, `{"is_fatal":true,"status":"%d","error_code":%q,"error_msg":%q}`, tc.status, tc.code, tc.msg)
+			}))
+			defer server.Close()
+
+			client, err := (&Config{
+				AccessToken:         token,
+				AccessTokenSecret:   secret,
+				APIRootURL:          server.URL,
+				APIRequestTimeout:   APIRequestTimeout,
+				APIRequestRateLimit: APIRequestRateLimit,
+			}).NewClient()
+			require.NoError(t, err)
+
+			ctx := WithAPIErrorCapture(context.Background())
+			op, err := client.KMSKeyOp()
+			require.NoError(t, err)
+			_, err = op.Create(ctx, kmsapi.CreateKey{
+				Name:      "foobar",
+				KeyOrigin: kmsapi.KeyOriginEnumImported,
+				PlainKey:  kmsapi.NewOptString(plainKey),
+			})
+			require.Error(t, err)
+
+			var diags diag.Diagnostics
+			AddAPIError(ctx, &diags, "KMS Create Error", err)
+			require.Len(t, diags, 1)
+
+			detail := diags[0].Detail()
+			assert.Contains(t, detail, fmt.Sprintf("status: %d %s", tc.status, http.StatusText(tc.status)))
+			assert.Contains(t, detail, "error code: "+tc.code)
+			assert.Contains(t, detail, "message: "+tc.msg)
+			assert.Contains(t, detail, "request ID: "+requestID)
+			assert.Contains(t, detail, "endpoint: POST /tk1a/api/cloud/1.1/kms/keys")
+			for _, s := range []string{token, secret, plainKey} {
+				assert.NotContains(t, detail, s)
+			}
+		})
+	}
+}
+
+func TestAPIErrorDetails(t *testing.T) {
+	t.Run("iaas error", func(t *testing.T) {
+		err := fmt.Errorf("reading: %w", iaas.NewAPIError(http.MethodGet, nil, http.StatusConflict, &iaas.APIErrorResponse{
+			Serial:       "serial-01",
+			ErrorCode:    "still_creating",
+			ErrorMessage: "resource is still creating",
+		}))
+
+		got := APIErrorDetails(context.Background(), err)
+		assert.Equal(t, &APIErrorDetail{
+			StatusCode: http.StatusConflict,
+			RequestID:  "serial-01",
+			ErrorCode:  "still_creating",
+			Message:    "resource is still creating",
+		}, got)
+	})
+
+	t.Run("status code only", func(t *testing.T) {
+		got := APIErrorDetails(context.Background(), ogen.UnexpectedStatusCode(http.StatusTooManyRequests))
+		assert.Equal(t, &APIErrorDetail{StatusCode: http.StatusTooManyRequests}, got)
+		assert.Equal(t, "status: 429 Too Many Requests", got.String())
+	})
+
+	t.Run("stale capture is ignored", func(t *testing.T) {
+		ctx := WithAPIErrorCapture(context.Background())
+		c := ctx.Value(apiErrorCaptureKey{}).(*apiErrorCapture)
+		c.detail = &APIErrorDetail{StatusCode: http.StatusNotFound, Path: "/other", RequestID: "stale"}
+
+		got := APIErrorDetails(ctx, ogen.UnexpectedStatusCode(http.StatusInternalServerError))
+		assert.Equal(t, &APIErrorDetail{StatusCode: http.StatusInternalServerError}, got)
+	})
+
+	t.Run("not an API error", func(t *testing.T) {
+		err := errors.New("plain_key is required")
+		assert.Nil(t, APIErrorDetails(context.Background(), err))
+		assert.Equal(t, err.Error(), FormatAPIError(context.Background(), err))
+	})
+}
+
+func TestFormatAPIError_authErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		status int
+		path   string
+		want   string
+	}{
+		{
+			name:   "401",
+			status: http.StatusUnauthorized,
+			path:   "/tk1a/api/cloud/1.1/kms/keys",
+			want:   "kms API rejected the credentials for POST /kms/keys; check token/secret.",
+		},
+		{
+			name:   "403",
+			status: http.StatusForbidden,
+			path:   "/tk1a/api/cloud/1.1/secretmanager/vaults",
+			want:   "The credentials are valid but lack permission for POST /secretmanager/vaults on secretmanager API; check the permissions of the API key.",
+		},
+		{
+			name:   "403 without endpoint",
+			status: http.StatusForbidden,
+			want:   "The credentials are valid but lack permission for the operation on SakuraCloud API; check the permissions of the API key.",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := WithAPIErrorCapture(context.Background())
+			if tc.path != "" {
+				c := ctx.Value(apiErrorCaptureKey{}).(*apiErrorCapture)
+				c.detail = &APIErrorDetail{StatusCode: tc.status, Method: http.MethodPost, Path: tc.path}
+			}
+
+			got := FormatAPIError(ctx, ogen.UnexpectedStatusCode(tc.status))
+			assert.Contains(t, got, "\n\n"+tc.want+"\n\n")
+		})
+	}
+
+	t.Run("other status codes have no hint", func(t *testing.T) {
+		got := FormatAPIError(context.Background(), ogen.UnexpectedStatusCode(http.StatusNotFound))
+		assert.NotContains(t, got, "credentials")
+	})
+}
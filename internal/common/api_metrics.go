@@ -0,0 +1,201 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"cmp"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// maxAPIMetricEndpoints は集計するエンドポイント数の上限。IDに置き換えられないパスが増え続けてもメモリを使い続けないようにする
+	maxAPIMetricEndpoints = 100
+	// apiMetricOtherEndpoint は上限を超えたエンドポイントをまとめて集計するエンドポイント名
+	apiMetricOtherEndpoint = "(other)"
+)
+
+// APIMetric はエンドポイントごとのAPIリクエストの所要時間の集計
+type APIMetric struct {
+	Endpoint string // メソッドとパス。パス中のリソースIDは{id}に置き換える
+	Count    int
+	Total    time.Duration
+	P95      time.Duration
+}
+
+func (m APIMetric) String() string {
+	return fmt.Sprintf("%s: count=%d total=%s p95=%s", m.Endpoint, m.Count, m.Total, m.P95)
+}
+
+// apiMetrics はtraceやDEBUGログの有効時に、APIリクエストごとの所要時間をDEBUGログに出力し、エンドポイントごとに集計するhttp.RoundTripper。
+// 集計結果はプロバイダーの終了時にLogAPIMetricsで出力する
+type apiMetrics struct {
+	transport http.RoundTripper
+	now       func() time.Time
+
+	mu        sync.Mutex
+	durations map[string][]time.Duration
+}
+
+func newAPIMetrics(transport http.RoundTripper) *apiMetrics {
+	m := &apiMetrics{transport: transport, durations: map[string][]time.Duration{}}
+	registeredAPIMetrics.add(m)
+	return m
+}
+
+func (m *apiMetrics) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := m.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	now := time.Now
+	if m.now != nil {
+		now = m.now
+	}
+
+	start := now()
+	resp, err := transport.RoundTrip(req)
+	duration := now().Sub(start)
+
+	endpoint := apiMetricEndpoint(req)
+	m.record(endpoint, duration)
+	fields := map[string]any{"endpoint": endpoint, "duration_ms": duration.Milliseconds()}
+	if resp != nil {
+		fields["status"] = resp.StatusCode
+	}
+	tflog.Debug(req.Context(), "API request completed", fields)
+	return resp, err
+}
+
+func (m *apiMetrics) record(endpoint string, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.durations[endpoint]; !ok && len(m.durations) >= maxAPIMetricEndpoints {
+		endpoint = apiMetricOtherEndpoint
+	}
+	m.durations[endpoint] = append(m.durations[endpoint], duration)
+}
+
+// Summary はエンドポイントごとの集計を合計時間の降順で返す
+func (m *apiMetrics) Summary() []APIMetric {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return summarizeAPIMetrics(m.durations)
+}
+
+func summarizeAPIMetrics(durations map[string][]time.Duration) []APIMetric {
+	metrics := make([]APIMetric, 0, len(durations))
+	for endpoint, ds := range durations {
+		sorted := slices.Clone(ds)
+		slices.Sort(sorted)
+		var total time.Duration
+		for _, d := range sorted {
+			total += d
+		}
+		metrics = append(metrics, APIMetric{Endpoint: endpoint, Count: len(sorted), Total: total, P95: percentile(sorted, 95)})
+	}
+	slices.SortFunc(metrics, func(a, b APIMetric) int {
+		return cmp.Or(cmp.Compare(b.Total, a.Total), strings.Compare(a.Endpoint, b.Endpoint))
+	})
+	return metrics
+}
+
+// percentile は昇順に並んだsortedのpパーセンタイルをnearest-rank法で返す
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// resourceIDSegment はパス中のリソースIDとみなすセグメント。数値のIDに加えてUUID形式のIDも置き換える
+var resourceIDSegment = regexp.MustCompile(`^(?:[0-9]+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})$`)
+
+// apiMetricEndpoint はリクエストを集計するエンドポイント名を返す。リソースごとに分かれないよう、パス中のIDは{id}に置き換える
+func apiMetricEndpoint(req *http.Request) string {
+	segments := strings.Split(req.URL.Path, "/")
+	for i, s := range segments {
+		if resourceIDSegment.MatchString(s) {
+			segments[i] = "{id}"
+		}
+	}
+	return req.Method + " " + strings.Join(segments, "/")
+}
+
+// apiMetricsEnabled はtraceが指定されているか、terraformのログレベルがDEBUG以下の場合にtrueを返す
+func apiMetricsEnabled(traceMode string, getenv func(string) string) bool {
+	if traceMode != "" {
+		return true
+	}
+	for _, key := range []string{"TF_LOG", "TF_LOG_PROVIDER"} {
+		switch strings.ToUpper(getenv(key)) {
+		case "TRACE", "DEBUG", "JSON":
+			return true
+		}
+	}
+	return false
+}
+
+var registeredAPIMetrics = &apiMetricsRegistry{}
+
+// apiMetricsRegistry はプロセス内で生成されたapiMetricsを保持する。プロバイダーのエイリアスごとに生成されたAPIClientの集計をまとめて出力するために利用する
+type apiMetricsRegistry struct {
+	mu      sync.Mutex
+	metrics []*apiMetrics
+}
+
+func (r *apiMetricsRegistry) add(m *apiMetrics) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = append(r.metrics, m)
+}
+
+func (r *apiMetricsRegistry) summary() []APIMetric {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	durations := map[string][]time.Duration{}
+	for _, m := range r.metrics {
+		m.mu.Lock()
+		for endpoint, ds := range m.durations {
+			durations[endpoint] = append(durations[endpoint], ds...)
+		}
+		m.mu.Unlock()
+	}
+	return summarizeAPIMetrics(durations)
+}
+
+// LogAPIMetrics はtraceやDEBUGログの有効時に集計したAPIリクエストの所要時間をログに出力する。プロバイダーのサーバーの終了時に呼び出す
+func LogAPIMetrics() {
+	metrics := registeredAPIMetrics.summary()
+	if len(metrics) == 0 {
+		return
+	}
+	lines := make([]string, 0, len(metrics))
+	for _, m := range metrics {
+		lines = append(lines, "  "+m.String())
+	}
+	log.Printf("[DEBUG] SakuraCloud API request timings (pid %d):\n%s", os.Getpid(), strings.Join(lines, "\n"))
+}
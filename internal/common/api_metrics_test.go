@@ -0,0 +1,148 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	ms := func(values ...int) []time.Duration {
+		ds := make([]time.Duration, 0, len(values))
+		for _, v := range values {
+			ds = append(ds, time.Duration(v)*time.Millisecond)
+		}
+		return ds
+	}
+
+	cases := []struct {
+		name   string
+		sorted []time.Duration
+		want   time.Duration
+	}{
+		{name: "empty", sorted: nil, want: 0},
+		{name: "single", sorted: ms(7), want: 7 * time.Millisecond},
+		{name: "twenty values", sorted: ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20), want: 19 * time.Millisecond},
+		{name: "small sample uses the maximum", sorted: ms(1, 2, 3), want: 3 * time.Millisecond},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, percentile(tc.sorted, 95))
+		})
+	}
+}
+
+func TestSummarizeAPIMetrics(t *testing.T) {
+	got := summarizeAPIMetrics(map[string][]time.Duration{
+		"GET /server/{id}": {30 * time.Millisecond, 10 * time.Millisecond, 20 * time.Millisecond},
+		"PUT /server/{id}": {100 * time.Millisecond},
+		"GET /disk":        {60 * time.Millisecond},
+	})
+	assert.Equal(t, []APIMetric{
+		{Endpoint: "PUT /server/{id}", Count: 1, Total: 100 * time.Millisecond, P95: 100 * time.Millisecond},
+		{Endpoint: "GET /disk", Count: 1, Total: 60 * time.Millisecond, P95: 60 * time.Millisecond},
+		{Endpoint: "GET /server/{id}", Count: 3, Total: 60 * time.Millisecond, P95: 30 * time.Millisecond},
+	}, got)
+	assert.Equal(t, "PUT /server/{id}: count=1 total=100ms p95=100ms", got[0].String())
+}
+
+func TestAPIMetrics_RoundTrip(t *testing.T) {
+	clock := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := &apiMetrics{
+		transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			clock = clock.Add(25 * time.Millisecond)
+			return &http.Response{StatusCode: http.StatusOK, Request: req}, nil
+		}),
+		now:       func() time.Time { return clock },
+		durations: map[string][]time.Duration{},
+	}
+
+	for _, path := range []string{
+		"/zone/is1a/api/cloud/1.1/server/113000000001",
+		"/zone/is1a/api/cloud/1.1/server/113000000002",
+		"/zone/is1a/api/cloud/1.1/server/0b9c7c1e-5d2a-4c8e-9f3b-2a1d6e4f8c7a",
+	} {
+		resp, err := m.RoundTrip(httptest.NewRequest(http.MethodGet, "http://sakura.example.com"+path+"?From=0", nil))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	assert.Equal(t, []APIMetric{
+		{Endpoint: "GET /zone/is1a/api/cloud/1.1/server/{id}", Count: 3, Total: 75 * time.Millisecond, P95: 25 * time.Millisecond},
+	}, m.Summary())
+}
+
+func TestAPIMetrics_maxEndpoints(t *testing.T) {
+	m := &apiMetrics{durations: map[string][]time.Duration{}}
+	for i := range maxAPIMetricEndpoints + 10 {
+		m.record(fmt.Sprintf("GET /queues/queue-%d", i), time.Millisecond)
+	}
+	// 上限を超えたエンドポイントはまとめて集計する
+	assert.Len(t, m.durations, maxAPIMetricEndpoints+1)
+	assert.Len(t, m.durations[apiMetricOtherEndpoint], 10)
+
+	// 集計済みのエンドポイントは上限に達した後もそのまま集計する
+	m.record("GET /queues/queue-0", time.Millisecond)
+	assert.Len(t, m.durations["GET /queues/queue-0"], 2)
+}
+
+func TestAPIMetricsEnabled(t *testing.T) {
+	env := func(values map[string]string) func(string) string {
+		return func(key string) string { return values[key] }
+	}
+	assert.False(t, apiMetricsEnabled("", env(nil)))
+	assert.False(t, apiMetricsEnabled("", env(map[string]string{"TF_LOG": "INFO"})))
+	assert.True(t, apiMetricsEnabled("api", env(nil)))
+	assert.True(t, apiMetricsEnabled("", env(map[string]string{"TF_LOG": "debug"})))
+	assert.True(t, apiMetricsEnabled("", env(map[string]string{"TF_LOG_PROVIDER": "TRACE"})))
+}
+
+func TestAPIClient_APIMetrics(t *testing.T) {
+	t.Setenv("TF_LOG", "")
+	t.Setenv("TF_LOG_PROVIDER", "")
+
+	client := newServiceClientTestClient(t, &listTransport{})
+	assert.Nil(t, client.APIMetrics())
+
+	client, err := (&Config{
+		AccessToken:         "token",
+		AccessTokenSecret:   "secret",
+		APIRootURL:          "http://sakura.example.com",
+		APIRequestRateLimit: 100,
+		TraceMode:           "api",
+		HTTPTransport:       &listTransport{},
+	}).NewClient()
+	require.NoError(t, err)
+	_, _, err = client.KMSKeyList(t.Context())
+	require.NoError(t, err)
+
+	metrics := client.APIMetrics()
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "GET /tk1a/api/cloud/1.1/kms/keys", metrics[0].Endpoint)
+	assert.Equal(t, 1, metrics[0].Count)
+
+	// プロバイダーの終了時にまとめて出力できるよう、プロセス内の集計にも含まれる
+	assert.True(t, slices.ContainsFunc(registeredAPIMetrics.summary(), func(m APIMetric) bool {
+		return m.Endpoint == metrics[0].Endpoint
+	}))
+}
@@ -0,0 +1,105 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	ogen "github.com/ogen-go/ogen/validate"
+)
+
+// APIFieldError はAPIのリクエストのうち、特定のフィールドに対するエラー
+type APIFieldError struct {
+	Field   string
+	Message string
+}
+
+// AttributePaths はAPIのフィールド名と、エラーを紐付けるTerraformの属性のパスの対応。
+// フィールド名は大文字小文字とアンダースコアを無視して比較するため、"KmsKeyID"と"kms_key_id"は同じフィールドとして扱う
+type AttributePaths map[string]path.Path
+
+func (p AttributePaths) lookup(field string) (path.Path, bool) {
+	key := normalizeFieldName(field)
+	for name, attrPath := range p {
+		if normalizeFieldName(name) == key {
+			return attrPath, true
+		}
+	}
+	return path.Empty(), false
+}
+
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// APIFieldErrors はerrに含まれるフィールド単位のエラーを返す。
+// ogenによるリクエストのバリデーションエラーと、エラーレスポンスのボディに含まれるフィールドの情報を対象とする
+func APIFieldErrors(ctx context.Context, err error) []APIFieldError {
+	var validateErr *ogen.Error
+	if errors.As(err, &validateErr) {
+		return flattenValidateError(validateErr)
+	}
+	if detail := APIErrorDetails(ctx, err); detail != nil {
+		return detail.Fields
+	}
+	return nil
+}
+
+// flattenValidateError はネストしたバリデーションエラーを末端のフィールドのエラーに展開する。
+// リクエストボディのラッパー(KeyやVaultなど)のフィールド名は属性に対応しないため使わない
+func flattenValidateError(err *ogen.Error) []APIFieldError {
+	var fields []APIFieldError
+	for _, f := range err.Fields {
+		var nested *ogen.Error
+		if errors.As(f.Error, &nested) {
+			fields = append(fields, flattenValidateError(nested)...)
+			continue
+		}
+		fields = append(fields, APIFieldError{Field: f.Name, Message: f.Error.Error()})
+	}
+	return fields
+}
+
+// AddAPIAttributeError はAPIエラーのうちpathsに対応するフィールドのエラーを属性に紐付けてdiagsに追加する。
+// フィールドの情報がない場合や、pathsに対応しないフィールドのエラーが含まれる場合はAddAPIErrorでリソース全体のエラーとして追加する
+func AddAPIAttributeError(ctx context.Context, diags *diag.Diagnostics, summary string, err error, paths AttributePaths) {
+	if IsCanceled(ctx, err) {
+		AddCanceledError(diags, summary, err)
+		return
+	}
+
+	var detail string
+	if d := APIErrorDetails(ctx, err); d != nil {
+		detail = "\n\n" + d.String()
+	}
+
+	fields := APIFieldErrors(ctx, err)
+	unmapped := len(fields) == 0
+	for _, f := range fields {
+		attrPath, ok := paths.lookup(f.Field)
+		if !ok {
+			unmapped = true
+			continue
+		}
+		diags.AddAttributeError(attrPath, summary, f.Message+detail)
+	}
+	if unmapped {
+		AddAPIError(ctx, diags, summary, err)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	kmsapi "github.com/sacloud/kms-api-go/apis/v1"
+	smapi "github.com/sacloud/secretmanager-api-go/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAttributeErrorTestClient は全てのリクエストに指定したステータスとボディを返すサーバーに接続したAPIClientを返す
+func newAttributeErrorTestClient(t *testing.T, status int, body string) *APIClient {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(RequestIDHeader, "req-0123456789")
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := (&Config{
+		AccessToken:       "token",
+		AccessTokenSecret: "secret",
+		APIRootURL:        server.URL,
+	}).NewClient()
+	require.NoError(t, err)
+	return client
+}
+
+func attributePath(t *testing.T, d diag.Diagnostic) path.Path {
+	t.Helper()
+	withPath, ok := d.(diag.DiagnosticWithPath)
+	require.True(t, ok, "diagnostic should have an attribute path: %v", d)
+	return withPath.Path()
+}
+
+func TestAddAPIAttributeError(t *testing.T) {
+	kmsPaths := AttributePaths{
+		"Name":        path.Root("name"),
+		"Description": path.Root("description"),
+		"Tags":        path.Root("tags"),
+	}
+
+	t.Run("request validation error", func(t *testing.T) {
+		client := newAttributeErrorTestClient(t, http.StatusOK, `{}`)
+
+		ctx := WithAPIErrorCapture(context.Background())
+		op, err := client.KMSKeyOp()
+		require.NoError(t, err)
+		_, err = op.Create(ctx, kmsapi.CreateKey{
+			Name:      strings.Repeat("a", 256),
+			KeyOrigin: kmsapi.KeyOriginEnumGenerated,
+		})
+		require.Error(t, err)
+
+		var diags diag.Diagnostics
+		AddAPIAttributeError(ctx, &diags, "KMS Create Error", err, kmsPaths)
+		require.Len(t, diags, 1)
+		assert.Equal(t, path.Root("name"), attributePath(t, diags[0]))
+		assert.Equal(t, "KMS Create Error", diags[0].Summary())
+		assert.Contains(t, diags[0].Detail(), "greater than maximum 255")
+	})
+
+	t.Run("field error in response body", func(t *testing.T) {
+		client := newAttributeErrorTestClient(t, http.StatusBadRequest,
+			`{"is_fatal":true,"status":"400 Bad Request","error_code":"bad_request","error_msg":"invalid parameter","errors":[{"field":"kms_key_id","message":"kms key is not found"}]}`)
+
+		ctx := WithAPIErrorCapture(context.Background())
+		op, err := client.SecretManagerVaultOp()
+		require.NoError(t, err)
+		_, err = op.Create(ctx, smapi.CreateVault{Name: "foobar", KmsKeyID: "110000000001"})
+		require.Error(t, err)
+
+		var diags diag.Diagnostics
+		AddAPIAttributeError(ctx, &diags, "SecretManager Create Error", err, AttributePaths{
+			"Name":     path.Root("name"),
+			"KmsKeyID": path.Root("kms_key_id"),
+		})
+		require.Len(t, diags, 1)
+		assert.Equal(t, path.Root("kms_key_id"), attributePath(t, diags[0]))
+		assert.Contains(t, diags[0].Detail(), "kms key is not found")
+		assert.Contains(t, diags[0].Detail(), "request ID: req-0123456789")
+	})
+
+	t.Run("unknown field falls back to resource error", func(t *testing.T) {
+		client := newAttributeErrorTestClient(t, http.StatusBadRequest,
+			`{"error_code":"bad_request","error_msg":"invalid parameter","errors":[{"field":"tags","message":"invalid tag"},{"field":"unknown","message":"invalid value"}]}`)
+
+		ctx := WithAPIErrorCapture(context.Background())
+		op, err := client.KMSKeyOp()
+		require.NoError(t, err)
+		_, err = op.Create(ctx, kmsapi.CreateKey{Name: "foobar", KeyOrigin: kmsapi.KeyOriginEnumGenerated})
+		require.Error(t, err)
+
+		var diags diag.Diagnostics
+		AddAPIAttributeError(ctx, &diags, "KMS Create Error", err, kmsPaths)
+		require.Len(t, diags, 2)
+		assert.Equal(t, path.Root("tags"), attributePath(t, diags[0]))
+		assert.NotImplements(t, (*diag.DiagnosticWithPath)(nil), diags[1])
+		assert.Contains(t, diags[1].Detail(), "field unknown: invalid value")
+	})
+
+	t.Run("no field information", func(t *testing.T) {
+		client := newAttributeErrorTestClient(t, http.StatusBadRequest, `{"error_code":"bad_request","error_msg":"invalid parameter"}`)
+
+		ctx := WithAPIErrorCapture(context.Background())
+		op, err := client.KMSKeyOp()
+		require.NoError(t, err)
+		_, err = op.Create(ctx, kmsapi.CreateKey{Name: "foobar", KeyOrigin: kmsapi.KeyOriginEnumGenerated})
+		require.Error(t, err)
+
+		var diags diag.Diagnostics
+		AddAPIAttributeError(ctx, &diags, "KMS Create Error", err, kmsPaths)
+		require.Len(t, diags, 1)
+		assert.NotImplements(t, (*diag.DiagnosticWithPath)(nil), diags[0])
+		assert.Contains(t, diags[0].Detail(), "message: invalid parameter")
+	})
+}
+
+func TestParseErrorBody_fields(t *testing.T) {
+	code, message, fields := parseErrorBody([]byte(`{"error_code":"bad_request","error_msg":"name is too long","field":"name"}`))
+	assert.Equal(t, "bad_request", code)
+	assert.Equal(t, "name is too long", message)
+	assert.Equal(t, []APIFieldError{{Field: "name", Message: "name is too long"}}, fields)
+}
+
+func TestAttributePaths_lookup(t *testing.T) {
+	paths := AttributePaths{"KmsKeyID": path.Root("kms_key_id")}
+
+	for _, field := range []string{"KmsKeyID", "kms_key_id", "kmskeyid"} {
+		got, ok := paths.lookup(field)
+		assert.True(t, ok, field)
+		assert.Equal(t, path.Root("kms_key_id"), got)
+	}
+	_, ok := paths.lookup("name")
+	assert.False(t, ok)
+}
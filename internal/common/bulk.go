@@ -0,0 +1,84 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+)
+
+// MaxParallelBulkWrites はmax_concurrent_api_requestsが未指定の場合に、複数の値をまとめて扱うリソースが並行して書き込む数
+const MaxParallelBulkWrites = 8
+
+// KeyError はForEachKeyで失敗したキーのエラー
+type KeyError struct {
+	Key string
+	Err error
+	// Detail はキーごとに記録したAPIエラーの詳細を含めたメッセージ
+	Detail string
+}
+
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Key, e.Err)
+}
+
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// ForEachKey はkeysのキーごとにfnを最大parallelism個まで並行して実行し、失敗したキーのエラーをkeysの順序で返す。
+// parallelismが0以下の場合はMaxParallelBulkWritesを利用する。通常はAPIClient.BulkWriteParallelismの値を渡すこと。
+// 一部のキーが失敗しても他のキーの処理は中断しない。呼び出し元は成功したキーの結果をstateに記録すること。
+// fnに渡すctxはキーごとにAPIエラーを記録するため、エラーの詳細が他のキーのものと混ざらない
+func ForEachKey(ctx context.Context, keys []string, parallelism int, fn func(ctx context.Context, key string) error) []*KeyError {
+	if parallelism <= 0 {
+		parallelism = MaxParallelBulkWrites
+	}
+
+	errs := make([]*KeyError, len(keys))
+	runParallel(len(keys), parallelism, func(i int) {
+		keyCtx := WithAPIErrorCapture(WithoutAPIErrorCapture(ctx))
+		err := ctx.Err()
+		if err == nil {
+			err = fn(keyCtx, keys[i])
+		}
+		if err == nil {
+			return
+		}
+		detail := FormatAPIError(keyCtx, err)
+		if IsCanceled(keyCtx, err) {
+			detail = fmt.Sprintf("operation cancelled before it completed: %s", err)
+		}
+		errs[i] = &KeyError{Key: keys[i], Err: err, Detail: detail}
+	})
+
+	var failed []*KeyError
+	for _, err := range errs {
+		if err != nil {
+			failed = append(failed, err)
+		}
+	}
+	return failed
+}
+
+// AddKeyErrors はForEachKeyが返したエラーを、キーごとにattributePathが返す属性のエラーとしてdiagsに追加する
+func AddKeyErrors(diags *diag.Diagnostics, summary string, errs []*KeyError, attributePath func(key string) path.Path) {
+	for _, err := range errs {
+		diags.AddAttributeError(attributePath(err.Key), summary, fmt.Sprintf("%q: %s", err.Key, err.Detail))
+	}
+}
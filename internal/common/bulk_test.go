@@ -0,0 +1,86 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForEachKey(t *testing.T) {
+	keys := []string{"a", "b", "c", "d", "e", "f"}
+
+	t.Run("bounded parallelism", func(t *testing.T) {
+		var counter inFlightCounter
+		errs := ForEachKey(context.Background(), keys, 2, func(context.Context, string) error {
+			defer counter.enter()()
+			time.Sleep(10 * time.Millisecond)
+			return nil
+		})
+		assert.Empty(t, errs)
+		assert.EqualValues(t, 2, counter.max.Load())
+	})
+
+	t.Run("failures do not stop other keys", func(t *testing.T) {
+		done := make(chan string, len(keys))
+		errs := ForEachKey(context.Background(), keys, 3, func(_ context.Context, key string) error {
+			done <- key
+			if key == "b" || key == "e" {
+				return errors.New("failed " + key)
+			}
+			return nil
+		})
+		close(done)
+		assert.Len(t, done, len(keys))
+		require.Len(t, errs, 2)
+		assert.Equal(t, "b", errs[0].Key)
+		assert.Equal(t, "e", errs[1].Key)
+		assert.EqualError(t, errs[0], "b: failed b")
+		assert.Equal(t, "failed b", errs[0].Detail)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		var called bool
+		errs := ForEachKey(ctx, keys[:1], 1, func(context.Context, string) error {
+			called = true
+			return nil
+		})
+		assert.False(t, called)
+		require.Len(t, errs, 1)
+		assert.ErrorIs(t, errs[0], context.Canceled)
+		assert.Contains(t, errs[0].Detail, "operation cancelled")
+	})
+
+	t.Run("attribute errors", func(t *testing.T) {
+		var diags diag.Diagnostics
+		AddKeyErrors(&diags, "Write Error", []*KeyError{{Key: "b", Err: errors.New("failed"), Detail: "failed"}}, func(key string) path.Path {
+			return path.Root("values").AtMapKey(key)
+		})
+		require.Len(t, diags, 1)
+		withPath, ok := diags[0].(diag.DiagnosticWithPath)
+		require.True(t, ok)
+		assert.Equal(t, path.Root("values").AtMapKey("b"), withPath.Path())
+		assert.Equal(t, `"b": failed`, diags[0].Detail())
+	})
+}
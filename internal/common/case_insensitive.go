@@ -0,0 +1,107 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	_ basetypes.StringTypable                    = CaseInsensitiveStringType{}
+	_ basetypes.StringValuableWithSemanticEquals = CaseInsensitiveString{}
+)
+
+// CaseInsensitiveStringType は大文字小文字を区別せずに比較する文字列の型。列挙値の属性のCustomTypeに指定する。
+// Terraformはplanの値を設定値と異なる表記に正規化できないため、設定値の表記のままstateに保存し、
+// APIが返す正規の表記("generated"など)との大文字小文字の違いのみであれば差分としない
+type CaseInsensitiveStringType struct {
+	basetypes.StringType
+}
+
+func (t CaseInsensitiveStringType) Equal(o attr.Type) bool {
+	other, ok := o.(CaseInsensitiveStringType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t CaseInsensitiveStringType) String() string {
+	return "CaseInsensitiveStringType"
+}
+
+func (t CaseInsensitiveStringType) ValueFromString(_ context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return CaseInsensitiveString{StringValue: in}, nil
+}
+
+func (t CaseInsensitiveStringType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	v, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := v.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", v)
+	}
+	return CaseInsensitiveString{StringValue: s}, nil
+}
+
+func (t CaseInsensitiveStringType) ValueType(_ context.Context) attr.Value {
+	return CaseInsensitiveString{}
+}
+
+// CaseInsensitiveString はCaseInsensitiveStringTypeの値
+type CaseInsensitiveString struct {
+	basetypes.StringValue
+}
+
+func NewCaseInsensitiveStringValue(v string) CaseInsensitiveString {
+	return CaseInsensitiveString{StringValue: basetypes.NewStringValue(v)}
+}
+
+func NewCaseInsensitiveStringNull() CaseInsensitiveString {
+	return CaseInsensitiveString{StringValue: basetypes.NewStringNull()}
+}
+
+func (v CaseInsensitiveString) Equal(o attr.Value) bool {
+	other, ok := o.(CaseInsensitiveString)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v CaseInsensitiveString) Type(_ context.Context) attr.Type {
+	return CaseInsensitiveStringType{}
+}
+
+// StringSemanticEquals は大文字小文字の違いのみであれば等しいとみなす。
+// 等しい場合、プラグインフレームワークはAPIから読み込んだ値ではなく設定値の表記をstateに保存する
+func (v CaseInsensitiveString) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	newValue, ok := newValuable.(CaseInsensitiveString)
+	if !ok {
+		diags.AddError("Semantic Equality Check Error", fmt.Sprintf("expected value type %T, got %T", v, newValuable))
+		return false, diags
+	}
+	return strings.EqualFold(v.ValueString(), newValue.ValueString()), diags
+}
@@ -0,0 +1,78 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaseInsensitiveString_StringSemanticEquals(t *testing.T) {
+	ctx := context.Background()
+
+	expects := []struct {
+		prior string
+		new   string
+		want  bool
+	}{
+		{prior: "generated", new: "generated", want: true},
+		{prior: "Generated", new: "generated", want: true},
+		{prior: "GENERATED", new: "generated", want: true},
+		{prior: "imPorted", new: "IMPORTED", want: true},
+		{prior: "generated", new: "imported", want: false},
+		{prior: "generated", new: "generated ", want: false},
+	}
+	for _, tc := range expects {
+		t.Run(tc.prior+"/"+tc.new, func(t *testing.T) {
+			got, diags := NewCaseInsensitiveStringValue(tc.prior).StringSemanticEquals(ctx, NewCaseInsensitiveStringValue(tc.new))
+			require.False(t, diags.HasError(), diags)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	t.Run("other value type", func(t *testing.T) {
+		_, diags := NewCaseInsensitiveStringValue("generated").StringSemanticEquals(ctx, types.StringValue("generated"))
+		assert.True(t, diags.HasError())
+	})
+}
+
+func TestCaseInsensitiveString_Equal(t *testing.T) {
+	// Equalは表記の違いを区別し、planの差分の判定はStringSemanticEqualsで行う
+	assert.True(t, NewCaseInsensitiveStringValue("generated").Equal(NewCaseInsensitiveStringValue("generated")))
+	assert.False(t, NewCaseInsensitiveStringValue("Generated").Equal(NewCaseInsensitiveStringValue("generated")))
+	assert.False(t, NewCaseInsensitiveStringValue("generated").Equal(types.StringValue("generated")))
+	assert.True(t, NewCaseInsensitiveStringNull().IsNull())
+}
+
+func TestCaseInsensitiveStringType_ValueFromTerraform(t *testing.T) {
+	ctx := context.Background()
+	typ := CaseInsensitiveStringType{}
+
+	v, err := typ.ValueFromTerraform(ctx, tftypes.NewValue(tftypes.String, "Generated"))
+	require.NoError(t, err)
+	assert.Equal(t, NewCaseInsensitiveStringValue("Generated"), v)
+
+	v, err = typ.ValueFromTerraform(ctx, tftypes.NewValue(tftypes.String, nil))
+	require.NoError(t, err)
+	assert.Equal(t, NewCaseInsensitiveStringNull(), v)
+
+	assert.True(t, typ.Equal(NewCaseInsensitiveStringValue("x").Type(ctx)))
+	assert.False(t, typ.Equal(types.StringType))
+}
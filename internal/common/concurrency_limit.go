@@ -0,0 +1,66 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// concurrencyLimiter は同時に処理中のAPIリクエストをmax_concurrent_api_requests件までに制限する。
+// レート制限(1秒あたりのリクエスト数)とは別に、terraformの-parallelismやリソースごとのポーリングが重なった場合でも
+// APIへの同時リクエスト数を抑える。レスポンスのBodyが閉じられるまでをリクエストの処理中として扱う
+type concurrencyLimiter struct {
+	transport http.RoundTripper
+	slots     chan struct{}
+}
+
+func newConcurrencyLimiter(transport http.RoundTripper, limit int) *concurrencyLimiter {
+	return &concurrencyLimiter{transport: transport, slots: make(chan struct{}, limit)}
+}
+
+func (l *concurrencyLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := l.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	release := sync.OnceFunc(func() { <-l.slots })
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		release()
+		return resp, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releaseOnClose はBodyが閉じられた時点でconcurrencyLimiterの枠を解放する
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseOnClose) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
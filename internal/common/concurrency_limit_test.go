@@ -0,0 +1,148 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	sacloudhttp "github.com/sacloud/go-http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_NewClient_maxConcurrentAPIRequests(t *testing.T) {
+	var counter inFlightCounter
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer counter.enter()()
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"Count":0,"From":0,"Total":0,"Keys":[]}`)
+	}))
+	defer server.Close()
+
+	client, err := (&Config{
+		AccessToken:              "token",
+		AccessTokenSecret:        "secret",
+		APIRootURL:               server.URL,
+		APIRequestRateLimit:      100,
+		MaxConcurrentAPIRequests: 2,
+	}).NewClient()
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, err := client.KMSKeyList(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), counter.max.Load())
+}
+
+func TestConfig_newTransport_unlimitedByDefault(t *testing.T) {
+	transport := (&Config{}).newTransport()
+	for rt := transport; rt != nil; {
+		_, ok := rt.(*concurrencyLimiter)
+		require.False(t, ok, "concurrencyLimiter must not be installed without max_concurrent_api_requests")
+		rt = innerTransport(rt)
+	}
+}
+
+// innerTransport はnewTransportが組み立てたミドルウェアの1つ内側のhttp.RoundTripperを返す
+func innerTransport(rt http.RoundTripper) http.RoundTripper {
+	switch rt := rt.(type) {
+	case *retryWaiter:
+		return rt.transport
+	case *apiMetrics:
+		return rt.transport
+	case *conditionalReader:
+		return rt.transport
+	case *apiErrorRecorder:
+		return rt.transport
+	case *unknownEnumTolerator:
+		return rt.transport
+	case *retryAfterLimiter:
+		return rt.transport
+	case *maintenanceRetrier:
+		return rt.transport
+	case *concurrencyLimiter:
+		return rt.transport
+	case *sacloudhttp.RateLimitRoundTripper:
+		return rt.Transport
+	}
+	return nil
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestConcurrencyLimiter(t *testing.T) {
+	newResponse := func(req *http.Request) *http.Response {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}")), Request: req}
+	}
+
+	t.Run("released when the body is closed", func(t *testing.T) {
+		l := newConcurrencyLimiter(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return newResponse(req), nil
+		}), 1)
+
+		req := httptest.NewRequest(http.MethodGet, "http://sakura.example.com/", nil)
+		resp, err := l.RoundTrip(req)
+		require.NoError(t, err)
+		assert.Len(t, l.slots, 1)
+
+		require.NoError(t, resp.Body.Close())
+		require.NoError(t, resp.Body.Close())
+		assert.Empty(t, l.slots)
+	})
+
+	t.Run("released on error", func(t *testing.T) {
+		l := newConcurrencyLimiter(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, io.ErrUnexpectedEOF
+		}), 1)
+
+		_, err := l.RoundTrip(httptest.NewRequest(http.MethodGet, "http://sakura.example.com/", nil))
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Empty(t, l.slots)
+	})
+
+	t.Run("waiting request is canceled by context", func(t *testing.T) {
+		l := newConcurrencyLimiter(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return newResponse(req), nil
+		}), 1)
+		resp, err := l.RoundTrip(httptest.NewRequest(http.MethodGet, "http://sakura.example.com/", nil))
+		require.NoError(t, err)
+		defer resp.Body.Close() //nolint:errcheck
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		req := httptest.NewRequest(http.MethodGet, "http://sakura.example.com/", nil).WithContext(ctx)
+		_, err = l.RoundTrip(req) //nolint:bodyclose
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
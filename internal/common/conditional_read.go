@@ -0,0 +1,214 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// readValidatorsKey はReadValidatorsを保存するリソースのprivate stateのキー
+const readValidatorsKey = "read_validators"
+
+// ReadValidators は前回のReadでAPIが返したETagとLast-Modified、レスポンスボディ。次回のReadで条件付きリクエストに利用する
+type ReadValidators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	// Body はAPIが304を返した場合に、レスポンスとして再利用するボディ
+	Body string `json:"body,omitempty"`
+	// ContentType はBodyのContent-Type。304のレスポンスには含まれないことがあるため、ボディと合わせて保存する
+	ContentType string `json:"content_type,omitempty"`
+}
+
+func (v ReadValidators) empty() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// PrivateStateGetter はリソースのprivate stateの読み込みに利用するインターフェース。resource.ReadRequest.Privateが実装する
+type PrivateStateGetter interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+// PrivateStateSetter はリソースのprivate stateの書き込みに利用するインターフェース。resource.ReadResponse.Privateが実装する
+type PrivateStateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+// ConditionalRead はReadのGETリクエストにIf-None-Match/If-Modified-Sinceを付与し、レスポンスのETag/Last-Modifiedとボディを記録する。
+// APIが304を返した場合は前回のレスポンスボディを200のレスポンスとして返すため、呼び出し元は通常のReadと同様にstateを更新できる。
+// ignore_system_tagsやdefault_tagsなど、APIのレスポンス以外から計算する値もリソースが変更されていなくても反映される。
+// APIがこれらのヘッダーに対応していない場合は通常のReadとなる
+type ConditionalRead struct {
+	sent ReadValidators
+
+	mu          sync.Mutex
+	received    ReadValidators
+	notModified bool
+}
+
+type conditionalReadKey struct{}
+
+// WithConditionalRead はprivate stateに保存されたReadValidatorsを条件とするConditionalReadをctxに設定する
+func WithConditionalRead(ctx context.Context, private PrivateStateGetter) (context.Context, *ConditionalRead) {
+	c := &ConditionalRead{}
+	if private != nil {
+		raw, diags := private.GetKey(ctx, readValidatorsKey)
+		if len(raw) > 0 && !diags.HasError() {
+			if err := json.Unmarshal(raw, &c.sent); err != nil {
+				// 保存した値が壊れている場合は条件を付けずにリクエストする
+				tflog.Debug(ctx, "ignoring invalid read validators in private state", map[string]any{"error": err.Error()})
+				c.sent = ReadValidators{}
+			}
+		}
+	}
+	return context.WithValue(ctx, conditionalReadKey{}, c), c
+}
+
+// ConditionalReadFrom はctxに設定されたConditionalReadを返す。設定されていない場合はnilを返す
+func ConditionalReadFrom(ctx context.Context) *ConditionalRead {
+	c, _ := ctx.Value(conditionalReadKey{}).(*ConditionalRead)
+	return c
+}
+
+// IsNotModified はctxのConditionalReadのリクエストに対してAPIが304を返したかを返す
+func IsNotModified(ctx context.Context) bool {
+	return ConditionalReadFrom(ctx).NotModified()
+}
+
+// NotModified はAPIが304を返し、前回のレスポンスボディを再利用したかを返す
+func (c *ConditionalRead) NotModified() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.notModified
+}
+
+// Record はレスポンスのステータスとETag/Last-Modified、ボディを記録する。
+// 304の場合は前回のレスポンスボディを200のレスポンスとして返し、それ以外の場合はrespをそのまま返す
+func (c *ConditionalRead) Record(resp *http.Response) (*http.Response, error) {
+	if c == nil || resp == nil {
+		return resp, nil
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		if c.sent.Body == "" {
+			return resp, nil
+		}
+		c.mu.Lock()
+		c.notModified = true
+		c.received = c.sent
+		c.mu.Unlock()
+		return cachedResponse(resp, c.sent), nil
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		received := ReadValidators{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}
+		// ETag/Last-Modifiedがない場合は条件付きリクエストを行わないため、ボディも保存しない
+		if !received.empty() && resp.Body != nil {
+			body, err := io.ReadAll(resp.Body)
+			resp.Body.Close() //nolint:errcheck
+			if err != nil {
+				return nil, err
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			received.Body = string(body)
+			received.ContentType = resp.Header.Get("Content-Type")
+		}
+		c.mu.Lock()
+		c.received = received
+		c.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// cachedResponse は304のレスポンスを、前回のレスポンスボディを持つ200のレスポンスに置き換える
+func cachedResponse(resp *http.Response, cache ReadValidators) *http.Response {
+	body := cache.Body
+	if resp.Body != nil {
+		resp.Body.Close() //nolint:errcheck
+	}
+	cached := *resp
+	cached.StatusCode = http.StatusOK
+	cached.Status = "200 OK"
+	cached.Header = resp.Header.Clone()
+	cached.Header.Set("Content-Length", strconv.Itoa(len(body)))
+	if cache.ContentType != "" {
+		cached.Header.Set("Content-Type", cache.ContentType)
+	}
+	cached.ContentLength = int64(len(body))
+	cached.Body = io.NopCloser(strings.NewReader(body))
+	return &cached
+}
+
+// Save はレスポンスのETag/Last-Modifiedをprivate stateに保存する。APIが返さなかった場合は以前の値を削除する
+func (c *ConditionalRead) Save(ctx context.Context, private PrivateStateSetter) diag.Diagnostics {
+	c.mu.Lock()
+	received, sent := c.received, c.sent
+	c.mu.Unlock()
+	if received == sent {
+		return nil
+	}
+	if received.empty() {
+		return private.SetKey(ctx, readValidatorsKey, nil)
+	}
+	raw, err := json.Marshal(received)
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError("Private State Error", err.Error())
+		return diags
+	}
+	return private.SetKey(ctx, readValidatorsKey, raw)
+}
+
+// conditionalReader はctxにConditionalReadが設定されたGETリクエストに条件を付与し、レスポンスを記録するhttp.RoundTripper
+type conditionalReader struct {
+	transport http.RoundTripper
+}
+
+func (r *conditionalReader) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := r.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	c := ConditionalReadFrom(req.Context())
+	if c == nil || req.Method != http.MethodGet {
+		return transport.RoundTrip(req)
+	}
+
+	// 304の場合に返すボディがない場合は条件を付けずにリクエストする
+	if !c.sent.empty() && c.sent.Body != "" {
+		req = req.Clone(req.Context())
+		if c.sent.ETag != "" {
+			req.Header.Set("If-None-Match", c.sent.ETag)
+		}
+		if c.sent.LastModified != "" {
+			req.Header.Set("If-Modified-Since", c.sent.LastModified)
+		}
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	return c.Record(resp)
+}
@@ -0,0 +1,176 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePrivateState はリソースのprivate stateのテストダブル
+type fakePrivateState map[string][]byte
+
+func (p fakePrivateState) GetKey(_ context.Context, key string) ([]byte, diag.Diagnostics) {
+	return p[key], nil
+}
+
+func (p fakePrivateState) SetKey(_ context.Context, key string, value []byte) diag.Diagnostics {
+	if len(value) == 0 {
+		delete(p, key)
+		return nil
+	}
+	p[key] = value
+	return nil
+}
+
+// etagTransport はIf-None-Matchがetagと一致する場合に304を返すKMSキーの参照APIのテストダブル
+type etagTransport struct {
+	mu          sync.Mutex
+	etag        string
+	keyOrigin   string
+	ifNoneMatch []string
+}
+
+func (t *etagTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ifNoneMatch = append(t.ifNoneMatch, req.Header.Get("If-None-Match"))
+
+	header := http.Header{}
+	if t.etag != "" {
+		header.Set("ETag", t.etag)
+		if req.Header.Get("If-None-Match") == t.etag {
+			// 304のレスポンスにはContent-Typeを含めない
+			return &http.Response{StatusCode: http.StatusNotModified, Header: header, Body: http.NoBody, Request: req}, nil
+		}
+	}
+	keyOrigin := t.keyOrigin
+	if keyOrigin == "" {
+		keyOrigin = "generated"
+	}
+	header.Set("Content-Type", "application/json")
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(`{"Key":{"ID":"110000000001","Name":"foo","KeyOrigin":"` + keyOrigin + `","Status":"active","Tags":[],"CreatedAt":"2025-01-01T00:00:00Z","ModifiedAt":"2025-01-01T00:00:00Z","LatestVersion":1}}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestConditionalRead(t *testing.T) {
+	transport := &etagTransport{etag: `"v1"`}
+	client := newServiceClientTestClient(t, transport)
+	keyOp, err := client.KMSKeyOp()
+	require.NoError(t, err)
+
+	private := fakePrivateState{}
+	read := func(t *testing.T) *ConditionalRead {
+		t.Helper()
+		ctx, cond := WithConditionalRead(context.Background(), private)
+		key, err := keyOp.Read(ctx, "110000000001")
+		require.NoError(t, err)
+		// 304の場合も前回のレスポンスボディからキーを取得できる
+		assert.Equal(t, "foo", key.Name)
+		require.False(t, cond.Save(ctx, private).HasError())
+		return cond
+	}
+	saved := func(t *testing.T) ReadValidators {
+		t.Helper()
+		var v ReadValidators
+		require.NoError(t, json.Unmarshal(private[readValidatorsKey], &v))
+		return v
+	}
+
+	t.Run("first read stores the etag and the body", func(t *testing.T) {
+		cond := read(t)
+		assert.False(t, cond.NotModified())
+		assert.Equal(t, `"v1"`, saved(t).ETag)
+		assert.Contains(t, saved(t).Body, `"Name":"foo"`)
+	})
+
+	t.Run("unchanged resource returns the cached body", func(t *testing.T) {
+		cond := read(t)
+		assert.True(t, cond.NotModified())
+		assert.Equal(t, `"v1"`, transport.ifNoneMatch[len(transport.ifNoneMatch)-1])
+		assert.Equal(t, `"v1"`, saved(t).ETag)
+	})
+
+	t.Run("changed resource updates the etag", func(t *testing.T) {
+		transport.etag = `"v2"`
+		cond := read(t)
+		assert.False(t, cond.NotModified())
+		assert.Equal(t, `"v2"`, saved(t).ETag)
+	})
+
+	t.Run("private state without the body is not used as a condition", func(t *testing.T) {
+		private[readValidatorsKey] = []byte(`{"etag":"\"v2\""}`)
+		cond := read(t)
+		assert.False(t, cond.NotModified())
+		assert.Empty(t, transport.ifNoneMatch[len(transport.ifNoneMatch)-1])
+		assert.NotEmpty(t, saved(t).Body)
+	})
+
+	t.Run("API without etag falls back to a plain read", func(t *testing.T) {
+		transport.etag = ""
+		cond := read(t)
+		assert.False(t, cond.NotModified())
+		assert.NotContains(t, private, readValidatorsKey)
+
+		cond = read(t)
+		assert.False(t, cond.NotModified())
+		assert.Empty(t, transport.ifNoneMatch[len(transport.ifNoneMatch)-1])
+	})
+
+	t.Run("requests without ConditionalRead are not changed", func(t *testing.T) {
+		transport.etag = `"v3"`
+		_, err := keyOp.Read(context.Background(), "110000000001")
+		require.NoError(t, err)
+		assert.Empty(t, transport.ifNoneMatch[len(transport.ifNoneMatch)-1])
+		assert.False(t, IsNotModified(context.Background()))
+	})
+}
+
+func TestConditionalRead_unknownEnum(t *testing.T) {
+	client := newServiceClientTestClient(t, &etagTransport{etag: `"v1"`, keyOrigin: "hsm"})
+	keyOp, err := client.KMSKeyOp()
+	require.NoError(t, err)
+
+	private := fakePrivateState{}
+	for _, notModified := range []bool{false, true} {
+		ctx, cond := WithConditionalRead(WithAPIErrorCapture(context.Background()), private)
+		key, err := keyOp.Read(ctx, "110000000001")
+		require.NoError(t, err)
+		require.False(t, cond.Save(ctx, private).HasError())
+
+		// 304の場合も置き換える前の値がstateに保存される
+		assert.Equal(t, notModified, cond.NotModified())
+		assert.Equal(t, types.StringValue("hsm"), FlattenEnum(ctx, "KeyOrigin", key.ID, key.KeyOrigin))
+	}
+}
+
+func TestWithConditionalRead_invalidPrivateState(t *testing.T) {
+	_, cond := WithConditionalRead(context.Background(), fakePrivateState{readValidatorsKey: []byte("{")})
+	assert.Equal(t, ReadValidators{}, cond.sent)
+}
@@ -15,25 +15,24 @@
 package common
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
 	"time"
 
-	"github.com/hashicorp/go-multierror"
-
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	client "github.com/sacloud/api-client-go"
 	"github.com/sacloud/api-client-go/profile"
+	sacloudhttp "github.com/sacloud/go-http"
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/helper/api"
 	"github.com/sacloud/iaas-api-go/helper/query"
-	"github.com/sacloud/simplemq-api-go"
-	"github.com/sacloud/simplemq-api-go/apis/v1/queue"
 
 	kms "github.com/sacloud/kms-api-go"
 	kmsapi "github.com/sacloud/kms-api-go/apis/v1"
@@ -43,10 +42,14 @@ import (
 )
 
 const (
+	traceAll  = "all"
 	traceHTTP = "http"
 	traceAPI  = "api"
 )
 
+// TraceModes はtraceに指定できる値の一覧。allはAPIとHTTPの両方のトレースを出力する
+var TraceModes = []string{traceAll, traceAPI, traceHTTP}
+
 const uaEnvVar = "SAKURACLOUD_APPEND_USER_AGENT"
 
 const (
@@ -54,6 +57,8 @@ const (
 	RetryMax            = 10
 	APIRequestTimeout   = 300
 	APIRequestRateLimit = 10
+	// MaxParallelZoneRequests は複数ゾーンを読み込む際に並行して処理するゾーン数のデフォルト値
+	MaxParallelZoneRequests = 4
 )
 
 var (
@@ -80,6 +85,31 @@ type Config struct {
 	APIRequestTimeout   int
 	APIRequestRateLimit int
 	TerraformVersion    string
+	HTTPTransport       http.RoundTripper // nilの場合はHTTPClientの設定でホストごとにhttp.Transportを生成する
+
+	// MaxParallelZoneRequests はForEachZoneで並行して処理するゾーン数。0以下の場合はデフォルト値を利用する
+	MaxParallelZoneRequests int
+	// MaxConcurrentAPIRequests は同時に処理するAPIリクエスト数の上限。0以下の場合は制限しない
+	MaxConcurrentAPIRequests int
+	// DisableReadCache はデータソースの一覧取得の結果をキャッシュしない場合にtrueとする
+	DisableReadCache bool
+	// IgnoreSystemTags は@で始まるシステムタグを、設定に記載されていない限りTerraformの管理外として扱う場合にtrueとする
+	IgnoreSystemTags bool
+	// ValidateReferences はplan時にkms_key_idなどの参照先が存在するかを確認する場合にtrueとする
+	ValidateReferences bool
+	// HTTPClient はAPIのホストごとのhttp.Transportの設定。HTTPTransportを指定した場合は利用しない
+	HTTPClient HTTPClientConfig
+	// ResourceDefaults はリソースで省略されたdescription/icon_idのデフォルト値と、tagsに追加するdefault_tags
+	ResourceDefaults ResourceDefaults
+
+	profileFile string // 読み込んだプロファイルのファイルパス。ファイルが存在しなかった場合は空
+}
+
+// APIクライアントがリトライするHTTPステータスコード
+var retryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusServiceUnavailable,
+	http.StatusLocked,
 }
 
 // APIClient for SakuraCloud API
@@ -92,9 +122,15 @@ type APIClient struct {
 	databaseWaitAfterCreateDuration  time.Duration
 	vpcRouterWaitAfterCreateDuration time.Duration
 	CallerOptions                    *client.Options
-	KmsClient                        *kmsapi.Client
-	SecretManagerClient              *smapi.Client
-	SimpleMqClient                   *queue.Client
+	apiRootURL                       string
+	transport                        http.RoundTripper // iaasと各サービスのクライアントで共有する
+	services                         serviceClients
+	listCache                        listCache
+	maxParallelZoneRequests          int
+	maxConcurrentAPIRequests         int
+	ignoreSystemTags                 bool
+	validateReferences               bool
+	resourceDefaults                 ResourceDefaults
 }
 
 func (c *APIClient) CheckReferencedOption() query.CheckReferencedOption {
@@ -108,6 +144,105 @@ func (c *APIClient) GetZones() []string {
 	return c.zones
 }
 
+// APIMetrics はtraceやDEBUGログの有効時に集計した、エンドポイントごとのAPIリクエストの所要時間を返す。無効な場合はnilを返す
+func (c *APIClient) APIMetrics() []APIMetric {
+	transport := c.transport
+	if w, ok := transport.(*retryWaiter); ok {
+		transport = w.transport
+	}
+	if m, ok := transport.(*apiMetrics); ok {
+		return m.Summary()
+	}
+	return nil
+}
+
+// MaxParallelZoneRequests はForEachZoneに渡す、並行して処理するゾーン数を返す
+func (c *APIClient) MaxParallelZoneRequests() int {
+	return c.maxParallelZoneRequests
+}
+
+// BulkWriteParallelism はForEachKeyに渡す、並行して書き込む数を返す。
+// max_concurrent_api_requestsが指定されている場合はその値を、未指定の場合はMaxParallelBulkWritesを返す
+func (c *APIClient) BulkWriteParallelism() int {
+	if c.maxConcurrentAPIRequests > 0 {
+		return c.maxConcurrentAPIRequests
+	}
+	return MaxParallelBulkWrites
+}
+
+// IgnoreSystemTags は@で始まるシステムタグをTerraformの管理外として扱う場合にtrueを返す
+func (c *APIClient) IgnoreSystemTags() bool {
+	return c.ignoreSystemTags
+}
+
+// ValidateReferences はplan時に参照先のリソースが存在するかを確認する場合にtrueを返す
+func (c *APIClient) ValidateReferences() bool {
+	return c.validateReferences
+}
+
+// KMSKeyList はKMSのキーの一覧を取得する。
+// APIが返したTotalより取得できた件数が少ない場合はtruncated=trueを返す
+func (c *APIClient) KMSKeyList(ctx context.Context) (keys []kmsapi.Key, truncated bool, err error) {
+	key := listCacheKey{service: serviceKMS, zone: serviceAPIZone}
+	return cachedList(ctx, &c.listCache, key, func(ctx context.Context) ([]kmsapi.Key, bool, error) {
+		kmsClient, err := c.KMSClient()
+		if err != nil {
+			return nil, false, err
+		}
+		res, err := kmsClient.KmsKeysList(ctx)
+		if err != nil {
+			return nil, false, kms.NewAPIError("List", APIStatusCode(err), err)
+		}
+		return res.Keys, listTruncated(res.Total.Value, len(res.Keys)), nil
+	})
+}
+
+// SecretManagerVaultList はシークレットマネージャのボールトの一覧を取得する。
+// APIが返したTotalより取得できた件数が少ない場合はtruncated=trueを返す
+func (c *APIClient) SecretManagerVaultList(ctx context.Context) (vaults []smapi.Vault, truncated bool, err error) {
+	key := listCacheKey{service: serviceSecretManager, zone: serviceAPIZone}
+	return cachedList(ctx, &c.listCache, key, func(ctx context.Context) ([]smapi.Vault, bool, error) {
+		smClient, err := c.SecretManagerClient()
+		if err != nil {
+			return nil, false, err
+		}
+		res, err := smClient.SecretmanagerVaultsList(ctx)
+		if err != nil {
+			return nil, false, sm.NewAPIError("List", APIStatusCode(err), err)
+		}
+		return res.Vaults, listTruncated(res.Total.Value, len(res.Vaults)), nil
+	})
+}
+
+// SecretManagerSecretList は指定したボールト内のシークレットの一覧を取得する。
+// APIが返したTotalより取得できた件数が少ない場合はtruncated=trueを返す
+func (c *APIClient) SecretManagerSecretList(ctx context.Context, vaultID string) (secrets []smapi.Secret, truncated bool, err error) {
+	key := listCacheKey{service: serviceSecretManager, zone: serviceAPIZone, scope: vaultID}
+	return cachedList(ctx, &c.listCache, key, func(ctx context.Context) ([]smapi.Secret, bool, error) {
+		smClient, err := c.SecretManagerClient()
+		if err != nil {
+			return nil, false, err
+		}
+		res, err := smClient.SecretmanagerVaultsSecretsList(ctx, smapi.SecretmanagerVaultsSecretsListParams{VaultResourceID: vaultID})
+		if err != nil {
+			return nil, false, sm.NewAPIError("List", APIStatusCode(err), err)
+		}
+		return res.Secrets, listTruncated(res.Total.Value, len(res.Secrets)), nil
+	})
+}
+
+// listTruncated は一覧取得APIが返したTotalより、レスポンスに含まれる件数が少ないかを返す。
+// KMS/シークレットマネージャの一覧取得APIはページングのパラメータを受け取らないため、残りを取得する方法はない
+func listTruncated(total, count int) bool {
+	return total > count
+}
+
+// AddListTruncatedWarning は一覧取得APIが全件を返さず、searched件のみを検索したことを表す警告をdiagsに追加する
+func AddListTruncatedWarning(diags *diag.Diagnostics, kind string, searched int) {
+	diags.AddWarning("Incomplete List Result",
+		fmt.Sprintf("The API returned only the first %d %s, fewer than the total it reported. The remaining %s could not be checked.", searched, kind, kind))
+}
+
 func (c *Config) loadFromProfile() error {
 	if c.Profile == "" {
 		c.Profile = profile.DefaultProfileName
@@ -116,10 +251,25 @@ func (c *Config) loadFromProfile() error {
 		log.Printf("[DEBUG] using profile %q", c.Profile)
 	}
 
-	pcv := &profile.ConfigValue{}
-	if err := profile.Load(c.Profile, pcv); err != nil {
+	path, err := profile.ConfigFilePath(c.Profile)
+	if err != nil {
 		return fmt.Errorf("loading profile %q is failed: %s", c.Profile, err)
 	}
+	info, err := os.Stat(path)
+	if err == nil {
+		c.profileFile = path
+	} else if c.Profile != profile.DefaultProfileName {
+		// ファイルのパスと探したディレクトリ、対処方法を含めたエラーを返す
+		return fmt.Errorf("loading profile %q is failed: %s does not exist (searched in %s). "+
+			"Create the profile with `usacloud config --profile %s`, or set token/secret in the provider block or SAKURACLOUD_ACCESS_TOKEN[_SECRET] environment variables instead",
+			c.Profile, path, filepath.Dir(filepath.Dir(path)), c.Profile)
+	}
+
+	// Statに失敗した場合のinfoはnilのため、ファイルが存在しないデフォルトのプロファイルは空の設定値となる
+	pcv, err := profiles.load(c.Profile, path, info)
+	if err != nil {
+		return fmt.Errorf("loading profile %q from %s is failed: %s", c.Profile, path, err)
+	}
 
 	if c.AccessToken == "" {
 		c.AccessToken = pcv.AccessToken
@@ -164,15 +314,38 @@ func (c *Config) loadFromProfile() error {
 	return nil
 }
 
+// validate は認証情報が設定されているかを検証する。
+// 未設定の場合は、参照した設定元(プロバイダーの設定・環境変数・プロファイル)を含めたエラーを返す
 func (c *Config) validate() error {
-	var err error
+	var missing []string
 	if c.AccessToken == "" {
-		err = multierror.Append(err, errors.New("AccessToken is required"))
+		missing = append(missing, "token")
 	}
 	if c.AccessTokenSecret == "" {
-		err = multierror.Append(err, errors.New("AccessTokenSecret is required"))
+		missing = append(missing, "secret")
 	}
-	return err
+
+	var summary string
+	switch len(missing) {
+	case 0:
+		return nil
+	case 1:
+		summary = fmt.Sprintf("SakuraCloud API %s is not set.", missing[0])
+	default:
+		summary = "No SakuraCloud API credentials found."
+	}
+
+	profileName := c.Profile
+	if profileName == "" {
+		profileName = profile.DefaultProfileName
+	}
+	// プロファイルは存在するが認証情報の一部が含まれていない場合
+	if c.profileFile != "" {
+		return fmt.Errorf("%s Profile %q loaded from %s does not contain %s. Add it with `usacloud config --profile %s`, or set token/secret in the provider block or SAKURACLOUD_ACCESS_TOKEN[_SECRET] environment variables.", //nolint:staticcheck
+			summary, profileName, c.profileFile, strings.Join(missing, " and "), profileName)
+	}
+
+	return fmt.Errorf("%s Set token/secret in the provider block, SAKURACLOUD_ACCESS_TOKEN[_SECRET] environment variables, or a usacloud profile created with `usacloud config` (current profile: %s, no profile file found).", summary, profileName) //nolint:staticcheck
 }
 
 // NewClient returns new API Client for SakuraCloud
@@ -205,44 +378,38 @@ func (c *Config) NewClient() (*APIClient, error) {
 			enableAPITrace = false
 		}
 	}
+	budgeter := c.newRetryBudgeter()
+	transport := c.newTransport()
 	callerOptions := &client.Options{
 		AccessToken:          c.AccessToken,
 		AccessTokenSecret:    c.AccessTokenSecret,
 		AcceptLanguage:       c.AcceptLanguage,
-		HttpClient:           http.DefaultClient,
+		HttpClient:           newHTTPClient(transport),
 		HttpRequestTimeout:   c.APIRequestTimeout,
 		HttpRequestRateLimit: c.APIRequestRateLimit,
 		RetryMax:             c.RetryMax,
-		RetryWaitMax:         c.RetryWaitMax,
-		RetryWaitMin:         c.RetryWaitMin,
 		UserAgent:            ua,
 		Trace:                enableHTTPTrace,
+		// iaas-api-goのデフォルト(503/423)に加えて、レート制限の429もRetry-Afterに従ってリトライする。
+		// リトライの合計時間はapi_request_timeoutとリクエストのcontextの期限で制限する
+		CheckRetryFunc:     budgeter.checkRetry,
+		RequestCustomizers: []sacloudhttp.RequestCustomizer{budgeter.customize},
 	}
-	caller := api.NewCallerWithOptions(&api.CallerOptions{
+	// NewCallerWithOptionsはAPIのルートURLやトレースなどのグローバルな設定のために呼び出す。
+	// 返されるiaas.Clientはgo-httpのバックオフを無効にできないため、APIの呼び出しにはiaasCallerを利用する(iaasCallerを参照)
+	api.NewCallerWithOptions(&api.CallerOptions{
 		Options:     callerOptions,
 		APIRootURL:  c.APIRootURL,
 		DefaultZone: c.DefaultZone,
 		TraceAPI:    enableAPITrace,
 	})
+	caller := newIaaSCaller(callerOptions)
 
 	zones := c.Zones
 	if len(zones) == 0 {
 		zones = iaas.SakuraCloudZones
 	}
 
-	kmsClient, err := kms.NewClient(client.WithOptions(callerOptions))
-	if err != nil {
-		return nil, err
-	}
-	smClient, err := sm.NewClient(client.WithOptions(callerOptions))
-	if err != nil {
-		return nil, err
-	}
-	simplemqClient, err := simplemq.NewQueueClient(client.WithOptions(callerOptions))
-	if err != nil {
-		return nil, err
-	}
-
 	return &APIClient{
 		APICaller:                        caller,
 		defaultZone:                      c.Zone,
@@ -252,12 +419,99 @@ func (c *Config) NewClient() (*APIClient, error) {
 		databaseWaitAfterCreateDuration:  databaseWaitAfterCreateDuration,
 		vpcRouterWaitAfterCreateDuration: vpcRouterWaitAfterCreateDuration,
 		CallerOptions:                    callerOptions,
-		KmsClient:                        kmsClient,
-		SecretManagerClient:              smClient,
-		SimpleMqClient:                   simplemqClient,
+		apiRootURL:                       c.APIRootURL,
+		transport:                        transport,
+		listCache:                        listCache{disabled: c.DisableReadCache},
+		maxParallelZoneRequests:          c.MaxParallelZoneRequests,
+		maxConcurrentAPIRequests:         c.MaxConcurrentAPIRequests,
+		ignoreSystemTags:                 c.IgnoreSystemTags,
+		validateReferences:               c.ValidateReferences,
+		resourceDefaults:                 c.ResourceDefaults,
 	}, nil
 }
 
+// newRetryBudgeter はapi_request_timeoutとretry_wait_min/maxから、リトライの合計時間を制限するretryBudgeterを返す。
+// 未指定の値はapi-client-go/go-httpのデフォルト値を利用する
+func (c *Config) newRetryBudgeter() *retryBudgeter {
+	waitMin, waitMax := c.RetryWaits()
+	return &retryBudgeter{timeout: c.RequestTimeout(), waitMin: waitMin, waitMax: waitMax, statusCodes: retryStatusCodes}
+}
+
+// RequestTimeout はapi_request_timeoutを返す。未指定の場合はデフォルト値を返す
+func (c *Config) RequestTimeout() time.Duration {
+	if c.APIRequestTimeout <= 0 {
+		return APIRequestTimeout * time.Second
+	}
+	return time.Duration(c.APIRequestTimeout) * time.Second
+}
+
+// RetryWaits はretry_wait_min/retry_wait_maxを返す。未指定の場合はgo-httpのデフォルト値を返す
+func (c *Config) RetryWaits() (waitMin, waitMax time.Duration) {
+	waitMin = time.Duration(c.RetryWaitMin) * time.Second
+	if waitMin <= 0 {
+		waitMin = sacloudhttp.DefaultRetryWaitMin
+	}
+	waitMax = time.Duration(c.RetryWaitMax) * time.Second
+	if waitMax <= 0 {
+		waitMax = sacloudhttp.DefaultRetryWaitMax
+	}
+	return waitMin, waitMax
+}
+
+// newTransport はiaasと各サービスのAPIクライアントで共有するhttp.RoundTripperを返す。
+// レート制限(api_request_rate_limit)もここで行い、全てのサービスへのリクエストの合計に適用する
+func (c *Config) newTransport() http.RoundTripper {
+	base := c.HTTPTransport
+	if base == nil {
+		base = &hostTransports{config: c.HTTPClient}
+	}
+	rateLimit := c.APIRequestRateLimit
+	if rateLimit <= 0 {
+		rateLimit = APIRequestRateLimit
+	}
+	var limited http.RoundTripper = &sacloudhttp.RateLimitRoundTripper{Transport: base, RateLimitPerSec: rateLimit}
+	// リトライの待機中は枠を占有しないよう、リトライを行うmaintenanceRetrierより内側で同時リクエスト数を制限する
+	if c.MaxConcurrentAPIRequests > 0 {
+		limited = newConcurrencyLimiter(limited, c.MaxConcurrentAPIRequests)
+	}
+	// 304の場合に返すボディも未知の列挙値を置き換える前の値となるよう、conditionalReaderはunknownEnumToleratorより内側に置く
+	var transport http.RoundTripper = &apiErrorRecorder{
+		transport: &unknownEnumTolerator{
+			transport: &conditionalReader{
+				transport: &retryAfterLimiter{
+					transport: &maintenanceRetrier{
+						transport: limited,
+					},
+					maxWait: time.Duration(c.RetryWaitMax) * time.Second,
+				},
+			},
+		},
+	}
+	// 所要時間はリトライを含むリクエスト全体で計測するため、最も外側に置く
+	if apiMetricsEnabled(c.TraceMode, os.Getenv) {
+		transport = newAPIMetrics(transport)
+	}
+	// リトライの待機時間は所要時間に含めない
+	return &retryWaiter{transport: transport}
+}
+
+// newHTTPClient はAPIクライアントごとのhttp.Clientを返す。
+// api-client-goはhttp.ClientのTransportやTimeoutを書き換えるため、http.Client自体は共有せず、transportのみを共有する
+func newHTTPClient(transport http.RoundTripper) *http.Client {
+	return &http.Client{Transport: transport}
+}
+
+// KMSなどのゾーンに依存しないサービスのエンドポイントはtk1aゾーン配下で提供されている
+const serviceAPIZone = "tk1a"
+
+// serviceAPIURL はapiRootURLが指定されていればそれを元にしたzoneのエンドポイントを、そうでなければdefaultURLを返す
+func serviceAPIURL(apiRootURL, zone, defaultURL string) string {
+	if apiRootURL == "" {
+		return defaultURL
+	}
+	return fmt.Sprintf("%s/%s/api/cloud/1.1", strings.TrimRight(apiRootURL, "/"), zone)
+}
+
 const tfUAEnvVar = "TF_APPEND_USER_AGENT"
 
 func terraformUserAgent(version string) string {
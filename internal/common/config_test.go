@@ -0,0 +1,140 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sacloud/api-client-go/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_validate(t *testing.T) {
+	cases := []struct {
+		name     string
+		config   Config
+		wantErrs []string
+	}{
+		{
+			name:   "both are set",
+			config: Config{AccessToken: "token", AccessTokenSecret: "secret"},
+		},
+		{
+			name:   "both are missing",
+			config: Config{},
+			wantErrs: []string{
+				"No SakuraCloud API credentials found.",
+				"Set token/secret in the provider block, SAKURACLOUD_ACCESS_TOKEN[_SECRET] environment variables, or a usacloud profile created with `usacloud config`",
+				"(current profile: default, no profile file found)",
+			},
+		},
+		{
+			name:   "token is missing in profile",
+			config: Config{AccessTokenSecret: "secret", Profile: "foo", profileFile: "/home/foo/.usacloud/foo/config.json"},
+			wantErrs: []string{
+				"SakuraCloud API token is not set.",
+				`Profile "foo" loaded from /home/foo/.usacloud/foo/config.json does not contain token.`,
+				"Add it with `usacloud config --profile foo`",
+			},
+		},
+		{
+			name:   "secret is missing",
+			config: Config{AccessToken: "token", Profile: "default"},
+			wantErrs: []string{
+				"SakuraCloud API secret is not set.",
+				"(current profile: default, no profile file found)",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.config.validate()
+			if len(tc.wantErrs) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tc.wantErrs {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestConfig_NewClient_missingCredentials(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(profile.DirectoryNameEnv, dir)
+
+	writeProfile := func(t *testing.T, name, body string) string {
+		t.Helper()
+		path := filepath.Join(dir, ".usacloud", name, "config.json")
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+		return path
+	}
+
+	t.Run("no profile file", func(t *testing.T) {
+		_, err := (&Config{}).NewClient()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "No SakuraCloud API credentials found.")
+		assert.Contains(t, err.Error(), "(current profile: default, no profile file found)")
+	})
+
+	t.Run("profile without secret", func(t *testing.T) {
+		path := writeProfile(t, "foo", `{"AccessToken": "token"}`)
+
+		_, err := (&Config{Profile: "foo"}).NewClient()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "SakuraCloud API secret is not set.")
+		assert.Contains(t, err.Error(), `Profile "foo" loaded from `+path+" does not contain secret.")
+	})
+
+	t.Run("secret from provider block completes profile", func(t *testing.T) {
+		writeProfile(t, "bar", `{"AccessToken": "token"}`)
+
+		_, err := (&Config{Profile: "bar", AccessTokenSecret: "secret"}).NewClient()
+		require.NoError(t, err)
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := (&Config{Profile: "unknown"}).NewClient()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `loading profile "unknown" is failed`)
+		assert.Contains(t, err.Error(), filepath.Join(dir, ".usacloud", "unknown", "config.json")+" does not exist")
+		assert.Contains(t, err.Error(), "searched in "+filepath.Join(dir, ".usacloud"))
+		assert.Contains(t, err.Error(), "usacloud config --profile unknown")
+	})
+
+	t.Run("profile without credentials", func(t *testing.T) {
+		path := writeProfile(t, "empty", `{"Zone": "tk1b"}`)
+
+		_, err := (&Config{Profile: "empty"}).NewClient()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "No SakuraCloud API credentials found.")
+		assert.Contains(t, err.Error(), `Profile "empty" loaded from `+path+" does not contain token and secret.")
+	})
+
+	t.Run("broken profile", func(t *testing.T) {
+		path := writeProfile(t, "broken", `{`)
+
+		_, err := (&Config{Profile: "broken"}).NewClient()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `loading profile "broken" from `+path+" is failed")
+	})
+}
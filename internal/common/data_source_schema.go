@@ -15,19 +15,47 @@
 package common
 
 import (
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/desc"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/filter"
+	sacloudvalidator "github.com/sacloud/terraform-provider-sakuracloud/internal/validator"
 )
 
+// SchemaDataSourceId は参照先のIDを指定するid属性を返す。"1.1e+11"などの数値を変換した表記も受け付ける
 func SchemaDataSourceId(name string) schema.Attribute {
 	return schema.StringAttribute{
+		CustomType:  SakuraIDType{},
 		Optional:    true,
 		Computed:    true,
 		Description: desc.Sprintf("The ID of the %s.", name),
 	}
 }
 
+// SchemaDataSourceResourceID はidの旧名であるresource_id属性を返す。idと同時には指定できない
+func SchemaDataSourceResourceID(name string) schema.Attribute {
+	return schema.StringAttribute{
+		CustomType:         SakuraIDType{},
+		Optional:           true,
+		Description:        desc.Sprintf("The ID of the %s. Deprecated: use id instead", name),
+		DeprecationMessage: "resource_id is deprecated and will be removed in a future version. Use id instead.",
+		Validators: []validator.String{
+			stringvalidator.ConflictsWith(path.MatchRoot("id")),
+		},
+	}
+}
+
+// DataSourceLookupID はidまたはresource_idで指定された参照先のIDを返す。どちらも未指定の場合はidをそのまま返す
+func DataSourceLookupID(id SakuraID, resourceID SakuraID) SakuraID {
+	if resourceID.IsNull() || resourceID.IsUnknown() {
+		return id
+	}
+	return NewSakuraIDValue(resourceID.CanonicalValue())
+}
+
 func SchemaDataSourceName(name string) schema.Attribute {
 	return schema.StringAttribute{
 		Optional:    true,
@@ -36,6 +64,23 @@ func SchemaDataSourceName(name string) schema.Attribute {
 	}
 }
 
+func SchemaDataSourceIgnoreCase(name string) schema.Attribute {
+	return schema.BoolAttribute{
+		Optional:    true,
+		Description: desc.Sprintf("If true, name is matched case-insensitively. An error is returned when more than one %s matches. Default is false", name),
+	}
+}
+
+func SchemaDataSourceNameMatch(name string) schema.Attribute {
+	return schema.StringAttribute{
+		Optional:    true,
+		Description: desc.Sprintf("How name is matched. This must be one of [%s]. With prefix or contains, exactly one %s must match and all matched names are reported otherwise. Default is exact", filter.NameMatchModes, name),
+		Validators: []validator.String{
+			stringvalidator.OneOf(filter.NameMatchModes...),
+		},
+	}
+}
+
 func SchemaDataSourceDescription(name string) schema.Attribute {
 	return schema.StringAttribute{
 		Computed:    true,
@@ -56,6 +101,14 @@ func SchemaDataSourceTags(name string) schema.Attribute {
 		Optional:    true,
 		Computed:    true,
 		Description: desc.Sprintf("The tags of the %s.", name),
+		Validators:  sacloudvalidator.Tags(),
+	}
+}
+
+func SchemaDataSourceWaitForExists(name string) schema.Attribute {
+	return schema.BoolAttribute{
+		Optional:    true,
+		Description: desc.Sprintf("If true, wait up to 30 seconds for the %s to become visible when it is not found. This is useful when the %s is created in the same apply.", name, name),
 	}
 }
 
@@ -0,0 +1,208 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	_ basetypes.StringTypable                    = DurationStringType{}
+	_ basetypes.StringValuableWithSemanticEquals = DurationString{}
+	_ xattr.ValidateableAttribute                = DurationString{}
+)
+
+// DurationStringType は"90s"や"5m"などの時間を表す文字列の型。待機時間や間隔を指定する属性のCustomTypeに指定する。
+// "300s"と"5m"のように表記が異なっても同じ時間であれば差分としない
+type DurationStringType struct {
+	basetypes.StringType
+}
+
+func (t DurationStringType) Equal(o attr.Type) bool {
+	other, ok := o.(DurationStringType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t DurationStringType) String() string {
+	return "DurationStringType"
+}
+
+func (t DurationStringType) ValueFromString(_ context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return DurationString{StringValue: in}, nil
+}
+
+func (t DurationStringType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	v, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := v.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", v)
+	}
+	return DurationString{StringValue: s}, nil
+}
+
+func (t DurationStringType) ValueType(_ context.Context) attr.Value {
+	return DurationString{}
+}
+
+// DurationString はDurationStringTypeの値
+type DurationString struct {
+	basetypes.StringValue
+}
+
+func NewDurationStringValue(v string) DurationString {
+	return DurationString{StringValue: basetypes.NewStringValue(v)}
+}
+
+func NewDurationStringNull() DurationString {
+	return DurationString{StringValue: basetypes.NewStringNull()}
+}
+
+func (v DurationString) Equal(o attr.Value) bool {
+	other, ok := o.(DurationString)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v DurationString) Type(_ context.Context) attr.Type {
+	return DurationStringType{}
+}
+
+// Duration は値をtime.Durationに変換する。null/unknownの場合はdefaultValueを返す
+func (v DurationString) Duration(defaultValue time.Duration) (time.Duration, error) {
+	if v.IsNull() || v.IsUnknown() {
+		return defaultValue, nil
+	}
+	return ParseDuration(v.ValueString())
+}
+
+// CanonicalValue は正規化した表記("5m"など)を返す。時間として解釈できない場合は値をそのまま返す
+func (v DurationString) CanonicalValue() string {
+	d, err := ParseDuration(v.ValueString())
+	if err != nil {
+		return v.ValueString()
+	}
+	return FormatDuration(d)
+}
+
+// StringSemanticEquals は同じ時間を表していれば等しいとみなす
+func (v DurationString) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	newValue, ok := newValuable.(DurationString)
+	if !ok {
+		diags.AddError("Semantic Equality Check Error", fmt.Sprintf("expected value type %T, got %T", v, newValuable))
+		return false, diags
+	}
+	return v.CanonicalValue() == newValue.CanonicalValue(), diags
+}
+
+// ValidateAttribute は時間として解釈できない値や負の値をエラーとする
+func (v DurationString) ValidateAttribute(_ context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+	if _, err := ParseDuration(v.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Duration", err.Error())
+	}
+}
+
+// ParseDuration は秒数の整数、またはtime.ParseDurationの書式の文字列を解釈する。
+// 整数は以前の整数の属性との互換性のため秒として扱う。負の値と1秒未満の端数を含む値はエラーとする
+func ParseDuration(value string) (time.Duration, error) {
+	if n, err := strconv.Atoi(value); err == nil {
+		if n < 0 {
+			return 0, errors.New("duration must not be negative, got " + value)
+		}
+		return time.Duration(n) * time.Second, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf(`expected a number of seconds such as "30" or a duration such as "30s" or "2m", got %q`, value)
+	}
+	if d < 0 {
+		return 0, errors.New("duration must not be negative, got " + value)
+	}
+	if d%time.Second != 0 {
+		return 0, fmt.Errorf("duration must be a whole number of seconds, got %q", value)
+	}
+	return d, nil
+}
+
+// ParseDurationSeconds はParseDurationで解釈した値を秒数で返す
+func ParseDurationSeconds(value string) (int, error) {
+	d, err := ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	return int(d / time.Second), nil
+}
+
+// FormatDuration はdを末尾の0の単位を省いた表記("5m0s"ではなく"5m")に変換する
+func FormatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// durationValidator はParseDurationで解釈できない値をエラーとする
+type durationValidator struct{}
+
+var _ validator.String = durationValidator{}
+
+func (v durationValidator) Description(_ context.Context) string {
+	return `value must be a non-negative number of seconds such as "30" or a duration such as "30s" or "2m"`
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v durationValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, err := ParseDuration(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Duration", err.Error())
+	}
+}
+
+// DurationValidator は秒数の整数または時間の文字列を受け付ける属性のバリデータを返す
+func DurationValidator() validator.String {
+	return durationValidator{}
+}
@@ -0,0 +1,235 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDuration(t *testing.T) {
+	expects := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{in: "90s", want: 90 * time.Second, ok: true},
+		{in: "5m", want: 5 * time.Minute, ok: true},
+		{in: "1h30m", want: 90 * time.Minute, ok: true},
+		{in: "1.5h", want: 90 * time.Minute, ok: true},
+		{in: "0s", want: 0, ok: true},
+		{in: "0", want: 0, ok: true},
+		{in: "5", want: 5 * time.Second, ok: true},
+		{in: "-5m", ok: false},
+		{in: "-5", ok: false},
+		{in: "500ms", ok: false},
+		{in: "5 minutes", ok: false},
+		{in: "", ok: false},
+	}
+	for _, tc := range expects {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParseDuration(tc.in)
+			if !tc.ok {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParseDurationSeconds(t *testing.T) {
+	expects := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{in: "30", want: 30, ok: true},
+		{in: "0", want: 0, ok: true},
+		{in: "30s", want: 30, ok: true},
+		{in: "2m", want: 120, ok: true},
+		{in: "1h30m", want: 5400, ok: true},
+		{in: "1.5m", want: 90, ok: true},
+		{in: "-1", ok: false},
+		{in: "-30s", ok: false},
+		{in: "500ms", ok: false},
+		{in: "1.5", ok: false},
+		{in: "ten", ok: false},
+		{in: "30 s", ok: false},
+		{in: "", ok: false},
+	}
+	for _, tc := range expects {
+		t.Run(tc.in, func(t *testing.T) {
+			got, err := ParseDurationSeconds(tc.in)
+			if !tc.ok {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDurationValidator(t *testing.T) {
+	ctx := context.Background()
+	for _, tc := range []struct {
+		value   types.String
+		wantErr bool
+	}{
+		{value: types.StringValue("30")},
+		{value: types.StringValue("2m")},
+		{value: types.StringNull()},
+		{value: types.StringUnknown()},
+		{value: types.StringValue("-1"), wantErr: true},
+		{value: types.StringValue("two minutes"), wantErr: true},
+	} {
+		t.Run(tc.value.String(), func(t *testing.T) {
+			resp := &validator.StringResponse{}
+			DurationValidator().ValidateString(ctx, validator.StringRequest{Path: path.Root("retry_wait_max"), ConfigValue: tc.value}, resp)
+			assert.Equal(t, tc.wantErr, resp.Diagnostics.HasError(), resp.Diagnostics)
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	expects := []struct {
+		in   time.Duration
+		want string
+	}{
+		{in: 0, want: "0s"},
+		{in: 90 * time.Second, want: "1m30s"},
+		{in: 5 * time.Minute, want: "5m"},
+		{in: time.Hour, want: "1h"},
+		{in: 90 * time.Minute, want: "1h30m"},
+		{in: time.Hour + 30*time.Second, want: "1h0m30s"},
+		{in: 1500 * time.Millisecond, want: "1.5s"},
+	}
+	for _, tc := range expects {
+		t.Run(tc.want, func(t *testing.T) {
+			assert.Equal(t, tc.want, FormatDuration(tc.in))
+		})
+	}
+}
+
+func TestDurationString_StringSemanticEquals(t *testing.T) {
+	ctx := context.Background()
+
+	expects := []struct {
+		prior string
+		new   string
+		want  bool
+	}{
+		{prior: "300s", new: "5m", want: true},
+		{prior: "5m0s", new: "5m", want: true},
+		{prior: "1h", new: "60m", want: true},
+		{prior: "90s", new: "1m30s", want: true},
+		{prior: "90", new: "1m30s", want: true},
+		{prior: "5m", new: "6m", want: false},
+		{prior: "invalid", new: "invalid", want: true},
+		{prior: "invalid", new: "5m", want: false},
+	}
+	for _, tc := range expects {
+		t.Run(tc.prior+"/"+tc.new, func(t *testing.T) {
+			got, diags := NewDurationStringValue(tc.prior).StringSemanticEquals(ctx, NewDurationStringValue(tc.new))
+			require.False(t, diags.HasError(), diags)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	t.Run("other type", func(t *testing.T) {
+		_, diags := NewDurationStringValue("5m").StringSemanticEquals(ctx, types.StringValue("5m"))
+		assert.True(t, diags.HasError())
+	})
+}
+
+func TestDurationString_Duration(t *testing.T) {
+	d, err := NewDurationStringNull().Duration(time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, d)
+
+	d, err = NewDurationStringValue("300s").Duration(time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Minute, d)
+
+	_, err = NewDurationStringValue("-1s").Duration(time.Minute)
+	assert.Error(t, err)
+}
+
+func TestDurationString_ValidateAttribute(t *testing.T) {
+	for _, tc := range []struct {
+		value   DurationString
+		wantErr bool
+	}{
+		{value: NewDurationStringValue("90s")},
+		{value: NewDurationStringValue("90")},
+		{value: NewDurationStringNull()},
+		{value: DurationString{StringValue: types.StringUnknown()}},
+		{value: NewDurationStringValue("-90s"), wantErr: true},
+		{value: NewDurationStringValue("1.5s"), wantErr: true},
+	} {
+		t.Run(tc.value.String(), func(t *testing.T) {
+			var resp xattr.ValidateAttributeResponse
+			tc.value.ValidateAttribute(context.Background(), xattr.ValidateAttributeRequest{Path: path.Root("delay_loop")}, &resp)
+			assert.Equal(t, tc.wantErr, resp.Diagnostics.HasError(), resp.Diagnostics)
+		})
+	}
+}
+
+func TestDurationStringType_ValueFromTerraform(t *testing.T) {
+	v, err := DurationStringType{}.ValueFromTerraform(context.Background(), tftypes.NewValue(tftypes.String, "5m"))
+	require.NoError(t, err)
+	assert.Equal(t, NewDurationStringValue("5m"), v)
+}
+
+func TestTimeoutsAttributes(t *testing.T) {
+	attr := TimeoutsAttributes(context.Background(), timeouts.Opts{Create: true, Delete: true}).(schema.SingleNestedAttribute)
+	require.Len(t, attr.Attributes, 2)
+
+	for _, tc := range []struct {
+		value   string
+		wantErr bool
+	}{
+		{value: "20m"},
+		{value: "-20m", wantErr: true},
+		{value: "-20", wantErr: true},
+		{value: "twenty minutes", wantErr: true},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			for name, a := range attr.Attributes {
+				var resp validator.StringResponse
+				for _, v := range a.(schema.StringAttribute).StringValidators() {
+					v.ValidateString(context.Background(), validator.StringRequest{
+						Path:        path.Root("timeouts").AtName(name),
+						ConfigValue: types.StringValue(tc.value),
+					}, &resp)
+				}
+				assert.Equal(t, tc.wantErr, resp.Diagnostics.HasError(), resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	kmsv1 "github.com/sacloud/kms-api-go/apis/v1"
+)
+
+// KnownEnums はAPIのレスポンスのJSONのフィールド名ごとの、プロバイダーが扱える列挙値。
+// APIに新しい値が追加された場合はここに1行追加する
+var KnownEnums = map[string][]string{
+	"KeyOrigin": {string(kmsv1.KeyOriginEnumGenerated), string(kmsv1.KeyOriginEnumImported)},
+}
+
+// knownEnumPaths はKnownEnumsの列挙値を置き換えるレスポンスを返すAPIのパスに含まれる文字列。
+// 他のサービスのレスポンスは同じ名前のフィールドを含んでいても変更しない
+var knownEnumPaths = []string{"/kms/"}
+
+// enumKey はレスポンス中の列挙値を、フィールド名とそれを含むリソースのIDで識別する
+type enumKey struct {
+	field string
+	id    string
+}
+
+// unknownEnumCapture はレスポンスに含まれていた未知の列挙値の元の値を保持する
+type unknownEnumCapture struct {
+	mu     sync.Mutex
+	values map[enumKey]string
+}
+
+type unknownEnumCaptureKey struct{}
+
+// WithUnknownEnumCapture はレスポンスに含まれていた未知の列挙値を記録するためのcontextを返す。
+// WithAPIErrorCaptureから呼び出されるため、CRUDで個別に呼び出す必要はない
+func WithUnknownEnumCapture(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(unknownEnumCaptureKey{}).(*unknownEnumCapture); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, unknownEnumCaptureKey{}, &unknownEnumCapture{values: map[enumKey]string{}})
+}
+
+// UnknownEnumValue はfieldとidで識別される列挙値が、プロバイダーが扱えない値として記録されていればその元の値を返す
+func UnknownEnumValue(ctx context.Context, field, id string) (string, bool) {
+	c, ok := ctx.Value(unknownEnumCaptureKey{}).(*unknownEnumCapture)
+	if !ok {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.values[enumKey{field: field, id: id}]
+	return v, ok
+}
+
+// FlattenEnum はAPIが返した列挙値をstateの値に変換する。
+// KnownEnumsにない値の場合はWARNログを出力し、空文字にせずAPIが返した元の値をそのまま返す。値が空の場合はnullを返す
+func FlattenEnum[T ~string](ctx context.Context, field, id string, v T) types.String {
+	if raw, ok := UnknownEnumValue(ctx, field, id); ok {
+		return types.StringValue(raw)
+	}
+	if v == "" {
+		return types.StringNull()
+	}
+	if known, ok := KnownEnums[field]; ok && !slices.Contains(known, string(v)) {
+		warnUnknownEnum(ctx, field, id, string(v))
+	}
+	return types.StringValue(string(v))
+}
+
+func warnUnknownEnum(ctx context.Context, field, id, value string) {
+	tflog.Warn(ctx, "API returned an enum value that this provider version does not recognize. Consider upgrading the provider", map[string]any{
+		"field":   field,
+		"id":      id,
+		"value":   value,
+		"allowed": strings.Join(KnownEnums[field], ","),
+	})
+}
+
+// unknownEnumTolerator はレスポンスのKnownEnumsにない列挙値をcontextに記録し、APIクライアントが受け付ける値に置き換えるhttp.RoundTripper。
+// ogenで生成されたクライアントは未知の列挙値を含むレスポンスを検証エラーにするため、APIに値が追加されるとReadが失敗してしまう。
+// 置き換えた値ではなく元の値をstateに保存するため、変換にはFlattenEnumを利用すること
+type unknownEnumTolerator struct {
+	transport http.RoundTripper
+}
+
+func (t *unknownEnumTolerator) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if !slices.ContainsFunc(knownEnumPaths, func(p string) bool { return strings.Contains(req.URL.Path, p) }) {
+		return transport.RoundTrip(req)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil || resp.StatusCode >= http.StatusBadRequest || !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close() //nolint:errcheck
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber() // 数値の精度を変えずに再エンコードする
+	if dec.Decode(&v) != nil {
+		return resp, nil
+	}
+	if !replaceUnknownEnums(req.Context(), v) {
+		return resp, nil
+	}
+	replaced, err := json.Marshal(v)
+	if err != nil {
+		return resp, nil //nolint:nilerr
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(replaced))
+	resp.ContentLength = int64(len(replaced))
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+// replaceUnknownEnums はvに含まれるKnownEnumsにない列挙値を記録して既知の値に置き換え、置き換えたかを返す
+func replaceUnknownEnums(ctx context.Context, v any) bool {
+	replaced := false
+	switch v := v.(type) {
+	case map[string]any:
+		id, _ := v["ID"].(string)
+		for field, value := range v {
+			if s, ok := value.(string); ok {
+				known, ok := KnownEnums[field]
+				if !ok || s == "" || slices.Contains(known, s) {
+					continue
+				}
+				warnUnknownEnum(ctx, field, id, s)
+				if c, ok := ctx.Value(unknownEnumCaptureKey{}).(*unknownEnumCapture); ok {
+					c.mu.Lock()
+					c.values[enumKey{field: field, id: id}] = s
+					c.mu.Unlock()
+				}
+				v[field] = known[0]
+				replaced = true
+				continue
+			}
+			if replaceUnknownEnums(ctx, value) {
+				replaced = true
+			}
+		}
+	case []any:
+		for _, e := range v {
+			if replaceUnknownEnums(ctx, e) {
+				replaced = true
+			}
+		}
+	}
+	return replaced
+}
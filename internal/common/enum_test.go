@@ -0,0 +1,137 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sacloud/kms-api-go"
+	kmsv1 "github.com/sacloud/kms-api-go/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlattenEnum(t *testing.T) {
+	cases := []struct {
+		name  string
+		value kmsv1.KeyOriginEnum
+		want  types.String
+	}{
+		{name: "generated", value: kmsv1.KeyOriginEnumGenerated, want: types.StringValue("generated")},
+		{name: "imported", value: kmsv1.KeyOriginEnumImported, want: types.StringValue("imported")},
+		{name: "unknown value is kept as is", value: "hsm", want: types.StringValue("hsm")},
+		{name: "empty", value: "", want: types.StringNull()},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, FlattenEnum(context.Background(), "KeyOrigin", "110000000001", tc.value))
+		})
+	}
+}
+
+// keyTransport はKeyOriginを指定した値にしたKMSキーを返す
+type keyTransport struct {
+	keyOrigin string
+}
+
+func (t *keyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := kmsv1.WrappedKey{Key: kmsv1.Key{
+		ID:         "110000000001",
+		CreatedAt:  "2025-01-01T00:00:00+09:00",
+		ModifiedAt: "2025-01-01T00:00:00+09:00",
+		Name:       "foobar",
+		KeyOrigin:  kmsv1.KeyOriginEnum(t.keyOrigin),
+		Tags:       []string{},
+	}}
+	body, err := key.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(string(body))),
+		Request:    req,
+	}, nil
+}
+
+func TestUnknownEnumTolerator(t *testing.T) {
+	newKeyOp := func(t *testing.T, keyOrigin string) kms.KeyAPI {
+		t.Helper()
+		c := &Config{
+			AccessToken:       "token",
+			AccessTokenSecret: "secret",
+			APIRootURL:        "http://sakura.example.com",
+			HTTPTransport:     &keyTransport{keyOrigin: keyOrigin},
+		}
+		client, err := c.NewClient()
+		require.NoError(t, err)
+		keyOp, err := client.KMSKeyOp()
+		require.NoError(t, err)
+		return keyOp
+	}
+
+	t.Run("unknown value", func(t *testing.T) {
+		ctx := WithAPIErrorCapture(context.Background())
+		key, err := newKeyOp(t, "hsm").Read(ctx, "110000000001")
+		require.NoError(t, err)
+
+		raw, ok := UnknownEnumValue(ctx, "KeyOrigin", "110000000001")
+		assert.True(t, ok)
+		assert.Equal(t, "hsm", raw)
+		assert.Equal(t, types.StringValue("hsm"), FlattenEnum(ctx, "KeyOrigin", key.ID, key.KeyOrigin))
+		assert.Equal(t, "foobar", key.Name)
+	})
+
+	t.Run("known value", func(t *testing.T) {
+		ctx := WithAPIErrorCapture(context.Background())
+		key, err := newKeyOp(t, "imported").Read(ctx, "110000000001")
+		require.NoError(t, err)
+
+		_, ok := UnknownEnumValue(ctx, "KeyOrigin", "110000000001")
+		assert.False(t, ok)
+		assert.Equal(t, types.StringValue("imported"), FlattenEnum(ctx, "KeyOrigin", key.ID, key.KeyOrigin))
+	})
+}
+
+func TestUnknownEnumTolerator_otherServices(t *testing.T) {
+	body := `{"Vault":{"ID":"110000000001","KeyOrigin":"hsm"}}`
+	tolerator := &unknownEnumTolerator{transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})}
+
+	// KMS以外のサービスのレスポンスは置き換えない
+	ctx := WithAPIErrorCapture(context.Background())
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "http://sakura.example.com/secretmanager/vaults/110000000001", nil)
+	resp, err := tolerator.RoundTrip(req)
+	require.NoError(t, err)
+	got, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(got))
+	_, ok := UnknownEnumValue(ctx, "KeyOrigin", "110000000001")
+	assert.False(t, ok)
+}
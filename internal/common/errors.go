@@ -0,0 +1,84 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	ogen "github.com/ogen-go/ogen/validate"
+	client "github.com/sacloud/api-client-go"
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/filter"
+)
+
+// IsNotFound はリソースが存在しないことを表すエラーかを返す。
+// api-client-goのAPIError、kms-api-go/secretmanager-api-goのエラー、ogenのUnexpectedStatusCodeError、iaas-api-goのエラー、
+// 一覧からの検索で一致しなかった場合のエラーを、ラップされている場合も含めて判定する
+func IsNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// kms-api-go/secretmanager-api-goのErrorはステータスコードを持つAPIErrorをラップしている
+	if client.IsNotFoundError(err) {
+		return true
+	}
+	var unexpected *ogen.UnexpectedStatusCodeError
+	if errors.As(err, &unexpected) {
+		return unexpected.StatusCode == http.StatusNotFound
+	}
+	// iaas.IsNotFoundErrorはラップされたエラーを判定しないためerrors.Asで取り出す
+	var iaasErr iaas.APIError
+	if errors.As(err, &iaasErr) {
+		return iaasErr.ResponseCode() == http.StatusNotFound
+	}
+	var noResults *iaas.NoResultsError
+	if errors.As(err, &noResults) {
+		return true
+	}
+	return errors.Is(err, ErrFilterNoResult) || errors.Is(err, filter.ErrNoResult)
+}
+
+// HandleNotFoundOnRead はRead時のエラーがnot foundの場合に、リソースをstateから削除してtrueを返す。
+// 実リソースが削除されている場合に次回のplanで再作成できるよう、Readではこれを使ってnot foundを判定すること
+func HandleNotFoundOnRead(ctx context.Context, err error, state *tfsdk.State, resourceName, id string) bool {
+	if !IsNotFound(err) {
+		return false
+	}
+
+	tflog.Warn(ctx, fmt.Sprintf("%s[%s] is not found. Removing it from the state", resourceName, id), map[string]any{
+		"error": err.Error(),
+	})
+	state.RemoveResource(ctx)
+	return true
+}
+
+// IsCanceled はctxがキャンセルされたか、errがキャンセルによるエラーかを返す。
+// timeoutsによる期限切れ(context.DeadlineExceeded)はキャンセルとして扱わない
+func IsCanceled(ctx context.Context, err error) bool {
+	return errors.Is(ctx.Err(), context.Canceled) || errors.Is(err, context.Canceled)
+}
+
+// AddCanceledError は操作がキャンセルされたことを表すdiagnosticを追加する。
+// 作成後にキャンセルされた場合も、stateを保存済みであればリソースはtaintedとして残り、次回のapplyで再作成される
+func AddCanceledError(diags *diag.Diagnostics, summary string, err error) {
+	diags.AddError("Operation Cancelled", fmt.Sprintf("%s: operation cancelled before it completed: %s", summary, err))
+}
@@ -0,0 +1,133 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	ogen "github.com/ogen-go/ogen/validate"
+	client "github.com/sacloud/api-client-go"
+	"github.com/sacloud/iaas-api-go"
+	kms "github.com/sacloud/kms-api-go"
+	sm "github.com/sacloud/secretmanager-api-go"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/filter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsNotFound(t *testing.T) {
+	iaasNotFound := iaas.NewAPIError(http.MethodGet, nil, http.StatusNotFound, nil)
+	iaasServerError := iaas.NewAPIError(http.MethodGet, nil, http.StatusInternalServerError, nil)
+
+	expects := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "plain error", err: errors.New("not found"), want: false},
+
+		{name: "api-client-go 404", err: client.NewAPIError(http.StatusNotFound, "not found", nil), want: true},
+		{name: "api-client-go 500", err: client.NewAPIError(http.StatusInternalServerError, "error", nil), want: false},
+		{name: "api-client-go 404 wrapped", err: fmt.Errorf("reading: %w", client.NewAPIError(http.StatusNotFound, "", nil)), want: true},
+
+		{name: "kms-api-go 404", err: kms.NewAPIError("Read", http.StatusNotFound, ogen.UnexpectedStatusCode(http.StatusNotFound)), want: true},
+		{name: "kms-api-go 403", err: kms.NewAPIError("Read", http.StatusForbidden, nil), want: false},
+		{name: "kms-api-go error wrapping ogen 404", err: kms.NewError("Read", ogen.UnexpectedStatusCode(http.StatusNotFound)), want: true},
+		{name: "kms-api-go 404 wrapped", err: fmt.Errorf("reading: %w", kms.NewAPIError("Read", http.StatusNotFound, nil)), want: true},
+
+		{name: "secretmanager-api-go 404", err: sm.NewAPIError("Read", http.StatusNotFound, nil), want: true},
+		{name: "secretmanager-api-go 500", err: sm.NewAPIError("Read", http.StatusInternalServerError, nil), want: false},
+		{name: "secretmanager-api-go 404 wrapped", err: fmt.Errorf("listing: %w", sm.NewAPIError("List", http.StatusNotFound, nil)), want: true},
+
+		{name: "ogen 404", err: ogen.UnexpectedStatusCode(http.StatusNotFound), want: true},
+		{name: "ogen 500", err: ogen.UnexpectedStatusCode(http.StatusInternalServerError), want: false},
+
+		{name: "iaas-api-go 404", err: iaasNotFound, want: true},
+		{name: "iaas-api-go 500", err: iaasServerError, want: false},
+		{name: "iaas-api-go 404 wrapped", err: fmt.Errorf("reading: %w", iaasNotFound), want: true},
+		{name: "iaas-api-go no results", err: &iaas.NoResultsError{}, want: true},
+
+		{name: "filter no result", err: ErrFilterNoResult, want: true},
+		{name: "filter no result wrapped", err: fmt.Errorf("finding: %w", filter.ErrNoResult), want: true},
+		{name: "filter no result with searched count", err: &filter.NoResultError{Kind: "KMS key", Searched: 3}, want: true},
+	}
+
+	for _, tc := range expects {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, IsNotFound(tc.err))
+		})
+	}
+}
+
+func TestHandleNotFoundOnRead(t *testing.T) {
+	s := schema.Schema{Attributes: map[string]schema.Attribute{
+		"id": schema.StringAttribute{Computed: true},
+	}}
+	newState := func() *tfsdk.State {
+		typ := s.Type().TerraformType(context.Background())
+		return &tfsdk.State{
+			Schema: s,
+			Raw:    tftypes.NewValue(typ, map[string]tftypes.Value{"id": tftypes.NewValue(tftypes.String, "110000000001")}),
+		}
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		state := newState()
+		err := fmt.Errorf("reading: %w", client.NewAPIError(http.StatusNotFound, "", nil))
+
+		require.True(t, HandleNotFoundOnRead(context.Background(), err, state, "KMS key", "110000000001"))
+		assert.True(t, state.Raw.IsNull())
+	})
+
+	t.Run("other error", func(t *testing.T) {
+		state := newState()
+		err := client.NewAPIError(http.StatusInternalServerError, "", nil)
+
+		require.False(t, HandleNotFoundOnRead(context.Background(), err, state, "KMS key", "110000000001"))
+		assert.False(t, state.Raw.IsNull())
+	})
+}
+
+func TestIsCanceled(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	assert.False(t, IsCanceled(context.Background(), errors.New("error")))
+	assert.True(t, IsCanceled(canceled, errors.New("error")))
+	assert.True(t, IsCanceled(context.Background(), fmt.Errorf("deleting: %w", context.Canceled)))
+	assert.False(t, IsCanceled(expired, context.DeadlineExceeded), "timeouts should not be treated as cancellation")
+}
+
+func TestAddAPIError_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var diags diag.Diagnostics
+	AddAPIError(ctx, &diags, "KMS Delete Error", fmt.Errorf("key is in use: %w", context.Canceled))
+	require.True(t, diags.HasError())
+	assert.Equal(t, "Operation Cancelled", diags[0].Summary())
+	assert.Equal(t, "KMS Delete Error: operation cancelled before it completed: key is in use: context canceled", diags[0].Detail())
+}
@@ -0,0 +1,117 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	client "github.com/sacloud/api-client-go"
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/search"
+	iaastypes "github.com/sacloud/iaas-api-go/types"
+	kms "github.com/sacloud/kms-api-go"
+	sm "github.com/sacloud/secretmanager-api-go"
+)
+
+// rawServiceClient はAPIクライアントを介さずにリクエストを送信するための、サービスのエンドポイントとHTTPクライアント
+type rawServiceClient struct {
+	serverURL string
+	doer      client.HttpRequestDoer
+}
+
+// rawService はserviceのrawServiceClientを返す。transportやレート制限はサービスのAPIクライアントと共有する
+func (c *APIClient) rawService(service, defaultURL string) (*rawServiceClient, error) {
+	return serviceClient(&c.services, service+"/raw", serviceAPIZone, func() (*rawServiceClient, error) {
+		serverURL, doer, err := c.serviceDoer(serviceAPIURL(c.apiRootURL, serviceAPIZone, defaultURL))
+		if err != nil {
+			return nil, err
+		}
+		return &rawServiceClient{serverURL: serverURL, doer: doer}, nil
+	})
+}
+
+// exists はresourcePathのリソースが存在するかを返す。レスポンスボディを受け取らないようHEADリクエストで確認し、
+// HEADに対応していない(405/501を返す)場合はGETで確認する。404の場合はfalseを、その他のエラーレスポンスの場合はエラーを返す
+func (s *rawServiceClient) exists(ctx context.Context, resourcePath string) (bool, error) {
+	u, err := url.JoinPath(s.serverURL, resourcePath)
+	if err != nil {
+		return false, err
+	}
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequestWithContext(ctx, method, u, nil)
+		if err != nil {
+			return false, err
+		}
+		// 空のボディをgzipとして展開しようとしないよう、圧縮を要求しない
+		req.Header.Set("Accept-Encoding", "identity")
+		resp, err := s.doer.Do(req)
+		if err != nil {
+			return false, err
+		}
+		io.Copy(io.Discard, resp.Body) //nolint:errcheck
+		resp.Body.Close()              //nolint:errcheck,gosec
+
+		switch {
+		case resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented:
+			continue
+		case resp.StatusCode == http.StatusNotFound:
+			return false, nil
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			return true, nil
+		}
+		return false, client.NewAPIError(resp.StatusCode, "", fmt.Errorf("%s %s", method, u))
+	}
+	return false, client.NewAPIError(http.StatusMethodNotAllowed, "", fmt.Errorf("checking existence of %s is not supported", u))
+}
+
+// KMSKeyExists はKMSのキーが存在するかを、キーの内容を取得せずに確認する
+func (c *APIClient) KMSKeyExists(ctx context.Context, id string) (bool, error) {
+	s, err := c.rawService(serviceKMS, kms.DefaultAPIRootURL)
+	if err != nil {
+		return false, kms.NewError("NewClient", err)
+	}
+	exists, err := s.exists(ctx, "kms/keys/"+url.PathEscape(id))
+	if err != nil {
+		return false, kms.NewAPIError("Exists", APIStatusCode(err), err)
+	}
+	return exists, nil
+}
+
+// SecretManagerVaultExists はシークレットマネージャのボールトが存在するかを、ボールトの内容を取得せずに確認する
+func (c *APIClient) SecretManagerVaultExists(ctx context.Context, id string) (bool, error) {
+	s, err := c.rawService(serviceSecretManager, sm.DefaultAPIRootURL)
+	if err != nil {
+		return false, sm.NewError("NewClient", err)
+	}
+	exists, err := s.exists(ctx, "secretmanager/vaults/"+url.PathEscape(id))
+	if err != nil {
+		return false, sm.NewAPIError("Exists", APIStatusCode(err), err)
+	}
+	return exists, nil
+}
+
+// IaaSExistsCondition はiaas-api-goのFindで、idのリソースの有無のみを確認するための検索条件を返す。
+// IaaSのAPIはHEADに対応していないため、IDのみを含めた1件の検索で応答を小さくする
+func IaaSExistsCondition(id iaastypes.ID) *iaas.FindCondition {
+	return &iaas.FindCondition{
+		Count:   1,
+		Filter:  search.Filter{search.Key("ID"): search.AndEqual(id.String())},
+		Include: []string{"ID"},
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	kmsapi "github.com/sacloud/kms-api-go/apis/v1"
+	smapi "github.com/sacloud/secretmanager-api-go/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test/fake"
+)
+
+// bodySizeTransport はレスポンスボディから読み込まれたバイト数とリクエストのメソッドを記録する
+type bodySizeTransport struct {
+	mu      sync.Mutex
+	methods []string
+	bytes   int64
+}
+
+func (t *bodySizeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	t.methods = append(t.methods, req.Method)
+	t.mu.Unlock()
+	resp.Body = &countingBody{ReadCloser: resp.Body, t: t}
+	return resp, nil
+}
+
+func (t *bodySizeTransport) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.methods = nil
+	t.bytes = 0
+}
+
+type countingBody struct {
+	io.ReadCloser
+	t *bodySizeTransport
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.t.mu.Lock()
+	b.t.bytes += int64(n)
+	b.t.mu.Unlock()
+	return n, err
+}
+
+func newExistsTestClient(t *testing.T, url string, transport http.RoundTripper) *APIClient {
+	t.Helper()
+	client, err := (&Config{
+		AccessToken:         fake.AccessToken,
+		AccessTokenSecret:   fake.AccessTokenSecret,
+		APIRootURL:          url,
+		APIRequestRateLimit: 100,
+		HTTPTransport:       transport,
+	}).NewClient()
+	require.NoError(t, err)
+	return client
+}
+
+func TestAPIClient_Exists(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	ctx := context.Background()
+
+	require.NoError(t, server.KMS.Put(kmsapi.Key{
+		ID:          "110000000001",
+		Name:        "foobar",
+		Description: kmsapi.NewOptString("a long description to make the payload of the key larger than the existence check"),
+		KeyOrigin:   kmsapi.KeyOriginEnumGenerated,
+		Tags:        []string{"tag1", "tag2"},
+	}))
+	require.NoError(t, server.SecretManager.Put(smapi.Vault{ID: "110000000002", Name: "foobar", KmsKeyID: "110000000001"}))
+
+	transport := &bodySizeTransport{}
+	client := newExistsTestClient(t, server.URL, transport)
+
+	t.Run("KMS key", func(t *testing.T) {
+		transport.reset()
+		exists, err := client.KMSKeyExists(ctx, "110000000001")
+		require.NoError(t, err)
+		assert.True(t, exists)
+		assert.Equal(t, []string{http.MethodHead}, transport.methods)
+		assert.Zero(t, transport.bytes)
+
+		// 内容を取得するReadと比べて、レスポンスボディを受け取らない
+		transport.reset()
+		keyOp, err := client.KMSKeyOp()
+		require.NoError(t, err)
+		_, err = keyOp.Read(ctx, "110000000001")
+		require.NoError(t, err)
+		assert.Positive(t, transport.bytes)
+
+		exists, err = client.KMSKeyExists(ctx, "110000000099")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("SecretManager vault", func(t *testing.T) {
+		transport.reset()
+		exists, err := client.SecretManagerVaultExists(ctx, "110000000002")
+		require.NoError(t, err)
+		assert.True(t, exists)
+		assert.Zero(t, transport.bytes)
+
+		exists, err = client.SecretManagerVaultExists(ctx, "110000000099")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
+
+func TestAPIClient_Exists_fallback(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		switch {
+		case r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case r.URL.Path == "/tk1a/api/cloud/1.1/kms/keys/110000000001":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"Key":{"ID":"110000000001"}}`)) //nolint:errcheck
+		case r.URL.Path == "/tk1a/api/cloud/1.1/kms/keys/110000000002":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := newExistsTestClient(t, server.URL, http.DefaultTransport)
+	ctx := context.Background()
+
+	// HEADに対応していないAPIではGETで確認する
+	exists, err := client.KMSKeyExists(ctx, "110000000001")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, []string{http.MethodHead, http.MethodGet}, methods)
+
+	exists, err = client.KMSKeyExists(ctx, "110000000099")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	_, err = client.KMSKeyExists(ctx, "110000000002")
+	require.Error(t, err)
+	assert.Equal(t, http.StatusInternalServerError, APIStatusCode(err))
+	assert.False(t, IsNotFound(err))
+}
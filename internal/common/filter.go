@@ -15,22 +15,24 @@
 package common
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
+	"slices"
 	"strings"
 
-	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
-	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/desc"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/filter"
+	sacloudvalidator "github.com/sacloud/terraform-provider-sakuracloud/internal/validator"
 
 	"github.com/sacloud/iaas-api-go/search"
 	"github.com/sacloud/iaas-api-go/search/keys"
@@ -38,8 +40,8 @@ import (
 
 const (
 	filterAttrName                   = "filter"
-	filteringOperatorPartialMatchAnd = "partial_match_and"
-	filteringOperatorExactMatchOr    = "exact_match_or"
+	filteringOperatorPartialMatchAnd = filter.OperatorPartialMatchAnd
+	filteringOperatorExactMatchOr    = filter.OperatorExactMatchOr
 )
 
 type FilterSchemaOption struct {
@@ -80,34 +82,21 @@ func FilterSchema(opt *FilterSchemaOption) map[string]schema.Block {
 	if opt.excludeTags {
 		keys = filterConfigKeys
 	}
-	pathExpressions := make([]path.Expression, 0, len(keys))
-	for _, key := range keys {
-		pathExpressions = append(pathExpressions, path.MatchRoot("filter").AtName(key))
-	}
-
 	attrs := map[string]schema.Attribute{
 		"id": schema.StringAttribute{
 			Optional:    true,
 			Description: "The resource id on SakuraCloud used for filtering",
-			Validators: []validator.String{
-				stringvalidator.ExactlyOneOf(pathExpressions...),
-			},
 		},
 		"names": schema.ListAttribute{
 			ElementType: types.StringType,
 			Optional:    true,
 			Description: "The resource names on SakuraCloud used for filtering. If multiple values are specified, they combined as AND condition",
-			Validators: []validator.List{
-				listvalidator.ExactlyOneOf(pathExpressions...),
-			},
 		},
 		"tags": schema.SetAttribute{
 			ElementType: types.StringType,
 			Optional:    true,
 			Description: "The resource tags on SakuraCloud used for filtering. If multiple values are specified, they combined as AND condition",
-			Validators: []validator.Set{
-				setvalidator.ExactlyOneOf(pathExpressions...),
-			},
+			Validators:  sacloudvalidator.Tags(),
 		},
 	}
 	if opt.excludeTags {
@@ -116,20 +105,20 @@ func FilterSchema(opt *FilterSchemaOption) map[string]schema.Block {
 
 	return map[string]schema.Block{
 		"filter": schema.SingleNestedBlock{
-			Description: "One or more values used for filtering, as defined below",
+			Description: "One or more values used for filtering, as defined below. If multiple values are specified, they combined as AND condition",
 			Attributes:  attrs,
+			Validators: []validator.Object{
+				filterNotEmptyValidator{keys: keys},
+			},
 			Blocks: map[string]schema.Block{
 				"condition": schema.ListNestedBlock{
 					Description: "One or more name/values pairs used for filtering. There are several valid keys, for a full reference, check out finding section in the [SakuraCloud API reference](https://developer.sakura.ad.jp/cloud/api/1.1/)",
-					Validators: []validator.List{
-						listvalidator.ExactlyOneOf(pathExpressions...),
-					},
 					NestedObject: schema.NestedBlockObject{
 						Attributes: map[string]schema.Attribute{
 							"name": schema.StringAttribute{
 								//Required:    true,　　// Blockの中に一つでもRequiredがあると、Block自体がRequiredになってしまうため、ここではOptionalにする
 								Optional:    true,
-								Description: "The name of the target field. This value is case-sensitive",
+								Description: "The name of the target field. This value is case-sensitive. Names supported by the data source are translated to API query fields where possible",
 							},
 							"values": schema.ListAttribute{
 								ElementType: types.StringType,
@@ -153,6 +142,41 @@ func FilterSchema(opt *FilterSchemaOption) map[string]schema.Block {
 	}
 }
 
+// filterNotEmptyValidator はfilterブロックに条件が1つも指定されていない場合にエラーとする。
+// ブロック自体が省略された場合もネストした属性のバリデータは実行されるため、AtLeastOneOfではなくブロックに対して検証する
+type filterNotEmptyValidator struct {
+	keys []string
+}
+
+var _ validator.Object = filterNotEmptyValidator{}
+
+func (v filterNotEmptyValidator) Description(_ context.Context) string {
+	return fmt.Sprintf("at least one of [%s] must be specified", strings.Join(v.keys, ", "))
+}
+
+func (v filterNotEmptyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v filterNotEmptyValidator) ValidateObject(ctx context.Context, req validator.ObjectRequest, resp *validator.ObjectResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	attrs := req.ConfigValue.Attributes()
+	for _, key := range v.keys {
+		value, ok := attrs[key]
+		if !ok || value.IsNull() {
+			continue
+		}
+		// conditionブロックは省略すると空のリストとなる
+		if list, ok := value.(types.List); ok && !list.IsUnknown() && len(list.Elements()) == 0 {
+			continue
+		}
+		return
+	}
+	resp.Diagnostics.AddAttributeError(req.Path, "Invalid Attribute Combination", v.Description(ctx))
+}
+
 var ErrFilterNoResult = errors.New("Your query returned no results. Please change your filter or selectors and try again")
 
 func FilterNoResultErr(diag *diag.Diagnostics) {
@@ -163,15 +187,48 @@ func FilterNoResultErr(diag *diag.Diagnostics) {
 	diag.AddError("Filter No Result", ErrFilterNoResult.Error())
 }
 
-func CreateFindCondition(id types.String, name types.String, tags types.Set) *iaas.FindCondition {
+// ExpandFilterCondition はfilterブロックを、APIが検索条件をサポートしていないリソースの絞り込みに利用するfilter.Conditionに変換する。
+// conditionのnameにはID/Name/Tagsに加えて、fieldsで指定したフィールド名を指定できる
+func ExpandFilterCondition(block *FilterBlockModel, fields ...string) (filter.Condition, error) {
+	var cond filter.Condition
+	if block == nil {
+		return cond, nil
+	}
+	if !block.ID.IsNull() && !block.ID.IsUnknown() {
+		cond.ID = block.ID.ValueString()
+	}
+	cond.Names = TlistToStrings(block.Names)
+	cond.Tags = TsetToStrings(block.Tags)
+
+	supported := append([]string{"ID", "Name", "Tags"}, fields...)
+	for _, c := range block.Condition {
+		name := c.Name.ValueString()
+		if !slices.Contains(supported, name) {
+			return filter.Condition{}, fmt.Errorf("unsupported condition name %q. This must be one of [%s]", name, strings.Join(supported, "/"))
+		}
+		cond.Fields = append(cond.Fields, filter.FieldCondition{
+			Name:     name,
+			Values:   TlistToStrings(c.Values),
+			Operator: c.Operator.ValueString(),
+		})
+	}
+	return cond, nil
+}
+
+func CreateFindCondition(id SakuraID, name types.String, tags types.Set) *iaas.FindCondition {
 	condition := &iaas.FindCondition{}
 
+	idValue := id.StringValue
+	if !id.IsNull() && !id.IsUnknown() {
+		idValue = types.StringValue(id.CanonicalValue())
+	}
+
 	var names types.List
 	if !name.IsNull() && !name.IsUnknown() && name.ValueString() != "" {
 		elements := []attr.Value{name}
 		names, _ = types.ListValue(types.StringType, elements)
 	}
-	condition.Filter = ExpandSearchFilter(&FilterBlockModel{ID: id, Names: names, Tags: tags})
+	condition.Filter = ExpandSearchFilter(&FilterBlockModel{ID: idValue, Names: names, Tags: tags})
 
 	return condition
 }
@@ -17,7 +17,11 @@ package common
 import (
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/filter"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type testNameFilterable struct {
@@ -122,3 +126,55 @@ func TestHasTagsFilter(t *testing.T) {
 		assert.Equal(t, e.hit, hasTags(target, e.conditions))
 	}
 }
+
+func TestExpandFilterCondition(t *testing.T) {
+	list := func(values ...string) types.List {
+		elements := make([]attr.Value, 0, len(values))
+		for _, v := range values {
+			elements = append(elements, types.StringValue(v))
+		}
+		return types.ListValueMust(types.StringType, elements)
+	}
+
+	cond, err := ExpandFilterCondition(nil)
+	require.NoError(t, err)
+	assert.Equal(t, filter.Condition{}, cond)
+
+	cond, err = ExpandFilterCondition(&FilterBlockModel{
+		ID:    types.StringValue("110000000001"),
+		Names: list("foo", "bar"),
+		Tags:  types.SetValueMust(types.StringType, []attr.Value{types.StringValue("tag1")}),
+		Condition: []FilterConditionBlockModel{
+			{Name: types.StringValue("Description"), Values: list("desc"), Operator: types.StringNull()},
+			{Name: types.StringValue("Name"), Values: list("foobar"), Operator: types.StringValue("exact_match_or")},
+		},
+	}, "Description")
+	require.NoError(t, err)
+	assert.Equal(t, filter.Condition{
+		ID:    "110000000001",
+		Names: []string{"foo", "bar"},
+		Tags:  []string{"tag1"},
+		Fields: []filter.FieldCondition{
+			{Name: "Description", Values: []string{"desc"}},
+			{Name: "Name", Values: []string{"foobar"}, Operator: "exact_match_or"},
+		},
+	}, cond)
+
+	cond, err = ExpandFilterCondition(&FilterBlockModel{
+		ID:    types.StringNull(),
+		Names: types.ListNull(types.StringType),
+		Tags:  types.SetNull(types.StringType),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, filter.Condition{}, cond)
+
+	// data sourceが対応していないフィールド名はエラーとする
+	_, err = ExpandFilterCondition(&FilterBlockModel{
+		Condition: []FilterConditionBlockModel{{Name: types.StringValue("Description"), Values: list("desc")}},
+	})
+	assert.EqualError(t, err, `unsupported condition name "Description". This must be one of [ID/Name/Tags]`)
+	_, err = ExpandFilterCondition(&FilterBlockModel{
+		Condition: []FilterConditionBlockModel{{Name: types.StringNull(), Values: list("desc")}},
+	}, "Description")
+	assert.EqualError(t, err, `unsupported condition name "". This must be one of [ID/Name/Tags/Description]`)
+}
@@ -0,0 +1,73 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultMaxIdleConnsPerHost はhttp_client.max_idle_conns_per_hostのデフォルト値。
+	// http.DefaultTransportの2では、ゾーンをまたいで並行にapplyすると接続の確立を繰り返すため大きくする
+	DefaultMaxIdleConnsPerHost = 16
+	// DefaultIdleConnTimeout はhttp_client.idle_conn_timeoutのデフォルト値
+	DefaultIdleConnTimeout = 90 * time.Second
+)
+
+// HTTPClientConfig はAPIのホストごとのhttp.Transportの設定(http_clientブロック)。0の場合はデフォルト値を利用する
+type HTTPClientConfig struct {
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int // 0の場合は制限しない
+	IdleConnTimeout     time.Duration
+}
+
+const serviceHTTPTransport = "http-transport"
+
+// hostTransports はAPIのホストごとに、HTTPClientConfigで調整したhttp.Transportを振り分ける。
+// ゾーンごとのAPIルートURLなどで複数のホストを利用する場合に、ホストごとの接続数の上限が他のホストへの並行リクエストを妨げないようにする。
+// 同じホストのゾーン同士は1つのhttp.Transportを共有する。生成したhttp.TransportはserviceClientsでホストごとに保持する
+type hostTransports struct {
+	config     HTTPClientConfig
+	transports serviceClients
+}
+
+func (t *hostTransports) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.transport(req.URL.Host).RoundTrip(req)
+}
+
+// transport はhostへのリクエストに利用するhttp.Transportを返す。未生成の場合は生成する
+func (t *hostTransports) transport(host string) *http.Transport {
+	transport, _ := serviceClient(&t.transports, serviceHTTPTransport, host, func() (*http.Transport, error) {
+		return t.newTransport(), nil
+	})
+	return transport
+}
+
+func (t *hostTransports) newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	if t.config.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = t.config.MaxIdleConnsPerHost
+	}
+	// 1つのホストのみを扱うため、全体のアイドル接続数の上限はホストごとの上限にあわせる
+	transport.MaxIdleConns = max(transport.MaxIdleConns, transport.MaxIdleConnsPerHost)
+	transport.MaxConnsPerHost = t.config.MaxConnsPerHost
+	transport.IdleConnTimeout = DefaultIdleConnTimeout
+	if t.config.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = t.config.IdleConnTimeout
+	}
+	return transport
+}
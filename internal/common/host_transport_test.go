@@ -0,0 +1,106 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_NewClient_hostTransports(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	})
+	server1 := httptest.NewServer(handler)
+	defer server1.Close()
+	server2 := httptest.NewServer(handler)
+	defer server2.Close()
+
+	client, err := (&Config{
+		AccessToken:       "token",
+		AccessTokenSecret: "secret",
+		APIRootURL:        server1.URL,
+		HTTPClient:        HTTPClientConfig{MaxIdleConnsPerHost: 4, MaxConnsPerHost: 8, IdleConnTimeout: 30 * time.Second},
+	}).NewClient()
+	require.NoError(t, err)
+
+	var transports *hostTransports
+	for rt := client.transport; rt != nil; rt = innerTransport(rt) {
+		if ht, ok := rt.(*hostTransports); ok {
+			transports = ht
+		}
+	}
+	require.NotNil(t, transports, "hostTransports must be installed without HTTPTransport")
+
+	hc := newHTTPClient(client.transport)
+	for _, u := range []string{
+		server1.URL + "/is1a/api/cloud/1.1/zone",
+		server1.URL + "/tk1a/api/cloud/1.1/zone",
+		server2.URL + "/is1a/api/cloud/1.1/zone",
+	} {
+		resp, err := hc.Get(u)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	host := func(s *httptest.Server) string {
+		u, err := url.Parse(s.URL)
+		require.NoError(t, err)
+		return u.Host
+	}
+	// 同じホストのゾーン同士は共有し、ホストごとに別のhttp.Transportを利用する
+	assert.Len(t, transports.transports.entries, 2)
+	t1, t2 := transports.transport(host(server1)), transports.transport(host(server2))
+	assert.NotSame(t, t1, t2)
+	assert.Same(t, t1, transports.transport(host(server1)))
+
+	// http_clientの設定はホストごとのhttp.Transportに反映される
+	for _, tr := range []*http.Transport{t1, t2} {
+		assert.Equal(t, 4, tr.MaxIdleConnsPerHost)
+		assert.Equal(t, 8, tr.MaxConnsPerHost)
+		assert.Equal(t, 30*time.Second, tr.IdleConnTimeout)
+	}
+}
+
+func TestHostTransports_defaults(t *testing.T) {
+	tr := (&hostTransports{}).transport("secure.sakura.ad.jp")
+	assert.Equal(t, DefaultMaxIdleConnsPerHost, tr.MaxIdleConnsPerHost)
+	assert.Zero(t, tr.MaxConnsPerHost)
+	assert.Equal(t, DefaultIdleConnTimeout, tr.IdleConnTimeout)
+	// http.DefaultTransportは書き換えない
+	assert.NotSame(t, http.DefaultTransport, tr)
+	assert.Zero(t, http.DefaultTransport.(*http.Transport).MaxIdleConnsPerHost)
+}
+
+func TestConfig_NewClient_httpTransportOverridesHostTransports(t *testing.T) {
+	client, err := (&Config{
+		AccessToken:       "token",
+		AccessTokenSecret: "secret",
+		HTTPTransport:     &listTransport{},
+	}).NewClient()
+	require.NoError(t, err)
+
+	for rt := client.transport; rt != nil; rt = innerTransport(rt) {
+		_, ok := rt.(*hostTransports)
+		require.False(t, ok, "hostTransports must not be installed with HTTPTransport")
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	client "github.com/sacloud/api-client-go"
+	sacloudhttp "github.com/sacloud/go-http"
+	"github.com/sacloud/iaas-api-go"
+)
+
+// iaasCaller はiaas.Clientと同様にIaaSのAPIを呼び出すiaas.APICaller。
+// iaas.ClientはOptionsをMergeOptionsで複製する際に0以下の待機時間をデフォルト値(1秒〜64秒)に置き換えるため、
+// retryWaiterの待機に加えてgo-httpのバックオフでも待機してしまう。このためgo-httpのバックオフを無効にしたdoerを利用する
+type iaasCaller struct {
+	doer client.HttpRequestDoer
+}
+
+func newIaaSCaller(opts *client.Options) *iaasCaller {
+	return &iaasCaller{doer: withoutBackoff(client.NewFactory(opts).NewHttpRequestDoer())}
+}
+
+// withoutBackoff はgo-httpのバックオフを無効にしたdoerを返す。
+// リトライの待機はretryBudgeterが決めた時間だけretryWaiterが行う
+func withoutBackoff(doer client.HttpRequestDoer) client.HttpRequestDoer {
+	hc, ok := doer.(*sacloudhttp.Client)
+	if !ok {
+		return doer
+	}
+	cd := &copyingDoer{client: *hc}
+	// go-httpは0の待機時間をデフォルト値に置き換えるため負の値を指定する。負の値ではバックオフの待機時間は0以下となる
+	cd.client.RetryWaitMin = -time.Second
+	cd.client.RetryWaitMax = -time.Second
+	return cd
+}
+
+// Do はiaas.Client.Doと同様にAPIを呼び出し、エラーの場合はiaas.APIErrorを返す
+func (c *iaasCaller) Do(ctx context.Context, method, uri string, body interface{}) ([]byte, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyJSON, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		if method == http.MethodGet {
+			uri = fmt.Sprintf("%s?%s", uri, bodyJSON)
+		} else {
+			bodyReader = bytes.NewReader(bodyJSON)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, uri, bodyReader)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted, http.StatusNoContent:
+		return data, nil
+	}
+	errResponse := &iaas.APIErrorResponse{}
+	if err := json.Unmarshal(data, errResponse); err != nil {
+		return nil, fmt.Errorf("error in response: %s", string(data))
+	}
+	return nil, iaas.NewAPIError(req.Method, req.URL, resp.StatusCode, errResponse)
+}
@@ -0,0 +1,90 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	client "github.com/sacloud/api-client-go"
+	"github.com/sacloud/iaas-api-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIaaSCaller(t *testing.T) {
+	newCaller := func(responses ...*http.Response) (*iaasCaller, *[]*http.Request) {
+		var requests []*http.Request
+		transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, req)
+			resp := responses[0]
+			if len(responses) > 1 {
+				responses = responses[1:]
+			}
+			resp.Request = req
+			return resp, nil
+		})
+		return newIaaSCaller(&client.Options{
+			HttpClient: newHTTPClient(transport),
+			RetryMax:   1,
+			CheckRetryFunc: func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+				return resp != nil && resp.StatusCode == http.StatusServiceUnavailable, err
+			},
+		}), &requests
+	}
+	response := func(code int, body string) *http.Response {
+		return &http.Response{StatusCode: code, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}
+	}
+
+	t.Run("retries without the go-http backoff", func(t *testing.T) {
+		caller, requests := newCaller(response(http.StatusServiceUnavailable, `{}`), response(http.StatusOK, `{"ID":"1"}`))
+
+		// go-httpのデフォルトのバックオフ(1秒)で待機しないこと
+		start := time.Now()
+		data, err := caller.Do(context.Background(), http.MethodPut, "http://example.com/server/1", map[string]string{"Name": "foo"})
+		require.NoError(t, err)
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.JSONEq(t, `{"ID":"1"}`, string(data))
+		assert.Len(t, *requests, 2)
+	})
+
+	t.Run("GET sends the body as the query", func(t *testing.T) {
+		caller, requests := newCaller(response(http.StatusOK, `{}`))
+
+		_, err := caller.Do(context.Background(), http.MethodGet, "http://example.com/server", map[string]int{"Count": 1})
+		require.NoError(t, err)
+		require.Len(t, *requests, 1)
+		assert.Equal(t, `{"Count":1}`, (*requests)[0].URL.RawQuery)
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		caller, _ := newCaller(response(http.StatusNotFound, `{"is_fatal":true,"status":"404 Not Found","error_code":"not_found","error_msg":"not found"}`))
+
+		_, err := caller.Do(context.Background(), http.MethodGet, "http://example.com/server/1", nil)
+		require.Error(t, err)
+		assert.True(t, iaas.IsNotFoundError(err))
+	})
+
+	t.Run("unexpected response", func(t *testing.T) {
+		caller, _ := newCaller(response(http.StatusBadGateway, `<html></html>`))
+
+		_, err := caller.Do(context.Background(), http.MethodGet, "http://example.com/server/1", nil)
+		assert.EqualError(t, err, "error in response: <html></html>")
+	})
+}
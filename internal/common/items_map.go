@@ -0,0 +1,97 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/desc"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/filter"
+)
+
+// 複数のリソースを返すデータソースは、listに加えてIDと名前をキーとするmapを出力する。
+// mapはfor_eachにそのまま渡すことができ、listのように一覧APIの返す順序の変化の影響を受けない。
+// 出力の属性名は<リソース>s_by_id/<リソース>s_by_name(例: keys_by_id/keys_by_name)とする
+
+// SchemaDataSourceItemsByID はIDをキーとするmapの出力のスキーマを返す
+func SchemaDataSourceItemsByID(name string, nested schema.NestedAttributeObject) schema.Attribute {
+	return schema.MapNestedAttribute{
+		Computed:     true,
+		NestedObject: nested,
+		Description:  desc.Sprintf("A map of the %ss keyed by id.", name),
+	}
+}
+
+// SchemaDataSourceItemsByName は名前をキーとするmapの出力のスキーマを返す
+func SchemaDataSourceItemsByName(name string, nested schema.NestedAttributeObject) schema.Attribute {
+	return schema.MapNestedAttribute{
+		Computed:     true,
+		NestedObject: nested,
+		Description: desc.Sprintf(
+			"A map of the %ss keyed by name. An error is returned when more than one %s has the same name. Use the map keyed by id in that case",
+			name, name,
+		),
+	}
+}
+
+// DuplicateNameError は名前をキーとするmapを作成する際に、同じ名前のリソースが複数存在したことを表す
+type DuplicateNameError struct {
+	Kind string
+	Name string
+	IDs  []string // 同じ名前のリソースのID。IDの昇順
+}
+
+func (e *DuplicateNameError) Error() string {
+	return fmt.Sprintf("multiple %ss have the same name %q (ids: %s). Use the map keyed by id instead, or narrow down the condition so that the names are unique",
+		e.Kind, e.Name, strings.Join(e.IDs, ", "))
+}
+
+// ItemsByID はitemsをIDをキーとするmapに変換する
+func ItemsByID[T any](items []T, attributes func(T) filter.Attributes) map[string]T {
+	m := make(map[string]T, len(items))
+	for _, v := range items {
+		m[attributes(v).ID] = v
+	}
+	return m
+}
+
+// ItemsByName はitemsを名前をキーとするmapに変換する。
+// 同じ名前のリソースが複数存在する場合は、一覧の順序によらず同じエラーとなるよう、辞書順で最初の名前について*DuplicateNameErrorを返す
+func ItemsByName[T any](items []T, attributes func(T) filter.Attributes, kind string) (map[string]T, error) {
+	m := make(map[string]T, len(items))
+	ids := make(map[string][]string, len(items))
+	for _, v := range items {
+		attrs := attributes(v)
+		m[attrs.Name] = v
+		ids[attrs.Name] = append(ids[attrs.Name], attrs.ID)
+	}
+
+	var duplicated []string
+	for name, v := range ids {
+		if len(v) > 1 {
+			duplicated = append(duplicated, name)
+		}
+	}
+	if len(duplicated) == 0 {
+		return m, nil
+	}
+	name := slices.Min(duplicated)
+	dupIDs := ids[name]
+	slices.SortFunc(dupIDs, compareID)
+	return nil, &DuplicateNameError{Kind: kind, Name: name, IDs: dupIDs}
+}
@@ -0,0 +1,109 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/filter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemsByID(t *testing.T) {
+	attributes := func(v filter.Attributes) filter.Attributes { return v }
+	items := []filter.Attributes{
+		{ID: "110000000001", Name: "key-a"},
+		{ID: "110000000002", Name: "key-a"},
+	}
+
+	// 名前が重複していてもIDをキーとするmapは作成できる
+	assert.Equal(t, map[string]filter.Attributes{
+		"110000000001": items[0],
+		"110000000002": items[1],
+	}, ItemsByID(items, attributes))
+	assert.Empty(t, ItemsByID(nil, attributes))
+}
+
+func TestItemsByName(t *testing.T) {
+	attributes := func(v filter.Attributes) filter.Attributes { return v }
+
+	got, err := ItemsByName([]filter.Attributes{
+		{ID: "110000000001", Name: "key-a"},
+		{ID: "110000000002", Name: "Key-a"},
+	}, attributes, "KMS key")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]filter.Attributes{
+		"key-a": {ID: "110000000001", Name: "key-a"},
+		"Key-a": {ID: "110000000002", Name: "Key-a"},
+	}, got)
+
+	items := []filter.Attributes{
+		{ID: "110000000004", Name: "key-b"},
+		{ID: "110000000003", Name: "key-c"},
+		{ID: "99", Name: "key-c"},
+		{ID: "110000000002", Name: "key-b"},
+		{ID: "110000000001", Name: "key-a"},
+	}
+	_, err = ItemsByName(items, attributes, "KMS key")
+	var duplicated *DuplicateNameError
+	require.ErrorAs(t, err, &duplicated)
+	assert.Equal(t, &DuplicateNameError{Kind: "KMS key", Name: "key-b", IDs: []string{"110000000002", "110000000004"}}, duplicated)
+	assert.EqualError(t, err, `multiple KMS keys have the same name "key-b" (ids: 110000000002, 110000000004). Use the map keyed by id instead, or narrow down the condition so that the names are unique`)
+
+	// 一覧の順序が変わっても同じエラーとなる
+	_, err2 := ItemsByName([]filter.Attributes{items[4], items[3], items[2], items[1], items[0]}, attributes, "KMS key")
+	assert.Equal(t, err, err2)
+}
+
+func TestItemsByName_schema(t *testing.T) {
+	ctx := context.Background()
+	type itemModel struct {
+		ID   types.String `tfsdk:"id"`
+		Name types.String `tfsdk:"name"`
+	}
+	nested := schema.NestedAttributeObject{
+		Attributes: map[string]schema.Attribute{
+			"id":   schema.StringAttribute{Computed: true},
+			"name": schema.StringAttribute{Computed: true},
+		},
+	}
+	attributes := func(v itemModel) filter.Attributes {
+		return filter.Attributes{ID: v.ID.ValueString(), Name: v.Name.ValueString()}
+	}
+	items := []itemModel{
+		{ID: types.StringValue("110000000001"), Name: types.StringValue("key-a")},
+		{ID: types.StringValue("110000000002"), Name: types.StringValue("key-b")},
+	}
+
+	byName, err := ItemsByName(items, attributes, "KMS key")
+	require.NoError(t, err)
+	for name, attr := range map[string]schema.Attribute{
+		"keys_by_id":   SchemaDataSourceItemsByID("KMS key", nested),
+		"keys_by_name": SchemaDataSourceItemsByName("KMS key", nested),
+	} {
+		m := ItemsByID(items, attributes)
+		if name == "keys_by_name" {
+			m = byName
+		}
+		elemType := attr.(schema.MapNestedAttribute).NestedObject.Type()
+		v, diags := types.MapValueFrom(ctx, elemType, m)
+		require.False(t, diags.HasError(), diags)
+		assert.Len(t, v.Elements(), 2, name)
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	kms "github.com/sacloud/kms-api-go"
+	kmsapi "github.com/sacloud/kms-api-go/apis/v1"
+	sm "github.com/sacloud/secretmanager-api-go"
+	smapi "github.com/sacloud/secretmanager-api-go/apis/v1"
+)
+
+// listCacheTTL は一覧取得APIの結果をキャッシュする期間。1回のplan/applyの中での重複した一覧取得をまとめることを目的とする
+const listCacheTTL = 30 * time.Second
+
+type listCacheKey struct {
+	service string
+	zone    string
+	scope   string // ボールト内のシークレットの一覧など、親リソースで分かれる一覧の親リソースのID
+}
+
+type listCacheEntry struct {
+	done      chan struct{}
+	items     any
+	truncated bool
+	err       error
+	expires   time.Time
+}
+
+// listCache は名前で検索するデータソースが行う一覧取得の結果を、サービス/ゾーンごとに短時間保持する。
+// 同じ条件の一覧取得が並行して行われた場合は、最初のリクエストの結果を共有する。
+// サービスへの書き込み(作成/更新/削除)を行った場合は、そのサービスのキャッシュを破棄する
+type listCache struct {
+	disabled bool
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[listCacheKey]*listCacheEntry
+}
+
+type listCacheContextKey struct{}
+
+// WithListCache はctxでの一覧取得APIの呼び出しにAPIClientのキャッシュを利用させる。
+// 作成直後のリソースを参照するリソースの処理では古い結果を返さないよう、データソースのReadでのみ利用すること
+func WithListCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, listCacheContextKey{}, true)
+}
+
+// withoutListCache はWithListCacheを打ち消したcontextを返す
+func withoutListCache(ctx context.Context) context.Context {
+	if !usesListCache(ctx) {
+		return ctx
+	}
+	return context.WithValue(ctx, listCacheContextKey{}, false)
+}
+
+func usesListCache(ctx context.Context) bool {
+	v, _ := ctx.Value(listCacheContextKey{}).(bool)
+	return v
+}
+
+func (c *listCache) timeNow() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// invalidate はserviceのキャッシュを破棄する。取得中の一覧の結果も、取得済みの呼び出し元以外には返さない
+func (c *listCache) invalidate(service string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.service == service {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// cachedList はkeyの一覧をキャッシュから返す。キャッシュが無いか期限切れの場合はfetchで取得する。
+// エラーはキャッシュしない。呼び出し元が結果を並べ替えてもキャッシュに影響しないよう、複製して返す
+func cachedList[T any](ctx context.Context, c *listCache, key listCacheKey, fetch func(ctx context.Context) ([]T, bool, error)) ([]T, bool, error) {
+	if c.disabled || !usesListCache(ctx) {
+		return fetch(ctx)
+	}
+
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = map[listCacheKey]*listCacheEntry{}
+	}
+	e, ok := c.entries[key]
+	if ok {
+		select {
+		case <-e.done:
+			if c.timeNow().After(e.expires) {
+				ok = false
+			}
+		default:
+		}
+	}
+	if !ok {
+		e = &listCacheEntry{done: make(chan struct{})}
+		c.entries[key] = e
+		c.mu.Unlock()
+
+		e.items, e.truncated, e.err = fetch(ctx)
+		c.mu.Lock()
+		e.expires = c.timeNow().Add(listCacheTTL)
+		if e.err != nil && c.entries[key] == e {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+		close(e.done)
+	} else {
+		c.mu.Unlock()
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+		// 最初に取得を始めた呼び出し元のcontextがキャンセルされた場合は、自身のcontextで取得し直す
+		if isContextError(e.err) && ctx.Err() == nil {
+			return fetch(ctx)
+		}
+	}
+
+	if e.err != nil {
+		return nil, false, e.err
+	}
+	return slices.Clone(e.items.([]T)), e.truncated, nil
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// cacheInvalidatingKeyOp はKMSのキーへの書き込み後に一覧のキャッシュを破棄する
+type cacheInvalidatingKeyOp struct {
+	kms.KeyAPI
+	cache *listCache
+}
+
+func (o *cacheInvalidatingKeyOp) Create(ctx context.Context, request kmsapi.CreateKey) (*kmsapi.CreateKey, error) {
+	defer o.cache.invalidate(serviceKMS)
+	return o.KeyAPI.Create(ctx, request)
+}
+
+func (o *cacheInvalidatingKeyOp) Update(ctx context.Context, id string, request kmsapi.Key) (*kmsapi.Key, error) {
+	defer o.cache.invalidate(serviceKMS)
+	return o.KeyAPI.Update(ctx, id, request)
+}
+
+func (o *cacheInvalidatingKeyOp) Delete(ctx context.Context, id string) error {
+	defer o.cache.invalidate(serviceKMS)
+	return o.KeyAPI.Delete(ctx, id)
+}
+
+// cacheInvalidatingVaultOp はシークレットマネージャのボールトへの書き込み後に一覧のキャッシュを破棄する
+type cacheInvalidatingVaultOp struct {
+	sm.VaultAPI
+	cache *listCache
+}
+
+func (o *cacheInvalidatingVaultOp) Create(ctx context.Context, request smapi.CreateVault) (*smapi.CreateVault, error) {
+	defer o.cache.invalidate(serviceSecretManager)
+	return o.VaultAPI.Create(ctx, request)
+}
+
+func (o *cacheInvalidatingVaultOp) Update(ctx context.Context, id string, request smapi.Vault) (*smapi.Vault, error) {
+	defer o.cache.invalidate(serviceSecretManager)
+	return o.VaultAPI.Update(ctx, id, request)
+}
+
+func (o *cacheInvalidatingVaultOp) Delete(ctx context.Context, id string) error {
+	defer o.cache.invalidate(serviceSecretManager)
+	return o.VaultAPI.Delete(ctx, id)
+}
+
+// cacheInvalidatingSecretOp はシークレットマネージャのシークレットへの書き込み後に一覧のキャッシュを破棄する
+type cacheInvalidatingSecretOp struct {
+	sm.SecretAPI
+	cache *listCache
+}
+
+func (o *cacheInvalidatingSecretOp) Create(ctx context.Context, request smapi.CreateSecret) (*smapi.Secret, error) {
+	defer o.cache.invalidate(serviceSecretManager)
+	return o.SecretAPI.Create(ctx, request)
+}
+
+func (o *cacheInvalidatingSecretOp) Update(ctx context.Context, request smapi.CreateSecret) (*smapi.Secret, error) {
+	defer o.cache.invalidate(serviceSecretManager)
+	return o.SecretAPI.Update(ctx, request)
+}
+
+func (o *cacheInvalidatingSecretOp) Delete(ctx context.Context, request smapi.DeleteSecret) error {
+	defer o.cache.invalidate(serviceSecretManager)
+	return o.SecretAPI.Delete(ctx, request)
+}
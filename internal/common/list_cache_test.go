@@ -0,0 +1,171 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCache(t *testing.T) {
+	t.Run("concurrent data source reads share one list call", func(t *testing.T) {
+		transport := &listTransport{}
+		client := newServiceClientTestClient(t, transport)
+		ctx := WithListCache(context.Background())
+
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _, err := client.KMSKeyList(ctx)
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+		assert.Len(t, transport.paths, 1)
+	})
+
+	t.Run("services are cached separately", func(t *testing.T) {
+		transport := &listTransport{}
+		client := newServiceClientTestClient(t, transport)
+		ctx := WithListCache(context.Background())
+
+		_, _, err := client.KMSKeyList(ctx)
+		require.NoError(t, err)
+		_, _, err = client.SecretManagerVaultList(ctx)
+		require.NoError(t, err)
+		assert.Len(t, transport.paths, 2)
+	})
+
+	t.Run("not used without WithListCache", func(t *testing.T) {
+		transport := &listTransport{}
+		client := newServiceClientTestClient(t, transport)
+
+		for range 2 {
+			_, _, err := client.KMSKeyList(context.Background())
+			require.NoError(t, err)
+		}
+		assert.Len(t, transport.paths, 2)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		transport := &listTransport{}
+		client, err := (&Config{
+			AccessToken:         "token",
+			AccessTokenSecret:   "secret",
+			APIRootURL:          "http://sakura.example.com",
+			APIRequestRateLimit: 100,
+			HTTPTransport:       transport,
+			DisableReadCache:    true,
+		}).NewClient()
+		require.NoError(t, err)
+		ctx := WithListCache(context.Background())
+
+		for range 2 {
+			_, _, err := client.KMSKeyList(ctx)
+			require.NoError(t, err)
+		}
+		assert.Len(t, transport.paths, 2)
+	})
+
+	t.Run("expires after TTL", func(t *testing.T) {
+		transport := &listTransport{}
+		client := newServiceClientTestClient(t, transport)
+		now := time.Now()
+		client.listCache.now = func() time.Time { return now }
+		ctx := WithListCache(context.Background())
+
+		_, _, err := client.KMSKeyList(ctx)
+		require.NoError(t, err)
+		now = now.Add(listCacheTTL - time.Second)
+		_, _, err = client.KMSKeyList(ctx)
+		require.NoError(t, err)
+		assert.Len(t, transport.paths, 1)
+
+		now = now.Add(2 * time.Second)
+		_, _, err = client.KMSKeyList(ctx)
+		require.NoError(t, err)
+		assert.Len(t, transport.paths, 2)
+	})
+
+	t.Run("invalidated by writes to the service", func(t *testing.T) {
+		transport := &listTransport{}
+		client := newServiceClientTestClient(t, transport)
+		ctx := WithListCache(context.Background())
+
+		_, _, err := client.KMSKeyList(ctx)
+		require.NoError(t, err)
+		_, _, err = client.SecretManagerVaultList(ctx)
+		require.NoError(t, err)
+
+		keyOp, err := client.KMSKeyOp()
+		require.NoError(t, err)
+		_ = keyOp.Delete(context.Background(), "123456789012") // 結果に関わらずキャッシュは破棄される
+
+		_, _, err = client.KMSKeyList(ctx)
+		require.NoError(t, err)
+		_, _, err = client.SecretManagerVaultList(ctx)
+		require.NoError(t, err)
+		// KMSの一覧のみ取得し直す
+		assert.Len(t, transport.paths, 4)
+		assert.Equal(t, transport.paths[0], transport.paths[3])
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		var c listCache
+		var calls atomic.Int32
+		ctx := WithListCache(context.Background())
+		fetch := func(context.Context) ([]int, bool, error) {
+			if calls.Add(1) == 1 {
+				return nil, false, errors.New("temporary")
+			}
+			return []int{1, 2}, false, nil
+		}
+		key := listCacheKey{service: serviceKMS}
+
+		_, _, err := cachedList(ctx, &c, key, fetch)
+		require.Error(t, err)
+		items, _, err := cachedList(ctx, &c, key, fetch)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, items)
+		_, _, err = cachedList(ctx, &c, key, fetch)
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, calls.Load())
+	})
+
+	t.Run("returned items are copies", func(t *testing.T) {
+		var c listCache
+		ctx := WithListCache(context.Background())
+		fetch := func(context.Context) ([]int, bool, error) {
+			return []int{1, 2}, false, nil
+		}
+		key := listCacheKey{service: serviceKMS}
+
+		items, _, err := cachedList(ctx, &c, key, fetch)
+		require.NoError(t, err)
+		items[0] = 100
+		items, _, err = cachedList(ctx, &c, key, fetch)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, items)
+	})
+}
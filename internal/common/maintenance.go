@@ -0,0 +1,160 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// maintenanceRetryInterval はメンテナンス中のAPIをリトライする間隔。Retry-Afterがあればそちらを優先する
+	maintenanceRetryInterval = 30 * time.Second
+	// maintenanceLogInterval はメンテナンス中であることをINFOログに出力する間隔
+	maintenanceLogInterval = time.Minute
+	// maintenanceMaxDuration はリクエストのcontextに期限がない場合にメンテナンスの終了を待つ最大時間
+	maintenanceMaxDuration = Timeout20min
+)
+
+// IsMaintenanceResponse はAPIのメンテナンス中を示す503レスポンスかを、エラーレスポンスのボディから判定する
+func IsMaintenanceResponse(statusCode int, body []byte) bool {
+	if statusCode != http.StatusServiceUnavailable {
+		return false
+	}
+	code, message, _ := parseErrorBody(body)
+	for _, s := range []string{code, message} {
+		if strings.Contains(strings.ToLower(s), "maintenance") || strings.Contains(s, "メンテナンス") {
+			return true
+		}
+	}
+	return false
+}
+
+// maintenanceRetrier はAPIのメンテナンス中の503レスポンスを、リクエストのcontextの期限(操作のタイムアウト)まで待ってリトライするhttp.RoundTripper。
+// APIクライアント(go-http)のリトライはretry_max回で終わるため、数分続くメンテナンスの間に長時間のapplyが途中で失敗しないようにする
+type maintenanceRetrier struct {
+	transport   http.RoundTripper
+	interval    time.Duration
+	logInterval time.Duration
+	maxDuration time.Duration
+	clock       clock
+	now         func() time.Time
+}
+
+func (m *maintenanceRetrier) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := m.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	c := m.clock
+	if c == nil {
+		c = realClock{}
+	}
+	now := time.Now
+	if m.now != nil {
+		now = m.now
+	}
+	interval := m.interval
+	if interval <= 0 {
+		interval = maintenanceRetryInterval
+	}
+	logInterval := m.logInterval
+	if logInterval <= 0 {
+		logInterval = maintenanceLogInterval
+	}
+
+	ctx := req.Context()
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		maxDuration := m.maxDuration
+		if maxDuration <= 0 {
+			maxDuration = maintenanceMaxDuration
+		}
+		deadline = now().Add(maxDuration)
+	}
+	start := now()
+	var lastLogged time.Time
+
+	for {
+		resp, err := transport.RoundTrip(req)
+		if err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+			return resp, err
+		}
+
+		// 待機中にmax_concurrent_api_requestsの枠とコネクションを占有しないよう、ボディは読み切ってから閉じる
+		body, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close() //nolint:errcheck
+		if readErr != nil {
+			return nil, readErr
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		if !IsMaintenanceResponse(resp.StatusCode, body) {
+			return resp, nil
+		}
+		// ボディを再送できないリクエストはリトライできないため、通常のリトライに任せる
+		if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+			return resp, nil
+		}
+
+		wait := interval
+		if retryAfter, ok := ParseRetryAfter(resp.Header.Get(RetryAfterHeader), now()); ok && retryAfter > 0 {
+			wait = retryAfter
+		}
+		remaining := deadline.Sub(now())
+		if remaining <= 0 {
+			return resp, nil
+		}
+		wait = min(wait, remaining)
+
+		// 最初のメンテナンスのレスポンスは直ちにログに出力し、以降はlogIntervalごとに出力する
+		if elapsed := now().Sub(start); lastLogged.IsZero() || now().Sub(lastLogged) >= logInterval {
+			tflog.Info(ctx, fmt.Sprintf("SakuraCloud API is under maintenance, retrying (%s elapsed)…", formatElapsed(elapsed)), map[string]any{
+				"endpoint": req.Method + " " + req.URL.Path,
+				"wait":     wait.String(),
+			})
+			lastLogged = now()
+		}
+
+		select {
+		case <-ctx.Done():
+			// 最後のレスポンスを返し、APIクライアントにcontextのエラーとして扱わせる
+			return resp, nil
+		case <-c.After(wait):
+		}
+
+		if req.GetBody != nil {
+			newBody, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(ctx)
+			req.Body = newBody
+		}
+	}
+}
+
+// formatElapsed は経過時間を"3m"や"45s"のようなログ向けの短い形式で返す
+func formatElapsed(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d/time.Second))
+	}
+	return fmt.Sprintf("%dm", int(d/time.Minute))
+}
@@ -0,0 +1,275 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const maintenanceBody = `{"is_fatal":true,"serial":"x","status":"503 Service Unavailable","error_code":"maintenance","error_msg":"現在メンテナンス中です"}`
+
+// maintenanceTransport は指定回数だけメンテナンス中の503を返した後、成功レスポンスを返す
+type maintenanceTransport struct {
+	mu          sync.Mutex
+	unavailable int
+	body        string
+	requests    int
+	bodies      []string
+}
+
+func (t *maintenanceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.requests++
+	if req.Body != nil {
+		b, _ := io.ReadAll(req.Body)
+		t.bodies = append(t.bodies, string(b))
+	}
+	if t.requests <= t.unavailable {
+		body := t.body
+		if body == "" {
+			body = maintenanceBody
+		}
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"Count":0,"From":0,"Total":0,"Keys":[]}`)),
+		Request:    req,
+	}, nil
+}
+
+// advancingClock は待機した分だけ現在時刻を進める時計
+type advancingClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits []time.Duration
+}
+
+func (c *advancingClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.waits = append(c.waits, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func (c *advancingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// gatedClock はAfterが呼ばれたことをwaitingに通知し、releaseに値が送られるまで待機させる時計
+type gatedClock struct {
+	waiting chan struct{}
+	release chan time.Time
+}
+
+func (c gatedClock) After(time.Duration) <-chan time.Time {
+	c.waiting <- struct{}{}
+	return c.release
+}
+
+func TestIsMaintenanceResponse(t *testing.T) {
+	assert.True(t, IsMaintenanceResponse(http.StatusServiceUnavailable, []byte(maintenanceBody)))
+	assert.True(t, IsMaintenanceResponse(http.StatusServiceUnavailable, []byte(`{"message":"API is under Maintenance"}`)))
+	assert.False(t, IsMaintenanceResponse(http.StatusServiceUnavailable, []byte(`{"error_code":"service_unavailable"}`)))
+	assert.False(t, IsMaintenanceResponse(http.StatusServiceUnavailable, nil))
+	assert.False(t, IsMaintenanceResponse(http.StatusInternalServerError, []byte(maintenanceBody)))
+}
+
+func TestMaintenanceRetrier(t *testing.T) {
+	// contextの期限は実時間で判定されるため、現在時刻から時計を進める
+	start := time.Now()
+
+	t.Run("retries until the maintenance ends", func(t *testing.T) {
+		transport := &maintenanceTransport{unavailable: 10}
+		clock := &advancingClock{now: start}
+		m := &maintenanceRetrier{transport: transport, clock: clock, now: clock.Now}
+
+		ctx, cancel := context.WithDeadline(context.Background(), start.Add(20*time.Minute))
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/keys", nil)
+		require.NoError(t, err)
+
+		resp, err := m.RoundTrip(req)
+		require.NoError(t, err)
+		defer resp.Body.Close() //nolint:errcheck
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 11, transport.requests)
+		assert.Len(t, clock.waits, 10)
+		// retry_maxの回数に関わらず、5分間のメンテナンスを待つ
+		assert.Equal(t, 5*time.Minute, clock.Now().Sub(start))
+	})
+
+	t.Run("resends the request body", func(t *testing.T) {
+		transport := &maintenanceTransport{unavailable: 2}
+		clock := &advancingClock{now: start}
+		m := &maintenanceRetrier{transport: transport, clock: clock, now: clock.Now}
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "http://example.com/keys", strings.NewReader(`{"Name":"foo"}`))
+		require.NoError(t, err)
+
+		resp, err := m.RoundTrip(req)
+		require.NoError(t, err)
+		defer resp.Body.Close() //nolint:errcheck
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{`{"Name":"foo"}`, `{"Name":"foo"}`, `{"Name":"foo"}`}, transport.bodies)
+	})
+
+	t.Run("gives up at the context deadline", func(t *testing.T) {
+		transport := &maintenanceTransport{unavailable: 100}
+		clock := &advancingClock{now: start}
+		m := &maintenanceRetrier{transport: transport, clock: clock, now: clock.Now}
+
+		ctx, cancel := context.WithDeadline(context.Background(), start.Add(time.Minute+10*time.Second))
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/keys", nil)
+		require.NoError(t, err)
+
+		resp, err := m.RoundTrip(req)
+		require.NoError(t, err)
+		defer resp.Body.Close() //nolint:errcheck
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, []time.Duration{30 * time.Second, 30 * time.Second, 10 * time.Second}, clock.waits)
+
+		// 呼び出し元がエラーの詳細を読めるよう、ボディは読み込める状態で返す
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, maintenanceBody, string(body))
+	})
+
+	t.Run("honors context cancellation", func(t *testing.T) {
+		transport := &maintenanceTransport{unavailable: 100}
+		m := &maintenanceRetrier{transport: transport, clock: blockingClock{}}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/keys", nil)
+		require.NoError(t, err)
+		time.AfterFunc(10*time.Millisecond, cancel)
+
+		resp, err := m.RoundTrip(req)
+		require.NoError(t, err)
+		defer resp.Body.Close() //nolint:errcheck
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, 1, transport.requests)
+	})
+
+	t.Run("logs the first maintenance response immediately", func(t *testing.T) {
+		transport := &maintenanceTransport{unavailable: 1}
+		clock := &advancingClock{now: start}
+		// リクエストの処理にかかる時間の分、呼び出すたびに時刻を進める
+		var drift time.Duration
+		now := func() time.Time {
+			drift += 100 * time.Millisecond
+			return clock.Now().Add(drift)
+		}
+		m := &maintenanceRetrier{transport: transport, clock: clock, now: now}
+
+		var logs bytes.Buffer
+		ctx := tflogtest.RootLogger(context.Background(), &logs)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/keys", nil)
+		require.NoError(t, err)
+
+		resp, err := m.RoundTrip(req)
+		require.NoError(t, err)
+		defer resp.Body.Close() //nolint:errcheck
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 1, strings.Count(logs.String(), "SakuraCloud API is under maintenance"))
+	})
+
+	t.Run("does not hold a concurrency slot while waiting", func(t *testing.T) {
+		transport := (&Config{
+			APIRequestRateLimit:      100,
+			MaxConcurrentAPIRequests: 1,
+			HTTPTransport:            &maintenanceTransport{unavailable: 1},
+		}).newTransport()
+		clock := gatedClock{waiting: make(chan struct{}, 1), release: make(chan time.Time)}
+		for rt := transport; rt != nil; rt = innerTransport(rt) {
+			if m, ok := rt.(*maintenanceRetrier); ok {
+				m.clock = clock
+			}
+		}
+
+		done := make(chan *http.Response, 1)
+		go func() {
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://sakura.example.com/keys", nil)
+			assert.NoError(t, err)
+			resp, err := transport.RoundTrip(req)
+			assert.NoError(t, err)
+			done <- resp
+		}()
+		<-clock.waiting
+
+		// メンテナンスの終了を待っている間も、他のリクエストは枠を取得できる
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://sakura.example.com/keys", nil)
+		require.NoError(t, err)
+		resp, err := transport.RoundTrip(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+
+		clock.release <- time.Now()
+		resp = <-done
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, resp.Body.Close())
+	})
+
+	t.Run("other 503 is left to the API client", func(t *testing.T) {
+		transport := &maintenanceTransport{unavailable: 1, body: `{"error_code":"service_unavailable"}`}
+		clock := &advancingClock{now: start}
+		m := &maintenanceRetrier{transport: transport, clock: clock, now: clock.Now}
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://example.com/keys", nil)
+		require.NoError(t, err)
+
+		resp, err := m.RoundTrip(req)
+		require.NoError(t, err)
+		defer resp.Body.Close() //nolint:errcheck
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, 1, transport.requests)
+		assert.Empty(t, clock.waits)
+	})
+}
+
+func TestFormatElapsed(t *testing.T) {
+	assert.Equal(t, "0s", formatElapsed(0))
+	assert.Equal(t, "45s", formatElapsed(45*time.Second))
+	assert.Equal(t, "3m", formatElapsed(3*time.Minute+20*time.Second))
+}
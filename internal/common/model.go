@@ -14,18 +14,47 @@
 
 package common
 
-import "github.com/hashicorp/terraform-plugin-framework/types"
+import (
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
 
 type SakuraBaseModel struct {
-	ID          types.String `tfsdk:"id"`
+	ID          SakuraID     `tfsdk:"id"`
 	Name        types.String `tfsdk:"name"`
 	Description types.String `tfsdk:"description"`
 	Tags        types.Set    `tfsdk:"tags"`
 }
 
+// GlobalZone はゾーンに依存しないリソースのzoneの値
+const GlobalZone = "global"
+
+// SakuraGlobalZoneModel はSchemaResourceGlobalZoneで定義したzone属性を持つモデル
+type SakuraGlobalZoneModel struct {
+	Zone types.String `tfsdk:"zone"`
+}
+
+// UpdateGlobalZoneState はzoneにGlobalZoneを設定する。zoneを持たない以前のstateもRead時に補完される
+func (model *SakuraGlobalZoneModel) UpdateGlobalZoneState() {
+	model.Zone = types.StringValue(GlobalZone)
+}
+
 func (model *SakuraBaseModel) UpdateBaseState(id string, name string, desc string, tags []string) {
-	model.ID = types.StringValue(id)
-	model.Name = types.StringValue(name)
+	model.ID = NewSakuraIDValue(id)
+	model.Name = NameFromAPI(model.Name, name)
 	model.Description = types.StringValue(desc)
-	model.Tags = StringsToTset(tags)
+	model.Tags = FlattenTags(tags)
+}
+
+// HasChangeFrom はAPIに送られる基本属性(name/description/tags)がstateから変更されているかを返す。
+// descriptionのnullと空文字、tagsのnullと空のsetはAPI上は同じ値になるため変更とみなさない
+func (model *SakuraBaseModel) HasChangeFrom(state *SakuraBaseModel) bool {
+	if model.Name.IsUnknown() || model.Description.IsUnknown() || model.Tags.IsUnknown() {
+		return true
+	}
+	if model.Name.ValueString() != state.Name.ValueString() || model.Description.ValueString() != state.Description.ValueString() {
+		return true
+	}
+	return !slices.Equal(ExpandTags(model.Tags), ExpandTags(state.Tags))
 }
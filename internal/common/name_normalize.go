@@ -0,0 +1,85 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// NormalizeName はAPIが名前を保存する際の正規化を再現する。
+// 前後の空白(全角スペースを含む)を取り除き、制御文字を除いた上で、連続する空白を1つの半角スペースにまとめる
+func NormalizeName(name string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range strings.TrimFunc(name, unicode.IsSpace) {
+		switch {
+		case unicode.IsSpace(r):
+			space = true
+			continue
+		case unicode.IsControl(r):
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// NameFromAPI はAPIから取得した名前をstateに設定する値に変換する。
+// priorを正規化した値がAPIの値と一致する場合は、次回のplanで差分とならないようにpriorをそのまま返す
+func NameFromAPI(prior types.String, name string) types.String {
+	if !prior.IsNull() && !prior.IsUnknown() && prior.ValueString() != name && NormalizeName(prior.ValueString()) == name {
+		return prior
+	}
+	return types.StringValue(name)
+}
+
+// nameNormalizationValidator はAPIによって正規化される名前を警告するバリデータ
+type nameNormalizationValidator struct{}
+
+var _ validator.String = nameNormalizationValidator{}
+
+// WarnNameNormalization は前後の空白や制御文字など、APIが保存時に取り除く文字を含む名前を警告するバリデータを返す
+func WarnNameNormalization() validator.String {
+	return nameNormalizationValidator{}
+}
+
+func (v nameNormalizationValidator) Description(_ context.Context) string {
+	return "warns if the name contains leading or trailing whitespace or characters that are normalized by the API"
+}
+
+func (v nameNormalizationValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v nameNormalizationValidator) ValidateString(_ context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	name := req.ConfigValue.ValueString()
+	if normalized := NormalizeName(name); normalized != name {
+		resp.Diagnostics.AddAttributeWarning(req.Path, "Name will be normalized",
+			fmt.Sprintf("The API removes leading and trailing whitespace and control characters, and collapses consecutive whitespace. name will be stored as %q", normalized))
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeName(t *testing.T) {
+	cases := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "unchanged", in: "prod-key", want: "prod-key"},
+		{name: "leading and trailing spaces", in: "  prod-key ", want: "prod-key"},
+		{name: "tabs and newlines", in: "\tprod-key\n", want: "prod-key"},
+		{name: "full-width spaces", in: "　prod-key　", want: "prod-key"},
+		{name: "consecutive spaces", in: "prod  \t key", want: "prod key"},
+		{name: "single inner space", in: "prod key", want: "prod key"},
+		{name: "control characters", in: "prod\x00-\x1bkey\x7f", want: "prod-key"},
+		{name: "multibyte", in: " 本番 キー ", want: "本番 キー"},
+		{name: "only spaces", in: "   ", want: ""},
+		{name: "empty", in: "", want: ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, NormalizeName(tc.in))
+		})
+	}
+}
+
+func TestNameFromAPI(t *testing.T) {
+	cases := []struct {
+		name  string
+		prior types.String
+		api   string
+		want  types.String
+	}{
+		{name: "same", prior: types.StringValue("prod-key"), api: "prod-key", want: types.StringValue("prod-key")},
+		{name: "normalized by the API", prior: types.StringValue(" prod-key "), api: "prod-key", want: types.StringValue(" prod-key ")},
+		{name: "changed outside Terraform", prior: types.StringValue(" prod-key "), api: "dev-key", want: types.StringValue("dev-key")},
+		{name: "null prior", prior: types.StringNull(), api: "prod-key", want: types.StringValue("prod-key")},
+		{name: "unknown prior", prior: types.StringUnknown(), api: "prod-key", want: types.StringValue("prod-key")},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, NameFromAPI(tc.prior, tc.api))
+		})
+	}
+}
+
+func TestWarnNameNormalization(t *testing.T) {
+	cases := []struct {
+		name  string
+		value types.String
+		want  string
+	}{
+		{name: "normalized", value: types.StringValue(" prod-key"), want: `name will be stored as "prod-key"`},
+		{name: "unchanged", value: types.StringValue("prod-key")},
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &validator.StringResponse{}
+			WarnNameNormalization().ValidateString(context.Background(), validator.StringRequest{
+				Path:        path.Root("name"),
+				ConfigValue: tc.value,
+			}, resp)
+			assert.False(t, resp.Diagnostics.HasError())
+			if tc.want == "" {
+				assert.Empty(t, resp.Diagnostics)
+				return
+			}
+			require.Len(t, resp.Diagnostics.Warnings(), 1)
+			assert.Contains(t, resp.Diagnostics.Warnings()[0].Detail(), tc.want)
+		})
+	}
+}
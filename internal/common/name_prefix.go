@@ -0,0 +1,88 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"math/rand/v2"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	validator "github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/desc"
+)
+
+const (
+	// NameSuffixLength はname_prefixに付与するランダムな文字列の長さ
+	NameSuffixLength = 8
+	nameMaxLength    = 64
+	nameSuffixChars  = "abcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+// SchemaResourceNameWithPrefix はname_prefixと組み合わせて利用するnameの属性を返す。
+// name_prefixを指定した場合は作成時に生成した名前を保持し、以降のapplyでも同じ名前を利用する
+func SchemaResourceNameWithPrefix(name string) schema.Attribute {
+	return schema.StringAttribute{
+		Optional:    true,
+		Computed:    true,
+		Description: desc.Sprintf("The name of the %s. Either this or `name_prefix` must be specified", name),
+		Validators: []validator.String{
+			stringvalidator.UTF8LengthBetween(1, nameMaxLength),
+			stringvalidator.AtLeastOneOf(path.MatchRoot("name_prefix")),
+			WarnNameNormalization(),
+		},
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+		},
+	}
+}
+
+// SchemaResourceNamePrefix はnameの代わりに指定し、作成時にランダムな文字列を付与して名前とするname_prefixの属性を返す。
+// create_before_destroyで置き換える際に、新旧のリソースの名前の重複を避けるために利用する
+func SchemaResourceNamePrefix(name string) schema.Attribute {
+	return schema.StringAttribute{
+		Optional: true,
+		Description: desc.Sprintf("The prefix of the name of the %s. A random suffix of %d characters is appended at creation. Conflicts with `name`",
+			name, NameSuffixLength),
+		Validators: []validator.String{
+			stringvalidator.UTF8LengthBetween(1, nameMaxLength-NameSuffixLength),
+			stringvalidator.ConflictsWith(path.MatchRoot("name")),
+		},
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplaceIfConfigured(),
+		},
+	}
+}
+
+// GenerateName は作成するリソースの名前を返す。nameが指定されていればそれを、そうでなければnamePrefixにランダムな文字列を付与した名前を返す
+func GenerateName(name, namePrefix types.String) string {
+	if !name.IsNull() && !name.IsUnknown() {
+		return name.ValueString()
+	}
+	return PrefixedUniqueName(namePrefix.ValueString())
+}
+
+// PrefixedUniqueName はprefixにNameSuffixLength文字の英小文字と数字からなるランダムな文字列を付与して返す
+func PrefixedUniqueName(prefix string) string {
+	suffix := make([]byte, NameSuffixLength)
+	for i := range suffix {
+		suffix[i] = nameSuffixChars[rand.IntN(len(nameSuffixChars))] //nolint:gosec
+	}
+	return prefix + string(suffix)
+}
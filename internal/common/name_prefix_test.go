@@ -0,0 +1,111 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixedUniqueName(t *testing.T) {
+	pattern := regexp.MustCompile(`^foobar-[a-z0-9]{8}$`)
+	names := make(map[string]struct{})
+	for range 1000 {
+		name := PrefixedUniqueName("foobar-")
+		require.Regexp(t, pattern, name)
+		names[name] = struct{}{}
+	}
+	// 36^8通りのため、1000回程度では重複しない
+	assert.Len(t, names, 1000)
+
+	assert.Len(t, PrefixedUniqueName(""), NameSuffixLength)
+}
+
+func TestGenerateName(t *testing.T) {
+	assert.Equal(t, "foobar", GenerateName(types.StringValue("foobar"), types.StringNull()))
+	assert.True(t, strings.HasPrefix(GenerateName(types.StringUnknown(), types.StringValue("prefix-")), "prefix-"))
+	assert.True(t, strings.HasPrefix(GenerateName(types.StringNull(), types.StringValue("prefix-")), "prefix-"))
+	assert.Len(t, GenerateName(types.StringNull(), types.StringValue("prefix-")), len("prefix-")+NameSuffixLength)
+}
+
+func TestSchemaResourceNamePrefix_validation(t *testing.T) {
+	ctx := context.Background()
+	s := schema.Schema{Attributes: map[string]schema.Attribute{
+		"name":        SchemaResourceNameWithPrefix("Test"),
+		"name_prefix": SchemaResourceNamePrefix("Test"),
+	}}
+	validate := func(name, namePrefix any) diag.Diagnostics {
+		config := tfsdk.Config{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), map[string]tftypes.Value{
+			"name":        tftypes.NewValue(tftypes.String, name),
+			"name_prefix": tftypes.NewValue(tftypes.String, namePrefix),
+		})}
+
+		var diags diag.Diagnostics
+		for attrName, value := range map[string]any{"name": name, "name_prefix": namePrefix} {
+			configValue := types.StringNull()
+			if value != nil {
+				configValue = types.StringValue(value.(string))
+			}
+			for _, v := range s.Attributes[attrName].(schema.StringAttribute).StringValidators() {
+				resp := &validator.StringResponse{}
+				v.ValidateString(ctx, validator.StringRequest{
+					Config:         config,
+					Path:           path.Root(attrName),
+					PathExpression: path.MatchRoot(attrName),
+					ConfigValue:    configValue,
+				}, resp)
+				diags.Append(resp.Diagnostics...)
+			}
+		}
+		return diags
+	}
+
+	testCases := []struct {
+		name       string
+		nameValue  any
+		namePrefix any
+		wantErr    string
+	}{
+		{name: "name", nameValue: "foobar"},
+		{name: "name_prefix", namePrefix: "foobar-"},
+		{name: "both", nameValue: "foobar", namePrefix: "foobar-", wantErr: "Invalid Attribute Combination"},
+		{name: "neither", wantErr: "Invalid Attribute Combination"},
+		{name: "name_prefix leaves no room for the suffix", namePrefix: strings.Repeat("a", 64-NameSuffixLength+1), wantErr: "name_prefix"},
+		{name: "longest name_prefix", namePrefix: strings.Repeat("a", 64-NameSuffixLength)},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			diags := validate(tc.nameValue, tc.namePrefix)
+			if tc.wantErr == "" {
+				assert.False(t, diags.HasError(), diags)
+				return
+			}
+			require.True(t, diags.HasError())
+			assert.Contains(t, diags.Errors()[0].Summary()+diags.Errors()[0].Detail(), tc.wantErr)
+		})
+	}
+}
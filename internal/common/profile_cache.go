@@ -0,0 +1,82 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sacloud/api-client-go/profile"
+)
+
+// プロファイルはplan/applyの各フェーズやエイリアスされたプロバイダーのConfigureごとに読み込まれるため、
+// パースした結果をプラグインのプロセス内でキャッシュする。ファイルの更新日時かサイズが変わった場合は読み込み直す
+var profiles = &profileCache{}
+
+type profileCacheKey struct {
+	name string
+	path string
+}
+
+type profileCacheEntry struct {
+	modTime time.Time
+	size    int64
+	value   profile.ConfigValue
+}
+
+type profileCache struct {
+	mu      sync.Mutex
+	entries map[profileCacheKey]profileCacheEntry
+}
+
+// load はpathに保存されたプロファイルnameの設定値を返す。
+// infoはpathのos.Statの結果で、nilの場合はファイルが存在しないものとして空の設定値を返す
+func (c *profileCache) load(name, path string, info os.FileInfo) (*profile.ConfigValue, error) {
+	key := profileCacheKey{name: name, path: path}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if info == nil {
+		delete(c.entries, key)
+		return &profile.ConfigValue{}, nil
+	}
+	if entry, ok := c.entries[key]; ok && entry.modTime.Equal(info.ModTime()) && entry.size == info.Size() {
+		return cloneConfigValue(entry.value), nil
+	}
+
+	buf, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	var value profile.ConfigValue
+	if err := json.Unmarshal(buf, &value); err != nil {
+		return nil, fmt.Errorf("parsing config is failed: %s", err)
+	}
+	if c.entries == nil {
+		c.entries = map[profileCacheKey]profileCacheEntry{}
+	}
+	c.entries[key] = profileCacheEntry{modTime: info.ModTime(), size: info.Size(), value: value}
+	return cloneConfigValue(value), nil
+}
+
+// cloneConfigValue は呼び出し元がZonesを並べ替えてもキャッシュに影響しないよう、vを複製する
+func cloneConfigValue(v profile.ConfigValue) *profile.ConfigValue {
+	v.Zones = slices.Clone(v.Zones)
+	return &v
+}
@@ -0,0 +1,138 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sacloud/api-client-go/profile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".usacloud", "foo", "config.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+
+	modTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	write := func(t *testing.T, body string, modTime time.Time) os.FileInfo {
+		t.Helper()
+		require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		return info
+	}
+
+	var cache profileCache
+	info := write(t, `{"AccessToken": "token1", "Zones": ["is1a", "tk1a"]}`, modTime)
+	v, err := cache.load("foo", path, info)
+	require.NoError(t, err)
+	assert.Equal(t, "token1", v.AccessToken)
+
+	t.Run("cache hit", func(t *testing.T) {
+		// 更新日時とサイズが同じ場合はファイルを読み込まないため、書き換えた内容は反映されない
+		info := write(t, `{"AccessToken": "token2", "Zones": ["is1a", "tk1a"]}`, modTime)
+		v, err := cache.load("foo", path, info)
+		require.NoError(t, err)
+		assert.Equal(t, "token1", v.AccessToken)
+
+		// 返した設定値を書き換えてもキャッシュには影響しない
+		v.Zones[0] = "tk1v"
+		v, err = cache.load("foo", path, info)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"is1a", "tk1a"}, v.Zones)
+	})
+
+	t.Run("invalidated by mtime", func(t *testing.T) {
+		info := write(t, `{"AccessToken": "token3", "Zones": ["is1a", "tk1a"]}`, modTime.Add(time.Minute))
+		v, err := cache.load("foo", path, info)
+		require.NoError(t, err)
+		assert.Equal(t, "token3", v.AccessToken)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		require.NoError(t, os.Remove(path))
+		v, err := cache.load("foo", path, nil)
+		require.NoError(t, err)
+		assert.Equal(t, &profile.ConfigValue{}, v)
+		assert.Empty(t, cache.entries)
+
+		// 同じ更新日時で作り直されたファイルも読み込み直す
+		info := write(t, `{"AccessToken": "token4", "Zones": ["is1a", "tk1a"]}`, modTime.Add(time.Minute))
+		v, err = cache.load("foo", path, info)
+		require.NoError(t, err)
+		assert.Equal(t, "token4", v.AccessToken)
+	})
+
+	t.Run("broken file is not cached", func(t *testing.T) {
+		info := write(t, `{`, modTime.Add(2*time.Minute))
+		_, err := cache.load("foo", path, info)
+		require.ErrorContains(t, err, "parsing config is failed")
+
+		info = write(t, `{"AccessToken": "token5"}`, modTime.Add(2*time.Minute))
+		v, err := cache.load("foo", path, info)
+		require.NoError(t, err)
+		assert.Equal(t, "token5", v.AccessToken)
+	})
+}
+
+func TestConfig_NewClient_profileCache(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(profile.DirectoryNameEnv, dir)
+	path := filepath.Join(dir, ".usacloud", "cached", "config.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte(`{"AccessToken": "token", "AccessTokenSecret": "secret", "Zone": "tk1b"}`), 0o600))
+
+	for range 2 {
+		cfg := &Config{Profile: "cached"}
+		_, err := cfg.NewClient()
+		require.NoError(t, err)
+		assert.Equal(t, "tk1b", cfg.Zone)
+		assert.Equal(t, path, cfg.profileFile)
+	}
+
+	profiles.mu.Lock()
+	defer profiles.mu.Unlock()
+	assert.Contains(t, profiles.entries, profileCacheKey{name: "cached", path: path})
+}
+
+func TestConfig_NewClient_explicitCredentials(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(profile.DirectoryNameEnv, dir)
+	path := filepath.Join(dir, ".usacloud", "default", "config.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte(`{"AccessToken": "profile-token", "AccessTokenSecret": "profile-secret", "Zone": "tk1b"}`), 0o600))
+
+	// 指定された認証情報はプロファイルで上書きせず、それ以外の設定はプロファイルから補完する
+	cfg := &Config{AccessToken: "token", AccessTokenSecret: "secret", Zone: Zone}
+	_, err := cfg.NewClient()
+	require.NoError(t, err)
+	assert.Equal(t, "token", cfg.AccessToken)
+	assert.Equal(t, "secret", cfg.AccessTokenSecret)
+	assert.Equal(t, "tk1b", cfg.Zone)
+	assert.Equal(t, path, cfg.profileFile)
+
+	// 一部のみ指定された場合は、残りをプロファイルから補完する
+	cfg = &Config{AccessToken: "token", Zone: Zone}
+	_, err = cfg.NewClient()
+	require.NoError(t, err)
+	assert.Equal(t, "token", cfg.AccessToken)
+	assert.Equal(t, "profile-secret", cfg.AccessTokenSecret)
+	assert.Equal(t, "tk1b", cfg.Zone)
+}
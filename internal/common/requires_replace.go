@@ -0,0 +1,36 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+)
+
+// RequiresReplaceWithReason は値の変更でリソースを置き換えるplan modifierを返す。
+// reasonは置き換えによって失われるデータなどの影響の説明で、plan modifierのdescriptionとして公開し、
+// 置き換えが計画された場合はplanの警告としても表示する。
+// equalがnilでない場合、equalで等しいとみなす変更(大文字小文字の違いなど)では置き換えない
+func RequiresReplaceWithReason(reason string, equal func(plan, state string) bool) planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(func(_ context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+		if equal != nil && equal(req.PlanValue.ValueString(), req.StateValue.ValueString()) {
+			return
+		}
+		resp.RequiresReplace = true
+		resp.Diagnostics.AddAttributeWarning(req.Path, "Resource will be replaced", reason)
+	}, reason, reason)
+}
@@ -0,0 +1,75 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiresReplaceWithReason(t *testing.T) {
+	ctx := context.Background()
+	const reason = "Replacing this vault deletes all contained secrets."
+	state := tfsdk.State{Raw: tftypes.NewValue(tftypes.Object{}, map[string]tftypes.Value{})}
+	plan := tfsdk.Plan{Raw: tftypes.NewValue(tftypes.Object{}, map[string]tftypes.Value{})}
+
+	expects := []struct {
+		name        string
+		equal       func(plan, state string) bool
+		state       string
+		plan        string
+		wantReplace bool
+	}{
+		{name: "changed", state: "generated", plan: "imported", wantReplace: true},
+		{name: "unchanged", state: "generated", plan: "generated"},
+		{name: "case only without equal", state: "generated", plan: "Generated", wantReplace: true},
+		{name: "case only with equal", equal: strings.EqualFold, state: "generated", plan: "Generated"},
+		{name: "same ID", equal: SameSakuraID, state: "110000000001", plan: "1.10000000001e+11"},
+		{name: "different ID", equal: SameSakuraID, state: "110000000001", plan: "110000000002", wantReplace: true},
+	}
+	for _, tc := range expects {
+		t.Run(tc.name, func(t *testing.T) {
+			m := RequiresReplaceWithReason(reason, tc.equal)
+			assert.Equal(t, reason, m.Description(ctx))
+
+			resp := &planmodifier.StringResponse{PlanValue: types.StringValue(tc.plan)}
+			m.PlanModifyString(ctx, planmodifier.StringRequest{
+				Path:        path.Root("kms_key_id"),
+				State:       state,
+				Plan:        plan,
+				StateValue:  types.StringValue(tc.state),
+				PlanValue:   types.StringValue(tc.plan),
+				ConfigValue: types.StringValue(tc.plan),
+			}, resp)
+			require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+			assert.Equal(t, tc.wantReplace, resp.RequiresReplace)
+			if tc.wantReplace {
+				require.Len(t, resp.Diagnostics.Warnings(), 1)
+				assert.Equal(t, reason, resp.Diagnostics.Warnings()[0].Detail())
+			} else {
+				assert.Empty(t, resp.Diagnostics)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ResourceDefaults はプロバイダーのresource_defaultsブロックで指定する、リソースで省略された属性のデフォルト値。空文字は未指定を表す
+type ResourceDefaults struct {
+	Description string
+	IconID      string
+	// Tags はプロバイダーのdefault_tags。省略時の値ではなく、リソースのtagsに常に追加する
+	Tags []string
+}
+
+// ResourceDefaultsSource はresource_defaultsの値を返すAPIクライアント
+type ResourceDefaultsSource interface {
+	ResourceDefaults() ResourceDefaults
+}
+
+// ResourceDefaults はプロバイダーのresource_defaultsの値を返す。プロバイダーの設定が確定していない場合は空の値を返す
+func (c *APIClient) ResourceDefaults() ResourceDefaults {
+	if c == nil {
+		return ResourceDefaults{}
+	}
+	return c.resourceDefaults
+}
+
+// PlanResourceDefaults はリソースのModifyPlanから呼び出し、省略されたdescription/icon_idにresource_defaultsの値をplanとして設定する。
+// 優先順位はリソースの設定、resource_defaults、未指定の順。planに"known after apply"ではなく実際に設定される値を表示する。
+// icon_idはresource_defaultsが未指定の場合はnullのままとする。
+// tagsにはプロバイダーのdefault_tagsを追加する。stateにも追加後のタグが保存されるため、差分は発生しない
+func PlanResourceDefaults(ctx context.Context, client ResourceDefaultsSource, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// 削除時やプロバイダーの設定が確定していない場合は何もしない
+	if req.Plan.Raw.IsNull() || client == nil {
+		return
+	}
+	defaults := client.ResourceDefaults()
+
+	attrs := req.Plan.Schema.GetAttributes()
+	for name, value := range map[string]string{"description": defaults.Description, "icon_id": defaults.IconID} {
+		// descriptionがComputedのみのリソースなど、リソース側で指定できない属性には適用しない
+		if attr, ok := attrs[name]; !ok || !attr.IsOptional() {
+			continue
+		}
+		var config types.String
+		resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root(name), &config)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		planned, ok := planResourceDefault(config, value, name == "icon_id")
+		if ok {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root(name), planned)...)
+		}
+	}
+
+	if attr, ok := attrs["tags"]; ok && attr.IsOptional() && len(defaults.Tags) > 0 {
+		planDefaultTags(ctx, defaults.Tags, req, resp)
+	}
+}
+
+// planDefaultTags はリソースのtagsにdefault_tagsを追加した値をplanに設定する
+func planDefaultTags(ctx context.Context, defaultTags []string, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var config types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("tags"), &config)...)
+	if resp.Diagnostics.HasError() || config.IsUnknown() {
+		return
+	}
+	// 一部の要素が確定していない場合はapply時のplanで追加する
+	for _, v := range config.Elements() {
+		if v.IsUnknown() {
+			return
+		}
+	}
+
+	tags := MergeDefaultTags(TsetToStrings(config), defaultTags)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("tags"), StringsToTset(tags))...)
+}
+
+// MergeDefaultTags はリソースのtagsにdefault_tagsを追加したタグを返す。既にリソースで指定されているタグは重複させない
+func MergeDefaultTags(tags, defaultTags []string) []string {
+	merged := make([]string, 0, len(tags)+len(defaultTags))
+	seen := make(map[string]bool, len(tags)+len(defaultTags))
+	for _, tag := range append(append([]string{}, tags...), defaultTags...) {
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		merged = append(merged, tag)
+	}
+	return merged
+}
+
+// planResourceDefault はconfigとデフォルト値から、planに設定する値を返す。planを変更しない場合はfalseを返す。
+// nullIfUnsetがtrueの場合、デフォルト値が未指定であればnullを返す
+func planResourceDefault(config types.String, defaultValue string, nullIfUnset bool) (types.String, bool) {
+	if !config.IsNull() {
+		return config, false
+	}
+	switch {
+	case defaultValue != "":
+		return types.StringValue(defaultValue), true
+	case nullIfUnset:
+		return types.StringNull(), true
+	}
+	return config, false
+}
@@ -0,0 +1,204 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanResourceDefault(t *testing.T) {
+	cases := []struct {
+		name        string
+		config      types.String
+		value       string
+		nullIfUnset bool
+		want        types.String
+		changed     bool
+	}{
+		{name: "resource value", config: types.StringValue("foo"), value: "default", want: types.StringValue("foo")},
+		{name: "resource empty value", config: types.StringValue(""), value: "default", want: types.StringValue("")},
+		{name: "unknown resource value", config: types.StringUnknown(), value: "default", want: types.StringUnknown()},
+		{name: "provider default", config: types.StringNull(), value: "default", want: types.StringValue("default"), changed: true},
+		{name: "none", config: types.StringNull(), want: types.StringNull()},
+		{name: "none with nullIfUnset", config: types.StringNull(), nullIfUnset: true, want: types.StringNull(), changed: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, changed := planResourceDefault(tc.config, tc.value, tc.nullIfUnset)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.changed, changed)
+		})
+	}
+}
+
+func TestPlanResourceDefaults(t *testing.T) {
+	ctx := context.Background()
+	s := schema.Schema{Attributes: map[string]schema.Attribute{
+		"description": SchemaResourceDescription("Test"),
+		"icon_id":     SchemaResourceIconID("Test"),
+		"note":        schema.StringAttribute{Computed: true},
+	}}
+	raw := func(description, iconID any) tftypes.Value {
+		return tftypes.NewValue(s.Type().TerraformType(ctx), map[string]tftypes.Value{
+			"description": tftypes.NewValue(tftypes.String, description),
+			"icon_id":     tftypes.NewValue(tftypes.String, iconID),
+			"note":        tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		})
+	}
+	modifyPlan := func(t *testing.T, client ResourceDefaultsSource, config, plan tftypes.Value) (types.String, types.String) {
+		t.Helper()
+		req := resource.ModifyPlanRequest{
+			Config: tfsdk.Config{Schema: s, Raw: config},
+			Plan:   tfsdk.Plan{Schema: s, Raw: plan},
+			State:  tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)},
+		}
+		resp := &resource.ModifyPlanResponse{Plan: req.Plan}
+		PlanResourceDefaults(ctx, client, req, resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+		var description, iconID types.String
+		require.False(t, resp.Plan.GetAttribute(ctx, path.Root("description"), &description).HasError())
+		require.False(t, resp.Plan.GetAttribute(ctx, path.Root("icon_id"), &iconID).HasError())
+		return description, iconID
+	}
+	client := &APIClient{resourceDefaults: ResourceDefaults{Description: "managed by terraform", IconID: "110000000001"}}
+
+	t.Run("defaults appear in the plan", func(t *testing.T) {
+		description, iconID := modifyPlan(t, client, raw(nil, nil), raw(tftypes.UnknownValue, tftypes.UnknownValue))
+		assert.Equal(t, types.StringValue("managed by terraform"), description)
+		assert.Equal(t, types.StringValue("110000000001"), iconID)
+	})
+
+	t.Run("resource values take precedence", func(t *testing.T) {
+		description, iconID := modifyPlan(t, client, raw("foo", "110000000002"), raw("foo", "110000000002"))
+		assert.Equal(t, types.StringValue("foo"), description)
+		assert.Equal(t, types.StringValue("110000000002"), iconID)
+	})
+
+	t.Run("no defaults", func(t *testing.T) {
+		description, iconID := modifyPlan(t, &APIClient{}, raw(nil, nil), raw(tftypes.UnknownValue, tftypes.UnknownValue))
+		assert.True(t, description.IsUnknown())
+		assert.True(t, iconID.IsNull())
+	})
+
+	t.Run("provider is not configured", func(t *testing.T) {
+		var nilClient *APIClient
+		description, iconID := modifyPlan(t, nilClient, raw(nil, nil), raw(tftypes.UnknownValue, tftypes.UnknownValue))
+		assert.True(t, description.IsUnknown())
+		assert.True(t, iconID.IsNull())
+	})
+}
+
+func TestMergeDefaultTags(t *testing.T) {
+	cases := []struct {
+		name        string
+		tags        []string
+		defaultTags []string
+		want        []string
+	}{
+		{name: "no resource tags", defaultTags: []string{"env=prod"}, want: []string{"env=prod"}},
+		{name: "no default tags", tags: []string{"tag1"}, want: []string{"tag1"}},
+		{name: "merged", tags: []string{"tag1", "tag2"}, defaultTags: []string{"env=prod", "team=infra"}, want: []string{"tag1", "tag2", "env=prod", "team=infra"}},
+		{name: "duplicated", tags: []string{"env=prod", "tag1"}, defaultTags: []string{"env=prod", "team=infra"}, want: []string{"env=prod", "tag1", "team=infra"}},
+		{name: "none", want: []string{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, MergeDefaultTags(tc.tags, tc.defaultTags))
+		})
+	}
+}
+
+func TestPlanResourceDefaults_tags(t *testing.T) {
+	ctx := context.Background()
+	s := schema.Schema{Attributes: map[string]schema.Attribute{
+		"tags": SchemaResourceTags("Test"),
+	}}
+	raw := func(tags tftypes.Value) tftypes.Value {
+		return tftypes.NewValue(s.Type().TerraformType(ctx), map[string]tftypes.Value{"tags": tags})
+	}
+	tagsValue := func(tags ...any) tftypes.Value {
+		var elements []tftypes.Value
+		for _, tag := range tags {
+			elements = append(elements, tftypes.NewValue(tftypes.String, tag))
+		}
+		return tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, elements)
+	}
+	modifyPlan := func(t *testing.T, client ResourceDefaultsSource, config, plan tftypes.Value) (types.Set, bool) {
+		t.Helper()
+		req := resource.ModifyPlanRequest{
+			Config: tfsdk.Config{Schema: s, Raw: config},
+			Plan:   tfsdk.Plan{Schema: s, Raw: plan},
+			State:  tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)},
+		}
+		resp := &resource.ModifyPlanResponse{Plan: req.Plan}
+		PlanResourceDefaults(ctx, client, req, resp)
+		if resp.Diagnostics.HasError() {
+			return types.Set{}, false
+		}
+
+		var tags types.Set
+		require.False(t, resp.Plan.GetAttribute(ctx, path.Root("tags"), &tags).HasError())
+		return tags, true
+	}
+	client := &APIClient{resourceDefaults: ResourceDefaults{Tags: []string{"env=prod", "team=infra"}}}
+
+	t.Run("defaults are added to resource tags", func(t *testing.T) {
+		tags, ok := modifyPlan(t, client, raw(tagsValue("tag1", "env=prod")), raw(tagsValue("tag1", "env=prod")))
+		require.True(t, ok)
+		assert.ElementsMatch(t, []string{"tag1", "env=prod", "team=infra"}, TsetToStrings(tags))
+	})
+
+	t.Run("resource without tags", func(t *testing.T) {
+		tags, ok := modifyPlan(t, client, raw(tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, nil)), raw(tagsValue()))
+		require.True(t, ok)
+		assert.ElementsMatch(t, []string{"env=prod", "team=infra"}, TsetToStrings(tags))
+	})
+
+	t.Run("unknown tags are left for apply", func(t *testing.T) {
+		tags, ok := modifyPlan(t, client, raw(tagsValue("tag1", tftypes.UnknownValue)), raw(tagsValue("tag1", tftypes.UnknownValue)))
+		require.True(t, ok)
+		assert.Len(t, tags.Elements(), 2)
+	})
+
+	t.Run("no defaults", func(t *testing.T) {
+		tags, ok := modifyPlan(t, &APIClient{}, raw(tagsValue("tag1")), raw(tagsValue("tag1")))
+		require.True(t, ok)
+		assert.Equal(t, []string{"tag1"}, TsetToStrings(tags))
+	})
+
+	t.Run("many tags", func(t *testing.T) {
+		values := make([]any, 9)
+		for i := range values {
+			values[i] = fmt.Sprintf("tag%d", i)
+		}
+		tags, ok := modifyPlan(t, client, raw(tagsValue(values...)), raw(tagsValue(values...)))
+		require.True(t, ok)
+		assert.Len(t, tags.Elements(), 11)
+	})
+}
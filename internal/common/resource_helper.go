@@ -47,3 +47,10 @@ func UpdateResourceByReadWithZone(ctx context.Context, r resource.Resource, stat
 	*diags = newReadResp.Diagnostics
 	*state = newReadResp.State
 }
+
+// SetCreatedID は作成したリソースのIDを直ちにstateに記録する。
+// Createではバックエンドにリソースが作成された時点でこれを呼び出し、以降の処理が失敗してもリソースが
+// taintedとしてstateで追跡されるようにする。記録しないと次回のapplyで同じリソースが重複して作成される
+func SetCreatedID(ctx context.Context, state *tfsdk.State, id string) diag.Diagnostics {
+	return state.SetAttribute(ctx, path.Root("id"), id)
+}
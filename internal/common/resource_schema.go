@@ -35,6 +35,7 @@ import (
 
 func SchemaResourceId(name string) schema.Attribute {
 	return schema.StringAttribute{
+		CustomType:  SakuraIDType{},
 		Computed:    true,
 		Description: desc.Sprintf("The ID of the %s.", name),
 		PlanModifiers: []planmodifier.String{
@@ -48,7 +49,8 @@ func SchemaResourceName(name string) schema.Attribute {
 		Required:    true,
 		Description: desc.Sprintf("The name of the %s.", name),
 		Validators: []validator.String{
-			stringvalidator.LengthBetween(1, 64),
+			stringvalidator.UTF8LengthBetween(1, 64),
+			WarnNameNormalization(),
 		},
 	}
 }
@@ -57,9 +59,9 @@ func SchemaResourceDescription(name string) schema.Attribute {
 	return schema.StringAttribute{
 		Optional:    true,
 		Computed:    true, // FrameworkはSDK v2とは違ってComputedをつけないとnullに値をセットしようとしてエラーになる
-		Description: desc.Sprintf("The description of the %s. %s", name, desc.Length(1, 512)),
+		Description: desc.Sprintf("The description of the %s. %s. Default is the description in the resource_defaults of the provider", name, desc.Length(1, 512)),
 		Validators: []validator.String{
-			stringvalidator.LengthBetween(1, 512),
+			stringvalidator.UTF8LengthBetween(1, 512),
 		},
 	}
 }
@@ -67,7 +69,8 @@ func SchemaResourceDescription(name string) schema.Attribute {
 func SchemaResourceIconID(name string) schema.Attribute {
 	return schema.StringAttribute{
 		Optional:    true,
-		Description: desc.Sprintf("The icon id to attach to the %s", name),
+		Computed:    true, // resource_defaultsのicon_idをplanに設定するため。PlanResourceDefaultsを呼び出すこと
+		Description: desc.Sprintf("The icon id to attach to the %s. Default is the icon_id in the resource_defaults of the provider", name),
 		Validators: []validator.String{
 			sacloudvalidator.SakuraIDValidator(),
 		},
@@ -104,6 +107,10 @@ func SchemaResourceTags(name string) schema.Attribute {
 		Optional:    true,
 		Computed:    true,
 		Description: desc.Sprintf("The tags of the %s.", name),
+		Validators:  sacloudvalidator.Tags(),
+		PlanModifiers: []planmodifier.Set{
+			EmptyTagsForNull(),
+		},
 	}
 }
 
@@ -111,13 +118,25 @@ func SchemaResourceZone(name string) schema.Attribute {
 	return schema.StringAttribute{
 		Optional:    true,
 		Computed:    true,
-		Description: desc.Sprintf("The name of zone that the %s will be created (e.g. `is1a`, `tk1a`)", name),
+		Description: desc.Sprintf("The name of zone that the %s will be created (e.g. `is1a`, `tk1a`). Default is the zone of the provider", name),
+		// 未指定の場合は作成時にPlanZoneでプロバイダーのzoneを設定し、以降はstateの値を引き継ぐ
 		PlanModifiers: []planmodifier.String{
-			stringplanmodifier.RequiresReplaceIfConfigured(),
+			stringplanmodifier.UseStateForUnknown(),
+			stringplanmodifier.RequiresReplace(),
 		},
 	}
 }
 
+// SchemaResourceGlobalZone はKMSなどのゾーンに依存しないリソースのzone属性を返す。
+// ゾーンを持つリソースとモジュールから同じように参照できるよう、値は常にGlobalZoneとなる
+func SchemaResourceGlobalZone(name string) schema.Attribute {
+	return schema.StringAttribute{
+		Computed:    true,
+		Default:     stringdefault.StaticString(GlobalZone),
+		Description: desc.Sprintf("The zone of the %s. This is always `%s` because the %s is not bound to a zone", name, GlobalZone, name),
+	}
+}
+
 func SchemaResourceSize(name string, defaultValue int64, validSizes ...int64) schema.Attribute {
 	s := schema.Int64Attribute{
 		Optional:    true,
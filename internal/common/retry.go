@@ -0,0 +1,103 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// clock はリトライの待機に利用する時計。テストでは待機せずに進められるものに差し替える
+type clock interface {
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// Backoff はジッター付きの指数バックオフでリトライを行う
+type Backoff struct {
+	// InitialInterval は初回のリトライまでの待機時間
+	InitialInterval time.Duration
+	// MaxInterval は待機時間の上限
+	MaxInterval time.Duration
+	// Multiplier はリトライごとに待機時間に掛ける倍率
+	Multiplier float64
+
+	clock  clock
+	jitter func() float64 // [0.0, 1.0)の値を返す
+}
+
+// DefaultBackoff はリソースの削除時などのリトライに利用するバックオフの設定
+var DefaultBackoff = &Backoff{
+	InitialInterval: 1 * time.Second,
+	MaxInterval:     30 * time.Second,
+	Multiplier:      2,
+}
+
+// Retry はisRetryableがtrueを返すエラーの間、ctxの期限までfnをリトライする。
+// リトライ対象外のエラーの場合はそのエラーを、待機中にctxがキャンセルされたり期限を過ぎた場合は最後のエラーにctx.Err()を加えたエラーを返す
+func (b *Backoff) Retry(ctx context.Context, isRetryable func(error) bool, fn func() error) error {
+	c := b.clock
+	if c == nil {
+		c = realClock{}
+	}
+	jitter := b.jitter
+	if jitter == nil {
+		jitter = rand.Float64 //nolint:gosec
+	}
+
+	interval := b.InitialInterval
+	for {
+		err := fn()
+		if err == nil || !isRetryable(err) {
+			return err
+		}
+
+		// 待機時間を[interval/2, interval)の範囲でずらし、複数のリソースのリトライが同時に行われないようにする
+		wait := interval/2 + time.Duration(jitter()*float64(interval/2))
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", err, ctx.Err())
+		case <-c.After(wait):
+		}
+
+		interval = time.Duration(float64(interval) * b.Multiplier)
+		if b.MaxInterval > 0 && interval > b.MaxInterval {
+			interval = b.MaxInterval
+		}
+	}
+}
+
+// RetryOnConflict はDefaultBackoffを利用して、409/423が返される間fnをリトライする。
+// 削除直後の関連リソースの状態がバックエンドで反映されるまでの競合を吸収するために利用する
+func RetryOnConflict(ctx context.Context, fn func() error) error {
+	return DefaultBackoff.Retry(ctx, IsConflict, fn)
+}
+
+// IsConflict はリソースの状態の競合によるエラー(409 Conflict/423 Locked)かを返す
+func IsConflict(err error) bool {
+	switch APIStatusCode(err) {
+	case http.StatusConflict, http.StatusLocked:
+		return true
+	}
+	return false
+}
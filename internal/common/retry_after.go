@@ -0,0 +1,103 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	sacloudhttp "github.com/sacloud/go-http"
+)
+
+// RetryAfterHeader はレート制限時などにAPIが返す、次のリクエストまでの待機時間を示すヘッダー
+const RetryAfterHeader = "Retry-After"
+
+// ParseRetryAfter はRetry-Afterヘッダーの値を待機時間に変換する。
+// 秒数とHTTP-dateの両方の形式に対応し、過去の日時の場合は0を返す。解釈できない場合はfalseを返す
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	at, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if wait := at.Sub(now); wait > 0 {
+		return wait, true
+	}
+	return 0, true
+}
+
+// retryAfterLimiter は429/503のレスポンスのRetry-Afterを、retry_wait_maxとリクエストのcontextの期限を上限に切り詰める。
+// APIクライアント(go-http)のリトライはRetry-Afterがあればその秒数だけ待機するため、
+// ヘッダーを書き換えることでサーバーの指示に従いつつ、過度に長い待機を避ける
+type retryAfterLimiter struct {
+	transport http.RoundTripper
+	maxWait   time.Duration
+	now       func() time.Time
+}
+
+func (l *retryAfterLimiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := l.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return resp, nil
+	}
+
+	now := time.Now
+	if l.now != nil {
+		now = l.now
+	}
+	wait, ok := ParseRetryAfter(resp.Header.Get(RetryAfterHeader), now())
+	if !ok {
+		return resp, nil
+	}
+
+	limit := l.maxWait
+	if limit <= 0 {
+		limit = sacloudhttp.DefaultRetryWaitMax
+	}
+	if deadline, ok := req.Context().Deadline(); ok {
+		limit = min(limit, max(deadline.Sub(now()), 0))
+	}
+	if wait > limit {
+		wait = limit
+	}
+
+	tflog.Debug(req.Context(), "API responded with Retry-After", map[string]any{
+		"status":      resp.StatusCode,
+		"endpoint":    req.Method + " " + req.URL.Path,
+		"retry_after": resp.Header.Get(RetryAfterHeader),
+		"wait":        wait.String(),
+	})
+	resp.Header.Set(RetryAfterHeader, strconv.FormatInt(int64(wait/time.Second), 10))
+	return resp, nil
+}
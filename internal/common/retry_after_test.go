@@ -0,0 +1,148 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "seconds", value: "120", want: 120 * time.Second, wantOK: true},
+		{name: "zero", value: "0", want: 0, wantOK: true},
+		{name: "http-date", value: "Wed, 01 Jan 2025 00:00:30 GMT", want: 30 * time.Second, wantOK: true},
+		{name: "http-date in the past", value: "Tue, 31 Dec 2024 23:59:00 GMT", want: 0, wantOK: true},
+		{name: "empty", value: ""},
+		{name: "negative", value: "-1"},
+		{name: "invalid", value: "soon"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := ParseRetryAfter(tc.value, now)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// rateLimitedTransport は指定回数だけ429を返した後、成功レスポンスを返す
+type rateLimitedTransport struct {
+	mu         sync.Mutex
+	limited    int
+	retryAfter string
+	requests   int
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.requests++
+	if t.requests <= t.limited {
+		return &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{RetryAfterHeader: []string{t.retryAfter}},
+			Body:       io.NopCloser(strings.NewReader(`{"error_msg":"too many requests"}`)),
+			Request:    req,
+		}, nil
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"Count":0,"From":0,"Total":0,"Keys":[]}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestRetryAfterLimiter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	roundTrip := func(t *testing.T, ctx context.Context, l *retryAfterLimiter) string {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/keys", nil)
+		require.NoError(t, err)
+		resp, err := l.RoundTrip(req)
+		require.NoError(t, err)
+		defer resp.Body.Close() //nolint:errcheck
+		return resp.Header.Get(RetryAfterHeader)
+	}
+
+	t.Run("shorter than max wait", func(t *testing.T) {
+		l := &retryAfterLimiter{transport: &rateLimitedTransport{limited: 1, retryAfter: "3"}, maxWait: 10 * time.Second, now: func() time.Time { return now }}
+		assert.Equal(t, "3", roundTrip(t, context.Background(), l))
+	})
+
+	t.Run("capped by max wait", func(t *testing.T) {
+		l := &retryAfterLimiter{transport: &rateLimitedTransport{limited: 1, retryAfter: "3600"}, maxWait: 10 * time.Second, now: func() time.Time { return now }}
+		assert.Equal(t, "10", roundTrip(t, context.Background(), l))
+	})
+
+	t.Run("http-date capped by max wait", func(t *testing.T) {
+		l := &retryAfterLimiter{transport: &rateLimitedTransport{limited: 1, retryAfter: "Wed, 01 Jan 2025 01:00:00 GMT"}, maxWait: 10 * time.Second, now: func() time.Time { return now }}
+		assert.Equal(t, "10", roundTrip(t, context.Background(), l))
+	})
+
+	t.Run("capped by context deadline", func(t *testing.T) {
+		ctx, cancel := context.WithDeadline(context.Background(), now.Add(5*time.Second))
+		defer cancel()
+		l := &retryAfterLimiter{transport: &rateLimitedTransport{limited: 1, retryAfter: "3600"}, maxWait: 10 * time.Second, now: func() time.Time { return now }}
+		assert.Equal(t, "5", roundTrip(t, ctx, l))
+	})
+
+	t.Run("invalid header is left as is", func(t *testing.T) {
+		l := &retryAfterLimiter{transport: &rateLimitedTransport{limited: 1, retryAfter: "soon"}, maxWait: 10 * time.Second}
+		assert.Equal(t, "soon", roundTrip(t, context.Background(), l))
+	})
+}
+
+func TestConfig_NewClient_retryAfter(t *testing.T) {
+	transport := &rateLimitedTransport{limited: 2, retryAfter: "3600"}
+	c := &Config{
+		AccessToken:       "token",
+		AccessTokenSecret: "secret",
+		APIRootURL:        "http://sakura.example.com",
+		RetryMax:          3,
+		RetryWaitMin:      1,
+		RetryWaitMax:      1,
+		HTTPTransport:     transport,
+	}
+	client, err := c.NewClient()
+	require.NoError(t, err)
+
+	// Retry-After: 3600に従うとテストが終わらないため、retry_wait_maxの1秒で切り詰められることを確認する
+	start := time.Now()
+	keyOp, err := client.KMSKeyOp()
+	require.NoError(t, err)
+	_, err = keyOp.List(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, transport.requests)
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
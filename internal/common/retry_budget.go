@@ -0,0 +1,211 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// RetryBudgetExceededError は1回のAPI呼び出しのリトライが、合計時間の上限(バジェット)を超えるため打ち切られたことを表す
+type RetryBudgetExceededError struct {
+	Attempts int
+	Elapsed  time.Duration
+	Budget   time.Duration
+}
+
+func (e *RetryBudgetExceededError) Error() string {
+	attempts := "attempts"
+	if e.Attempts == 1 {
+		attempts = "attempt"
+	}
+	return fmt.Sprintf("gave up after %d %s over %s (budget %s)", e.Attempts, attempts, formatSeconds(e.Elapsed), formatSeconds(e.Budget))
+}
+
+func formatSeconds(d time.Duration) string {
+	return fmt.Sprintf("%ds", int(d.Round(time.Second)/time.Second))
+}
+
+// retryBudget は1回のAPI呼び出しの開始時刻とリトライにかけられる合計時間、これまでの試行回数を保持する。
+// budgetが0の場合は合計時間を制限しない
+type retryBudget struct {
+	mu       sync.Mutex
+	start    time.Time
+	budget   time.Duration
+	attempts int
+	rand     *rand.Rand    // API呼び出しごとにシードしたジッター用の乱数
+	wait     time.Duration // 次の試行の前に待機する時間
+}
+
+// takeWait は次の試行の前に待機する時間を返し、リセットする
+func (b *retryBudget) takeWait() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	wait := b.wait
+	b.wait = 0
+	return wait
+}
+
+type retryBudgetKey struct{}
+
+// retryBudgeter はAPIクライアント(go-http)のリトライの合計時間を、api_request_timeoutとリクエストのcontextの期限の短い方に制限する。
+// retry_maxは試行回数のみを制限するため、retry_wait_maxが大きいと1回のAPI呼び出しが数分以上かかることがある。
+// 呼び出しの開始時にcustomizeでバジェットをcontextに設定し、リトライの判定(checkRetry)で次の待機後にバジェットを超える場合は打ち切る。
+//
+// 待機時間はgo-httpのデフォルト(ジッターなしの指数バックオフ)ではなく、retry_wait_minからretry_wait_maxの間のfull jitterで決める。
+// 429の後に多数のリソースが同時にリトライして再びレート制限に達することを避けるため。
+// go-httpはBackoffを差し替えられないため、checkRetryで決めた待機時間はretryWaiterが次の試行の前に待機する
+type retryBudgeter struct {
+	timeout     time.Duration // api_request_timeout
+	waitMin     time.Duration
+	waitMax     time.Duration
+	statusCodes []int
+	now         func() time.Time
+	newSource   func() rand.Source // API呼び出しごとの乱数のシード。テストで差し替える
+}
+
+func (b *retryBudgeter) clock() time.Time {
+	if b.now != nil {
+		return b.now()
+	}
+	return time.Now()
+}
+
+// customize はAPI呼び出しごとに1回、リトライの前に呼び出されるRequestCustomizer
+func (b *retryBudgeter) customize(req *http.Request) error {
+	now := b.clock()
+	budget := b.timeout
+	if deadline, ok := req.Context().Deadline(); ok && (budget <= 0 || deadline.Sub(now) < budget) {
+		budget = deadline.Sub(now)
+	}
+	if budget < 0 {
+		// contextの期限を過ぎている場合はcheckRetryでリトライしない
+		budget = 0
+	}
+	newSource := b.newSource
+	if newSource == nil {
+		newSource = func() rand.Source {
+			return rand.NewPCG(rand.Uint64(), rand.Uint64()) //nolint:gosec
+		}
+	}
+	// RequestCustomizerはリクエストを差し替えられないため、contextを設定したリクエストで上書きする
+	*req = *req.WithContext(context.WithValue(req.Context(), retryBudgetKey{}, &retryBudget{
+		start:  now,
+		budget: budget,
+		rand:   rand.New(newSource()), //nolint:gosec
+	}))
+	return nil
+}
+
+// checkRetry はstatusCodesのレスポンスをリトライする、api-client-goのCheckRetryStatusCodesと同じ判定に、バジェットによる打ち切りを加えたもの
+func (b *retryBudgeter) checkRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	retry, checkErr := b.shouldRetry(ctx, resp, err)
+	if !retry || checkErr != nil {
+		return retry, checkErr
+	}
+
+	budget, ok := ctx.Value(retryBudgetKey{}).(*retryBudget)
+	if !ok {
+		return true, nil
+	}
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+
+	budget.attempts++
+	wait := b.backoff(budget.attempts-1, resp, budget.rand)
+	elapsed := b.clock().Sub(budget.start)
+	if budget.budget > 0 && elapsed+wait > budget.budget {
+		return false, &RetryBudgetExceededError{Attempts: budget.attempts, Elapsed: elapsed, Budget: budget.budget}
+	}
+	budget.wait = wait
+	if resp != nil {
+		// go-httpのバックオフはRetry-Afterがあればその時間だけ待機するため、二重に待機しないよう取り除く
+		resp.Header.Del(RetryAfterHeader)
+	}
+	return true, nil
+}
+
+// backoff はattempt回目(0始まり)の失敗の後、次の試行までに待機する時間を返す。
+// 429/503のレスポンスにRetry-Afterがあればその時間(retryAfterLimiterで切り詰め済み)を、
+// なければfullJitterBackoffの時間を返す
+func (b *retryBudgeter) backoff(attempt int, resp *http.Response, r *rand.Rand) time.Duration {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if wait, ok := ParseRetryAfter(resp.Header.Get(RetryAfterHeader), b.clock()); ok {
+			return wait
+		}
+	}
+	return fullJitterBackoff(b.waitMin, b.waitMax, attempt, r)
+}
+
+// fullJitterBackoff はwaitMin*2^attemptとwaitMaxの小さい方を上限に、waitMinから上限までの一様乱数の待機時間を返す
+func fullJitterBackoff(waitMin, waitMax time.Duration, attempt int, r *rand.Rand) time.Duration {
+	upper := waitMin
+	for i := 0; i < attempt && upper < waitMax; i++ {
+		upper *= 2
+	}
+	upper = min(upper, waitMax)
+	if upper <= waitMin {
+		return upper
+	}
+	return waitMin + time.Duration(r.Int64N(int64(upper-waitMin)+1))
+}
+
+func (b *retryBudgeter) shouldRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+	if err != nil {
+		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	}
+	if resp.StatusCode == 0 {
+		return true, nil
+	}
+	return slices.Contains(b.statusCodes, resp.StatusCode), nil
+}
+
+// retryWaiter はcheckRetryで決めた待機時間だけ、次の試行の送信を遅らせる。
+// 待機中にcontextがキャンセルされたり期限を過ぎた場合はそのエラーを返す
+type retryWaiter struct {
+	transport http.RoundTripper
+	clock     clock
+}
+
+func (w *retryWaiter) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := w.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if budget, ok := req.Context().Value(retryBudgetKey{}).(*retryBudget); ok {
+		if wait := budget.takeWait(); wait > 0 {
+			c := w.clock
+			if c == nil {
+				c = realClock{}
+			}
+			select {
+			case <-req.Context().Done():
+				return nil, req.Context().Err()
+			case <-c.After(wait):
+			}
+		}
+	}
+	return transport.RoundTrip(req)
+}
@@ -0,0 +1,330 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// budgetClock は試行ごとに手動で進める時計
+type budgetClock struct {
+	now time.Time
+}
+
+func (c *budgetClock) Now() time.Time {
+	return c.now
+}
+
+func (c *budgetClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// maxSource は常に最大値を返す乱数のソースで、full jitterの待機時間は上限となる
+type maxSource struct{}
+
+func (maxSource) Uint64() uint64 {
+	return math.MaxUint64
+}
+
+func TestRetryBudgeter(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	unavailable := func(retryAfter string) *http.Response {
+		resp := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}}
+		if retryAfter != "" {
+			resp.Header.Set(RetryAfterHeader, retryAfter)
+		}
+		return resp
+	}
+	newRequest := func(t *testing.T, b *retryBudgeter, ctx context.Context) context.Context {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/keys", nil)
+		require.NoError(t, err)
+		require.NoError(t, b.customize(req))
+		return req.Context()
+	}
+
+	t.Run("gives up when the next backoff exceeds the budget", func(t *testing.T) {
+		clock := &budgetClock{now: start}
+		b := &retryBudgeter{
+			timeout: 90 * time.Second, waitMin: 10 * time.Second, waitMax: time.Minute, statusCodes: retryStatusCodes, now: clock.Now,
+			newSource: func() rand.Source { return maxSource{} },
+		}
+		ctx := newRequest(t, b, context.Background())
+		budget := ctx.Value(retryBudgetKey{}).(*retryBudget)
+
+		// 各試行に1秒かかり、その後ジッターの上限の10s/20s/40s待機してリトライする
+		for _, wait := range []time.Duration{10 * time.Second, 20 * time.Second, 40 * time.Second} {
+			clock.Advance(time.Second)
+			retry, err := b.checkRetry(ctx, unavailable(""), nil)
+			require.NoError(t, err)
+			require.True(t, retry)
+			require.Equal(t, wait, budget.takeWait())
+			clock.Advance(wait)
+		}
+
+		// 4回目の失敗は74s経過時点で、次の待機(60s)でバジェットの90sを超える
+		clock.Advance(time.Second)
+		retry, err := b.checkRetry(ctx, unavailable(""), nil)
+		assert.False(t, retry)
+		var budgetErr *RetryBudgetExceededError
+		require.ErrorAs(t, err, &budgetErr)
+		assert.Equal(t, 4, budgetErr.Attempts)
+		assert.EqualError(t, err, "gave up after 4 attempts over 74s (budget 90s)")
+	})
+
+	t.Run("Retry-After is counted as the backoff", func(t *testing.T) {
+		clock := &budgetClock{now: start}
+		b := &retryBudgeter{timeout: 90 * time.Second, waitMin: time.Second, waitMax: time.Minute, statusCodes: retryStatusCodes, now: clock.Now}
+		ctx := newRequest(t, b, context.Background())
+
+		clock.Advance(time.Second)
+		retry, err := b.checkRetry(ctx, unavailable("120"), nil)
+		assert.False(t, retry)
+		assert.EqualError(t, err, "gave up after 1 attempt over 1s (budget 90s)")
+	})
+
+	t.Run("Retry-After is waited instead of the library backoff", func(t *testing.T) {
+		clock := &budgetClock{now: start}
+		b := &retryBudgeter{timeout: 90 * time.Second, waitMin: time.Second, waitMax: time.Minute, statusCodes: retryStatusCodes, now: clock.Now}
+		ctx := newRequest(t, b, context.Background())
+
+		resp := unavailable("30")
+		retry, err := b.checkRetry(ctx, resp, nil)
+		require.NoError(t, err)
+		assert.True(t, retry)
+		assert.Equal(t, 30*time.Second, ctx.Value(retryBudgetKey{}).(*retryBudget).takeWait())
+		// go-httpが二重に待機しないよう取り除かれる
+		assert.Empty(t, resp.Header.Get(RetryAfterHeader))
+	})
+
+	t.Run("context deadline is shorter than api_request_timeout", func(t *testing.T) {
+		clock := &budgetClock{now: time.Now()}
+		b := &retryBudgeter{timeout: 300 * time.Second, waitMin: 10 * time.Second, waitMax: time.Minute, statusCodes: retryStatusCodes, now: clock.Now}
+		parent, cancel := context.WithDeadline(context.Background(), clock.Now().Add(15*time.Second))
+		defer cancel()
+		ctx := newRequest(t, b, parent)
+
+		clock.Advance(time.Second)
+		retry, err := b.checkRetry(ctx, unavailable(""), nil)
+		require.NoError(t, err)
+		assert.True(t, retry)
+
+		clock.Advance(10*time.Second + time.Second)
+		retry, err = b.checkRetry(ctx, unavailable(""), nil)
+		assert.False(t, retry)
+		assert.EqualError(t, err, "gave up after 2 attempts over 12s (budget 15s)")
+	})
+
+	t.Run("non-retryable responses do not consume the budget", func(t *testing.T) {
+		clock := &budgetClock{now: start}
+		b := &retryBudgeter{timeout: 90 * time.Second, waitMin: 10 * time.Second, waitMax: time.Minute, statusCodes: retryStatusCodes, now: clock.Now}
+		ctx := newRequest(t, b, context.Background())
+
+		retry, err := b.checkRetry(ctx, &http.Response{StatusCode: http.StatusBadRequest}, nil)
+		require.NoError(t, err)
+		assert.False(t, retry)
+		retry, err = b.checkRetry(ctx, &http.Response{StatusCode: http.StatusOK}, nil)
+		require.NoError(t, err)
+		assert.False(t, retry)
+	})
+}
+
+// unavailableTransport は常にメンテナンス以外の503を返す
+type unavailableTransport struct {
+	requests int
+}
+
+func (t *unavailableTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"error_code":"service_unavailable"}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestConfig_NewClient_retryBudget(t *testing.T) {
+	transport := &unavailableTransport{}
+	c := &Config{
+		AccessToken:       "token",
+		AccessTokenSecret: "secret",
+		APIRootURL:        "http://sakura.example.com",
+		APIRequestTimeout: 3,
+		RetryMax:          10,
+		RetryWaitMin:      2,
+		RetryWaitMax:      2,
+		HTTPTransport:     transport,
+	}
+	client, err := c.NewClient()
+	require.NoError(t, err)
+
+	// retry_maxの10回に達する前に、api_request_timeoutの3秒を超えるためリトライを打ち切る
+	keyOp, err := client.KMSKeyOp()
+	require.NoError(t, err)
+	_, err = keyOp.List(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "gave up after 2 attempts over 2s (budget 3s)")
+	assert.Equal(t, 2, transport.requests)
+}
+
+func TestFullJitterBackoff(t *testing.T) {
+	const (
+		waitMin = time.Second
+		waitMax = 30 * time.Second
+		samples = 1000
+	)
+	r := rand.New(rand.NewPCG(1, 2))
+
+	for attempt, upper := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 30 * time.Second, 30 * time.Second} {
+		var lowest, highest time.Duration = math.MaxInt64, 0
+		var total time.Duration
+		for range samples {
+			wait := fullJitterBackoff(waitMin, waitMax, attempt, r)
+			require.GreaterOrEqual(t, wait, waitMin)
+			require.LessOrEqual(t, wait, upper)
+			lowest, highest = min(lowest, wait), max(highest, wait)
+			total += wait
+		}
+		if upper == waitMin {
+			assert.Equal(t, waitMin, lowest)
+			assert.Equal(t, waitMin, highest)
+			continue
+		}
+		// 待機時間がばらけ、範囲全体に分布する
+		span := upper - waitMin
+		assert.Less(t, lowest, waitMin+span/10, "attempt %d", attempt)
+		assert.Greater(t, highest, upper-span/10, "attempt %d", attempt)
+		assert.InDelta(t, float64(waitMin+span/2), float64(total/samples), float64(span/10), "attempt %d", attempt)
+	}
+
+	t.Run("same seed gives the same waits", func(t *testing.T) {
+		r1, r2 := rand.New(rand.NewPCG(3, 4)), rand.New(rand.NewPCG(3, 4))
+		for attempt := range 10 {
+			assert.Equal(t, fullJitterBackoff(waitMin, waitMax, attempt, r1), fullJitterBackoff(waitMin, waitMax, attempt, r2))
+		}
+	})
+
+	t.Run("retry_wait_min is greater than retry_wait_max", func(t *testing.T) {
+		assert.Equal(t, 5*time.Second, fullJitterBackoff(10*time.Second, 5*time.Second, 3, r))
+	})
+
+	t.Run("large attempts do not overflow", func(t *testing.T) {
+		for range samples {
+			wait := fullJitterBackoff(waitMin, waitMax, 100, r)
+			require.GreaterOrEqual(t, wait, waitMin)
+			require.LessOrEqual(t, wait, waitMax)
+		}
+	})
+}
+
+func TestRetryBudgeter_seededPerRequest(t *testing.T) {
+	var seed uint64
+	b := &retryBudgeter{
+		timeout: time.Hour, waitMin: time.Second, waitMax: time.Minute, statusCodes: retryStatusCodes,
+		newSource: func() rand.Source {
+			seed++
+			return rand.NewPCG(seed, seed)
+		},
+	}
+	waits := func() []time.Duration {
+		req, err := http.NewRequest(http.MethodGet, "http://example.com/keys", nil)
+		require.NoError(t, err)
+		require.NoError(t, b.customize(req))
+		budget := req.Context().Value(retryBudgetKey{}).(*retryBudget)
+
+		var waits []time.Duration
+		for range 5 {
+			retry, err := b.checkRetry(req.Context(), &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}, nil)
+			require.NoError(t, err)
+			require.True(t, retry)
+			waits = append(waits, budget.takeWait())
+		}
+		return waits
+	}
+
+	// 同時にリトライする呼び出し同士で待機時間がずれる
+	first, second := waits(), waits()
+	assert.NotEqual(t, first, second)
+
+	// 同じシードであれば同じ待機時間となる
+	seed = 0
+	assert.Equal(t, first, waits())
+}
+
+// afterRecorder は待機せずに、要求された待機時間を記録する時計
+type afterRecorder struct {
+	waits []time.Duration
+}
+
+func (c *afterRecorder) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
+
+func TestRetryWaiter(t *testing.T) {
+	ok := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}")), Request: req}, nil
+	})
+	b := &retryBudgeter{timeout: time.Hour, waitMin: time.Second, waitMax: time.Minute, statusCodes: retryStatusCodes}
+	newRequest := func(t *testing.T, ctx context.Context) *http.Request {
+		t.Helper()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/keys", nil)
+		require.NoError(t, err)
+		require.NoError(t, b.customize(req))
+		return req
+	}
+
+	t.Run("waits the backoff before the retried attempt only", func(t *testing.T) {
+		clock := &afterRecorder{}
+		w := &retryWaiter{transport: ok, clock: clock}
+		req := newRequest(t, context.Background())
+
+		_, err := w.RoundTrip(req)
+		require.NoError(t, err)
+		assert.Empty(t, clock.waits)
+
+		retry, err := b.checkRetry(req.Context(), &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{RetryAfterHeader: []string{"3"}}}, nil)
+		require.NoError(t, err)
+		require.True(t, retry)
+		_, err = w.RoundTrip(req)
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{3 * time.Second}, clock.waits)
+	})
+
+	t.Run("canceled while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		req := newRequest(t, ctx)
+		retry, err := b.checkRetry(req.Context(), &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}}, nil)
+		require.NoError(t, err)
+		require.True(t, retry)
+
+		cancel()
+		_, err = (&retryWaiter{transport: ok}).RoundTrip(req)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,159 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	client "github.com/sacloud/api-client-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock は待機せずに即座に時間を進め、待機時間を記録する
+type fakeClock struct {
+	waits []time.Duration
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.waits = append(c.waits, d)
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
+
+// blockingClock は待機が終わらない時計。ctxの期限切れを検証するために利用する
+type blockingClock struct{}
+
+func (blockingClock) After(time.Duration) <-chan time.Time {
+	return make(chan time.Time)
+}
+
+func testBackoff(c clock, jitter float64) *Backoff {
+	return &Backoff{
+		InitialInterval: time.Second,
+		MaxInterval:     4 * time.Second,
+		Multiplier:      2,
+		clock:           c,
+		jitter:          func() float64 { return jitter },
+	}
+}
+
+func TestBackoff_Retry(t *testing.T) {
+	conflict := client.NewAPIError(http.StatusConflict, "", nil)
+	locked := client.NewAPIError(http.StatusLocked, "", nil)
+	serverError := client.NewAPIError(http.StatusInternalServerError, "", nil)
+
+	expects := []struct {
+		name      string
+		results   []error
+		jitter    float64
+		wantErr   error
+		wantCalls int
+		wantWaits []time.Duration
+	}{
+		{
+			name:      "succeeded",
+			results:   []error{nil},
+			wantCalls: 1,
+		},
+		{
+			name:      "not retryable",
+			results:   []error{serverError},
+			wantErr:   serverError,
+			wantCalls: 1,
+		},
+		{
+			name:      "succeeded after retries",
+			results:   []error{conflict, locked, conflict, conflict, nil},
+			wantCalls: 5,
+			// 待機時間の下限(interval/2)。上限はMaxIntervalで頭打ちになる
+			wantWaits: []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 2 * time.Second},
+		},
+		{
+			name:      "jitter",
+			results:   []error{conflict, conflict, nil},
+			jitter:    0.5,
+			wantCalls: 3,
+			wantWaits: []time.Duration{750 * time.Millisecond, 1500 * time.Millisecond},
+		},
+	}
+
+	for _, tc := range expects {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &fakeClock{}
+			calls := 0
+			err := testBackoff(c, tc.jitter).Retry(context.Background(), IsConflict, func() error {
+				err := tc.results[calls]
+				calls++
+				return err
+			})
+
+			assert.Equal(t, tc.wantErr, err)
+			assert.Equal(t, tc.wantCalls, calls)
+			assert.Equal(t, tc.wantWaits, c.waits)
+		})
+	}
+}
+
+func TestBackoff_Retry_deadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	conflict := errors.Join(errors.New("deleting vault"), client.NewAPIError(http.StatusConflict, "", nil))
+	calls := 0
+	err := testBackoff(blockingClock{}, 0).Retry(ctx, IsConflict, func() error {
+		calls++
+		return conflict
+	})
+
+	// 期限を過ぎた場合は最後のエラーと期限切れの両方を返す
+	assert.ErrorIs(t, err, conflict)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, calls)
+}
+
+func TestBackoff_Retry_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	conflict := client.NewAPIError(http.StatusConflict, "", nil)
+	b := &Backoff{InitialInterval: time.Hour, MaxInterval: time.Hour, Multiplier: 2}
+
+	calls := 0
+	start := time.Now()
+	err := b.Retry(ctx, IsConflict, func() error {
+		calls++
+		// 1回目の呼び出しの後、待機中にキャンセルする
+		time.AfterFunc(10*time.Millisecond, cancel)
+		return conflict
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, conflict)
+	assert.Equal(t, 1, calls)
+	assert.Less(t, time.Since(start), 5*time.Second, "Retry should return promptly after cancellation")
+}
+
+func TestIsConflict(t *testing.T) {
+	assert.True(t, IsConflict(client.NewAPIError(http.StatusConflict, "", nil)))
+	assert.True(t, IsConflict(client.NewAPIError(http.StatusLocked, "", nil)))
+	assert.False(t, IsConflict(client.NewAPIError(http.StatusNotFound, "", nil)))
+	assert.False(t, IsConflict(errors.New("conflict")))
+	assert.False(t, IsConflict(nil))
+}
@@ -0,0 +1,164 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+var (
+	_ basetypes.StringTypable                    = SakuraIDType{}
+	_ basetypes.StringValuableWithSemanticEquals = SakuraID{}
+	_ xattr.ValidateableAttribute                = SakuraID{}
+)
+
+// SakuraIDType は他のリソースを参照するID属性の型。stateでは文字列として扱う。
+// モジュールの出力やjsondecodeの結果を数値のまま渡した場合、Terraformが文字列に変換した"1.10000000001e+11"や
+// "110000000001.0"などの表記も受け付け、正規の表記("110000000001")と同じ値とみなす
+type SakuraIDType struct {
+	basetypes.StringType
+}
+
+func (t SakuraIDType) Equal(o attr.Type) bool {
+	other, ok := o.(SakuraIDType)
+	if !ok {
+		return false
+	}
+	return t.StringType.Equal(other.StringType)
+}
+
+func (t SakuraIDType) String() string {
+	return "SakuraIDType"
+}
+
+func (t SakuraIDType) ValueFromString(_ context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return SakuraID{StringValue: in}, nil
+}
+
+func (t SakuraIDType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	v, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	s, ok := v.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", v)
+	}
+	return SakuraID{StringValue: s}, nil
+}
+
+func (t SakuraIDType) ValueType(_ context.Context) attr.Value {
+	return SakuraID{}
+}
+
+// SakuraID はSakuraIDTypeの値
+type SakuraID struct {
+	basetypes.StringValue
+}
+
+func NewSakuraIDValue(v string) SakuraID {
+	return SakuraID{StringValue: basetypes.NewStringValue(v)}
+}
+
+func NewSakuraIDNull() SakuraID {
+	return SakuraID{StringValue: basetypes.NewStringNull()}
+}
+
+func NewSakuraIDUnknown() SakuraID {
+	return SakuraID{StringValue: basetypes.NewStringUnknown()}
+}
+
+func (v SakuraID) Equal(o attr.Value) bool {
+	other, ok := o.(SakuraID)
+	if !ok {
+		return false
+	}
+	return v.StringValue.Equal(other.StringValue)
+}
+
+func (v SakuraID) Type(_ context.Context) attr.Type {
+	return SakuraIDType{}
+}
+
+// CanonicalValue はAPIに送る正規の表記のIDを返す。IDとして解釈できない場合は値をそのまま返す
+func (v SakuraID) CanonicalValue() string {
+	if id, ok := CanonicalSakuraID(v.ValueString()); ok {
+		return id
+	}
+	return v.ValueString()
+}
+
+// StringSemanticEquals は正規の表記が同じであれば等しいとみなす。
+// 等しい場合、プラグインフレームワークはAPIから読み込んだ値ではなく設定値の表記をstateに保存する
+func (v SakuraID) StringSemanticEquals(_ context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	newValue, ok := newValuable.(SakuraID)
+	if !ok {
+		diags.AddError("Semantic Equality Check Error", fmt.Sprintf("expected value type %T, got %T", v, newValuable))
+		return false, diags
+	}
+	return v.CanonicalValue() == newValue.CanonicalValue(), diags
+}
+
+// ValidateAttribute はIDとして解釈できない値をエラーとする。SakuraIDValidatorの代わりに利用する
+func (v SakuraID) ValidateAttribute(_ context.Context, req xattr.ValidateAttributeRequest, resp *xattr.ValidateAttributeResponse) {
+	if v.IsNull() || v.IsUnknown() {
+		return
+	}
+	if _, ok := CanonicalSakuraID(v.ValueString()); !ok {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid SakuraCloud resource ID",
+			fmt.Sprintf("expected a SakuraCloud resource ID (numeric), got %q — did you mean to use the name attribute?", v.ValueString()))
+	}
+}
+
+// SameSakuraID はaとbが同じIDを表す場合にtrueを返す
+func SameSakuraID(a, b string) bool {
+	return NewSakuraIDValue(a).CanonicalValue() == NewSakuraIDValue(b).CanonicalValue()
+}
+
+// CanonicalSakuraID はvalueを正規の表記のIDに変換する。
+// 数字のみの文字列に加えて、数値を文字列に変換した"1.10000000001e+11"などの表記も受け付ける。
+// float64を経由すると2^53を超えるIDの精度が失われるため、有理数として厳密に解釈する
+func CanonicalSakuraID(value string) (string, bool) {
+	if value == "" {
+		return "", false
+	}
+	if strings.TrimLeft(value, "0123456789") == "" {
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatInt(n, 10), true
+	}
+	// 符号や空白、16進数などの表記は受け付けない
+	if strings.TrimLeft(value, "0123456789.eE+") != "" || strings.HasPrefix(value, "+") {
+		return "", false
+	}
+	r, ok := new(big.Rat).SetString(value)
+	if !ok || !r.IsInt() || !r.Num().IsInt64() {
+		return "", false
+	}
+	return r.Num().String(), true
+}
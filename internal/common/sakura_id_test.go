@@ -0,0 +1,141 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalSakuraID(t *testing.T) {
+	expects := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{in: "110000000001", want: "110000000001", ok: true},
+		{in: "0110000000001", want: "110000000001", ok: true},
+		{in: "110000000001.0", want: "110000000001", ok: true},
+		{in: "1.10000000001e+11", want: "110000000001", ok: true},
+		{in: "1.10000000001E11", want: "110000000001", ok: true},
+		// float64では精度が失われる大きなID
+		{in: "9007199254740993", want: "9007199254740993", ok: true},
+		{in: "9.007199254740993e15", want: "9007199254740993", ok: true},
+		{in: "9223372036854775807", want: "9223372036854775807", ok: true},
+		{in: "9223372036854775808", ok: false},
+		{in: "9.223372036854775808e18", ok: false},
+		{in: "1.5", ok: false},
+		{in: "1.100000000011e+11", ok: false},
+		{in: "-110000000001", ok: false},
+		{in: "+110000000001", ok: false},
+		{in: " 110000000001", ok: false},
+		{in: "0x19999999", ok: false},
+		{in: "my-key", ok: false},
+		{in: "", ok: false},
+	}
+	for _, tc := range expects {
+		t.Run(tc.in, func(t *testing.T) {
+			got, ok := CanonicalSakuraID(tc.in)
+			assert.Equal(t, tc.ok, ok)
+			if tc.ok {
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestSakuraID_StringSemanticEquals(t *testing.T) {
+	ctx := context.Background()
+
+	expects := []struct {
+		prior string
+		new   string
+		want  bool
+	}{
+		{prior: "110000000001", new: "110000000001", want: true},
+		{prior: "1.10000000001e+11", new: "110000000001", want: true},
+		{prior: "110000000001.0", new: "110000000001", want: true},
+		{prior: "9.007199254740993e15", new: "9007199254740993", want: true},
+		{prior: "9.007199254740993e15", new: "9007199254740992", want: false},
+		{prior: "110000000001", new: "110000000002", want: false},
+		{prior: "my-key", new: "my-key", want: true},
+	}
+	for _, tc := range expects {
+		t.Run(tc.prior+"/"+tc.new, func(t *testing.T) {
+			got, diags := NewSakuraIDValue(tc.prior).StringSemanticEquals(ctx, NewSakuraIDValue(tc.new))
+			require.False(t, diags.HasError(), diags)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	t.Run("other value type", func(t *testing.T) {
+		_, diags := NewSakuraIDValue("110000000001").StringSemanticEquals(ctx, types.StringValue("110000000001"))
+		assert.True(t, diags.HasError())
+	})
+}
+
+func TestSakuraID_Equal(t *testing.T) {
+	// Equalは表記の違いを区別し、planの差分の判定はStringSemanticEqualsで行う
+	assert.True(t, NewSakuraIDValue("110000000001").Equal(NewSakuraIDValue("110000000001")))
+	assert.False(t, NewSakuraIDValue("1.10000000001e+11").Equal(NewSakuraIDValue("110000000001")))
+	assert.False(t, NewSakuraIDValue("110000000001").Equal(types.StringValue("110000000001")))
+	assert.True(t, NewSakuraIDNull().IsNull())
+	assert.Equal(t, "110000000001", NewSakuraIDValue("1.10000000001e+11").CanonicalValue())
+}
+
+func TestSakuraID_ValidateAttribute(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		value   SakuraID
+		wantErr bool
+	}{
+		{value: NewSakuraIDValue("110000000001")},
+		{value: NewSakuraIDValue("1.10000000001e+11")},
+		{value: NewSakuraIDNull()},
+		{value: SakuraID{StringValue: types.StringUnknown()}},
+		{value: NewSakuraIDValue("my-key"), wantErr: true},
+		{value: NewSakuraIDValue("1.5"), wantErr: true},
+	} {
+		t.Run(tc.value.String(), func(t *testing.T) {
+			resp := &xattr.ValidateAttributeResponse{}
+			tc.value.ValidateAttribute(ctx, xattr.ValidateAttributeRequest{Path: path.Root("kms_key_id")}, resp)
+			assert.Equal(t, tc.wantErr, resp.Diagnostics.HasError(), resp.Diagnostics)
+		})
+	}
+}
+
+func TestSakuraIDType_ValueFromTerraform(t *testing.T) {
+	ctx := context.Background()
+	typ := SakuraIDType{}
+
+	v, err := typ.ValueFromTerraform(ctx, tftypes.NewValue(tftypes.String, "110000000001"))
+	require.NoError(t, err)
+	assert.Equal(t, NewSakuraIDValue("110000000001"), v)
+
+	v, err = typ.ValueFromTerraform(ctx, tftypes.NewValue(tftypes.String, nil))
+	require.NoError(t, err)
+	assert.Equal(t, NewSakuraIDNull(), v)
+
+	assert.True(t, typ.Equal(NewSakuraIDValue("x").Type(ctx)))
+	assert.False(t, typ.Equal(types.StringType))
+}
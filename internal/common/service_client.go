@@ -0,0 +1,173 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"net/http"
+	"sync"
+
+	client "github.com/sacloud/api-client-go"
+	sacloudhttp "github.com/sacloud/go-http"
+	"github.com/sacloud/simplemq-api-go"
+	"github.com/sacloud/simplemq-api-go/apis/v1/queue"
+
+	kms "github.com/sacloud/kms-api-go"
+	kmsapi "github.com/sacloud/kms-api-go/apis/v1"
+	sm "github.com/sacloud/secretmanager-api-go"
+	smapi "github.com/sacloud/secretmanager-api-go/apis/v1"
+)
+
+const (
+	serviceKMS           = "kms"
+	serviceSecretManager = "secretmanager"
+	serviceSimpleMQ      = "simplemq"
+)
+
+type serviceClientKey struct {
+	service string
+	zone    string
+}
+
+type serviceClientEntry struct {
+	once   sync.Once
+	client any
+	err    error
+}
+
+// serviceClients はサービス/ゾーンごとのAPIクライアントを初回利用時に1度だけ生成して保持する
+type serviceClients struct {
+	mu      sync.Mutex
+	entries map[serviceClientKey]*serviceClientEntry
+}
+
+func (c *serviceClients) entry(service, zone string) *serviceClientEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[serviceClientKey]*serviceClientEntry)
+	}
+	key := serviceClientKey{service: service, zone: zone}
+	e, ok := c.entries[key]
+	if !ok {
+		e = &serviceClientEntry{}
+		c.entries[key] = e
+	}
+	return e
+}
+
+// serviceClient はservice/zoneのクライアントを返す。未生成の場合はbuildで生成する。
+// 生成に失敗した場合もその結果を保持し、以降の呼び出しでは同じエラーを返す
+func serviceClient[T any](c *serviceClients, service, zone string, build func() (T, error)) (T, error) {
+	e := c.entry(service, zone)
+	e.once.Do(func() {
+		e.client, e.err = build()
+	})
+	if e.err != nil {
+		var zero T
+		return zero, e.err
+	}
+	return e.client.(T), nil
+}
+
+// serviceDoer はiaas以外のサービスのクライアント向けに、transportを共有したHTTPクライアントとエンドポイントを返す
+func (c *APIClient) serviceDoer(apiURL string) (string, client.HttpRequestDoer, error) {
+	opts := *c.CallerOptions
+	transport := c.transport
+	// api-client-goのトレースはボディをそのまま出力するため、シークレットの値などを伏せて出力するredactingTracerに置き換える
+	if opts.Trace {
+		opts.Trace = false
+		transport = &redactingTracer{transport: transport}
+	}
+	// api-client-goはhttp.ClientのTransportやTimeoutを書き換えるため、http.Clientはクライアントごとに用意する
+	opts.HttpClient = newHTTPClient(transport)
+	apiClient, err := client.NewClient(apiURL, client.WithOptions(&opts))
+	if err != nil {
+		return "", nil, err
+	}
+
+	return apiClient.ServerURL(), withoutBackoff(apiClient.NewHttpRequestDoer()), nil
+}
+
+// copyingDoer はリクエストごとにsacloudhttp.Clientの複製を利用する。
+// sacloudhttp.Client.Doは呼び出しのたびに未設定のフィールドへデフォルト値を書き込むため、共有したまま並行に呼び出すとデータ競合となる
+type copyingDoer struct {
+	client sacloudhttp.Client
+}
+
+func (d *copyingDoer) Do(req *http.Request) (*http.Response, error) {
+	c := d.client
+	return c.Do(req)
+}
+
+// KMSClient はKMSのAPIクライアントを返す
+func (c *APIClient) KMSClient() (*kmsapi.Client, error) {
+	return serviceClient(&c.services, serviceKMS, serviceAPIZone, func() (*kmsapi.Client, error) {
+		serverURL, doer, err := c.serviceDoer(serviceAPIURL(c.apiRootURL, serviceAPIZone, kms.DefaultAPIRootURL))
+		if err != nil {
+			return nil, kms.NewError("NewClient", err)
+		}
+		return kmsapi.NewClient(serverURL, kms.DummySecuritySource{}, kmsapi.WithClient(doer))
+	})
+}
+
+// SecretManagerClient はシークレットマネージャのAPIクライアントを返す
+func (c *APIClient) SecretManagerClient() (*smapi.Client, error) {
+	return serviceClient(&c.services, serviceSecretManager, serviceAPIZone, func() (*smapi.Client, error) {
+		serverURL, doer, err := c.serviceDoer(serviceAPIURL(c.apiRootURL, serviceAPIZone, sm.DefaultAPIRootURL))
+		if err != nil {
+			return nil, sm.NewError("NewClient", err)
+		}
+		return smapi.NewClient(serverURL, sm.DummySecuritySource{}, smapi.WithClient(doer))
+	})
+}
+
+// SimpleMQClient はシンプルMQのAPIクライアントを返す
+func (c *APIClient) SimpleMQClient() (*queue.Client, error) {
+	return serviceClient(&c.services, serviceSimpleMQ, serviceAPIZone, func() (*queue.Client, error) {
+		serverURL, doer, err := c.serviceDoer(simplemq.DefaultQueueAPIRootURL)
+		if err != nil {
+			return nil, simplemq.NewError("NewClient", err)
+		}
+		return queue.NewClient(serverURL, simplemq.DummySecuritySource{Token: "simplemq-client"}, queue.WithClient(doer))
+	})
+}
+
+// KMSKeyOp はKMSのキーを操作するAPIを返す
+func (c *APIClient) KMSKeyOp() (kms.KeyAPI, error) {
+	kmsClient, err := c.KMSClient()
+	if err != nil {
+		return nil, err
+	}
+	return &cacheInvalidatingKeyOp{KeyAPI: kms.NewKeyOp(kmsClient), cache: &c.listCache}, nil
+}
+
+// SecretManagerVaultOp はシークレットマネージャのボールトを操作するAPIを返す
+func (c *APIClient) SecretManagerVaultOp() (sm.VaultAPI, error) {
+	smClient, err := c.SecretManagerClient()
+	if err != nil {
+		return nil, err
+	}
+	return &cacheInvalidatingVaultOp{VaultAPI: sm.NewVaultOp(smClient), cache: &c.listCache}, nil
+}
+
+// SecretManagerSecretOp は指定したボールト内のシークレットを操作するAPIを返す
+func (c *APIClient) SecretManagerSecretOp(vaultID string) (sm.SecretAPI, error) {
+	smClient, err := c.SecretManagerClient()
+	if err != nil {
+		return nil, err
+	}
+	return &cacheInvalidatingSecretOp{SecretAPI: sm.NewSecretOp(smClient, vaultID), cache: &c.listCache}, nil
+}
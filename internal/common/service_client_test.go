@@ -0,0 +1,270 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listTransport はKMSとシークレットマネージャの空の一覧を返し、受け付けたリクエストのパスを記録する
+type listTransport struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (t *listTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.paths = append(t.paths, req.URL.Path)
+	t.mu.Unlock()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"Count":0,"From":0,"Total":0,"Keys":[],"Vaults":[]}`)),
+		Request:    req,
+	}, nil
+}
+
+func newServiceClientTestClient(t *testing.T, transport http.RoundTripper) *APIClient {
+	t.Helper()
+	client, err := (&Config{
+		AccessToken:         "token",
+		AccessTokenSecret:   "secret",
+		APIRootURL:          "http://sakura.example.com",
+		APIRequestRateLimit: 100,
+		HTTPTransport:       transport,
+	}).NewClient()
+	require.NoError(t, err)
+	return client
+}
+
+func TestServiceClient(t *testing.T) {
+	t.Run("builds once per service and zone", func(t *testing.T) {
+		var clients serviceClients
+		var builds atomic.Int32
+		build := func() (*int, error) {
+			builds.Add(1)
+			return new(int), nil
+		}
+
+		var wg sync.WaitGroup
+		results := make([]*int, 50)
+		for i := range results {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				v, err := serviceClient(&clients, "kms", "tk1a", build)
+				assert.NoError(t, err)
+				results[i] = v
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), builds.Load())
+		for _, v := range results {
+			assert.Same(t, results[0], v)
+		}
+
+		other, err := serviceClient(&clients, "kms", "is1a", build)
+		require.NoError(t, err)
+		assert.NotSame(t, results[0], other)
+		assert.Equal(t, int32(2), builds.Load())
+	})
+
+	t.Run("keeps the build error", func(t *testing.T) {
+		var clients serviceClients
+		var builds int
+		build := func() (*int, error) {
+			builds++
+			return nil, assert.AnError
+		}
+		for range 2 {
+			_, err := serviceClient(&clients, "kms", "tk1a", build)
+			assert.ErrorIs(t, err, assert.AnError)
+		}
+		assert.Equal(t, 1, builds)
+	})
+}
+
+func TestAPIClient_serviceClients(t *testing.T) {
+	t.Run("concurrent operations share one client per service", func(t *testing.T) {
+		transport := &listTransport{}
+		client := newServiceClientTestClient(t, transport)
+
+		kmsClient, err := client.KMSClient()
+		require.NoError(t, err)
+		smClient, err := client.SecretManagerClient()
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				c, err := client.KMSClient()
+				assert.NoError(t, err)
+				assert.Same(t, kmsClient, c)
+				_, _, err = client.KMSKeyList(context.Background())
+				assert.NoError(t, err)
+			}()
+			go func() {
+				defer wg.Done()
+				c, err := client.SecretManagerClient()
+				assert.NoError(t, err)
+				assert.Same(t, smClient, c)
+				_, _, err = client.SecretManagerVaultList(context.Background())
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+
+		// どちらのサービスへのリクエストも共有したtransportを経由する
+		assert.Len(t, transport.paths, 40)
+		assert.Contains(t, transport.paths, "/tk1a/api/cloud/1.1/kms/keys")
+		assert.Contains(t, transport.paths, "/tk1a/api/cloud/1.1/secretmanager/vaults")
+	})
+
+	t.Run("built on first use", func(t *testing.T) {
+		client := newServiceClientTestClient(t, &listTransport{})
+		assert.Empty(t, client.services.entries, "NewClient must not build service clients")
+
+		_, _, err := client.SecretManagerVaultList(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, client.services.entries, 1)
+		assert.Contains(t, client.services.entries, serviceClientKey{service: serviceSecretManager, zone: serviceAPIZone})
+	})
+
+	t.Run("misconfigured service does not affect others", func(t *testing.T) {
+		client := newServiceClientTestClient(t, &listTransport{})
+		misconfigured := errors.New("invalid SimpleMQ endpoint")
+		e := client.services.entry(serviceSimpleMQ, serviceAPIZone)
+		e.once.Do(func() { e.err = misconfigured })
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_, err := client.SimpleMQClient()
+				assert.ErrorIs(t, err, misconfigured)
+			}()
+			go func() {
+				defer wg.Done()
+				_, _, err := client.KMSKeyList(context.Background())
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("getters do not allocate after the first call", func(t *testing.T) {
+		client := newServiceClientTestClient(t, &listTransport{})
+		_, err := client.KMSClient()
+		require.NoError(t, err)
+		_, err = client.SecretManagerClient()
+		require.NoError(t, err)
+		_, err = client.SimpleMQClient()
+		require.NoError(t, err)
+
+		allocs := testing.AllocsPerRun(100, func() {
+			_, _ = client.KMSClient()
+			_, _ = client.SecretManagerClient()
+			_, _ = client.SimpleMQClient()
+		})
+		assert.Zero(t, allocs)
+	})
+}
+
+func TestAPIClient_listTruncated(t *testing.T) {
+	const key = `{"ID":"110000000001","Name":"foo","KeyOrigin":"generated","Status":"active","Tags":[],"CreatedAt":"2025-01-01T00:00:00Z","ModifiedAt":"2025-01-01T00:00:00Z","LatestVersion":1}`
+	newClient := func(t *testing.T, body string) *APIClient {
+		t.Helper()
+		return newServiceClientTestClient(t, roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}))
+	}
+
+	t.Run("total exceeds the returned keys", func(t *testing.T) {
+		client := newClient(t, `{"Count":1,"From":0,"Total":3,"Keys":[`+key+`]}`)
+		keys, truncated, err := client.KMSKeyList(context.Background())
+		require.NoError(t, err)
+		assert.Len(t, keys, 1)
+		assert.True(t, truncated)
+
+		// キャッシュから返す場合も結果が欠けていることを返す
+		ctx := WithListCache(context.Background())
+		for range 2 {
+			_, truncated, err = client.KMSKeyList(ctx)
+			require.NoError(t, err)
+			assert.True(t, truncated)
+		}
+	})
+
+	t.Run("all keys are returned", func(t *testing.T) {
+		client := newClient(t, `{"Count":1,"From":0,"Total":1,"Keys":[`+key+`]}`)
+		_, truncated, err := client.KMSKeyList(context.Background())
+		require.NoError(t, err)
+		assert.False(t, truncated)
+	})
+
+	t.Run("total is not returned", func(t *testing.T) {
+		client := newClient(t, `{"Count":1,"Keys":[`+key+`]}`)
+		_, truncated, err := client.KMSKeyList(context.Background())
+		require.NoError(t, err)
+		assert.False(t, truncated)
+	})
+
+	t.Run("vaults and secrets", func(t *testing.T) {
+		client := newClient(t, `{"Count":0,"From":0,"Total":2,"Vaults":[],"Secrets":[]}`)
+		_, truncated, err := client.SecretManagerVaultList(context.Background())
+		require.NoError(t, err)
+		assert.True(t, truncated)
+		_, truncated, err = client.SecretManagerSecretList(context.Background(), "110000000001")
+		require.NoError(t, err)
+		assert.True(t, truncated)
+	})
+}
+
+func BenchmarkAPIClient_KMSClient(b *testing.B) {
+	client, err := (&Config{
+		AccessToken:       "token",
+		AccessTokenSecret: "secret",
+		HTTPTransport:     &listTransport{},
+	}).NewClient()
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := client.KMSClient(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,47 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/filter"
+)
+
+// 一覧APIが返す順序は保証されないため、複数のリソースをlistとしてstateに保存するデータソースは、
+// 保存前にここのヘルパーで並べ替えること。for_eachのキーに利用された場合に、順序の変化で依存するリソースが再作成されるのを防ぐ。
+// タグはFlattenTagsで辞書順に並べ替える。サーバーのnetwork_interfaceやパケットフィルタのexpressionのように、
+// 順序自体に意味があるlistはAPIの順序のまま保存する
+
+// SortByName はitemsを名前の昇順で並べ替える。名前が同じ場合はIDの昇順とする
+func SortByName[T any](items []T, attributes func(T) filter.Attributes) {
+	slices.SortStableFunc(items, func(a, b T) int {
+		x, y := attributes(a), attributes(b)
+		return cmp.Or(cmp.Compare(x.Name, y.Name), compareID(x.ID, y.ID))
+	})
+}
+
+// SortVersionsDesc はitemsをバージョンの降順(新しいものが先頭)で並べ替える
+func SortVersionsDesc[T any](items []T, version func(T) int) {
+	slices.SortStableFunc(items, func(a, b T) int {
+		return cmp.Compare(version(b), version(a))
+	})
+}
+
+// compareID はSakuraCloudのリソースIDを数値として比較する。桁数の異なるIDも数値の順序になるよう、桁数を先に比較する
+func compareID(a, b string) int {
+	return cmp.Or(cmp.Compare(len(a), len(b)), cmp.Compare(a, b))
+}
@@ -0,0 +1,65 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/filter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortByName(t *testing.T) {
+	attributes := func(v filter.Attributes) filter.Attributes { return v }
+	items := []filter.Attributes{
+		{ID: "110000000003", Name: "key-b"},
+		{ID: "110000000002", Name: "key-a"},
+		{ID: "99", Name: "key-c"},
+		{ID: "110000000001", Name: "key-c"},
+		{ID: "110000000004", Name: "Key-a"},
+	}
+
+	SortByName(items, attributes)
+	assert.Equal(t, []filter.Attributes{
+		{ID: "110000000004", Name: "Key-a"},
+		{ID: "110000000002", Name: "key-a"},
+		{ID: "110000000003", Name: "key-b"},
+		{ID: "99", Name: "key-c"},
+		{ID: "110000000001", Name: "key-c"},
+	}, items)
+
+	// APIの返す順序が変わっても結果は同じ
+	reversed := []filter.Attributes{items[4], items[3], items[2], items[1], items[0]}
+	SortByName(reversed, attributes)
+	assert.Equal(t, items, reversed)
+}
+
+func TestSortVersionsDesc(t *testing.T) {
+	type version struct {
+		Version int
+		Value   string
+	}
+	items := []version{{2, "b"}, {10, "c"}, {1, "a"}}
+
+	SortVersionsDesc(items, func(v version) int { return v.Version })
+	assert.Equal(t, []version{{10, "c"}, {2, "b"}, {1, "a"}}, items)
+}
+
+func TestFlattenTags_order(t *testing.T) {
+	a := FlattenTags([]string{"tag2", "@auto-reboot", "tag1", "Tag1"})
+	b := FlattenTags([]string{"tag1", "Tag1", "tag2", "@auto-reboot"})
+	assert.Equal(t, a, b)
+	assert.Equal(t, []string{"@auto-reboot", "Tag1", "tag1", "tag2"}, TsetToStrings(a))
+}
@@ -37,11 +37,20 @@ func SakuraCloudID(id string) iaastypes.ID {
 	return iaastypes.StringID(id)
 }
 
-func ExpandSakuraCloudID(d basetypes.StringValue) iaastypes.ID {
+// stringValuer はtypes.StringとSakuraIDに共通するメソッドを持つ値
+type stringValuer interface {
+	IsNull() bool
+	IsUnknown() bool
+	ValueString() string
+}
+
+func ExpandSakuraCloudID(d stringValuer) iaastypes.ID {
 	if d.IsNull() || d.IsUnknown() {
 		return iaastypes.ID(0)
 	}
-
+	if id, ok := d.(SakuraID); ok {
+		return SakuraCloudID(id.CanonicalValue())
+	}
 	return SakuraCloudID(d.ValueString())
 }
 
@@ -68,7 +77,7 @@ func GetZone(zone basetypes.StringValue, client *APIClient, diags *diag.Diagnost
 	}
 
 	z := zone.ValueString()
-	if err := StringInSlice(client.zones, "zone", z, false); err != nil {
+	if err := CheckZoneAllowed(z, client.GetZones()); err != nil {
 		diags.AddError("Get zone error", err.Error())
 		return ""
 	}
@@ -132,6 +141,12 @@ func StringsToTset(tags []string) types.Set {
 	return setValue
 }
 
+func StringsToTlist(values []string) types.List {
+	// StringsToTsetと同様にcontext.Background()を利用する
+	listValue, _ := types.ListValueFrom(context.Background(), types.StringType, values)
+	return listValue
+}
+
 func IntToInt32(i int) int32 {
 	return int32(i)
 }
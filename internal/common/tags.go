@@ -0,0 +1,123 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// NormalizeTags はタグを並べ替え、重複を取り除いたスライスを返す。
+// タグは大文字小文字を区別するため、大文字小文字のみが異なるタグや@で始まる特殊タグの表記はそのまま保持する
+func NormalizeTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+	normalized := slices.Clone(tags)
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
+// ExpandTags はtagsの値をAPIに送るタグのスライスに変換する
+func ExpandTags(d types.Set) []string {
+	return NormalizeTags(TsetToStrings(d))
+}
+
+// FlattenTags はAPIから取得したタグをstateに設定する値に変換する。
+// タグが無い場合はnullではなく空のsetを返し、作成直後とimport後のReadで同じ値になるようにする
+func FlattenTags(tags []string) types.Set {
+	normalized := NormalizeTags(tags)
+	if normalized == nil {
+		normalized = []string{}
+	}
+	return StringsToTset(normalized)
+}
+
+// SystemTagPrefix はプラットフォームや他のツールが付与する特殊タグ(システムタグ)の接頭辞
+const SystemTagPrefix = "@"
+
+// IsSystemTag はtagが@で始まるシステムタグの場合にtrueを返す
+func IsSystemTag(tag string) bool {
+	return strings.HasPrefix(tag, SystemTagPrefix)
+}
+
+// MergeSystemTags は更新時にAPIへ送るタグを返す。
+// ignoreSystemTagsがtrueの場合、desiredに加えてremoteに存在するシステムタグのうちpriorに含まれないもの(Terraform外で付与されたもの)を保持する。
+// priorに含まれるシステムタグはTerraformの管理下にあるため、desiredから取り除かれた場合は削除する。falseの場合はdesiredをそのまま返す
+func MergeSystemTags(desired, remote []string, prior types.Set, ignoreSystemTags bool) []string {
+	if !ignoreSystemTags {
+		return NormalizeTags(desired)
+	}
+	known := TsetToStrings(prior)
+	merged := slices.Clone(desired)
+	for _, tag := range remote {
+		if IsSystemTag(tag) && !slices.Contains(known, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	return NormalizeTags(merged)
+}
+
+// ManagedTags はAPIから取得したタグのうち、stateに保存するタグを返す。
+// ignoreSystemTagsがtrueの場合、priorに含まれないシステムタグを取り除き、Terraform外で付与されたシステムタグを差分としない
+func ManagedTags(remote []string, prior types.Set, ignoreSystemTags bool) []string {
+	if !ignoreSystemTags {
+		return NormalizeTags(remote)
+	}
+	known := TsetToStrings(prior)
+	var managed []string
+	for _, tag := range remote {
+		if IsSystemTag(tag) && !slices.Contains(known, tag) {
+			continue
+		}
+		managed = append(managed, tag)
+	}
+	return NormalizeTags(managed)
+}
+
+// emptyTagsForNull はtagsが未指定(null)で、stateのタグも空の場合にplanを空のsetとするplan modifier。
+// FlattenTagsはタグが無い場合に空のsetを返すため、未指定、nullとtags = []を切り替えても差分や"known after apply"とならないようにする。
+// stateにタグがある場合に未指定とした際の動作は変えない
+type emptyTagsForNull struct{}
+
+var _ planmodifier.Set = emptyTagsForNull{}
+
+// EmptyTagsForNull はnull/未指定と空のtagsを同じ値として扱うplan modifierを返す
+func EmptyTagsForNull() planmodifier.Set {
+	return emptyTagsForNull{}
+}
+
+func (m emptyTagsForNull) Description(_ context.Context) string {
+	return "Treats null or unset tags as an empty set when the resource has no tags."
+}
+
+func (m emptyTagsForNull) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m emptyTagsForNull) PlanModifySet(_ context.Context, req planmodifier.SetRequest, resp *planmodifier.SetResponse) {
+	if !req.ConfigValue.IsNull() {
+		return
+	}
+	if !req.StateValue.IsNull() && len(req.StateValue.Elements()) > 0 {
+		return
+	}
+	resp.PlanValue = types.SetValueMust(types.StringType, []attr.Value{})
+}
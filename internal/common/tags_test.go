@@ -0,0 +1,166 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	cases := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{name: "nil", tags: nil, want: nil},
+		{name: "empty", tags: []string{}, want: []string{}},
+		{name: "sorted", tags: []string{"tag2", "tag1", "tag3"}, want: []string{"tag1", "tag2", "tag3"}},
+		{name: "duplicates", tags: []string{"tag1", "tag2", "tag1", "tag2"}, want: []string{"tag1", "tag2"}},
+		{name: "mixed-case duplicates", tags: []string{"tag1", "Tag1", "TAG1", "tag1"}, want: []string{"TAG1", "Tag1", "tag1"}},
+		{
+			name: "special tags keep their case",
+			tags: []string{"web", "@keyboard-us", "@auto-reboot", "@nic-double-queue", "@auto-reboot"},
+			want: []string{"@auto-reboot", "@keyboard-us", "@nic-double-queue", "web"},
+		},
+		{name: "special tags with upper case", tags: []string{"@group=A", "@group=a"}, want: []string{"@group=A", "@group=a"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, NormalizeTags(tc.tags))
+		})
+	}
+}
+
+func TestNormalizeTags_doesNotModifyInput(t *testing.T) {
+	tags := []string{"tag2", "tag1", "tag2"}
+	NormalizeTags(tags)
+	assert.Equal(t, []string{"tag2", "tag1", "tag2"}, tags)
+}
+
+func TestExpandTags(t *testing.T) {
+	assert.Nil(t, ExpandTags(types.SetNull(types.StringType)))
+	assert.Nil(t, ExpandTags(types.SetUnknown(types.StringType)))
+	assert.Equal(t, []string{"@auto-reboot", "Tag2", "tag1"}, ExpandTags(types.SetValueMust(types.StringType, []attr.Value{
+		types.StringValue("tag1"), types.StringValue("@auto-reboot"), types.StringValue("Tag2"),
+	})))
+}
+
+func TestFlattenTags(t *testing.T) {
+	empty := types.SetValueMust(types.StringType, []attr.Value{})
+	assert.Equal(t, empty, FlattenTags(nil))
+	assert.Equal(t, empty, FlattenTags([]string{}))
+	assert.Equal(t, types.SetValueMust(types.StringType, []attr.Value{
+		types.StringValue("tag1"), types.StringValue("tag2"),
+	}), FlattenTags([]string{"tag2", "tag1", "tag2"}))
+}
+
+func TestMergeSystemTags(t *testing.T) {
+	cases := []struct {
+		name    string
+		desired []string
+		remote  []string
+		prior   []string
+		ignore  bool
+		want    []string
+	}{
+		{name: "no system tags", desired: []string{"tag2"}, remote: []string{"tag1"}, ignore: true, want: []string{"tag2"}},
+		{name: "remote system tags are kept", desired: []string{"tag2"}, remote: []string{"tag1", "@auto-reboot"}, ignore: true, want: []string{"@auto-reboot", "tag2"}},
+		{name: "listed system tags", desired: []string{"@auto-reboot", "tag1"}, remote: []string{"@auto-reboot"}, ignore: true, want: []string{"@auto-reboot", "tag1"}},
+		{name: "listed system tags not on remote", desired: []string{"@keyboard-us"}, remote: []string{"@auto-reboot"}, ignore: true, want: []string{"@auto-reboot", "@keyboard-us"}},
+		{name: "empty desired", desired: []string{}, remote: []string{"tag1", "@auto-reboot"}, ignore: true, want: []string{"@auto-reboot"}},
+		{name: "nil remote", desired: []string{"tag1"}, remote: nil, ignore: true, want: []string{"tag1"}},
+		{name: "managed system tags removed from config", desired: []string{"tag1"}, remote: []string{"tag1", "@auto-reboot", "@keyboard-us"}, prior: []string{"tag1", "@auto-reboot"}, ignore: true, want: []string{"@keyboard-us", "tag1"}},
+		{name: "remote system tags are removed when not ignored", desired: []string{"tag2"}, remote: []string{"tag1", "@auto-reboot"}, ignore: false, want: []string{"tag2"}},
+		{name: "listed system tags when not ignored", desired: []string{"@keyboard-us"}, remote: []string{"@auto-reboot"}, ignore: false, want: []string{"@keyboard-us"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			prior := types.SetNull(types.StringType)
+			if tc.prior != nil {
+				prior = StringsToTset(tc.prior)
+			}
+			assert.Equal(t, tc.want, MergeSystemTags(tc.desired, tc.remote, prior, tc.ignore))
+		})
+	}
+}
+
+func TestManagedTags(t *testing.T) {
+	prior := func(tags ...string) types.Set {
+		return StringsToTset(tags)
+	}
+	cases := []struct {
+		name   string
+		remote []string
+		prior  types.Set
+		ignore bool
+		want   []string
+	}{
+		{name: "no system tags", remote: []string{"tag2", "tag1"}, prior: prior("tag1"), ignore: true, want: []string{"tag1", "tag2"}},
+		{name: "unlisted system tags are excluded", remote: []string{"tag1", "@auto-reboot"}, prior: prior("tag1"), ignore: true, want: []string{"tag1"}},
+		{name: "listed system tags are kept", remote: []string{"tag1", "@auto-reboot"}, prior: prior("tag1", "@auto-reboot"), ignore: true, want: []string{"@auto-reboot", "tag1"}},
+		{name: "null prior", remote: []string{"tag1", "@auto-reboot"}, prior: types.SetNull(types.StringType), ignore: true, want: []string{"tag1"}},
+		{name: "unknown prior", remote: []string{"@auto-reboot"}, prior: types.SetUnknown(types.StringType), ignore: true, want: nil},
+		{name: "system tags are kept when not ignored", remote: []string{"tag1", "@auto-reboot"}, prior: prior("tag1"), ignore: false, want: []string{"@auto-reboot", "tag1"}},
+		{name: "no tags", remote: nil, prior: prior(), ignore: false, want: nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ManagedTags(tc.remote, tc.prior, tc.ignore))
+		})
+	}
+}
+
+func TestEmptyTagsForNull(t *testing.T) {
+	ctx := context.Background()
+	empty := types.SetValueMust(types.StringType, []attr.Value{})
+	tags := types.SetValueMust(types.StringType, []attr.Value{types.StringValue("tag1")})
+	nullSet := types.SetNull(types.StringType)
+	unknownSet := types.SetUnknown(types.StringType)
+
+	testCases := []struct {
+		name   string
+		config types.Set
+		state  types.Set
+		plan   types.Set
+		want   types.Set
+	}{
+		{name: "create without tags", config: nullSet, state: nullSet, plan: unknownSet, want: empty},
+		{name: "unset with empty state", config: nullSet, state: empty, plan: unknownSet, want: empty},
+		{name: "unset with no changes", config: nullSet, state: empty, plan: empty, want: empty},
+		{name: "empty config", config: empty, state: nullSet, plan: empty, want: empty},
+		{name: "configured tags", config: tags, state: empty, plan: tags, want: tags},
+		{name: "unset with tags in state is left as is", config: nullSet, state: tags, plan: unknownSet, want: unknownSet},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &planmodifier.SetResponse{PlanValue: tc.plan}
+			EmptyTagsForNull().PlanModifySet(ctx, planmodifier.SetRequest{
+				ConfigValue: tc.config,
+				StateValue:  tc.state,
+				PlanValue:   tc.plan,
+			}, resp)
+			assert.Equal(t, tc.want, resp.PlanValue)
+		})
+	}
+}
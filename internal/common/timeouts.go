@@ -17,9 +17,11 @@ package common
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
@@ -30,6 +32,20 @@ const (
 	Timeout24hour = 24 * time.Hour
 )
 
+// TimeoutsAttributes はtimeouts.Attributesに、負の値を拒否するDurationValidatorを加えたtimeouts属性を返す。
+// timeouts.Valueは各値をtypes.Stringとして読み込むため、DurationStringTypeは指定できない
+func TimeoutsAttributes(ctx context.Context, opts timeouts.Opts) schema.Attribute {
+	attr := timeouts.Attributes(ctx, opts).(schema.SingleNestedAttribute)
+	attrs := make(map[string]schema.Attribute, len(attr.Attributes))
+	for name, a := range attr.Attributes {
+		s := a.(schema.StringAttribute)
+		s.Validators = append(slices.Clone(s.Validators), DurationValidator())
+		attrs[name] = s
+	}
+	attr.Attributes = attrs
+	return attr
+}
+
 func SetupTimeoutCreate(ctx context.Context, tov timeouts.Value, defaultTimeout time.Duration) (context.Context, context.CancelFunc) {
 	createTimeout, diags := tov.Create(ctx, defaultTimeout)
 
@@ -0,0 +1,134 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httputil"
+)
+
+// redactedTraceValue はトレースログで機密情報の値を置き換える文字列
+const redactedTraceValue = "(sensitive value)"
+
+// sensitiveTraceFields はトレースログで値を出力しないJSONのフィールド名。
+// シークレットマネージャのシークレットの値(Value)とKMSのインポートする鍵(PlainKey)
+var sensitiveTraceFields = map[string]bool{
+	"Value":    true,
+	"PlainKey": true,
+}
+
+// redactingTracer はsacloudhttp.TracingRoundTripperと同じ形式でリクエスト/レスポンスのトレースログを出力するhttp.RoundTripper。
+// ボディのJSONに含まれる機密情報はsensitiveTraceFieldsの値を置き換えてから出力する
+type redactingTracer struct {
+	transport http.RoundTripper
+}
+
+func (t *redactingTracer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = body
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	res, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: トレースログの出力に失敗してもリクエスト自体は失敗させない
+	traceReq := req.Clone(req.Context())
+	if reqBody != nil {
+		// 値を置き換えるとボディの長さが変わるため、Content-Lengthも合わせて更新する
+		redacted := redactTraceBody(reqBody)
+		traceReq.Body = io.NopCloser(bytes.NewReader(redacted))
+		traceReq.ContentLength = int64(len(redacted))
+		traceReq.Header.Del("Content-Length")
+	}
+	if data, err := httputil.DumpRequest(traceReq, true); err != nil {
+		log.Printf("[WARN] failed to dump request for trace log: %s", err)
+	} else {
+		log.Printf("[TRACE] \trequest: %s %s\n==============================\n%s\n============================\n", req.Method, req.URL.String(), string(data))
+	}
+
+	resBody, err := io.ReadAll(res.Body)
+	res.Body.Close() //nolint:errcheck
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	traceRes := *res
+	redacted := redactTraceBody(resBody)
+	traceRes.Header = res.Header.Clone()
+	traceRes.Header.Del("Content-Length")
+	traceRes.Body = io.NopCloser(bytes.NewReader(redacted))
+	traceRes.ContentLength = int64(len(redacted))
+	if data, err := httputil.DumpResponse(&traceRes, true); err != nil {
+		log.Printf("[WARN] failed to dump response for trace log: %s", err)
+	} else {
+		log.Printf("[TRACE] \tresponse: %s %s\n==============================\n%s\n============================\n", req.Method, req.URL.String(), string(data))
+	}
+
+	return res, nil
+}
+
+// redactTraceBody はJSONのボディに含まれるsensitiveTraceFieldsの値を置き換えたボディを返す。JSONでない場合はそのまま返す
+func redactTraceBody(body []byte) []byte {
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+	if !redactTraceValue(v) {
+		return body
+	}
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// redactTraceValue はvに含まれるsensitiveTraceFieldsの値を置き換え、置き換えた場合にtrueを返す
+func redactTraceValue(v any) bool {
+	redacted := false
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if sensitiveTraceFields[k] && child != nil {
+				v[k] = redactedTraceValue
+				redacted = true
+				continue
+			}
+			if redactTraceValue(child) {
+				redacted = true
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if redactTraceValue(child) {
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
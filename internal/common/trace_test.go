@@ -0,0 +1,131 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	sm "github.com/sacloud/secretmanager-api-go"
+	v1 "github.com/sacloud/secretmanager-api-go/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactTraceBody(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+		want string
+	}{
+		{name: "secret value", body: `{"Secret":{"Name":"foo","Value":"s3cr3t","Version":1}}`, want: `{"Secret":{"Name":"foo","Value":"(sensitive value)","Version":1}}`},
+		{name: "plain key", body: `{"Key":{"Name":"foo","PlainKey":"AfL5zzjD"}}`, want: `{"Key":{"Name":"foo","PlainKey":"(sensitive value)"}}`},
+		{name: "in a list", body: `[{"Value":"a"},{"Value":"b"}]`, want: `[{"Value":"(sensitive value)"},{"Value":"(sensitive value)"}]`},
+		{name: "null is kept", body: `{"Value":null}`, want: `{"Value":null}`},
+		{name: "nothing to redact", body: `{"Name": "foo"}`, want: `{"Name": "foo"}`},
+		{name: "not json", body: `Value=s3cr3t`, want: `Value=s3cr3t`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, string(redactTraceBody([]byte(tc.body))))
+		})
+	}
+}
+
+// unveilTransport はシークレットマネージャのUnveilの結果を返す
+type unveilTransport struct{}
+
+func (unveilTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"Secret":{"Name":"foo","Version":1,"Value":"response-s3cr3t"}}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestRedactingTracer(t *testing.T) {
+	var buf bytes.Buffer
+	orig := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(orig)
+
+	client, err := (&Config{
+		AccessToken:         "token",
+		AccessTokenSecret:   "secret",
+		APIRootURL:          "http://sakura.example.com",
+		APIRequestRateLimit: 100,
+		HTTPTransport:       unveilTransport{},
+		TraceMode:           traceAll,
+	}).NewClient()
+	require.NoError(t, err)
+
+	smClient, err := client.SecretManagerClient()
+	require.NoError(t, err)
+	unveil, err := sm.NewSecretOp(smClient, "110000000001").Unveil(context.Background(), v1.Unveil{Name: "foo", Value: "request-s3cr3t"})
+	require.NoError(t, err)
+	// 呼び出し元には伏せていない値を返す
+	assert.Equal(t, "response-s3cr3t", unveil.Value)
+
+	logs := buf.String()
+	assert.Contains(t, logs, "[TRACE] \trequest: POST")
+	assert.Contains(t, logs, "[TRACE] \tresponse: POST")
+	assert.Contains(t, logs, redactedTraceValue)
+	assert.NotContains(t, logs, "request-s3cr3t")
+	assert.NotContains(t, logs, "response-s3cr3t")
+}
+
+func TestRedactingTracer_contentLength(t *testing.T) {
+	var buf bytes.Buffer
+	orig := log.Writer()
+	log.SetOutput(&buf)
+	defer log.SetOutput(orig)
+
+	const body = `{"Secret":{"Name":"foo","Version":1,"Value":"response-s3cr3t"}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.Write([]byte(body)) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	tracer := &redactingTracer{transport: http.DefaultTransport}
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`{"Secret":{"Value":"request-s3cr3t"}}`))
+	require.NoError(t, err)
+	res, err := tracer.RoundTrip(req)
+	require.NoError(t, err)
+	defer res.Body.Close() //nolint:errcheck
+
+	got, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(got))
+	assert.Equal(t, int64(len(body)), res.ContentLength)
+
+	logs := buf.String()
+	assert.NotContains(t, logs, "failed to dump")
+	assert.Contains(t, logs, "[TRACE] \trequest: POST")
+	assert.Contains(t, logs, "[TRACE] \tresponse: POST")
+	assert.Contains(t, logs, redactedTraceValue)
+	assert.NotContains(t, logs, "request-s3cr3t")
+	assert.NotContains(t, logs, "response-s3cr3t")
+}
@@ -0,0 +1,55 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"time"
+)
+
+// ReadAfterCreateTimeout は作成したリソースがAPIから参照できるようになるまで待機する時間の上限
+const ReadAfterCreateTimeout = 30 * time.Second
+
+// existsBackoff はWaitForExistsでのポーリング間隔
+var existsBackoff = &Backoff{
+	InitialInterval: 500 * time.Millisecond,
+	MaxInterval:     5 * time.Second,
+	Multiplier:      2,
+}
+
+// WaitForExists はreadがnot found以外を返すまで、最大ReadAfterCreateTimeoutの間ポーリングする。
+// 作成直後のリソースが参照系のAPIに反映されるまでの遅延を吸収するために利用する
+func WaitForExists[T any](ctx context.Context, read func(ctx context.Context) (*T, error)) (*T, error) {
+	return waitForExists(ctx, existsBackoff, ReadAfterCreateTimeout, read)
+}
+
+func waitForExists[T any](ctx context.Context, b *Backoff, timeout time.Duration, read func(ctx context.Context) (*T, error)) (*T, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var v *T
+	readCtx := ctx
+	err := b.Retry(ctx, IsNotFound, func() error {
+		var err error
+		v, err = read(readCtx)
+		// 一覧のキャッシュには作成前の結果が残っているため、2回目以降はキャッシュを使わずに取得する
+		readCtx = withoutListCache(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
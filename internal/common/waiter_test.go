@@ -0,0 +1,99 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	client "github.com/sacloud/api-client-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type waiterStub struct {
+	results []error
+	calls   int
+}
+
+func (s *waiterStub) read(_ context.Context) (*string, error) {
+	err := s.results[min(s.calls, len(s.results)-1)]
+	s.calls++
+	if err != nil {
+		return nil, err
+	}
+	v := "found"
+	return &v, nil
+}
+
+func TestWaitForExists(t *testing.T) {
+	notFound := client.NewAPIError(http.StatusNotFound, "", nil)
+
+	t.Run("found after not found twice", func(t *testing.T) {
+		c := &fakeClock{}
+		stub := &waiterStub{results: []error{notFound, notFound, nil}}
+
+		v, err := waitForExists(context.Background(), testBackoff(c, 0), time.Minute, stub.read)
+		require.NoError(t, err)
+		assert.Equal(t, "found", *v)
+		assert.Equal(t, 3, stub.calls)
+		assert.Len(t, c.waits, 2)
+	})
+
+	t.Run("retries bypass the list cache", func(t *testing.T) {
+		var cached []bool
+		c := &fakeClock{}
+		_, err := waitForExists(WithListCache(context.Background()), testBackoff(c, 0), time.Minute, func(ctx context.Context) (*string, error) {
+			cached = append(cached, usesListCache(ctx))
+			if len(cached) < 3 {
+				return nil, notFound
+			}
+			found := "found"
+			return &found, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []bool{true, false, false}, cached)
+	})
+
+	t.Run("other errors are returned immediately", func(t *testing.T) {
+		stub := &waiterStub{results: []error{notFound, errors.New("internal server error")}}
+
+		_, err := waitForExists(context.Background(), testBackoff(&fakeClock{}, 0), time.Minute, stub.read)
+		assert.EqualError(t, err, "internal server error")
+		assert.Equal(t, 2, stub.calls)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		stub := &waiterStub{results: []error{notFound}}
+
+		_, err := waitForExists(context.Background(), testBackoff(blockingClock{}, 0), 10*time.Millisecond, stub.read)
+		assert.True(t, IsNotFound(err))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, stub.calls)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		stub := &waiterStub{results: []error{notFound}}
+
+		_, err := waitForExists(ctx, testBackoff(blockingClock{}, 0), time.Minute, stub.read)
+		assert.True(t, IsNotFound(err))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,115 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/desc"
+)
+
+// WriteOnlyAttribute はパスワード等の属性を、通常の<name>属性とwrite-onlyな<name>_wo / <name>_wo_version属性の組で扱うためのヘルパー。
+// write-onlyな値はStateに保存されないため、値の変更は<name>_wo_versionの変更で検出する
+type WriteOnlyAttribute struct {
+	Name string
+}
+
+func NewWriteOnlyAttribute(name string) WriteOnlyAttribute {
+	return WriteOnlyAttribute{Name: name}
+}
+
+func (w WriteOnlyAttribute) WriteOnlyName() string {
+	return w.Name + "_wo"
+}
+
+func (w WriteOnlyAttribute) VersionName() string {
+	return w.Name + "_wo_version"
+}
+
+// Schema は<name>_wo / <name>_wo_versionのスキーマを返す。<name>自体のスキーマは各リソースで定義する。
+// validatorsは<name>_woに設定する。<name>と同じバリデータを渡すこと
+func (w WriteOnlyAttribute) Schema(description string, validators ...validator.String) map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		w.WriteOnlyName(): schema.StringAttribute{
+			Optional:  true,
+			Sensitive: true,
+			WriteOnly: true,
+			Description: desc.Sprintf("%s This value is write-only and is not stored in the state. Requires Terraform 1.11 or later. %s",
+				description, desc.Conflicts(w.Name)),
+			Validators: validators,
+		},
+		w.VersionName(): schema.Int64Attribute{
+			Optional:    true,
+			Description: desc.Sprintf("The version of `%s`. Change this value to apply the new `%s`", w.WriteOnlyName(), w.WriteOnlyName()),
+		},
+	}
+}
+
+// ConfigValidators は<name>と<name>_woの排他、<name>_woと<name>_wo_versionの同時指定を検証するバリデータを返す
+func (w WriteOnlyAttribute) ConfigValidators() []resource.ConfigValidator {
+	return []resource.ConfigValidator{
+		resourcevalidator.Conflicting(path.MatchRoot(w.Name), path.MatchRoot(w.WriteOnlyName())),
+		resourcevalidator.RequiredTogether(path.MatchRoot(w.WriteOnlyName()), path.MatchRoot(w.VersionName())),
+	}
+}
+
+// ValueFromConfig は<name>_woが指定されていればその値を、そうでなければ<name>の値を返す。
+// write-onlyな値はPlanには含まれないため、Create/Update時にはConfigから取得する必要がある
+func (w WriteOnlyAttribute) ValueFromConfig(ctx context.Context, config tfsdk.Config) (types.String, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	var wo types.String
+	diags.Append(config.GetAttribute(ctx, path.Root(w.WriteOnlyName()), &wo)...)
+	if diags.HasError() {
+		return types.StringNull(), diags
+	}
+	if !wo.IsNull() {
+		return wo, diags
+	}
+
+	var v types.String
+	diags.Append(config.GetAttribute(ctx, path.Root(w.Name), &v)...)
+	return v, diags
+}
+
+// HasChange はPlanとStateの間で<name>もしくは<name>_wo_versionが変更されているかを返す。ModifyPlanでの変更検出に利用する
+func (w WriteOnlyAttribute) HasChange(ctx context.Context, plan tfsdk.Plan, state tfsdk.State) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if state.Raw.IsNull() {
+		return true, diags
+	}
+	if plan.Raw.IsNull() {
+		return false, diags
+	}
+
+	var planValue, stateValue types.String
+	var planVersion, stateVersion types.Int64
+	diags.Append(plan.GetAttribute(ctx, path.Root(w.Name), &planValue)...)
+	diags.Append(state.GetAttribute(ctx, path.Root(w.Name), &stateValue)...)
+	diags.Append(plan.GetAttribute(ctx, path.Root(w.VersionName()), &planVersion)...)
+	diags.Append(state.GetAttribute(ctx, path.Root(w.VersionName()), &stateVersion)...)
+	if diags.HasError() {
+		return false, diags
+	}
+
+	return !planValue.Equal(stateValue) || !planVersion.Equal(stateVersion), diags
+}
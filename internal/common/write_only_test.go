@@ -0,0 +1,149 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testWriteOnly = NewWriteOnlyAttribute("password")
+
+func testWriteOnlySchema() schema.Schema {
+	attrs := map[string]schema.Attribute{
+		"password": schema.StringAttribute{Optional: true, Sensitive: true},
+	}
+	for k, v := range testWriteOnly.Schema("Password.") {
+		attrs[k] = v
+	}
+	return schema.Schema{Attributes: attrs}
+}
+
+func testWriteOnlyRaw(password, passwordWO interface{}, version interface{}) tftypes.Value {
+	return tftypes.NewValue(tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"password":            tftypes.String,
+			"password_wo":         tftypes.String,
+			"password_wo_version": tftypes.Number,
+		},
+	}, map[string]tftypes.Value{
+		"password":            tftypes.NewValue(tftypes.String, password),
+		"password_wo":         tftypes.NewValue(tftypes.String, passwordWO),
+		"password_wo_version": tftypes.NewValue(tftypes.Number, version),
+	})
+}
+
+func TestWriteOnlyAttribute_Schema(t *testing.T) {
+	attrs := testWriteOnly.Schema("Password.")
+
+	require.Len(t, attrs, 2)
+	wo, ok := attrs["password_wo"].(schema.StringAttribute)
+	require.True(t, ok)
+	assert.True(t, wo.IsWriteOnly())
+	assert.True(t, wo.IsSensitive())
+	_, ok = attrs["password_wo_version"].(schema.Int64Attribute)
+	assert.True(t, ok)
+
+	assert.Len(t, testWriteOnly.ConfigValidators(), 2)
+}
+
+func TestWriteOnlyAttribute_ValueFromConfig(t *testing.T) {
+	ctx := context.Background()
+	s := testWriteOnlySchema()
+
+	expects := []struct {
+		name string
+		raw  tftypes.Value
+		want string
+	}{
+		{
+			name: "plain value",
+			raw:  testWriteOnlyRaw("plain", nil, nil),
+			want: "plain",
+		},
+		{
+			name: "write-only value",
+			raw:  testWriteOnlyRaw(nil, "wo", 1),
+			want: "wo",
+		},
+	}
+
+	for _, tc := range expects {
+		t.Run(tc.name, func(t *testing.T) {
+			v, diags := testWriteOnly.ValueFromConfig(ctx, tfsdk.Config{Schema: s, Raw: tc.raw})
+			require.False(t, diags.HasError(), diags)
+			assert.Equal(t, tc.want, v.ValueString())
+		})
+	}
+}
+
+func TestWriteOnlyAttribute_HasChange(t *testing.T) {
+	ctx := context.Background()
+	s := testWriteOnlySchema()
+
+	expects := []struct {
+		name  string
+		plan  tftypes.Value
+		state tftypes.Value
+		want  bool
+	}{
+		{
+			name:  "create",
+			plan:  testWriteOnlyRaw(nil, nil, 1),
+			state: tftypes.NewValue(s.Type().TerraformType(ctx), nil),
+			want:  true,
+		},
+		{
+			name:  "no change",
+			plan:  testWriteOnlyRaw(nil, nil, 1),
+			state: testWriteOnlyRaw(nil, nil, 1),
+			want:  false,
+		},
+		{
+			name:  "version changed",
+			plan:  testWriteOnlyRaw(nil, nil, 2),
+			state: testWriteOnlyRaw(nil, nil, 1),
+			want:  true,
+		},
+		{
+			name:  "plain value changed",
+			plan:  testWriteOnlyRaw("new", nil, nil),
+			state: testWriteOnlyRaw("old", nil, nil),
+			want:  true,
+		},
+		{
+			name:  "switched to write-only",
+			plan:  testWriteOnlyRaw(nil, nil, 1),
+			state: testWriteOnlyRaw("old", nil, nil),
+			want:  true,
+		},
+	}
+
+	for _, tc := range expects {
+		t.Run(tc.name, func(t *testing.T) {
+			changed, diags := testWriteOnly.HasChange(ctx,
+				tfsdk.Plan{Schema: s, Raw: tc.plan},
+				tfsdk.State{Schema: s, Raw: tc.state})
+			require.False(t, diags.HasError(), diags)
+			assert.Equal(t, tc.want, changed)
+		})
+	}
+}
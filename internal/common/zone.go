@@ -0,0 +1,195 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/filter"
+)
+
+// ZoneErrorMode はForEachZoneで一部のゾーンの処理が失敗した場合の扱い
+type ZoneErrorMode int
+
+const (
+	// ZoneFailFast はいずれかのゾーンが失敗した時点で残りのゾーンの処理をキャンセルし、エラーとする
+	ZoneFailFast ZoneErrorMode = iota
+	// ZonePartialResults は成功したゾーンの結果を返し、失敗したゾーンは警告とする。全てのゾーンが失敗した場合はエラーとする
+	ZonePartialResults
+)
+
+// ZoneResult はForEachZoneで処理に成功したゾーンの結果
+type ZoneResult[T any] struct {
+	Zone  string
+	Value T
+}
+
+// ZoneErrors はForEachZoneで失敗したゾーンごとのエラー
+type ZoneErrors struct {
+	Mode   ZoneErrorMode
+	Errors map[string]error
+	Total  int // 処理したゾーンの数
+}
+
+// Zones は失敗したゾーンを名前順で返す
+func (e *ZoneErrors) Zones() []string {
+	zones := make([]string, 0, len(e.Errors))
+	for zone := range e.Errors {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+	return zones
+}
+
+// Partial は成功したゾーンの結果を利用できるか(一部のゾーンのみが失敗したか)を返す
+func (e *ZoneErrors) Partial() bool {
+	return e.Mode == ZonePartialResults && len(e.Errors) < e.Total
+}
+
+func (e *ZoneErrors) Error() string {
+	lines := make([]string, 0, len(e.Errors))
+	for _, zone := range e.Zones() {
+		lines = append(lines, fmt.Sprintf("zone %s: %s", zone, e.Errors[zone]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (e *ZoneErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, zone := range e.Zones() {
+		errs = append(errs, e.Errors[zone])
+	}
+	return errs
+}
+
+// ForEachZone はzonesのゾーンごとにfnを最大parallelism個まで並行して実行し、成功したゾーンの結果をzonesの順序で返す。
+// parallelismが0以下の場合はMaxParallelZoneRequestsを利用する。通常はAPIClient.MaxParallelZoneRequestsの値を渡すこと。
+// fnがAPIClientを利用する場合、並行して実行されるリクエストもapi_request_rate_limitのレート制限を共有する。
+// 失敗したゾーンがある場合は*ZoneErrorsを返す。ZoneFailFastの場合は結果を返さず、ZonePartialResultsの場合は成功したゾーンの結果もあわせて返す。
+// エラーのdiagnosticsへの追加にはAddZoneErrorsを利用すること
+func ForEachZone[T any](ctx context.Context, zones []string, parallelism int, mode ZoneErrorMode, fn func(ctx context.Context, zone string) (T, error)) ([]ZoneResult[T], error) {
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if parallelism <= 0 {
+		parallelism = MaxParallelZoneRequests
+	}
+
+	values := make([]T, len(zones))
+	errs := make([]error, len(zones))
+	var failFastOnce sync.Once
+	runParallel(len(zones), parallelism, func(i int) {
+		// キャンセル後に順番が回ってきたゾーンはリクエストを送信しない
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			return
+		}
+		values[i], errs[i] = fn(ctx, zones[i])
+		if errs[i] != nil && mode == ZoneFailFast {
+			failFastOnce.Do(cancel)
+		}
+	})
+
+	zoneErrs := &ZoneErrors{Mode: mode, Errors: map[string]error{}, Total: len(zones)}
+	var results []ZoneResult[T]
+	for i, zone := range zones {
+		if errs[i] == nil {
+			results = append(results, ZoneResult[T]{Zone: zone, Value: values[i]})
+			continue
+		}
+		// fail-fastで他のゾーンの失敗によりキャンセルされた処理は、失敗したゾーンとして扱わない
+		if mode == ZoneFailFast && errors.Is(errs[i], context.Canceled) && parent.Err() == nil {
+			continue
+		}
+		zoneErrs.Errors[zone] = errs[i]
+	}
+	if len(zoneErrs.Errors) == 0 {
+		return results, nil
+	}
+	if mode == ZoneFailFast {
+		return nil, zoneErrs
+	}
+	return results, zoneErrs
+}
+
+// runParallel はfn(0)からfn(n-1)を最大parallelism個まで並行して実行し、全ての完了を待つ
+func runParallel(n, parallelism int, fn func(i int)) {
+	indexes := make(chan int, n)
+	for i := range n {
+		indexes <- i
+	}
+	close(indexes)
+
+	var wg sync.WaitGroup
+	for range min(parallelism, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// MergeZoneResults はゾーンごとの一覧をゾーン名、名前、IDの順で並べ替えて1つの一覧にまとめる。
+// ForEachZoneの完了順に依存せず、同じ結果からは常に同じ順序の一覧を返す
+func MergeZoneResults[T any](results []ZoneResult[[]T], attributes func(T) filter.Attributes) []ZoneResult[T] {
+	sorted := slices.Clone(results)
+	slices.SortStableFunc(sorted, func(a, b ZoneResult[[]T]) int {
+		return strings.Compare(a.Zone, b.Zone)
+	})
+
+	var merged []ZoneResult[T]
+	for _, result := range sorted {
+		items := slices.Clone(result.Value)
+		SortByName(items, attributes)
+		for _, item := range items {
+			merged = append(merged, ZoneResult[T]{Zone: result.Zone, Value: item})
+		}
+	}
+	return merged
+}
+
+// AddZoneErrors はForEachZoneが返したエラーをdiagsに追加する。
+// 一部のゾーンのみが失敗したZonePartialResultsの場合は警告を、それ以外の場合はエラーを追加する
+func AddZoneErrors(diags *diag.Diagnostics, summary string, err error) {
+	if err == nil {
+		return
+	}
+	var zoneErrs *ZoneErrors
+	if !errors.As(err, &zoneErrs) {
+		diags.AddError(summary, err.Error())
+		return
+	}
+
+	failed := strings.Join(zoneErrs.Zones(), ", ")
+	if zoneErrs.Partial() {
+		diags.AddWarning(summary,
+			fmt.Sprintf("The results do not include zones [%s] because the requests for them failed. Other zones were read successfully.\n\n%s", failed, zoneErrs))
+		return
+	}
+	diags.AddError(summary, fmt.Sprintf("The requests failed in zones [%s].\n\n%s", failed, zoneErrs))
+}
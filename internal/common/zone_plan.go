@@ -0,0 +1,90 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// PlanZone はSchemaResourceZoneを利用するリソースのModifyPlanから呼び出し、未指定のzoneをプロバイダーのzoneで確定させる。
+// planに"known after apply"ではなく作成先のゾーンを表示するため。指定されたzoneはプロバイダーのzonesに含まれるかを検証する。
+// プラグインフレームワークの属性のplan modifierからはプロバイダーの設定を参照できないため、リソースのModifyPlanで行う
+func PlanZone(ctx context.Context, client *APIClient, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// 削除時やプロバイダーの設定が確定していない場合は何もしない
+	if req.Plan.Raw.IsNull() || client == nil {
+		return
+	}
+
+	var config, plan types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("zone"), &config)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("zone"), &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	zone, d := planZone(config, plan, client.defaultZone, client.GetZones())
+	if d != nil {
+		resp.Diagnostics.Append(d)
+		return
+	}
+	if !zone.Equal(plan) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("zone"), zone)...)
+	}
+}
+
+// planZone はconfigとplanのzoneから、planに設定するzoneを返す。
+// 未指定で作成する場合はdefaultZoneを、既存のリソースではplan modifier(UseStateForUnknown)で設定されたstateの値をそのまま返す
+func planZone(config, plan types.String, defaultZone string, zones []string) (types.String, diag.Diagnostic) {
+	switch {
+	case config.IsUnknown():
+		// 他のリソースの属性を参照するなどで確定していない場合は、applyの時点でGetZoneが検証する
+		return plan, nil
+	case config.IsNull():
+		if plan.IsUnknown() && defaultZone != "" {
+			return types.StringValue(defaultZone), nil
+		}
+		return plan, nil
+	}
+	if err := CheckZoneAllowed(config.ValueString(), zones); err != nil {
+		return plan, diag.NewAttributeErrorDiagnostic(path.Root("zone"), "Invalid zone", err.Error())
+	}
+	return plan, nil
+}
+
+// ZoneNotAllowedError は指定されたゾーンがプロバイダーのzonesに含まれないことを表す
+type ZoneNotAllowedError struct {
+	Zone    string
+	Allowed []string
+}
+
+func (e *ZoneNotAllowedError) Error() string {
+	return fmt.Sprintf("zone %q is not available. This must be one of %q, which can be changed with the zones in the provider configuration", e.Zone, e.Allowed)
+}
+
+// CheckZoneAllowed はzoneがプロバイダーで利用できるゾーンに含まれるかを検証する。
+// allowedにはAPIClient.GetZonesの値(プロファイルなどから取得したゾーンを含む)を渡す。allowedが空の場合は全てのゾーンを許可する
+func CheckZoneAllowed(zone string, allowed []string) error {
+	if len(allowed) == 0 || slices.Contains(allowed, zone) {
+		return nil
+	}
+	return &ZoneNotAllowedError{Zone: zone, Allowed: allowed}
+}
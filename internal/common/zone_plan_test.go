@@ -0,0 +1,165 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanZone_defaulting(t *testing.T) {
+	zones := []string{"is1a", "is1b", "tk1a"}
+
+	expects := []struct {
+		name    string
+		config  types.String
+		plan    types.String
+		want    types.String
+		wantErr bool
+	}{
+		{
+			name:   "create without zone uses the provider zone",
+			config: types.StringNull(),
+			plan:   types.StringUnknown(),
+			want:   types.StringValue("is1b"),
+		},
+		{
+			name:   "existing resource keeps the zone in state",
+			config: types.StringNull(),
+			plan:   types.StringValue("tk1a"),
+			want:   types.StringValue("tk1a"),
+		},
+		{
+			name:   "configured zone overrides the provider zone",
+			config: types.StringValue("tk1a"),
+			plan:   types.StringValue("tk1a"),
+			want:   types.StringValue("tk1a"),
+		},
+		{
+			name:   "unknown zone is left to apply",
+			config: types.StringUnknown(),
+			plan:   types.StringUnknown(),
+			want:   types.StringUnknown(),
+		},
+		{
+			name:    "unavailable zone",
+			config:  types.StringValue("is1c"),
+			plan:    types.StringValue("is1c"),
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range expects {
+		t.Run(tc.name, func(t *testing.T) {
+			got, d := planZone(tc.config, tc.plan, "is1b", zones)
+			if tc.wantErr {
+				require.NotNil(t, d)
+				assert.Equal(t, "Invalid zone", d.Summary())
+				assert.Contains(t, d.Detail(), `zone "is1c" is not available`)
+				return
+			}
+			require.Nil(t, d)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestPlanZone(t *testing.T) {
+	ctx := context.Background()
+	s := schema.Schema{Attributes: map[string]schema.Attribute{
+		"name": schema.StringAttribute{Required: true},
+		"zone": SchemaResourceZone("Test"),
+	}}
+	raw := func(zone any) tftypes.Value {
+		return tftypes.NewValue(s.Type().TerraformType(ctx), map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "foobar"),
+			"zone": tftypes.NewValue(tftypes.String, zone),
+		})
+	}
+	client := &APIClient{defaultZone: "is1b", zones: []string{"is1a", "is1b", "tk1a"}}
+	modifyPlan := func(t *testing.T, client *APIClient, config, plan tftypes.Value) *resource.ModifyPlanResponse {
+		t.Helper()
+		req := resource.ModifyPlanRequest{
+			Config: tfsdk.Config{Schema: s, Raw: config},
+			Plan:   tfsdk.Plan{Schema: s, Raw: plan},
+			State:  tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)},
+		}
+		resp := &resource.ModifyPlanResponse{Plan: req.Plan}
+		PlanZone(ctx, client, req, resp)
+		return resp
+	}
+
+	t.Run("zone is known at plan time", func(t *testing.T) {
+		resp := modifyPlan(t, client, raw(nil), raw(tftypes.UnknownValue))
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+		var zone types.String
+		require.False(t, resp.Plan.GetAttribute(ctx, path.Root("zone"), &zone).HasError())
+		assert.Equal(t, types.StringValue("is1b"), zone)
+	})
+
+	t.Run("unavailable zone is reported on the attribute", func(t *testing.T) {
+		resp := modifyPlan(t, client, raw("is1c"), raw("is1c"))
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, path.Root("zone"), resp.Diagnostics.Errors()[0].(diag.DiagnosticWithPath).Path())
+	})
+
+	t.Run("provider is not configured", func(t *testing.T) {
+		resp := modifyPlan(t, nil, raw(nil), raw(tftypes.UnknownValue))
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.True(t, resp.Plan.Raw.Equal(raw(tftypes.UnknownValue)))
+	})
+}
+
+func TestCheckZoneAllowed(t *testing.T) {
+	// zonesが空の場合は全てのゾーンを許可する
+	assert.NoError(t, CheckZoneAllowed("tk1b", nil))
+	assert.NoError(t, CheckZoneAllowed("is1a", []string{"is1a", "tk1a"}))
+
+	err := CheckZoneAllowed("tk1b", []string{"is1a"})
+	var notAllowed *ZoneNotAllowedError
+	require.ErrorAs(t, err, &notAllowed)
+	assert.Equal(t, &ZoneNotAllowedError{Zone: "tk1b", Allowed: []string{"is1a"}}, notAllowed)
+	// 指定されたゾーンと利用できるゾーンの両方をメッセージに含める
+	assert.EqualError(t, err, `zone "tk1b" is not available. This must be one of ["is1a"], which can be changed with the zones in the provider configuration`)
+}
+
+func TestGetZone_allowList(t *testing.T) {
+	client := &APIClient{defaultZone: "is1a", zones: []string{"is1a"}}
+
+	var diags diag.Diagnostics
+	assert.Equal(t, "is1a", GetZone(types.StringNull(), client, &diags))
+	assert.Equal(t, "is1a", GetZone(types.StringValue("is1a"), client, &diags))
+	require.False(t, diags.HasError(), diags)
+
+	assert.Empty(t, GetZone(types.StringValue("tk1b"), client, &diags))
+	require.True(t, diags.HasError())
+	assert.Contains(t, diags.Errors()[0].Detail(), `zone "tk1b" is not available. This must be one of ["is1a"]`)
+
+	// プロバイダーのzonesが空の場合は全てのゾーンを許可する
+	diags = nil
+	assert.Equal(t, "tk1b", GetZone(types.StringValue("tk1b"), &APIClient{}, &diags))
+	assert.False(t, diags.HasError(), diags)
+}
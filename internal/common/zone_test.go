@@ -0,0 +1,224 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package common
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	client "github.com/sacloud/api-client-go"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/filter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForEachZone(t *testing.T) {
+	zones := []string{"is1a", "is1b", "tk1a"}
+	unavailable := client.NewAPIError(http.StatusServiceUnavailable, "", errors.New("service unavailable"))
+
+	t.Run("partial results", func(t *testing.T) {
+		results, err := ForEachZone(context.Background(), zones, 0, ZonePartialResults, func(ctx context.Context, zone string) ([]string, error) {
+			if zone == "is1b" {
+				return nil, unavailable
+			}
+			return []string{zone + "-server"}, nil
+		})
+		require.Error(t, err)
+		assert.Equal(t, []ZoneResult[[]string]{
+			{Zone: "is1a", Value: []string{"is1a-server"}},
+			{Zone: "tk1a", Value: []string{"tk1a-server"}},
+		}, results)
+
+		var zoneErrs *ZoneErrors
+		require.ErrorAs(t, err, &zoneErrs)
+		assert.Equal(t, []string{"is1b"}, zoneErrs.Zones())
+		assert.True(t, zoneErrs.Partial())
+		assert.ErrorIs(t, err, unavailable)
+
+		var diags diag.Diagnostics
+		AddZoneErrors(&diags, "Server List Error", err)
+		assert.False(t, diags.HasError())
+		require.Len(t, diags.Warnings(), 1)
+		assert.Contains(t, diags.Warnings()[0].Detail(), "do not include zones [is1b]")
+		assert.Contains(t, diags.Warnings()[0].Detail(), "zone is1b: ")
+	})
+
+	t.Run("fail fast", func(t *testing.T) {
+		// is1bのみ失敗し、他のゾーンはキャンセルされるまで結果を返さない
+		results, err := ForEachZone(context.Background(), zones, 0, ZoneFailFast, func(ctx context.Context, zone string) ([]string, error) {
+			if zone == "is1b" {
+				return nil, unavailable
+			}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		require.Error(t, err)
+		assert.Nil(t, results)
+
+		// 失敗したis1bによってキャンセルされた他のゾーンは、失敗したゾーンに含めない
+		var zoneErrs *ZoneErrors
+		require.ErrorAs(t, err, &zoneErrs)
+		assert.Equal(t, []string{"is1b"}, zoneErrs.Zones())
+		assert.False(t, zoneErrs.Partial())
+
+		var diags diag.Diagnostics
+		AddZoneErrors(&diags, "Server List Error", err)
+		require.True(t, diags.HasError())
+		assert.Contains(t, diags.Errors()[0].Detail(), "The requests failed in zones [is1b]")
+	})
+
+	t.Run("all zones fail with partial results", func(t *testing.T) {
+		results, err := ForEachZone(context.Background(), zones, 0, ZonePartialResults, func(context.Context, string) (int, error) {
+			return 0, unavailable
+		})
+		require.Error(t, err)
+		assert.Empty(t, results)
+
+		var diags diag.Diagnostics
+		AddZoneErrors(&diags, "Server List Error", err)
+		require.True(t, diags.HasError())
+		assert.Contains(t, diags.Errors()[0].Detail(), "[is1a, is1b, tk1a]")
+	})
+
+	t.Run("no errors", func(t *testing.T) {
+		results, err := ForEachZone(context.Background(), zones, 0, ZoneFailFast, func(_ context.Context, zone string) (string, error) {
+			return zone, nil
+		})
+		require.NoError(t, err)
+		assert.Len(t, results, 3)
+
+		var diags diag.Diagnostics
+		AddZoneErrors(&diags, "Server List Error", err)
+		assert.Empty(t, diags)
+	})
+}
+
+// inFlightCounter は並行して実行されている処理の数の最大値を記録する
+type inFlightCounter struct {
+	current, max atomic.Int32
+}
+
+func (c *inFlightCounter) enter() (leave func()) {
+	n := c.current.Add(1)
+	for {
+		m := c.max.Load()
+		if n <= m || c.max.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	return func() { c.current.Add(-1) }
+}
+
+func TestForEachZone_parallelism(t *testing.T) {
+	zones := []string{"is1a", "is1b", "is1c", "tk1a", "tk1b", "tk1c", "tk1v"}
+
+	t.Run("bounded", func(t *testing.T) {
+		var counter inFlightCounter
+		results, err := ForEachZone(context.Background(), zones, 2, ZoneFailFast, func(_ context.Context, zone string) (string, error) {
+			defer counter.enter()()
+			time.Sleep(10 * time.Millisecond)
+			return zone, nil
+		})
+		require.NoError(t, err)
+		assert.LessOrEqual(t, counter.max.Load(), int32(2))
+
+		// 完了順に関わらずzonesの順序で返す
+		got := make([]string, 0, len(results))
+		for _, r := range results {
+			got = append(got, r.Value)
+		}
+		assert.Equal(t, zones, got)
+	})
+
+	t.Run("fail fast skips pending zones", func(t *testing.T) {
+		var calls atomic.Int32
+		_, err := ForEachZone(context.Background(), zones, 1, ZoneFailFast, func(_ context.Context, zone string) (string, error) {
+			calls.Add(1)
+			return "", errors.New("failed")
+		})
+		var zoneErrs *ZoneErrors
+		require.ErrorAs(t, err, &zoneErrs)
+		assert.Equal(t, []string{"is1a"}, zoneErrs.Zones())
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("default parallelism", func(t *testing.T) {
+		var counter inFlightCounter
+		_, err := ForEachZone(context.Background(), zones, 0, ZonePartialResults, func(_ context.Context, zone string) (string, error) {
+			defer counter.enter()()
+			time.Sleep(10 * time.Millisecond)
+			return zone, nil
+		})
+		require.NoError(t, err)
+		assert.LessOrEqual(t, counter.max.Load(), int32(MaxParallelZoneRequests))
+	})
+
+	t.Run("shares the rate limiter of the client", func(t *testing.T) {
+		client, err := (&Config{
+			AccessToken:         "token",
+			AccessTokenSecret:   "secret",
+			APIRootURL:          "http://sakura.example.com",
+			APIRequestRateLimit: 20,
+			HTTPTransport:       &listTransport{},
+		}).NewClient()
+		require.NoError(t, err)
+
+		// サービスごとではなくクライアント全体で20req/sに制限されるため、10件のリクエストには少なくとも450msかかる
+		start := time.Now()
+		_, err = ForEachZone(context.Background(), []string{"z0", "z1", "z2", "z3", "z4", "z5", "z6", "z7", "z8", "z9"}, 10, ZoneFailFast,
+			func(ctx context.Context, zone string) (int, error) {
+				if zone[1]%2 == 0 {
+					items, _, err := client.KMSKeyList(ctx)
+					if err != nil {
+						return 0, err
+					}
+					return len(items), nil
+				}
+				items, _, err := client.SecretManagerVaultList(ctx)
+				if err != nil {
+					return 0, err
+				}
+				return len(items), nil
+			})
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 400*time.Millisecond)
+	})
+}
+
+func TestMergeZoneResults(t *testing.T) {
+	attributes := func(v filter.Attributes) filter.Attributes { return v }
+	results := []ZoneResult[[]filter.Attributes]{
+		{Zone: "tk1a", Value: []filter.Attributes{{ID: "2", Name: "web"}, {ID: "1", Name: "db"}}},
+		{Zone: "is1a", Value: []filter.Attributes{{ID: "10", Name: "web"}, {ID: "9", Name: "web"}}},
+		{Zone: "is1b", Value: nil},
+	}
+	want := []ZoneResult[filter.Attributes]{
+		{Zone: "is1a", Value: filter.Attributes{ID: "9", Name: "web"}},
+		{Zone: "is1a", Value: filter.Attributes{ID: "10", Name: "web"}},
+		{Zone: "tk1a", Value: filter.Attributes{ID: "1", Name: "db"}},
+		{Zone: "tk1a", Value: filter.Attributes{ID: "2", Name: "web"}},
+	}
+	assert.Equal(t, want, MergeZoneResults(results, attributes))
+
+	// 入力の順序によらず同じ結果となり、入力は書き換えない
+	reversed := []ZoneResult[[]filter.Attributes]{results[2], results[1], results[0]}
+	assert.Equal(t, want, MergeZoneResults(reversed, attributes))
+	assert.Equal(t, "2", results[0].Value[0].ID)
+}
@@ -0,0 +1,299 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package filter はAPIが検索条件をサポートしていないリソースの一覧を、クライアント側で絞り込むための仕組みを提供する
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrNoResult は条件に一致するリソースが存在しないことを表す
+var ErrNoResult = errors.New("no result")
+
+// Attributes は条件の評価に利用するリソースの属性
+type Attributes struct {
+	ID   string   `json:"id,omitempty"`
+	Name string   `json:"name,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+	// Fields はFieldConditionで参照するID/Name/Tags以外の属性。キーはAPIのフィールド名
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// NameMatchの値
+const (
+	NameMatchExact    = "exact"
+	NameMatchPrefix   = "prefix"
+	NameMatchContains = "contains"
+)
+
+// NameMatchModes はNameMatchに指定できる値の一覧
+var NameMatchModes = []string{NameMatchExact, NameMatchPrefix, NameMatchContains}
+
+// FieldConditionのOperatorの値
+const (
+	OperatorPartialMatchAnd = "partial_match_and"
+	OperatorExactMatchOr    = "exact_match_or"
+)
+
+// Operators はFieldCondition.Operatorに指定できる値の一覧
+var Operators = []string{OperatorPartialMatchAnd, OperatorExactMatchOr}
+
+// Condition は絞り込みの条件。未指定の項目は条件に含めず、指定した項目はすべてAND条件として評価する
+type Condition struct {
+	Name string `json:"name,omitempty"`
+	// NameMatch はNameの比較方法。未指定の場合はNameMatchExactとして扱う
+	NameMatch string `json:"name_match,omitempty"`
+	// IgnoreCase はNameを大文字小文字を区別せずに比較する場合にtrueとする
+	IgnoreCase bool `json:"ignore_case,omitempty"`
+
+	// 以下はv2のfilterブロックに相当する条件
+	ID     string           `json:"id,omitempty"`
+	Names  []string         `json:"names,omitempty"` // 名前にすべての値を含む
+	Tags   []string         `json:"tags,omitempty"`  // すべてのタグを持つ
+	Fields []FieldCondition `json:"fields,omitempty"`
+}
+
+// FieldCondition はフィールド名と値による条件。
+// NameにはID/Name/TagsまたはAttributes.Fieldsのキーを指定する
+type FieldCondition struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+	// Operator は値の比較方法。未指定の場合はOperatorPartialMatchAndとして扱う
+	Operator string `json:"operator,omitempty"`
+}
+
+// Match はattrsが条件に一致するかを返す
+func (c *Condition) Match(attrs Attributes) bool {
+	if c.Name != "" && !c.matchName(attrs.Name) {
+		return false
+	}
+	if c.ID != "" && c.ID != attrs.ID {
+		return false
+	}
+	if !containsAll(attrs.Name, c.Names) || !hasAllTags(attrs.Tags, c.Tags) {
+		return false
+	}
+	for _, f := range c.Fields {
+		if !f.match(attrs) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *FieldCondition) match(attrs Attributes) bool {
+	values := f.values()
+	if len(values) == 0 {
+		return true
+	}
+	exactOr := strings.EqualFold(f.Operator, OperatorExactMatchOr)
+	if f.Name == "Tags" {
+		if exactOr {
+			return slices.ContainsFunc(values, func(v string) bool { return slices.Contains(attrs.Tags, v) })
+		}
+		return hasAllTags(attrs.Tags, values)
+	}
+
+	var field string
+	switch f.Name {
+	case "ID":
+		field = attrs.ID
+	case "Name":
+		field = attrs.Name
+	default:
+		v, ok := attrs.Fields[f.Name]
+		if !ok {
+			return false
+		}
+		field = v
+	}
+	if exactOr {
+		return slices.Contains(values, field)
+	}
+	return containsAll(field, values)
+}
+
+// values は空文字を除いた値を返す。v2と同様に空文字の値は条件として扱わない
+func (f *FieldCondition) values() []string {
+	var values []string
+	for _, v := range f.Values {
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func (f *FieldCondition) String() string {
+	operator := f.Operator
+	if operator == "" {
+		operator = OperatorPartialMatchAnd
+	}
+	return fmt.Sprintf("%s %s %q", f.Name, strings.ToLower(operator), f.values())
+}
+
+func containsAll(s string, values []string) bool {
+	for _, v := range values {
+		if !strings.Contains(s, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasAllTags(tags []string, want []string) bool {
+	for _, tag := range want {
+		if !slices.Contains(tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *Condition) matchName(name string) bool {
+	want := c.Name
+	if c.IgnoreCase {
+		want, name = strings.ToLower(want), strings.ToLower(name)
+	}
+	switch c.NameMatch {
+	case NameMatchPrefix:
+		return strings.HasPrefix(name, want)
+	case NameMatchContains:
+		return strings.Contains(name, want)
+	default:
+		return want == name
+	}
+}
+
+// exactName はNameを完全一致で比較する場合にtrueを返す
+func (c *Condition) exactName() bool {
+	return c.NameMatch == "" || c.NameMatch == NameMatchExact
+}
+
+func (c *Condition) String() string {
+	var conditions []string
+	if c.Name != "" {
+		conditions = append(conditions, fmt.Sprintf("name=%q", c.Name))
+	}
+	if !c.exactName() {
+		conditions = append(conditions, "name_match="+c.NameMatch)
+	}
+	if c.IgnoreCase {
+		conditions = append(conditions, "ignore_case=true")
+	}
+	if c.ID != "" {
+		conditions = append(conditions, fmt.Sprintf("id=%q", c.ID))
+	}
+	if len(c.Names) > 0 {
+		conditions = append(conditions, fmt.Sprintf("names=%q", c.Names))
+	}
+	if len(c.Tags) > 0 {
+		conditions = append(conditions, fmt.Sprintf("tags=%q", c.Tags))
+	}
+	for _, f := range c.Fields {
+		if len(f.values()) > 0 {
+			conditions = append(conditions, fmt.Sprintf("condition=(%s)", f.String()))
+		}
+	}
+	return strings.Join(conditions, " ")
+}
+
+// Select はitemsのうち条件に一致するものを元の順序のまま返す
+func Select[T any](items []T, cond Condition, attributes func(T) Attributes) []T {
+	var match []T
+	for _, v := range items {
+		if cond.Match(attributes(v)) {
+			match = append(match, v)
+		}
+	}
+	return match
+}
+
+// NoResultError は条件に一致するリソースが存在しないことを、検索したリソースの件数とともに表す。
+// errors.Is(err, ErrNoResult)はtrueを返す
+type NoResultError struct {
+	Kind      string
+	Condition Condition
+	Searched  int // 条件を評価したリソースの件数
+}
+
+func (e *NoResultError) Error() string {
+	if e.Searched == 0 {
+		return fmt.Sprintf("no %ss exist in this account", e.Kind)
+	}
+	searched := fmt.Sprintf("searched %d %s", e.Searched, plural(e.Kind, e.Searched))
+	if c := e.Condition.String(); c != "" {
+		return fmt.Sprintf("no %s matched %s (%s)", e.Kind, c, searched)
+	}
+	return fmt.Sprintf("no %s matched (%s)", e.Kind, searched)
+}
+
+func (e *NoResultError) Unwrap() error {
+	return ErrNoResult
+}
+
+// MultipleResultsError は条件に複数のリソースが一致したことを、一致した件数とともに表す
+type MultipleResultsError struct {
+	Kind      string
+	Condition Condition
+	Matched   int      // 条件に一致したリソースの件数
+	Names     []string // 一致したリソースの名前。名前の完全一致で検索した場合は同じ名前となるため記録しない
+}
+
+func (e *MultipleResultsError) Error() string {
+	matched := fmt.Sprintf("%d matched", e.Matched)
+	if len(e.Names) > 0 {
+		quoted := make([]string, len(e.Names))
+		for i, name := range e.Names {
+			quoted[i] = strconv.Quote(name)
+		}
+		matched += ": " + strings.Join(quoted, ", ")
+	}
+	if c := e.Condition.String(); c != "" {
+		return fmt.Sprintf("multiple %s resources found with the same condition. %s (%s)", e.Kind, c, matched)
+	}
+	return fmt.Sprintf("multiple %s resources found (%s)", e.Kind, matched)
+}
+
+func plural(kind string, n int) string {
+	if n == 1 {
+		return kind
+	}
+	return kind + "s"
+}
+
+// One は条件に一致するものを一つだけ返す。
+// 一致するものがない場合は*NoResultErrorを、複数一致する場合は*MultipleResultsErrorを返す。
+// kindはエラーメッセージに利用するリソースの種類で、"KMS key"のように単数形で指定する
+func One[T any](items []T, cond Condition, attributes func(T) Attributes, kind string) (*T, error) {
+	match := Select(items, cond, attributes)
+	if len(match) == 0 {
+		return nil, &NoResultError{Kind: kind, Condition: cond, Searched: len(items)}
+	}
+	if len(match) > 1 {
+		err := &MultipleResultsError{Kind: kind, Condition: cond, Matched: len(match)}
+		if cond.Name == "" || !cond.exactName() {
+			for _, v := range match {
+				err.Names = append(err.Names, attributes(v).Name)
+			}
+		}
+		return nil, err
+	}
+	return &match[0], nil
+}
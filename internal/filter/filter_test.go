@@ -0,0 +1,115 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files under testdata")
+
+// testdata/*.input.jsonはリソースの一覧と評価する条件の組、*.golden.jsonはその評価結果。
+// ケースを追加する場合はinputを編集し、-updateでgoldenを再生成して差分をレビューする
+type goldenInput struct {
+	Items []Attributes `json:"items"`
+	Cases []struct {
+		Name      string    `json:"name"`
+		Condition Condition `json:"condition"`
+	} `json:"cases"`
+}
+
+type goldenResult struct {
+	Name      string    `json:"name"`
+	Condition Condition `json:"condition"`
+	Selected  []string  `json:"selected"`
+	One       string    `json:"one,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+func TestGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.input.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, inputs)
+
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".input.json")
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(input) //nolint:gosec
+			require.NoError(t, err)
+			var in goldenInput
+			require.NoError(t, json.Unmarshal(data, &in))
+
+			results := make([]goldenResult, 0, len(in.Cases))
+			for _, tc := range in.Cases {
+				result := goldenResult{Name: tc.Name, Condition: tc.Condition, Selected: []string{}}
+				attributes := func(v Attributes) Attributes { return v }
+				for _, v := range Select(in.Items, tc.Condition, attributes) {
+					result.Selected = append(result.Selected, v.ID)
+				}
+				one, err := One(in.Items, tc.Condition, attributes, "test")
+				if err != nil {
+					result.Error = err.Error()
+				} else {
+					result.One = one.ID
+				}
+				results = append(results, result)
+			}
+
+			got, err := json.MarshalIndent(results, "", "  ")
+			require.NoError(t, err)
+			got = append(got, '\n')
+
+			golden := filepath.Join("testdata", name+".golden.json")
+			if *updateGolden {
+				require.NoError(t, os.WriteFile(golden, got, 0o600))
+				return
+			}
+			want, err := os.ReadFile(golden) //nolint:gosec
+			require.NoError(t, err, "golden file is missing. run the test with -update to create it")
+			assert.JSONEq(t, string(want), string(got))
+		})
+	}
+}
+
+func TestOne_errors(t *testing.T) {
+	attributes := func(v Attributes) Attributes { return v }
+	items := []Attributes{{ID: "1", Name: "foo"}, {ID: "2", Name: "foo"}, {ID: "3", Name: "bar"}}
+
+	_, err := One(items, Condition{Name: "baz"}, attributes, "KMS key")
+	var noResult *NoResultError
+	require.ErrorAs(t, err, &noResult)
+	assert.ErrorIs(t, err, ErrNoResult)
+	assert.Equal(t, 3, noResult.Searched)
+
+	_, err = One(items, Condition{Name: "foo"}, attributes, "KMS key")
+	var multiple *MultipleResultsError
+	require.ErrorAs(t, err, &multiple)
+	assert.NotErrorIs(t, err, ErrNoResult)
+	assert.Equal(t, 2, multiple.Matched)
+
+	_, err = One(items[:1], Condition{Name: "bar"}, attributes, "KMS key")
+	assert.EqualError(t, err, `no KMS key matched name="bar" (searched 1 KMS key)`)
+
+	_, err = One([]Attributes{}, Condition{Name: "bar"}, attributes, "KMS key")
+	assert.EqualError(t, err, "no KMS keys exist in this account")
+}
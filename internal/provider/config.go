@@ -0,0 +1,328 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sakura
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	apiprof "github.com/sacloud/api-client-go/profile"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+)
+
+// 環境変数の参照用。テストではmapを使った実装に差し替える
+type envLookupFunc func(key string) (string, bool)
+
+// 空文字が設定された環境変数は未設定として扱う
+func getStringValueFromEnv(lookupEnv envLookupFunc, envVar string, defaultValue string) string {
+	value, ok := lookupEnv(envVar)
+	if !ok || value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func getIntValueFromEnv(lookupEnv envLookupFunc, diags *diag.Diagnostics, envVar string, defaultValue int) int {
+	valueStr, ok := lookupEnv(envVar)
+	if !ok || valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Error parsing environment variable %q", envVar), err.Error())
+		return defaultValue
+	}
+	return value
+}
+
+// getDurationSecondsValueFromEnv は秒数の整数または"30s"などの時間の文字列の環境変数を秒数として返す
+func getDurationSecondsValueFromEnv(lookupEnv envLookupFunc, diags *diag.Diagnostics, envVar string, defaultValue int) int {
+	valueStr, ok := lookupEnv(envVar)
+	if !ok || valueStr == "" {
+		return defaultValue
+	}
+	value, err := common.ParseDurationSeconds(valueStr)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Error parsing environment variable %q", envVar), err.Error())
+		return defaultValue
+	}
+	return value
+}
+
+// getDurationSecondsValueFromConfig は秒数または時間の文字列の設定値を秒数として返す。未指定の場合はdefaultValueを返す
+func getDurationSecondsValueFromConfig(value types.String, diags *diag.Diagnostics, name string, defaultValue int) int {
+	if value.IsNull() || value.IsUnknown() {
+		return defaultValue
+	}
+	// 設定値はスキーマのバリデータで検証済みのため、通常はエラーにならない
+	seconds, err := common.ParseDurationSeconds(value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root(name), "Invalid provider configuration", err.Error())
+		return defaultValue
+	}
+	return seconds
+}
+
+func getBoolValueFromEnv(lookupEnv envLookupFunc, diags *diag.Diagnostics, envVar string, defaultValue bool) bool {
+	valueStr, ok := lookupEnv(envVar)
+	if !ok || valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Error parsing environment variable %q", envVar), err.Error())
+		return defaultValue
+	}
+	return value
+}
+
+func getStringSliceValueFromEnv(lookupEnv envLookupFunc, envVar string) []string {
+	value, ok := lookupEnv(envVar)
+	if !ok || value == "" {
+		return nil
+	}
+	values := strings.Split(value, ",")
+	for i := range values {
+		values[i] = strings.Trim(values[i], " ")
+	}
+	return values
+}
+
+func isValidTraceMode(mode string) bool {
+	return slices.ContainsFunc(common.TraceModes, func(v string) bool { return strings.EqualFold(v, mode) })
+}
+
+// ゾーン関連の属性のうち、plan時点で値が確定していないものの属性名を返す
+func unknownZoneAttributes(config *sakuraProviderModel) []string {
+	var unknowns []string
+	if config.Zone.IsUnknown() {
+		unknowns = append(unknowns, "zone")
+	}
+	if config.Zones.IsUnknown() {
+		unknowns = append(unknowns, "zones")
+	}
+	if config.DefaultZone.IsUnknown() {
+		unknowns = append(unknowns, "default_zone")
+	}
+	return unknowns
+}
+
+// プロバイダーの設定値・環境変数・デフォルト値から最終的なConfigを組み立てる。優先順位は設定値 > 環境変数 > デフォルト値
+func resolveConfig(config *sakuraProviderModel, lookupEnv envLookupFunc) (*common.Config, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	// ゾーンが未確定のままデフォルト値で処理を進めると、apply時に意図しないゾーンを操作してしまうためエラーにする
+	for _, name := range unknownZoneAttributes(config) {
+		diags.AddAttributeError(path.Root(name), "Unknown provider configuration",
+			fmt.Sprintf("provider %s is not known at plan time. Set a value that is known during plan, or apply the resources it depends on first.", name))
+	}
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	profile := getStringValueFromEnv(lookupEnv, "SAKURACLOUD_PROFILE", apiprof.DefaultProfileName)
+	token := getStringValueFromEnv(lookupEnv, "SAKURACLOUD_ACCESS_TOKEN", "")
+	secret := getStringValueFromEnv(lookupEnv, "SAKURACLOUD_ACCESS_TOKEN_SECRET", "")
+	zone := getStringValueFromEnv(lookupEnv, "SAKURACLOUD_ZONE", common.Zone)
+	defaultZone := getStringValueFromEnv(lookupEnv, "SAKURACLOUD_DEFAULT_ZONE", "")
+	apiRootUrl := getStringValueFromEnv(lookupEnv, "SAKURACLOUD_API_ROOT_URL", "")
+	retryMax := getIntValueFromEnv(lookupEnv, &diags, "SAKURACLOUD_RETRY_MAX", common.RetryMax)
+	retryWaitMax := getDurationSecondsValueFromEnv(lookupEnv, &diags, "SAKURACLOUD_RETRY_WAIT_MAX", 0)
+	retryWaitMin := getDurationSecondsValueFromEnv(lookupEnv, &diags, "SAKURACLOUD_RETRY_WAIT_MIN", 0)
+	apiRequestTimeout := getDurationSecondsValueFromEnv(lookupEnv, &diags, "SAKURACLOUD_API_REQUEST_TIMEOUT", common.APIRequestTimeout)
+	apiRequestRateLimit := getIntValueFromEnv(lookupEnv, &diags, "SAKURACLOUD_RATE_LIMIT", common.APIRequestRateLimit)
+	traceMode := getStringValueFromEnv(lookupEnv, "SAKURACLOUD_TRACE", "")
+	maxParallelZoneRequests := getIntValueFromEnv(lookupEnv, &diags, "SAKURACLOUD_MAX_PARALLEL_ZONE_REQUESTS", common.MaxParallelZoneRequests)
+	maxConcurrentAPIRequests := getIntValueFromEnv(lookupEnv, &diags, "SAKURACLOUD_MAX_CONCURRENT_API_REQUESTS", 0)
+	disableReadCache := getBoolValueFromEnv(lookupEnv, &diags, "SAKURACLOUD_DISABLE_READ_CACHE", false)
+	ignoreSystemTags := getBoolValueFromEnv(lookupEnv, &diags, "SAKURACLOUD_IGNORE_SYSTEM_TAGS", true)
+	validateReferences := getBoolValueFromEnv(lookupEnv, &diags, "SAKURACLOUD_VALIDATE_REFERENCES", false)
+
+	// Plugin Frameworkの設定値が最優先
+	if config.Profile.ValueString() != "" {
+		profile = config.Profile.ValueString()
+	}
+	// token/secretは項目ごとにプロバイダーの設定・環境変数・プロファイルの順に解決する
+	if config.AccessToken.ValueString() != "" {
+		token = config.AccessToken.ValueString()
+	}
+	if config.AccessTokenSecret.ValueString() != "" {
+		secret = config.AccessTokenSecret.ValueString()
+	}
+	if config.Zone.ValueString() != "" {
+		zone = config.Zone.ValueString()
+	}
+	if config.DefaultZone.ValueString() != "" {
+		defaultZone = config.DefaultZone.ValueString()
+	}
+	if config.APIRootURL.ValueString() != "" {
+		apiRootUrl = config.APIRootURL.ValueString()
+	}
+	if config.TraceMode.ValueString() != "" {
+		traceMode = config.TraceMode.ValueString()
+	} else if traceMode != "" && !isValidTraceMode(traceMode) {
+		// 設定値はスキーマのバリデータで検証済みのため、環境変数から渡された場合のみ検証する
+		diags.AddError(`Invalid environment variable "SAKURACLOUD_TRACE"`,
+			fmt.Sprintf("SAKURACLOUD_TRACE must be one of %s, got: %q", strings.Join(common.TraceModes, ", "), traceMode))
+	}
+	if !config.RetryMax.IsNull() && !config.RetryMax.IsUnknown() {
+		retryMax = int(config.RetryMax.ValueInt64())
+	}
+	retryWaitMax = getDurationSecondsValueFromConfig(config.RetryWaitMax, &diags, "retry_wait_max", retryWaitMax)
+	retryWaitMin = getDurationSecondsValueFromConfig(config.RetryWaitMin, &diags, "retry_wait_min", retryWaitMin)
+	apiRequestTimeout = getDurationSecondsValueFromConfig(config.APIRequestTimeout, &diags, "api_request_timeout", apiRequestTimeout)
+	if !config.APIRequestRateLimit.IsNull() && !config.APIRequestRateLimit.IsUnknown() {
+		apiRequestRateLimit = int(config.APIRequestRateLimit.ValueInt64())
+	}
+	if !config.MaxParallelZoneRequests.IsNull() && !config.MaxParallelZoneRequests.IsUnknown() {
+		maxParallelZoneRequests = int(config.MaxParallelZoneRequests.ValueInt64())
+	}
+	if !config.MaxConcurrentAPIRequests.IsNull() && !config.MaxConcurrentAPIRequests.IsUnknown() {
+		maxConcurrentAPIRequests = int(config.MaxConcurrentAPIRequests.ValueInt64())
+	}
+	if !config.DisableReadCache.IsNull() && !config.DisableReadCache.IsUnknown() {
+		disableReadCache = config.DisableReadCache.ValueBool()
+	}
+	if !config.IgnoreSystemTags.IsNull() && !config.IgnoreSystemTags.IsUnknown() {
+		ignoreSystemTags = config.IgnoreSystemTags.ValueBool()
+	}
+	if !config.ValidateReferences.IsNull() && !config.ValidateReferences.IsUnknown() {
+		validateReferences = config.ValidateReferences.ValueBool()
+	}
+	var httpClient common.HTTPClientConfig
+	if hc := config.HTTPClient; hc != nil {
+		if !hc.MaxIdleConnsPerHost.IsNull() && !hc.MaxIdleConnsPerHost.IsUnknown() {
+			httpClient.MaxIdleConnsPerHost = int(hc.MaxIdleConnsPerHost.ValueInt64())
+		}
+		if !hc.MaxConnsPerHost.IsNull() && !hc.MaxConnsPerHost.IsUnknown() {
+			httpClient.MaxConnsPerHost = int(hc.MaxConnsPerHost.ValueInt64())
+		}
+		if !hc.IdleConnTimeout.IsNull() && !hc.IdleConnTimeout.IsUnknown() {
+			httpClient.IdleConnTimeout = time.Duration(hc.IdleConnTimeout.ValueInt64()) * time.Second
+		}
+	}
+	var resourceDefaults common.ResourceDefaults
+	if rd := config.ResourceDefaults; rd != nil {
+		resourceDefaults.Description = rd.Description.ValueString()
+		resourceDefaults.IconID = rd.IconID.ValueString()
+	}
+	if !config.DefaultTags.IsNull() && !config.DefaultTags.IsUnknown() {
+		var elements []types.String
+		diags.Append(config.DefaultTags.ElementsAs(context.Background(), &elements, true)...)
+		for i, v := range elements {
+			switch {
+			case v.IsNull():
+				diags.AddAttributeError(path.Root("default_tags").AtListIndex(i), "Invalid provider configuration", fmt.Sprintf("default_tags[%d] is null", i))
+			case v.IsUnknown():
+				diags.AddAttributeError(path.Root("default_tags").AtListIndex(i), "Invalid provider configuration", fmt.Sprintf("default_tags[%d] is not known at plan time", i))
+			default:
+				resourceDefaults.Tags = append(resourceDefaults.Tags, v.ValueString())
+			}
+		}
+	}
+	zones := []string{}
+	if !config.Zones.IsNull() && !config.Zones.IsUnknown() {
+		var elements []types.String
+		diags.Append(config.Zones.ElementsAs(context.Background(), &elements, true)...)
+		for i, v := range elements {
+			switch {
+			case v.IsNull():
+				diags.AddAttributeError(path.Root("zones").AtListIndex(i), "Invalid provider configuration", fmt.Sprintf("zones[%d] is null", i))
+			case v.IsUnknown():
+				diags.AddAttributeError(path.Root("zones").AtListIndex(i), "Invalid provider configuration", fmt.Sprintf("zones[%d] is not known at plan time", i))
+			default:
+				zones = append(zones, v.ValueString())
+			}
+		}
+	}
+	if len(zones) == 0 {
+		zones = getStringSliceValueFromEnv(lookupEnv, "SAKURACLOUD_ZONES")
+	}
+
+	cfg := &common.Config{
+		Profile:             profile,
+		AccessToken:         token,
+		AccessTokenSecret:   secret,
+		Zone:                zone,
+		Zones:               zones,
+		DefaultZone:         defaultZone,
+		TraceMode:           traceMode,
+		APIRootURL:          apiRootUrl,
+		RetryMax:            retryMax,
+		RetryWaitMax:        retryWaitMax,
+		RetryWaitMin:        retryWaitMin,
+		APIRequestTimeout:   apiRequestTimeout,
+		APIRequestRateLimit: apiRequestRateLimit,
+
+		MaxParallelZoneRequests:  maxParallelZoneRequests,
+		MaxConcurrentAPIRequests: maxConcurrentAPIRequests,
+		DisableReadCache:         disableReadCache,
+		IgnoreSystemTags:         ignoreSystemTags,
+		ValidateReferences:       validateReferences,
+		HTTPClient:               httpClient,
+		ResourceDefaults:         resourceDefaults,
+	}
+	diags.Append(checkRetrySettings(cfg)...)
+	return cfg, diags
+}
+
+// checkRetrySettings はリトライとレート制限の設定の組み合わせが、意図通りに機能しない場合に警告を返す。
+// 設定自体は有効なため、エラーにはしない。プロファイルで指定された値はNewClientで反映されるため考慮しない
+func checkRetrySettings(cfg *common.Config) diag.Diagnostics {
+	var diags diag.Diagnostics
+	const summary = "Ineffective retry settings"
+
+	if cfg.APIRequestRateLimit <= 0 {
+		diags.AddWarning(summary, fmt.Sprintf("api_request_rate_limit must be greater than 0, got %d. The default of %d requests per second is used instead", cfg.APIRequestRateLimit, common.APIRequestRateLimit))
+	}
+
+	waitMin, waitMax := cfg.RetryWaits()
+	if waitMin > waitMax {
+		diags.AddWarning(summary, fmt.Sprintf("retry_wait_min (%s) is greater than retry_wait_max (%s). Every retry waits retry_wait_max", waitMin, waitMax))
+	}
+	if cfg.RetryMax <= 0 {
+		return diags
+	}
+
+	// 待機時間は最短でもretry_wait_min(retry_wait_maxの方が小さい場合はretry_wait_max)となる
+	wait := min(waitMin, waitMax)
+	timeout := cfg.RequestTimeout()
+	switch fit := int(timeout / wait); {
+	case fit == 0:
+		diags.AddWarning(summary, fmt.Sprintf("api_request_timeout (%s) is shorter than a single retry wait (%s), so failed requests are never retried. Increase api_request_timeout or decrease retry_wait_min", timeout, wait))
+	case fit < cfg.RetryMax:
+		diags.AddWarning(summary, fmt.Sprintf("retry_max is %d, but at most %d retries waiting at least %s each fit in api_request_timeout (%s). Increase api_request_timeout or decrease retry_max/retry_wait_min", cfg.RetryMax, fit, wait, timeout))
+	}
+	return diags
+}
+
+// configKey はAPIクライアントの生成に使う設定値から、生成済みのクライアントを再利用できるかを判定するためのキーを返す。
+// NewClientはプロファイルの読み込みでcfgを書き換えるため、NewClientの呼び出し前に計算すること
+func configKey(cfg *common.Config) string {
+	c := *cfg
+	c.HTTPTransport = nil // プロバイダーごとに固定のため比較しない
+	sum := sha256.Sum256(fmt.Appendf(nil, "%#v", c))
+	return hex.EncodeToString(sum[:])
+}
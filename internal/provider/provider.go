@@ -16,23 +16,34 @@ package sakura
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
-	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 
-	apiprof "github.com/sacloud/api-client-go/profile"
-	"github.com/sacloud/packages-go/envvar"
+	sacloudhttp "github.com/sacloud/go-http"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/desc"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/service/archive"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/service/bridge"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/service/container_registry"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/service/disk"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/service/dns"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/service/icon"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/service/internet"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/service/kms"
@@ -45,6 +56,7 @@ import (
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/service/simple_mq"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/service/ssh_key"
 	sw1tch "github.com/sacloud/terraform-provider-sakuracloud/internal/service/switch"
+	sacloudvalidator "github.com/sacloud/terraform-provider-sakuracloud/internal/validator"
 )
 
 type sakuraProviderModel struct {
@@ -56,22 +68,81 @@ type sakuraProviderModel struct {
 	DefaultZone         types.String `tfsdk:"default_zone"`
 	APIRootURL          types.String `tfsdk:"api_root_url"`
 	RetryMax            types.Int64  `tfsdk:"retry_max"`
-	RetryWaitMax        types.Int64  `tfsdk:"retry_wait_max"`
-	RetryWaitMin        types.Int64  `tfsdk:"retry_wait_min"`
-	APIRequestTimeout   types.Int64  `tfsdk:"api_request_timeout"`
+	RetryWaitMax        types.String `tfsdk:"retry_wait_max"`
+	RetryWaitMin        types.String `tfsdk:"retry_wait_min"`
+	APIRequestTimeout   types.String `tfsdk:"api_request_timeout"`
 	APIRequestRateLimit types.Int64  `tfsdk:"api_request_rate_limit"`
 	TraceMode           types.String `tfsdk:"trace"`
+
+	MaxParallelZoneRequests  types.Int64 `tfsdk:"max_parallel_zone_requests"`
+	MaxConcurrentAPIRequests types.Int64 `tfsdk:"max_concurrent_api_requests"`
+	DisableReadCache         types.Bool  `tfsdk:"disable_read_cache"`
+	IgnoreSystemTags         types.Bool  `tfsdk:"ignore_system_tags"`
+	ValidateReferences       types.Bool  `tfsdk:"validate_references"`
+	DefaultTags              types.List  `tfsdk:"default_tags"`
+
+	HTTPClient       *sakuraProviderHTTPClientModel       `tfsdk:"http_client"`
+	ResourceDefaults *sakuraProviderResourceDefaultsModel `tfsdk:"resource_defaults"`
+}
+
+type sakuraProviderResourceDefaultsModel struct {
+	Description types.String `tfsdk:"description"`
+	IconID      types.String `tfsdk:"icon_id"`
+}
+
+type sakuraProviderHTTPClientModel struct {
+	MaxIdleConnsPerHost types.Int64 `tfsdk:"max_idle_conns_per_host"`
+	MaxConnsPerHost     types.Int64 `tfsdk:"max_conns_per_host"`
+	IdleConnTimeout     types.Int64 `tfsdk:"idle_conn_timeout"`
 }
 
-func New(version string) func() provider.Provider {
+func New(version string, opts ...Option) func() provider.Provider {
+	// タイプ名の重複などの登録の誤りはterraformの実行時まで気づきにくいため、プロバイダーの生成前に検出する
+	if err := validateRegistrations(context.Background(), &sakuraProvider{}); err != nil {
+		panic(fmt.Sprintf("invalid resource/data source registration: %s", err))
+	}
 	return func() provider.Provider {
-		return &sakuraProvider{version: version}
+		p := &sakuraProvider{version: version, lookupEnv: os.LookupEnv}
+		for _, opt := range opts {
+			opt(p)
+		}
+		return p
+	}
+}
+
+// Option はプロバイダーの動作を差し替えるためのオプション。テストで利用する
+type Option func(p *sakuraProvider)
+
+// WithHTTPTransport はAPIリクエストに利用するhttp.RoundTripperを差し替える。テストでのリクエストの記録/再生に利用する
+func WithHTTPTransport(transport http.RoundTripper) Option {
+	return func(p *sakuraProvider) {
+		p.transport = transport
 	}
 }
 
+// WithEnvLookup は環境変数の参照を差し替える。テストでプロセスの環境変数を書き換えずに設定値を渡すために利用する
+func WithEnvLookup(lookupEnv func(key string) (string, bool)) Option {
+	return func(p *sakuraProvider) {
+		p.lookupEnv = lookupEnv
+	}
+}
+
+var (
+	_ provider.Provider                   = &sakuraProvider{}
+	_ provider.ProviderWithFunctions      = &sakuraProvider{}
+	_ provider.ProviderWithValidateConfig = &sakuraProvider{}
+)
+
 type sakuraProvider struct {
-	version string
-	client  *common.APIClient
+	version   string
+	transport http.RoundTripper
+	lookupEnv envLookupFunc
+
+	// Configureはplan/applyの各フェーズやエイリアスされたプロバイダーから並行して呼び出されることがあるため、
+	// clientの生成をmuで保護し、同じ設定であれば生成済みのclientを再利用する
+	mu        sync.Mutex
+	client    *common.APIClient
+	clientKey string
 }
 
 func (p *sakuraProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -83,136 +154,218 @@ func (p *sakuraProvider) Metadata(_ context.Context, _ provider.MetadataRequest,
 func (p *sakuraProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
-			"profile": schema.StringAttribute{Optional: true},
-			"token":   schema.StringAttribute{Optional: true},
-			"secret":  schema.StringAttribute{Optional: true},
-			"zone":    schema.StringAttribute{Optional: true},
+			"profile": schema.StringAttribute{
+				Optional:    true,
+				Description: "The name of the usacloud profile. This conflicts with token and secret",
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("token"), path.MatchRoot("secret")),
+				},
+			},
+			"token": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The API key of SakuraCloud. When omitted, SAKURACLOUD_ACCESS_TOKEN or the profile is used",
+			},
+			"secret": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "The API secret of SakuraCloud. When omitted, SAKURACLOUD_ACCESS_TOKEN_SECRET or the profile is used",
+			},
+			"zone": schema.StringAttribute{Optional: true},
 			"zones": schema.ListAttribute{
 				ElementType: types.StringType,
 				Optional:    true,
 			},
-			"default_zone":           schema.StringAttribute{Optional: true},
-			"api_root_url":           schema.StringAttribute{Optional: true},
-			"retry_max":              schema.Int64Attribute{Optional: true},
-			"retry_wait_max":         schema.Int64Attribute{Optional: true},
-			"retry_wait_min":         schema.Int64Attribute{Optional: true},
-			"api_request_timeout":    schema.Int64Attribute{Optional: true},
+			"default_zone": schema.StringAttribute{Optional: true},
+			"api_root_url": schema.StringAttribute{Optional: true},
+			"retry_max":    schema.Int64Attribute{Optional: true},
+			"retry_wait_max": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("The maximum wait between retries of an API request, as a number of seconds such as `30` or a duration such as `30s` or `2m`. Default is %s. This can also be specified with the SAKURACLOUD_RETRY_WAIT_MAX environment variable", common.FormatDuration(sacloudhttp.DefaultRetryWaitMax)),
+				Validators: []validator.String{
+					common.DurationValidator(),
+				},
+			},
+			"retry_wait_min": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("The minimum wait between retries of an API request, as a number of seconds such as `1` or a duration such as `1s`. Default is %s. This can also be specified with the SAKURACLOUD_RETRY_WAIT_MIN environment variable", common.FormatDuration(sacloudhttp.DefaultRetryWaitMin)),
+				Validators: []validator.String{
+					common.DurationValidator(),
+				},
+			},
+			"api_request_timeout": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("The time limit of an API request including retries, as a number of seconds such as `300` or a duration such as `5m`. Default is %s. This can also be specified with the SAKURACLOUD_API_REQUEST_TIMEOUT environment variable", common.FormatDuration(common.APIRequestTimeout*time.Second)),
+				Validators: []validator.String{
+					common.DurationValidator(),
+				},
+			},
 			"api_request_rate_limit": schema.Int64Attribute{Optional: true},
-			"trace":                  schema.StringAttribute{Optional: true},
+			"trace": schema.StringAttribute{
+				Optional:    true,
+				Description: fmt.Sprintf("The trace mode of API calls. This must be one of [%s]. This can also be specified with the SAKURACLOUD_TRACE environment variable", strings.Join(common.TraceModes, "/")),
+				Validators: []validator.String{
+					stringvalidator.OneOfCaseInsensitive(common.TraceModes...),
+				},
+			},
+			"max_parallel_zone_requests": schema.Int64Attribute{
+				Optional:    true,
+				Description: fmt.Sprintf("The maximum number of zones read in parallel by data sources that query multiple zones. Default is %d. This can also be specified with the SAKURACLOUD_MAX_PARALLEL_ZONE_REQUESTS environment variable", common.MaxParallelZoneRequests),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"max_concurrent_api_requests": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The maximum number of API requests in flight at the same time, regardless of Terraform's parallelism. 0 or unset means no limit. This can also be specified with the SAKURACLOUD_MAX_CONCURRENT_API_REQUESTS environment variable",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"disable_read_cache": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Set true to disable reusing list results across data sources that look up resources by name within a short period. This can also be specified with the SAKURACLOUD_DISABLE_READ_CACHE environment variable",
+			},
+			"ignore_system_tags": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Set false to manage tags beginning with `@` like other tags. When true, such tags added outside Terraform are kept on update and excluded from the diff unless they are listed in `tags`. This currently applies to `sakuracloud_kms` and `sakuracloud_secret_manager` only. Default is true. This can also be specified with the SAKURACLOUD_IGNORE_SYSTEM_TAGS environment variable",
+			},
+			"validate_references": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Set true to check at plan time that IDs of other resources such as `kms_key_id` refer to existing resources. This issues an additional API request per resource when the ID is known. Default is false. This can also be specified with the SAKURACLOUD_VALIDATE_REFERENCES environment variable",
+			},
+			"default_tags": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "The tags added to all resources that have `tags`. Tags listed in both `default_tags` and a resource are not duplicated. Data sources are not affected",
+				Validators:  sacloudvalidator.TagList(),
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"http_client": schema.SingleNestedBlock{
+				Description: "The connection settings of the HTTP client. Each API host uses its own connection pool with these settings",
+				Attributes: map[string]schema.Attribute{
+					"max_idle_conns_per_host": schema.Int64Attribute{
+						Optional:    true,
+						Description: fmt.Sprintf("The maximum number of idle connections kept per API host. Default is %d", common.DefaultMaxIdleConnsPerHost),
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+					"max_conns_per_host": schema.Int64Attribute{
+						Optional:    true,
+						Description: "The maximum number of connections per API host. 0 or unset means no limit",
+						Validators: []validator.Int64{
+							int64validator.AtLeast(0),
+						},
+					},
+					"idle_conn_timeout": schema.Int64Attribute{
+						Optional:    true,
+						Description: fmt.Sprintf("The number of seconds an idle connection is kept before closing. Default is %d", int(common.DefaultIdleConnTimeout.Seconds())),
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+				},
+			},
+			"resource_defaults": schema.SingleNestedBlock{
+				Description: "The default values of the attributes common to resources. These are used when a resource omits the attribute, and a value set in the resource takes precedence",
+				Attributes: map[string]schema.Attribute{
+					"description": schema.StringAttribute{
+						Optional:    true,
+						Description: desc.Sprintf("The default description of resources. %s", desc.Length(1, 512)),
+						Validators: []validator.String{
+							stringvalidator.UTF8LengthBetween(1, 512),
+						},
+					},
+					"icon_id": schema.StringAttribute{
+						Optional:    true,
+						Description: "The default icon id to attach to resources",
+						Validators: []validator.String{
+							sacloudvalidator.SakuraIDValidator(),
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
-func getIntValueFromEnv(resp *provider.ConfigureResponse, envVar string, defaultValue int) int {
-	valueStr, ok := os.LookupEnv(envVar)
-	if !ok {
-		return defaultValue
+// ValidateConfig はプロバイダーブロックで指定されたretry_wait_minがretry_wait_maxより大きい場合にplan時にエラーとする。
+// 環境変数やデフォルト値との組み合わせはConfigureで警告する(checkRetrySettingsを参照)
+func (p *sakuraProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var waitMin, waitMax types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("retry_wait_min"), &waitMin)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("retry_wait_max"), &waitMax)...)
+	if resp.Diagnostics.HasError() || waitMin.IsNull() || waitMin.IsUnknown() || waitMax.IsNull() || waitMax.IsUnknown() {
+		return
 	}
-	value, err := strconv.Atoi(valueStr)
+	// 解釈できない値は属性のバリデータでエラーとなる
+	minValue, err := common.ParseDuration(waitMin.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError(fmt.Sprintf("Error parsing environment variable %q", envVar), err.Error())
-		return defaultValue
+		return
 	}
-	return value
-}
-
-func (p *sakuraProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
-	profile, ok := os.LookupEnv("SAKURACLOUD_PROFILE")
-	if !ok {
-		profile = apiprof.DefaultProfileName
+	maxValue, err := common.ParseDuration(waitMax.ValueString())
+	if err != nil {
+		return
 	}
-	token := os.Getenv("SAKURACLOUD_ACCESS_TOKEN")
-	secret := os.Getenv("SAKURACLOUD_ACCESS_TOKEN_SECRET")
-	zone, ok := os.LookupEnv("SAKURACLOUD_ZONE")
-	if !ok {
-		zone = common.Zone
+	if minValue > maxValue {
+		resp.Diagnostics.AddAttributeError(path.Root("retry_wait_min"), "Invalid provider configuration",
+			fmt.Sprintf("retry_wait_min (%s) must not be greater than retry_wait_max (%s)", common.FormatDuration(minValue), common.FormatDuration(maxValue)))
 	}
-	defaultZone, _ := os.LookupEnv("SAKURACLOUD_DEFAULT_ZONE")
-	apiRootUrl := os.Getenv("SAKURACLOUD_API_ROOT_URL")
-	retryMax := getIntValueFromEnv(resp, "SAKURACLOUD_RETRY_MAX", common.RetryMax)
-	retryWaitMax := getIntValueFromEnv(resp, "SAKURACLOUD_RETRY_WAIT_MAX", 0)
-	retryWaitMin := getIntValueFromEnv(resp, "SAKURACLOUD_RETRY_WAIT_MIN", 0)
-	apiRequestTimeout := getIntValueFromEnv(resp, "SAKURACLOUD_API_REQUEST_TIMEOUT", common.APIRequestTimeout)
-	apiRequestRateLimit := getIntValueFromEnv(resp, "SAKURACLOUD_RATE_LIMIT", common.APIRequestRateLimit)
+}
 
+func (p *sakuraProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
 	var config sakuraProviderModel
 	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Plugin Frameworkの設定値が最優先
-	if config.Profile.ValueString() != "" {
-		profile = config.Profile.ValueString()
-	}
-	if config.AccessToken.ValueString() != "" {
-		token = config.AccessToken.ValueString()
-	}
-	if config.AccessTokenSecret.ValueString() != "" {
-		secret = config.AccessTokenSecret.ValueString()
-	}
-	if config.Zone.ValueString() != "" {
-		zone = config.Zone.ValueString()
-	}
-	if config.DefaultZone.ValueString() != "" {
-		defaultZone = config.DefaultZone.ValueString()
-	}
-	if config.APIRootURL.ValueString() != "" {
-		apiRootUrl = config.APIRootURL.ValueString()
-	}
-	if !config.RetryMax.IsNull() && !config.RetryMax.IsUnknown() {
-		retryMax = int(config.RetryMax.ValueInt64())
-	}
-	if !config.RetryWaitMax.IsNull() && !config.RetryWaitMax.IsUnknown() {
-		retryWaitMax = int(config.RetryWaitMax.ValueInt64())
-	}
-	if !config.RetryWaitMin.IsNull() && !config.RetryWaitMin.IsUnknown() {
-		retryWaitMin = int(config.RetryWaitMin.ValueInt64())
-	}
-	if !config.APIRequestTimeout.IsNull() && !config.APIRequestTimeout.IsUnknown() {
-		apiRequestTimeout = int(config.APIRequestTimeout.ValueInt64())
-	}
-	if !config.APIRequestRateLimit.IsNull() && !config.APIRequestRateLimit.IsUnknown() {
-		apiRequestRateLimit = int(config.APIRequestRateLimit.ValueInt64())
-	}
-	zones := []string{}
-	if !config.Zones.IsNull() && !config.Zones.IsUnknown() {
-		for _, v := range config.Zones.Elements() {
-			zones = append(zones, v.(types.String).ValueString())
-		}
-	}
-	if len(zones) == 0 {
-		zones = envvar.StringSliceFromEnv("SAKURACLOUD_ZONES", nil)
+	if len(unknownZoneAttributes(&config)) > 0 && req.ClientCapabilities.DeferralAllowed {
+		resp.Deferred = &provider.Deferred{Reason: provider.DeferredReasonProviderConfigUnknown}
+		return
 	}
 
-	cfg := common.Config{
-		Profile:             profile,
-		AccessToken:         token,
-		AccessTokenSecret:   secret,
-		Zone:                zone,
-		Zones:               zones,
-		DefaultZone:         defaultZone,
-		TraceMode:           config.TraceMode.ValueString(),
-		APIRootURL:          apiRootUrl,
-		RetryMax:            retryMax,
-		RetryWaitMax:        retryWaitMax,
-		RetryWaitMin:        retryWaitMin,
-		APIRequestTimeout:   apiRequestTimeout,
-		APIRequestRateLimit: apiRequestRateLimit,
-		TerraformVersion:    req.TerraformVersion,
+	cfg, diags := resolveConfig(&config, p.lookupEnv)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
+	cfg.TerraformVersion = req.TerraformVersion
+	cfg.HTTPTransport = p.transport
 
-	client, err := cfg.NewClient()
+	client, err := p.configureClient(cfg)
 	if err != nil {
 		resp.Diagnostics.AddError("Error creating Sakura client", err.Error())
 		return
 	}
 
-	p.client = client
 	resp.DataSourceData = client
 	resp.ResourceData = client
 }
 
+// configureClient はcfgに対応するAPIクライアントを返す。
+// 同じ設定で生成済みのクライアントがあればそれを返し、設定が異なる場合も既存のクライアントは利用中のリソースがあるため閉じたり変更したりしない
+func (p *sakuraProvider) configureClient(cfg *common.Config) (*common.APIClient, error) {
+	key := configKey(cfg)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client != nil && p.clientKey == key {
+		return p.client, nil
+	}
+
+	client, err := cfg.NewClient()
+	if err != nil {
+		return nil, err
+	}
+	p.client = client
+	p.clientKey = key
+	return client, nil
+}
+
 func (p *sakuraProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		archive.NewArchiveDataSource,
@@ -242,6 +395,8 @@ func (p *sakuraProvider) Resources(_ context.Context) []func() resource.Resource
 		bridge.NewBridgeResource,
 		container_registry.NewContainerRegistryResource,
 		disk.NewDiskResource,
+		dns.NewDNSResource,
+		dns.NewDNSRecordResource,
 		icon.NewIconResource,
 		internet.NewInternetResource,
 		kms.NewKMSResource,
@@ -252,6 +407,7 @@ func (p *sakuraProvider) Resources(_ context.Context) []func() resource.Resource
 		private_host.NewPrivateHostResource,
 		secret_manager.NewSecretManagerResource,
 		secret_manager.NewSecretManagerSecretResource,
+		secret_manager.NewSecretManagerSecretsResource,
 		server.NewServerResource,
 		simple_mq.NewSimpleMQResource,
 		ssh_key.NewSSHKeyResource,
@@ -259,3 +415,50 @@ func (p *sakuraProvider) Resources(_ context.Context) []func() resource.Resource
 		// ...他のリソースも同様に追加...
 	}
 }
+
+func (p *sakuraProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		secret_manager.NewSecretImportIDFunction,
+	}
+}
+
+// validateRegistrations は登録された全てのリソース/データソースのMetadataを呼び出し、
+// タイプ名の重複やプロバイダーのタイプ名で始まらないものがあればエラーを返す
+func validateRegistrations(ctx context.Context, p *sakuraProvider) error {
+	var metaResp provider.MetadataResponse
+	p.Metadata(ctx, provider.MetadataRequest{}, &metaResp)
+	providerTypeName := metaResp.TypeName
+
+	var resourceNames []string
+	for _, f := range p.Resources(ctx) {
+		var resp resource.MetadataResponse
+		f().Metadata(ctx, resource.MetadataRequest{ProviderTypeName: providerTypeName}, &resp)
+		resourceNames = append(resourceNames, resp.TypeName)
+	}
+	var dataSourceNames []string
+	for _, f := range p.DataSources(ctx) {
+		var resp datasource.MetadataResponse
+		f().Metadata(ctx, datasource.MetadataRequest{ProviderTypeName: providerTypeName}, &resp)
+		dataSourceNames = append(dataSourceNames, resp.TypeName)
+	}
+
+	return errors.Join(
+		validateTypeNames("resource", providerTypeName, resourceNames),
+		validateTypeNames("data source", providerTypeName, dataSourceNames),
+	)
+}
+
+func validateTypeNames(kind, providerTypeName string, names []string) error {
+	var errs []error
+	seen := make(map[string]bool, len(names))
+	for i, name := range names {
+		if !strings.HasPrefix(name, providerTypeName+"_") {
+			errs = append(errs, fmt.Errorf("%s type name %q (index %d) must be prefixed with %q", kind, name, i, providerTypeName+"_"))
+		}
+		if seen[name] {
+			errs = append(errs, fmt.Errorf("%s type name %q is registered more than once", kind, name))
+		}
+		seen[name] = true
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,1363 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sakura
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	apiprof "github.com/sacloud/api-client-go/profile"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testEnvLookup(envs map[string]string) envLookupFunc {
+	return func(key string) (string, bool) {
+		v, ok := envs[key]
+		return v, ok
+	}
+}
+
+func testProviderModel() *sakuraProviderModel {
+	return &sakuraProviderModel{
+		Profile:             types.StringNull(),
+		AccessToken:         types.StringNull(),
+		AccessTokenSecret:   types.StringNull(),
+		Zone:                types.StringNull(),
+		Zones:               types.ListNull(types.StringType),
+		DefaultZone:         types.StringNull(),
+		APIRootURL:          types.StringNull(),
+		RetryMax:            types.Int64Null(),
+		RetryWaitMax:        types.StringNull(),
+		RetryWaitMin:        types.StringNull(),
+		APIRequestTimeout:   types.StringNull(),
+		APIRequestRateLimit: types.Int64Null(),
+		TraceMode:           types.StringNull(),
+
+		MaxParallelZoneRequests:  types.Int64Null(),
+		MaxConcurrentAPIRequests: types.Int64Null(),
+		DisableReadCache:         types.BoolNull(),
+		IgnoreSystemTags:         types.BoolNull(),
+		ValidateReferences:       types.BoolNull(),
+		DefaultTags:              types.ListNull(types.StringType),
+	}
+}
+
+func TestResolveConfig_unknownZone(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		modify func(m *sakuraProviderModel)
+		paths  []path.Path
+	}{
+		{
+			name:   "zone",
+			modify: func(m *sakuraProviderModel) { m.Zone = types.StringUnknown() },
+			paths:  []path.Path{path.Root("zone")},
+		},
+		{
+			name:   "zones",
+			modify: func(m *sakuraProviderModel) { m.Zones = types.ListUnknown(types.StringType) },
+			paths:  []path.Path{path.Root("zones")},
+		},
+		{
+			name:   "default_zone",
+			modify: func(m *sakuraProviderModel) { m.DefaultZone = types.StringUnknown() },
+			paths:  []path.Path{path.Root("default_zone")},
+		},
+		{
+			name: "all",
+			modify: func(m *sakuraProviderModel) {
+				m.Zone = types.StringUnknown()
+				m.Zones = types.ListUnknown(types.StringType)
+				m.DefaultZone = types.StringUnknown()
+			},
+			paths: []path.Path{path.Root("zone"), path.Root("zones"), path.Root("default_zone")},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := testProviderModel()
+			tc.modify(model)
+
+			// 環境変数にゾーンがあっても、未確定の値をそれで置き換えてはいけない
+			cfg, diags := resolveConfig(model, testEnvLookup(map[string]string{"SAKURACLOUD_ZONE": "tk1a"}))
+			require.True(t, diags.HasError())
+			assert.Nil(t, cfg)
+			assert.Equal(t, len(tc.paths), diags.ErrorsCount())
+			for i, d := range diags.Errors() {
+				assert.Contains(t, d.Detail(), "is not known at plan time")
+				assert.Equal(t, tc.paths[i], d.(diag.DiagnosticWithPath).Path())
+			}
+		})
+	}
+}
+
+func TestResolveConfig_knownZone(t *testing.T) {
+	t.Parallel()
+
+	model := testProviderModel()
+	model.Zone = types.StringValue("tk1b")
+
+	cfg, diags := resolveConfig(model, testEnvLookup(map[string]string{"SAKURACLOUD_ZONE": "tk1a"}))
+	require.False(t, diags.HasError())
+	assert.Equal(t, "tk1b", cfg.Zone)
+}
+
+func TestResolveConfig_stringPrecedence(t *testing.T) {
+	t.Parallel()
+
+	attributes := []struct {
+		name         string
+		envVar       string
+		defaultValue string
+		set          func(m *sakuraProviderModel, v types.String)
+		get          func(c *common.Config) string
+	}{
+		{
+			name:         "profile",
+			envVar:       "SAKURACLOUD_PROFILE",
+			defaultValue: apiprof.DefaultProfileName,
+			set:          func(m *sakuraProviderModel, v types.String) { m.Profile = v },
+			get:          func(c *common.Config) string { return c.Profile },
+		},
+		{
+			name:   "token",
+			envVar: "SAKURACLOUD_ACCESS_TOKEN",
+			set:    func(m *sakuraProviderModel, v types.String) { m.AccessToken = v },
+			get:    func(c *common.Config) string { return c.AccessToken },
+		},
+		{
+			name:   "secret",
+			envVar: "SAKURACLOUD_ACCESS_TOKEN_SECRET",
+			set:    func(m *sakuraProviderModel, v types.String) { m.AccessTokenSecret = v },
+			get:    func(c *common.Config) string { return c.AccessTokenSecret },
+		},
+		{
+			name:         "zone",
+			envVar:       "SAKURACLOUD_ZONE",
+			defaultValue: common.Zone,
+			set:          func(m *sakuraProviderModel, v types.String) { m.Zone = v },
+			get:          func(c *common.Config) string { return c.Zone },
+		},
+		{
+			name:   "default_zone",
+			envVar: "SAKURACLOUD_DEFAULT_ZONE",
+			set:    func(m *sakuraProviderModel, v types.String) { m.DefaultZone = v },
+			get:    func(c *common.Config) string { return c.DefaultZone },
+		},
+		{
+			name:   "api_root_url",
+			envVar: "SAKURACLOUD_API_ROOT_URL",
+			set:    func(m *sakuraProviderModel, v types.String) { m.APIRootURL = v },
+			get:    func(c *common.Config) string { return c.APIRootURL },
+		},
+	}
+
+	testCases := []struct {
+		name   string
+		config types.String
+		env    *string // nilの場合は環境変数を設定しない
+		want   func(defaultValue string) string
+	}{
+		{
+			name:   "default",
+			config: types.StringNull(),
+			want:   func(d string) string { return d },
+		},
+		{
+			name:   "env",
+			config: types.StringNull(),
+			env:    ptr("from-env"),
+			want:   func(string) string { return "from-env" },
+		},
+		{
+			name:   "config overrides env",
+			config: types.StringValue("from-config"),
+			env:    ptr("from-env"),
+			want:   func(string) string { return "from-config" },
+		},
+		{
+			name:   "empty config falls back to env",
+			config: types.StringValue(""),
+			env:    ptr("from-env"),
+			want:   func(string) string { return "from-env" },
+		},
+		{
+			name:   "empty env falls back to default",
+			config: types.StringNull(),
+			env:    ptr(""),
+			want:   func(d string) string { return d },
+		},
+	}
+
+	for _, attr := range attributes {
+		for _, tc := range testCases {
+			t.Run(attr.name+"/"+tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				model := testProviderModel()
+				attr.set(model, tc.config)
+				envs := map[string]string{}
+				if tc.env != nil {
+					envs[attr.envVar] = *tc.env
+				}
+
+				cfg, diags := resolveConfig(model, testEnvLookup(envs))
+				require.False(t, diags.HasError(), diags)
+				assert.Equal(t, tc.want(attr.defaultValue), attr.get(cfg))
+			})
+		}
+	}
+}
+
+func TestResolveConfig_intPrecedence(t *testing.T) {
+	t.Parallel()
+
+	attributes := []struct {
+		name         string
+		envVar       string
+		defaultValue int
+		set          func(m *sakuraProviderModel, v types.Int64)
+		get          func(c *common.Config) int
+	}{
+		{
+			name:         "retry_max",
+			envVar:       "SAKURACLOUD_RETRY_MAX",
+			defaultValue: common.RetryMax,
+			set:          func(m *sakuraProviderModel, v types.Int64) { m.RetryMax = v },
+			get:          func(c *common.Config) int { return c.RetryMax },
+		},
+		{
+			name:         "api_request_rate_limit",
+			envVar:       "SAKURACLOUD_RATE_LIMIT",
+			defaultValue: common.APIRequestRateLimit,
+			set:          func(m *sakuraProviderModel, v types.Int64) { m.APIRequestRateLimit = v },
+			get:          func(c *common.Config) int { return c.APIRequestRateLimit },
+		},
+		{
+			name:         "max_parallel_zone_requests",
+			envVar:       "SAKURACLOUD_MAX_PARALLEL_ZONE_REQUESTS",
+			defaultValue: common.MaxParallelZoneRequests,
+			set:          func(m *sakuraProviderModel, v types.Int64) { m.MaxParallelZoneRequests = v },
+			get:          func(c *common.Config) int { return c.MaxParallelZoneRequests },
+		},
+		{
+			name:   "max_concurrent_api_requests",
+			envVar: "SAKURACLOUD_MAX_CONCURRENT_API_REQUESTS",
+			set:    func(m *sakuraProviderModel, v types.Int64) { m.MaxConcurrentAPIRequests = v },
+			get:    func(c *common.Config) int { return c.MaxConcurrentAPIRequests },
+		},
+	}
+
+	testCases := []struct {
+		name    string
+		config  types.Int64
+		env     *string
+		want    func(defaultValue int) int
+		wantErr bool
+	}{
+		{
+			name:   "default",
+			config: types.Int64Null(),
+			want:   func(d int) int { return d },
+		},
+		{
+			name:   "env",
+			config: types.Int64Null(),
+			env:    ptr("42"),
+			want:   func(int) int { return 42 },
+		},
+		{
+			name:   "config overrides env",
+			config: types.Int64Value(7),
+			env:    ptr("42"),
+			want:   func(int) int { return 7 },
+		},
+		{
+			name:   "zero in config overrides env",
+			config: types.Int64Value(0),
+			env:    ptr("42"),
+			want:   func(int) int { return 0 },
+		},
+		{
+			name:   "empty env falls back to default",
+			config: types.Int64Null(),
+			env:    ptr(""),
+			want:   func(d int) int { return d },
+		},
+		{
+			name:    "malformed env",
+			config:  types.Int64Null(),
+			env:     ptr("ten"),
+			wantErr: true,
+		},
+		{
+			name:   "malformed env with config",
+			config: types.Int64Value(7),
+			env:    ptr("ten"),
+			// 設定値が優先されるが、環境変数の誤りは利用者が気付けるようエラーにする
+			wantErr: true,
+		},
+	}
+
+	for _, attr := range attributes {
+		for _, tc := range testCases {
+			t.Run(attr.name+"/"+tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				model := testProviderModel()
+				attr.set(model, tc.config)
+				envs := map[string]string{}
+				if tc.env != nil {
+					envs[attr.envVar] = *tc.env
+				}
+
+				cfg, diags := resolveConfig(model, testEnvLookup(envs))
+				if tc.wantErr {
+					require.True(t, diags.HasError())
+					assert.Contains(t, diags.Errors()[0].Summary(), attr.envVar)
+					return
+				}
+				require.False(t, diags.HasError(), diags)
+				assert.Equal(t, tc.want(attr.defaultValue), attr.get(cfg))
+			})
+		}
+	}
+}
+
+func TestResolveConfig_durationPrecedence(t *testing.T) {
+	t.Parallel()
+
+	attributes := []struct {
+		name         string
+		envVar       string
+		defaultValue int
+		set          func(m *sakuraProviderModel, v types.String)
+		get          func(c *common.Config) int
+	}{
+		{
+			name:   "retry_wait_max",
+			envVar: "SAKURACLOUD_RETRY_WAIT_MAX",
+			set:    func(m *sakuraProviderModel, v types.String) { m.RetryWaitMax = v },
+			get:    func(c *common.Config) int { return c.RetryWaitMax },
+		},
+		{
+			name:   "retry_wait_min",
+			envVar: "SAKURACLOUD_RETRY_WAIT_MIN",
+			set:    func(m *sakuraProviderModel, v types.String) { m.RetryWaitMin = v },
+			get:    func(c *common.Config) int { return c.RetryWaitMin },
+		},
+		{
+			name:         "api_request_timeout",
+			envVar:       "SAKURACLOUD_API_REQUEST_TIMEOUT",
+			defaultValue: common.APIRequestTimeout,
+			set:          func(m *sakuraProviderModel, v types.String) { m.APIRequestTimeout = v },
+			get:          func(c *common.Config) int { return c.APIRequestTimeout },
+		},
+	}
+
+	testCases := []struct {
+		name    string
+		config  types.String
+		env     *string
+		want    func(defaultValue int) int
+		wantErr bool
+	}{
+		{
+			name:   "default",
+			config: types.StringNull(),
+			want:   func(d int) int { return d },
+		},
+		{
+			name:   "seconds in env",
+			config: types.StringNull(),
+			env:    ptr("42"),
+			want:   func(int) int { return 42 },
+		},
+		{
+			name:   "duration in env",
+			config: types.StringNull(),
+			env:    ptr("2m"),
+			want:   func(int) int { return 120 },
+		},
+		{
+			name:   "seconds in config overrides env",
+			config: types.StringValue("7"),
+			env:    ptr("42"),
+			want:   func(int) int { return 7 },
+		},
+		{
+			name:   "duration in config overrides env",
+			config: types.StringValue("30s"),
+			env:    ptr("2m"),
+			want:   func(int) int { return 30 },
+		},
+		{
+			name:   "empty env falls back to default",
+			config: types.StringNull(),
+			env:    ptr(""),
+			want:   func(d int) int { return d },
+		},
+		{
+			name:    "negative env",
+			config:  types.StringNull(),
+			env:     ptr("-1s"),
+			wantErr: true,
+		},
+		{
+			name:    "malformed env",
+			config:  types.StringNull(),
+			env:     ptr("ten"),
+			wantErr: true,
+		},
+	}
+
+	for _, attr := range attributes {
+		for _, tc := range testCases {
+			t.Run(attr.name+"/"+tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				model := testProviderModel()
+				attr.set(model, tc.config)
+				envs := map[string]string{}
+				if tc.env != nil {
+					envs[attr.envVar] = *tc.env
+				}
+
+				cfg, diags := resolveConfig(model, testEnvLookup(envs))
+				if tc.wantErr {
+					require.True(t, diags.HasError())
+					assert.Contains(t, diags.Errors()[0].Summary(), attr.envVar)
+					return
+				}
+				require.False(t, diags.HasError(), diags)
+				assert.Equal(t, tc.want(attr.defaultValue), attr.get(cfg))
+			})
+		}
+	}
+}
+
+func TestResolveConfig_zones(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		config types.List
+		env    map[string]string
+		want   []string
+	}{
+		{
+			name:   "unset",
+			config: types.ListNull(types.StringType),
+			want:   nil,
+		},
+		{
+			name:   "env",
+			config: types.ListNull(types.StringType),
+			env:    map[string]string{"SAKURACLOUD_ZONES": "is1a, is1b ,tk1a"},
+			want:   []string{"is1a", "is1b", "tk1a"},
+		},
+		{
+			name:   "empty env",
+			config: types.ListNull(types.StringType),
+			env:    map[string]string{"SAKURACLOUD_ZONES": ""},
+			want:   nil,
+		},
+		{
+			name: "config overrides env",
+			config: types.ListValueMust(types.StringType, []attr.Value{
+				types.StringValue("tk1b"),
+			}),
+			env:  map[string]string{"SAKURACLOUD_ZONES": "is1a,is1b"},
+			want: []string{"tk1b"},
+		},
+		{
+			name:   "empty config falls back to env",
+			config: types.ListValueMust(types.StringType, []attr.Value{}),
+			env:    map[string]string{"SAKURACLOUD_ZONES": "is1a"},
+			want:   []string{"is1a"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := testProviderModel()
+			model.Zones = tc.config
+
+			cfg, diags := resolveConfig(model, testEnvLookup(tc.env))
+			require.False(t, diags.HasError(), diags)
+			assert.Equal(t, tc.want, cfg.Zones)
+		})
+	}
+}
+
+func TestResolveConfig_invalidZonesElement(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		element attr.Value
+		want    string
+	}{
+		{
+			name:    "null",
+			element: types.StringNull(),
+			want:    "zones[2] is null",
+		},
+		{
+			name:    "unknown",
+			element: types.StringUnknown(),
+			want:    "zones[2] is not known at plan time",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := testProviderModel()
+			model.Zones = types.ListValueMust(types.StringType, []attr.Value{
+				types.StringValue("is1a"),
+				types.StringValue("is1b"),
+				tc.element,
+			})
+
+			var diags diag.Diagnostics
+			require.NotPanics(t, func() {
+				_, diags = resolveConfig(model, testEnvLookup(nil))
+			})
+			require.True(t, diags.HasError())
+			require.Len(t, diags.Errors(), 1)
+			assert.Equal(t, tc.want, diags.Errors()[0].Detail())
+			assert.Equal(t, path.Root("zones").AtListIndex(2), diags.Errors()[0].(diag.DiagnosticWithPath).Path())
+		})
+	}
+}
+
+func TestResolveConfig_ignoreSystemTags(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		config types.Bool
+		env    map[string]string
+		want   bool
+	}{
+		{
+			name:   "unset",
+			config: types.BoolNull(),
+			want:   true,
+		},
+		{
+			name:   "config",
+			config: types.BoolValue(false),
+			want:   false,
+		},
+		{
+			name:   "env",
+			config: types.BoolNull(),
+			env:    map[string]string{"SAKURACLOUD_IGNORE_SYSTEM_TAGS": "false"},
+			want:   false,
+		},
+		{
+			name:   "config overrides env",
+			config: types.BoolValue(true),
+			env:    map[string]string{"SAKURACLOUD_IGNORE_SYSTEM_TAGS": "false"},
+			want:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := testProviderModel()
+			model.IgnoreSystemTags = tc.config
+
+			cfg, diags := resolveConfig(model, testEnvLookup(tc.env))
+			require.False(t, diags.HasError(), diags)
+			assert.Equal(t, tc.want, cfg.IgnoreSystemTags)
+		})
+	}
+}
+
+func TestResolveConfig_retryWarnings(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name  string
+		model func(m *sakuraProviderModel)
+		env   map[string]string
+		want  []string
+	}{
+		{
+			name:  "defaults",
+			model: func(*sakuraProviderModel) {},
+		},
+		{
+			name: "wait min greater than wait max",
+			model: func(m *sakuraProviderModel) {
+				m.RetryWaitMin = types.StringValue("10")
+				m.RetryWaitMax = types.StringValue("5")
+			},
+			want: []string{"retry_wait_min (10s) is greater than retry_wait_max (5s)"},
+		},
+		{
+			name: "wait min greater than the default wait max",
+			env:  map[string]string{"SAKURACLOUD_RETRY_WAIT_MIN": "100"},
+			want: []string{
+				"retry_wait_min (1m40s) is greater than retry_wait_max (1m4s)",
+				"retry_max is 10, but at most 4 retries waiting at least 1m4s each fit in api_request_timeout (5m0s)",
+			},
+		},
+		{
+			name: "retries do not fit in the timeout",
+			model: func(m *sakuraProviderModel) {
+				m.RetryMax = types.Int64Value(30)
+				m.APIRequestTimeout = types.StringValue("10s")
+			},
+			want: []string{"retry_max is 30, but at most 10 retries waiting at least 1s each fit in api_request_timeout (10s)"},
+		},
+		{
+			name: "timeout shorter than a single retry wait",
+			model: func(m *sakuraProviderModel) {
+				m.RetryWaitMin = types.StringValue("20s")
+				m.APIRequestTimeout = types.StringValue("10")
+			},
+			want: []string{"api_request_timeout (10s) is shorter than a single retry wait (20s)"},
+		},
+		{
+			name: "retries disabled",
+			model: func(m *sakuraProviderModel) {
+				m.RetryMax = types.Int64Value(0)
+				m.RetryWaitMin = types.StringValue("20")
+				m.APIRequestTimeout = types.StringValue("10")
+			},
+		},
+		{
+			name: "rate limit is zero",
+			model: func(m *sakuraProviderModel) {
+				m.APIRequestRateLimit = types.Int64Value(0)
+			},
+			want: []string{"api_request_rate_limit must be greater than 0, got 0"},
+		},
+		{
+			name: "rate limit is negative",
+			env:  map[string]string{"SAKURACLOUD_RATE_LIMIT": "-1"},
+			want: []string{"api_request_rate_limit must be greater than 0, got -1"},
+		},
+		{
+			name: "multiple warnings",
+			model: func(m *sakuraProviderModel) {
+				m.APIRequestRateLimit = types.Int64Value(0)
+				m.RetryWaitMin = types.StringValue("10")
+				m.RetryWaitMax = types.StringValue("5")
+				m.APIRequestTimeout = types.StringValue("20")
+			},
+			want: []string{
+				"api_request_rate_limit must be greater than 0",
+				"retry_wait_min (10s) is greater than retry_wait_max (5s)",
+				"retry_max is 10, but at most 4 retries waiting at least 5s each fit in api_request_timeout (20s)",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := testProviderModel()
+			if tc.model != nil {
+				tc.model(model)
+			}
+			_, diags := resolveConfig(model, testEnvLookup(tc.env))
+			require.False(t, diags.HasError(), diags)
+
+			warnings := diags.Warnings()
+			require.Len(t, warnings, len(tc.want), warnings)
+			for i, want := range tc.want {
+				assert.Equal(t, "Ineffective retry settings", warnings[i].Summary())
+				assert.Contains(t, warnings[i].Detail(), want)
+			}
+		})
+	}
+}
+
+func TestResolveConfig_validateReferences(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		config types.Bool
+		env    map[string]string
+		want   bool
+	}{
+		{
+			name:   "unset",
+			config: types.BoolNull(),
+			want:   false,
+		},
+		{
+			name:   "config",
+			config: types.BoolValue(true),
+			want:   true,
+		},
+		{
+			name:   "env",
+			config: types.BoolNull(),
+			env:    map[string]string{"SAKURACLOUD_VALIDATE_REFERENCES": "true"},
+			want:   true,
+		},
+		{
+			name:   "config overrides env",
+			config: types.BoolValue(false),
+			env:    map[string]string{"SAKURACLOUD_VALIDATE_REFERENCES": "true"},
+			want:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := testProviderModel()
+			model.ValidateReferences = tc.config
+
+			cfg, diags := resolveConfig(model, testEnvLookup(tc.env))
+			require.False(t, diags.HasError(), diags)
+			assert.Equal(t, tc.want, cfg.ValidateReferences)
+		})
+	}
+}
+
+func TestResolveConfig_resourceDefaults(t *testing.T) {
+	t.Parallel()
+
+	t.Run("unset", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, diags := resolveConfig(testProviderModel(), testEnvLookup(nil))
+		require.False(t, diags.HasError(), diags)
+		assert.Equal(t, common.ResourceDefaults{}, cfg.ResourceDefaults)
+	})
+
+	t.Run("config", func(t *testing.T) {
+		t.Parallel()
+
+		model := testProviderModel()
+		model.ResourceDefaults = &sakuraProviderResourceDefaultsModel{
+			Description: types.StringValue("managed by terraform"),
+			IconID:      types.StringNull(),
+		}
+		cfg, diags := resolveConfig(model, testEnvLookup(nil))
+		require.False(t, diags.HasError(), diags)
+		assert.Equal(t, common.ResourceDefaults{Description: "managed by terraform"}, cfg.ResourceDefaults)
+	})
+
+	t.Run("default_tags", func(t *testing.T) {
+		t.Parallel()
+
+		model := testProviderModel()
+		model.DefaultTags = types.ListValueMust(types.StringType, []attr.Value{
+			types.StringValue("env=prod"),
+			types.StringValue("managed-by=terraform"),
+		})
+		cfg, diags := resolveConfig(model, testEnvLookup(nil))
+		require.False(t, diags.HasError(), diags)
+		assert.Equal(t, common.ResourceDefaults{Tags: []string{"env=prod", "managed-by=terraform"}}, cfg.ResourceDefaults)
+	})
+}
+
+func TestResolveConfig_disableReadCache(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		config  types.Bool
+		env     map[string]string
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:   "unset",
+			config: types.BoolNull(),
+			want:   false,
+		},
+		{
+			name:   "config",
+			config: types.BoolValue(true),
+			want:   true,
+		},
+		{
+			name:   "env",
+			config: types.BoolNull(),
+			env:    map[string]string{"SAKURACLOUD_DISABLE_READ_CACHE": "true"},
+			want:   true,
+		},
+		{
+			name:   "config overrides env",
+			config: types.BoolValue(false),
+			env:    map[string]string{"SAKURACLOUD_DISABLE_READ_CACHE": "1"},
+			want:   false,
+		},
+		{
+			name:    "malformed env",
+			config:  types.BoolNull(),
+			env:     map[string]string{"SAKURACLOUD_DISABLE_READ_CACHE": "yes"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := testProviderModel()
+			model.DisableReadCache = tc.config
+
+			cfg, diags := resolveConfig(model, testEnvLookup(tc.env))
+			if tc.wantErr {
+				require.True(t, diags.HasError())
+				assert.Contains(t, diags.Errors()[0].Summary(), "SAKURACLOUD_DISABLE_READ_CACHE")
+				return
+			}
+			require.False(t, diags.HasError(), diags)
+			assert.Equal(t, tc.want, cfg.DisableReadCache)
+		})
+	}
+}
+
+func TestResolveConfig_traceMode(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		config  types.String
+		env     map[string]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "unset",
+			config: types.StringNull(),
+			want:   "",
+		},
+		{
+			name:   "config",
+			config: types.StringValue("api"),
+			want:   "api",
+		},
+		{
+			name:   "env",
+			config: types.StringNull(),
+			env:    map[string]string{"SAKURACLOUD_TRACE": "http"},
+			want:   "http",
+		},
+		{
+			name:   "env is case insensitive",
+			config: types.StringNull(),
+			env:    map[string]string{"SAKURACLOUD_TRACE": "ALL"},
+			want:   "ALL",
+		},
+		{
+			name:   "config overrides env",
+			config: types.StringValue("api"),
+			env:    map[string]string{"SAKURACLOUD_TRACE": "http"},
+			want:   "api",
+		},
+		{
+			name:    "invalid env",
+			config:  types.StringNull(),
+			env:     map[string]string{"SAKURACLOUD_TRACE": "debug"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := testProviderModel()
+			model.TraceMode = tc.config
+
+			cfg, diags := resolveConfig(model, testEnvLookup(tc.env))
+			if tc.wantErr {
+				require.True(t, diags.HasError())
+				assert.Contains(t, diags.Errors()[0].Detail(), `got: "debug"`)
+				return
+			}
+			require.False(t, diags.HasError(), diags)
+			assert.Equal(t, tc.want, cfg.TraceMode)
+		})
+	}
+}
+
+func TestProviderSchema_traceValidator(t *testing.T) {
+	t.Parallel()
+
+	var resp provider.SchemaResponse
+	New("test")().Schema(context.Background(), provider.SchemaRequest{}, &resp)
+	attr, ok := resp.Schema.Attributes["trace"].(schema.StringAttribute)
+	require.True(t, ok)
+
+	for _, tc := range []struct {
+		value   string
+		wantErr bool
+	}{
+		{value: "all"},
+		{value: "api"},
+		{value: "http"},
+		{value: "HTTP"},
+		{value: "debug", wantErr: true},
+		{value: "1", wantErr: true},
+	} {
+		t.Run(tc.value, func(t *testing.T) {
+			t.Parallel()
+
+			var diags diag.Diagnostics
+			for _, v := range attr.StringValidators() {
+				vResp := &validator.StringResponse{}
+				v.ValidateString(context.Background(), validator.StringRequest{
+					Path:        path.Root("trace"),
+					ConfigValue: types.StringValue(tc.value),
+				}, vResp)
+				diags.Append(vResp.Diagnostics...)
+			}
+			assert.Equal(t, tc.wantErr, diags.HasError(), diags)
+		})
+	}
+}
+
+func TestProvider_ValidateProviderConfig_credentials(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name   string
+		modify func(m *sakuraProviderModel)
+		paths  []string
+	}{
+		{
+			name: "token and secret",
+			modify: func(m *sakuraProviderModel) {
+				m.AccessToken = types.StringValue("token")
+				m.AccessTokenSecret = types.StringValue("secret")
+			},
+		},
+		{
+			name:   "profile",
+			modify: func(m *sakuraProviderModel) { m.Profile = types.StringValue("foo") },
+		},
+		{
+			name: "token with profile",
+			modify: func(m *sakuraProviderModel) {
+				m.Profile = types.StringValue("foo")
+				m.AccessToken = types.StringValue("token")
+				m.AccessTokenSecret = types.StringValue("secret")
+			},
+			paths: []string{"profile"},
+		},
+		{
+			// secretは環境変数やプロファイルから補完できる
+			name:   "token without secret",
+			modify: func(m *sakuraProviderModel) { m.AccessToken = types.StringValue("token") },
+		},
+		{
+			name:   "secret without token",
+			modify: func(m *sakuraProviderModel) { m.AccessTokenSecret = types.StringValue("secret") },
+		},
+		{
+			name: "secret with profile",
+			modify: func(m *sakuraProviderModel) {
+				m.Profile = types.StringValue("foo")
+				m.AccessTokenSecret = types.StringValue("secret")
+			},
+			paths: []string{"profile"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			p := New("test")()
+			model := testProviderModel()
+			tc.modify(model)
+			req := newConfigureRequest(t, p, model)
+			config, err := tfprotov6.NewDynamicValue(req.Config.Schema.Type().TerraformType(ctx), req.Config.Raw)
+			require.NoError(t, err)
+
+			server, err := providerserver.NewProtocol6WithError(p)()
+			require.NoError(t, err)
+			resp, err := server.ValidateProviderConfig(ctx, &tfprotov6.ValidateProviderConfigRequest{Config: &config})
+			require.NoError(t, err)
+
+			// ConflictsWithは競合する属性ごとにエラーを返すため、属性名の重複を除いて比較する
+			got := map[string]bool{}
+			for _, d := range resp.Diagnostics {
+				require.Equal(t, tfprotov6.DiagnosticSeverityError, d.Severity, d.Summary)
+				require.NotNil(t, d.Attribute)
+				got[string(d.Attribute.Steps()[0].(tftypes.AttributeName))] = true
+			}
+			assert.ElementsMatch(t, tc.paths, slices.Collect(maps.Keys(got)))
+		})
+	}
+}
+
+func TestProvider_ValidateProviderConfig_retryWaits(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		min     types.String
+		max     types.String
+		wantErr string
+	}{
+		{name: "min less than max", min: types.StringValue("1"), max: types.StringValue("1m")},
+		{name: "equal", min: types.StringValue("60"), max: types.StringValue("1m")},
+		{name: "min only", min: types.StringValue("2m"), max: types.StringNull()},
+		{name: "unknown", min: types.StringValue("2m"), max: types.StringUnknown()},
+		{name: "min greater than max", min: types.StringValue("2m"), max: types.StringValue("30"), wantErr: "retry_wait_min (2m) must not be greater than retry_wait_max (30s)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			p := New("test")()
+			model := testProviderModel()
+			model.AccessToken = types.StringValue("token")
+			model.AccessTokenSecret = types.StringValue("secret")
+			model.RetryWaitMin = tc.min
+			model.RetryWaitMax = tc.max
+			req := newConfigureRequest(t, p, model)
+
+			resp := &provider.ValidateConfigResponse{}
+			p.(provider.ProviderWithValidateConfig).ValidateConfig(ctx, provider.ValidateConfigRequest{Config: req.Config}, resp)
+			if tc.wantErr == "" {
+				assert.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+				return
+			}
+			require.Len(t, resp.Diagnostics, 1)
+			assert.Equal(t, tc.wantErr, resp.Diagnostics[0].Detail())
+		})
+	}
+}
+
+func TestProvider_ValidateDataResourceConfig_resourceID(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	require.NoError(t, err)
+	schemaResp, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+
+	for _, typeName := range []string{"sakura_kms", "sakura_secret_manager"} {
+		validate := func(t *testing.T, values map[string]string) []*tfprotov6.Diagnostic {
+			t.Helper()
+
+			typ := schemaResp.DataSourceSchemas[typeName].ValueType().(tftypes.Object)
+			attrs := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+			for name, attrType := range typ.AttributeTypes {
+				attrs[name] = tftypes.NewValue(attrType, nil)
+			}
+			for name, v := range values {
+				attrs[name] = tftypes.NewValue(tftypes.String, v)
+			}
+			config, err := tfprotov6.NewDynamicValue(typ, tftypes.NewValue(typ, attrs))
+			require.NoError(t, err)
+
+			resp, err := server.ValidateDataResourceConfig(ctx, &tfprotov6.ValidateDataResourceConfigRequest{TypeName: typeName, Config: &config})
+			require.NoError(t, err)
+			return resp.Diagnostics
+		}
+
+		t.Run(typeName, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Empty(t, validate(t, map[string]string{"id": "110000000001"}))
+
+			diags := validate(t, map[string]string{"resource_id": "110000000001"})
+			require.Len(t, diags, 1)
+			assert.Equal(t, tfprotov6.DiagnosticSeverityWarning, diags[0].Severity)
+			assert.Contains(t, diags[0].Detail, "Use id instead")
+
+			diags = validate(t, map[string]string{"id": "110000000001", "resource_id": "110000000001"})
+			assert.True(t, slices.ContainsFunc(diags, func(d *tfprotov6.Diagnostic) bool {
+				return d.Severity == tfprotov6.DiagnosticSeverityError
+			}), diags)
+		})
+	}
+}
+
+func TestProvider_ValidateDataResourceConfig_filter(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	require.NoError(t, err)
+	schemaResp, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+
+	for _, typeName := range []string{"sakura_kms", "sakura_secret_manager"} {
+		validate := func(t *testing.T, filter map[string]tftypes.Value) []*tfprotov6.Diagnostic {
+			t.Helper()
+
+			typ := schemaResp.DataSourceSchemas[typeName].ValueType().(tftypes.Object)
+			attrs := make(map[string]tftypes.Value, len(typ.AttributeTypes))
+			for name, attrType := range typ.AttributeTypes {
+				attrs[name] = tftypes.NewValue(attrType, nil)
+			}
+			filterType := typ.AttributeTypes["filter"].(tftypes.Object)
+			filterAttrs := make(map[string]tftypes.Value, len(filterType.AttributeTypes))
+			for name, attrType := range filterType.AttributeTypes {
+				filterAttrs[name] = tftypes.NewValue(attrType, nil)
+			}
+			for name, v := range filter {
+				filterAttrs[name] = v
+			}
+			attrs["filter"] = tftypes.NewValue(filterType, filterAttrs)
+			config, err := tfprotov6.NewDynamicValue(typ, tftypes.NewValue(typ, attrs))
+			require.NoError(t, err)
+
+			resp, err := server.ValidateDataResourceConfig(ctx, &tfprotov6.ValidateDataResourceConfigRequest{TypeName: typeName, Config: &config})
+			require.NoError(t, err)
+			return resp.Diagnostics
+		}
+		stringValues := func(typ tftypes.Type, values ...string) tftypes.Value {
+			elements := make([]tftypes.Value, 0, len(values))
+			for _, v := range values {
+				elements = append(elements, tftypes.NewValue(tftypes.String, v))
+			}
+			return tftypes.NewValue(typ, elements)
+		}
+
+		t.Run(typeName, func(t *testing.T) {
+			t.Parallel()
+
+			// 条件を指定しないfilterブロックはエラーとする
+			diags := validate(t, nil)
+			require.NotEmpty(t, diags)
+			assert.Equal(t, tfprotov6.DiagnosticSeverityError, diags[0].Severity)
+
+			// 複数の条件はAND条件として組み合わせられる
+			assert.Empty(t, validate(t, map[string]tftypes.Value{
+				"id":    tftypes.NewValue(tftypes.String, "110000000001"),
+				"names": stringValues(tftypes.List{ElementType: tftypes.String}, "foo", "bar"),
+				"tags":  stringValues(tftypes.Set{ElementType: tftypes.String}, "tag1"),
+			}))
+		})
+	}
+}
+
+func TestResolveConfig_credentials(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		token, secret string
+		envs          map[string]string
+		wantToken     string
+		wantSecret    string
+		wantProfile   string
+	}{
+		{
+			name:        "profile from env",
+			envs:        map[string]string{"SAKURACLOUD_PROFILE": "from-env"},
+			wantProfile: "from-env",
+		},
+		{
+			name:        "token and secret from config",
+			token:       "token",
+			secret:      "secret",
+			envs:        map[string]string{"SAKURACLOUD_PROFILE": "from-env"},
+			wantToken:   "token",
+			wantSecret:  "secret",
+			wantProfile: "from-env",
+		},
+		{
+			name:        "token from config and secret from env",
+			token:       "token",
+			envs:        map[string]string{"SAKURACLOUD_ACCESS_TOKEN": "env-token", "SAKURACLOUD_ACCESS_TOKEN_SECRET": "env-secret"},
+			wantToken:   "token",
+			wantSecret:  "env-secret",
+			wantProfile: apiprof.DefaultProfileName,
+		},
+		{
+			// secretはプロファイルから補完する
+			name:        "token from config and secret from profile",
+			token:       "token",
+			envs:        map[string]string{"SAKURACLOUD_PROFILE": "from-env"},
+			wantToken:   "token",
+			wantProfile: "from-env",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			model := testProviderModel()
+			if tc.token != "" {
+				model.AccessToken = types.StringValue(tc.token)
+			}
+			if tc.secret != "" {
+				model.AccessTokenSecret = types.StringValue(tc.secret)
+			}
+			cfg, diags := resolveConfig(model, testEnvLookup(tc.envs))
+			require.False(t, diags.HasError(), diags)
+			assert.Equal(t, tc.wantToken, cfg.AccessToken)
+			assert.Equal(t, tc.wantSecret, cfg.AccessTokenSecret)
+			assert.Equal(t, tc.wantProfile, cfg.Profile)
+		})
+	}
+}
+
+func TestResolveConfig_credentialsWithProfile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(apiprof.DirectoryNameEnv, dir)
+	profilePath := filepath.Join(dir, ".usacloud", apiprof.DefaultProfileName, "config.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(profilePath), 0o700))
+	require.NoError(t, os.WriteFile(profilePath, []byte(`{"AccessToken": "profile-token", "AccessTokenSecret": "profile-secret", "Zone": "tk1b"}`), 0o600))
+
+	cfg, diags := resolveConfig(testProviderModel(), testEnvLookup(map[string]string{
+		"SAKURACLOUD_ACCESS_TOKEN":        "env-token",
+		"SAKURACLOUD_ACCESS_TOKEN_SECRET": "env-secret",
+	}))
+	require.False(t, diags.HasError(), diags)
+	_, err := cfg.NewClient()
+	require.NoError(t, err)
+
+	// 環境変数の認証情報を使い、それ以外の設定はプロファイルから補完する
+	assert.Equal(t, "env-token", cfg.AccessToken)
+	assert.Equal(t, "env-secret", cfg.AccessTokenSecret)
+	assert.Equal(t, "tk1b", cfg.Zone)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func newConfigureRequest(t *testing.T, p provider.Provider, model *sakuraProviderModel) provider.ConfigureRequest {
+	t.Helper()
+
+	ctx := context.Background()
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+	require.False(t, plan.Set(ctx, model).HasError())
+	return provider.ConfigureRequest{
+		TerraformVersion: "1.11.0",
+		Config:           tfsdk.Config{Schema: schemaResp.Schema, Raw: plan.Raw},
+	}
+}
+
+func TestProvider_Configure_concurrent(t *testing.T) {
+	p := New("test", WithEnvLookup(testEnvLookup(map[string]string{
+		"SAKURACLOUD_ACCESS_TOKEN":        "token",
+		"SAKURACLOUD_ACCESS_TOKEN_SECRET": "secret",
+	})))()
+	req := newConfigureRequest(t, p, testProviderModel())
+
+	// plan/applyやエイリアスされたプロバイダーからの並行したConfigureでも、同じクライアントが共有される
+	const n = 16
+	clients := make([]any, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var resp provider.ConfigureResponse
+			p.Configure(context.Background(), req, &resp)
+			assert.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+			clients[i] = resp.ResourceData
+		}()
+	}
+	wg.Wait()
+
+	require.NotNil(t, clients[0])
+	for _, c := range clients {
+		assert.Same(t, clients[0], c)
+	}
+
+	// 設定が変わった場合は新しいクライアントを生成し、既に渡したクライアントは変更しない
+	model := testProviderModel()
+	model.Zone = types.StringValue("tk1b")
+	var resp provider.ConfigureResponse
+	p.Configure(context.Background(), newConfigureRequest(t, p, model), &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	assert.NotSame(t, clients[0], resp.ResourceData)
+}
+
+func TestProvider_registrations(t *testing.T) {
+	require.NoError(t, validateRegistrations(context.Background(), &sakuraProvider{}))
+	assert.NotPanics(t, func() { New("test")() })
+}
+
+func TestValidateTypeNames(t *testing.T) {
+	assert.NoError(t, validateTypeNames("resource", "sakura", []string{"sakura_kms", "sakura_secret_manager"}))
+
+	err := validateTypeNames("resource", "sakura", []string{"sakura_kms", "sakuracloud_disk", "sakura_kms", ""})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `resource type name "sakura_kms" is registered more than once`)
+	assert.Contains(t, err.Error(), `resource type name "sakuracloud_disk" (index 1) must be prefixed with "sakura_"`)
+	assert.Contains(t, err.Error(), `resource type name "" (index 3) must be prefixed with "sakura_"`)
+}
+
+func TestResolveConfig_httpClient(t *testing.T) {
+	t.Parallel()
+
+	model := testProviderModel()
+	cfg, diags := resolveConfig(model, testEnvLookup(nil))
+	require.False(t, diags.HasError())
+	assert.Equal(t, common.HTTPClientConfig{}, cfg.HTTPClient)
+
+	model.HTTPClient = &sakuraProviderHTTPClientModel{
+		MaxIdleConnsPerHost: types.Int64Value(32),
+		MaxConnsPerHost:     types.Int64Null(),
+		IdleConnTimeout:     types.Int64Value(30),
+	}
+	cfg, diags = resolveConfig(model, testEnvLookup(nil))
+	require.False(t, diags.HasError())
+	assert.Equal(t, common.HTTPClientConfig{MaxIdleConnsPerHost: 32, IdleConnTimeout: 30 * time.Second}, cfg.HTTPClient)
+}
@@ -0,0 +1,211 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sakura
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update golden files under testdata")
+
+const schemaGoldenFile = "schema.golden.json"
+
+// スナップショットに含めるスキーマの情報。説明文の変更で差分が出ないよう、互換性に関わる項目のみを記録する
+type schemaSnapshot struct {
+	Provider    *blockSnapshot               `json:"provider"`
+	Resources   map[string]*blockSnapshot    `json:"resources"`
+	DataSources map[string]*blockSnapshot    `json:"data_sources"`
+	Functions   map[string]*functionSnapshot `json:"functions"`
+}
+
+type blockSnapshot struct {
+	Version    int64                           `json:"version,omitempty"`
+	Attributes map[string]*attributeSnapshot   `json:"attributes,omitempty"`
+	Blocks     map[string]*nestedBlockSnapshot `json:"blocks,omitempty"`
+}
+
+type nestedBlockSnapshot struct {
+	Nesting  string `json:"nesting"`
+	MinItems int64  `json:"min_items,omitempty"`
+	MaxItems int64  `json:"max_items,omitempty"`
+	*blockSnapshot
+}
+
+type attributeSnapshot struct {
+	Type       json.RawMessage               `json:"type,omitempty"`
+	Nesting    string                        `json:"nesting,omitempty"`
+	Attributes map[string]*attributeSnapshot `json:"attributes,omitempty"`
+	Required   bool                          `json:"required,omitempty"`
+	Optional   bool                          `json:"optional,omitempty"`
+	Computed   bool                          `json:"computed,omitempty"`
+	Sensitive  bool                          `json:"sensitive,omitempty"`
+	WriteOnly  bool                          `json:"write_only,omitempty"`
+	Deprecated bool                          `json:"deprecated,omitempty"`
+}
+
+type functionSnapshot struct {
+	Parameters        []*parameterSnapshot `json:"parameters,omitempty"`
+	VariadicParameter *parameterSnapshot   `json:"variadic_parameter,omitempty"`
+	Return            json.RawMessage      `json:"return"`
+}
+
+type parameterSnapshot struct {
+	Name           string          `json:"name"`
+	Type           json.RawMessage `json:"type"`
+	AllowNullValue bool            `json:"allow_null_value,omitempty"`
+}
+
+func marshalType(t *testing.T, typ tftypes.Type) json.RawMessage {
+	t.Helper()
+
+	if typ == nil {
+		return nil
+	}
+	data, err := typ.MarshalJSON()
+	require.NoError(t, err)
+	return data
+}
+
+func snapshotBlock(t *testing.T, version int64, block *tfprotov6.SchemaBlock) *blockSnapshot {
+	t.Helper()
+
+	s := &blockSnapshot{Version: version}
+	if block == nil {
+		return s
+	}
+	if len(block.Attributes) > 0 {
+		s.Attributes = make(map[string]*attributeSnapshot)
+		for _, attr := range block.Attributes {
+			s.Attributes[attr.Name] = snapshotAttribute(t, attr)
+		}
+	}
+	if len(block.BlockTypes) > 0 {
+		s.Blocks = make(map[string]*nestedBlockSnapshot)
+		for _, nested := range block.BlockTypes {
+			s.Blocks[nested.TypeName] = &nestedBlockSnapshot{
+				Nesting:       nested.Nesting.String(),
+				MinItems:      nested.MinItems,
+				MaxItems:      nested.MaxItems,
+				blockSnapshot: snapshotBlock(t, 0, nested.Block),
+			}
+		}
+	}
+	return s
+}
+
+func snapshotAttribute(t *testing.T, attr *tfprotov6.SchemaAttribute) *attributeSnapshot {
+	t.Helper()
+
+	s := &attributeSnapshot{
+		Type:       marshalType(t, attr.Type),
+		Required:   attr.Required,
+		Optional:   attr.Optional,
+		Computed:   attr.Computed,
+		Sensitive:  attr.Sensitive,
+		WriteOnly:  attr.WriteOnly,
+		Deprecated: attr.Deprecated,
+	}
+	if attr.NestedType != nil {
+		s.Nesting = attr.NestedType.Nesting.String()
+		s.Attributes = make(map[string]*attributeSnapshot)
+		for _, nested := range attr.NestedType.Attributes {
+			s.Attributes[nested.Name] = snapshotAttribute(t, nested)
+		}
+	}
+	return s
+}
+
+func snapshotParameter(t *testing.T, param *tfprotov6.FunctionParameter) *parameterSnapshot {
+	t.Helper()
+
+	if param == nil {
+		return nil
+	}
+	return &parameterSnapshot{
+		Name:           param.Name,
+		Type:           marshalType(t, param.Type),
+		AllowNullValue: param.AllowNullValue,
+	}
+}
+
+func renderSchemaSnapshot(t *testing.T) []byte {
+	t.Helper()
+
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	require.NoError(t, err)
+
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+	for _, d := range resp.Diagnostics {
+		require.NotEqual(t, tfprotov6.DiagnosticSeverityError, d.Severity, "%s: %s", d.Summary, d.Detail)
+	}
+
+	snapshot := &schemaSnapshot{
+		Provider:    snapshotBlock(t, resp.Provider.Version, resp.Provider.Block),
+		Resources:   make(map[string]*blockSnapshot),
+		DataSources: make(map[string]*blockSnapshot),
+		Functions:   make(map[string]*functionSnapshot),
+	}
+	for name, s := range resp.ResourceSchemas {
+		snapshot.Resources[name] = snapshotBlock(t, s.Version, s.Block)
+	}
+	for name, s := range resp.DataSourceSchemas {
+		snapshot.DataSources[name] = snapshotBlock(t, s.Version, s.Block)
+	}
+	for name, f := range resp.Functions {
+		fs := &functionSnapshot{
+			VariadicParameter: snapshotParameter(t, f.VariadicParameter),
+		}
+		for _, p := range f.Parameters {
+			fs.Parameters = append(fs.Parameters, snapshotParameter(t, p))
+		}
+		if f.Return != nil {
+			fs.Return = marshalType(t, f.Return.Type)
+		}
+		snapshot.Functions[name] = fs
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	require.NoError(t, err)
+	return append(data, '\n')
+}
+
+// TestProviderSchemaSnapshot はプロバイダー/リソース/データソースのスキーマをgoldenファイルと比較する。
+// スキーマを意図して変更した場合は`go test ./internal/provider -run TestProviderSchemaSnapshot -update`でgoldenファイルを更新し、差分をレビューすること
+func TestProviderSchemaSnapshot(t *testing.T) {
+	got := renderSchemaSnapshot(t)
+	golden := filepath.Join("testdata", schemaGoldenFile)
+
+	if *updateGolden {
+		require.NoError(t, os.MkdirAll(filepath.Dir(golden), 0o755))
+		require.NoError(t, os.WriteFile(golden, got, 0o600))
+		return
+	}
+
+	want, err := os.ReadFile(golden) //nolint:gosec
+	require.NoError(t, err, "golden file is missing. run the test with -update to create it")
+	assert.JSONEq(t, string(want), string(got), "provider schema has changed. if it is intended, run the test with -update and review the diff of %s", golden)
+}
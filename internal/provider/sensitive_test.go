@@ -0,0 +1,88 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sakura
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sensitiveAttributePattern は機密情報を扱う可能性が高い属性名のパターン
+var sensitiveAttributePattern = regexp.MustCompile(`(^|_)(password|secret|token|private_key|value)s?(_wo)?$`)
+
+// sensitiveAttributeExceptions はパターンに一致するが機密情報を含まない属性。追加する場合は理由を記載すること
+var sensitiveAttributeExceptions = map[string]string{
+	"data.sakura_container_registry.user.password":       "APIはパスワードを返さないため、データソースでは常に空となる",
+	"data.sakura_kms.filter.condition.values":            "絞り込みに利用する検索条件の値",
+	"data.sakura_secret_manager.filter.condition.values": "絞り込みに利用する検索条件の値",
+	"sakura_dns_record.value":                            "公開されるDNSレコードの値",
+}
+
+// collectUnprotectedAttributes はblock配下でパターンに一致し、SensitiveでもWriteOnlyでもない属性のパスを返す
+func collectUnprotectedAttributes(prefix string, block *tfprotov6.SchemaBlock) []string {
+	if block == nil {
+		return nil
+	}
+	var found []string
+	var walk func(prefix string, attrs []*tfprotov6.SchemaAttribute)
+	walk = func(prefix string, attrs []*tfprotov6.SchemaAttribute) {
+		for _, attr := range attrs {
+			name := prefix + "." + attr.Name
+			if sensitiveAttributePattern.MatchString(attr.Name) && !attr.Sensitive && !attr.WriteOnly {
+				found = append(found, name)
+			}
+			if attr.NestedType != nil {
+				walk(name, attr.NestedType.Attributes)
+			}
+		}
+	}
+	walk(prefix, block.Attributes)
+	for _, nested := range block.BlockTypes {
+		found = append(found, collectUnprotectedAttributes(prefix+"."+nested.TypeName, nested.Block)...)
+	}
+	return found
+}
+
+// TestSchema_sensitiveAttributes はパスワードやトークンなどの属性にSensitiveまたはWriteOnlyが指定されていることを検証する
+func TestSchema_sensitiveAttributes(t *testing.T) {
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	require.NoError(t, err)
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+
+	found := collectUnprotectedAttributes("provider", resp.Provider.Block)
+	for name, s := range resp.ResourceSchemas {
+		found = append(found, collectUnprotectedAttributes(name, s.Block)...)
+	}
+	for name, s := range resp.DataSourceSchemas {
+		found = append(found, collectUnprotectedAttributes("data."+name, s.Block)...)
+	}
+
+	var offenders []string
+	for _, name := range found {
+		if _, ok := sensitiveAttributeExceptions[name]; !ok {
+			offenders = append(offenders, name)
+		}
+	}
+	sort.Strings(offenders)
+	assert.Empty(t, offenders, "mark these attributes Sensitive or WriteOnly, or add them to sensitiveAttributeExceptions with a reason")
+}
@@ -96,6 +96,8 @@ func (d *archiveDataSource) Read(ctx context.Context, req datasource.ReadRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx = common.WithAPIErrorCapture(ctx)
 	zone := common.GetZone(data.Zone, d.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -107,14 +109,14 @@ func (d *archiveDataSource) Read(ctx context.Context, req datasource.ReadRequest
 		strOSType := data.OSType.ValueString()
 		res, err := query.FindArchiveByOSType(ctx, searcher, zone, ostype.StrToOSType(strOSType))
 		if err != nil {
-			resp.Diagnostics.AddError("Archive Search Error", err.Error())
+			common.AddAPIError(ctx, &resp.Diagnostics, "Archive Search Error", err)
 			return
 		}
 		archive = res
 	} else {
 		res, err := searcher.Find(ctx, zone, common.CreateFindCondition(data.ID, data.Name, data.Tags))
 		if err != nil {
-			resp.Diagnostics.AddError("Read Error", fmt.Sprintf("could not find SakuraCloud Archive: %s", err))
+			common.AddAPIError(ctx, &resp.Diagnostics, "Read Error", fmt.Errorf("could not find SakuraCloud Archive: %w", err))
 			return
 		}
 		if res == nil || len(res.Archives) == 0 {
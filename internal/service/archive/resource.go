@@ -53,6 +53,7 @@ var (
 	_ resource.Resource                = &archiveResource{}
 	_ resource.ResourceWithConfigure   = &archiveResource{}
 	_ resource.ResourceWithImportState = &archiveResource{}
+	_ resource.ResourceWithModifyPlan  = &archiveResource{}
 )
 
 func NewArchiveResource() resource.Resource {
@@ -180,7 +181,7 @@ func (r *archiveResource) Schema(ctx context.Context, req resource.SchemaRequest
 					stringplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
 				Create: true, Update: true, Delete: true,
 			}),
 		},
@@ -191,6 +192,11 @@ func (r *archiveResource) ImportState(ctx context.Context, req resource.ImportSt
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *archiveResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanZone(ctx, r.client, req, resp)
+	common.PlanResourceDefaults(ctx, r.client, req, resp)
+}
+
 func (r *archiveResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan archiveResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -200,6 +206,7 @@ func (r *archiveResource) Create(ctx context.Context, req resource.CreateRequest
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, 24*time.Hour)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(plan.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -217,7 +224,7 @@ func (r *archiveResource) Create(ctx context.Context, req resource.CreateRequest
 
 	archive, err := builder.Build(ctx, zone)
 	if err != nil {
-		resp.Diagnostics.AddError("Create Archive Error", fmt.Sprintf("creating SakuraCloud Archive is failed: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Archive Error", fmt.Errorf("creating SakuraCloud Archive is failed: %w", err))
 		return
 	}
 
@@ -232,6 +239,8 @@ func (r *archiveResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	zone := common.GetZone(state.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -255,6 +264,7 @@ func (r *archiveResource) Update(ctx context.Context, req resource.UpdateRequest
 
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout24hour)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(plan.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -263,7 +273,7 @@ func (r *archiveResource) Update(ctx context.Context, req resource.UpdateRequest
 
 	archiveOp := iaas.NewArchiveOp(r.client)
 	if _, err := archiveOp.Update(ctx, zone, common.ExpandSakuraCloudID(plan.ID), expandArchiveUpdateRequest(&plan)); err != nil {
-		resp.Diagnostics.AddError("Update Error", fmt.Sprintf("updating SakuraCloud Archive[%s] is failed: %s", plan.ID.ValueString(), err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", fmt.Errorf("updating SakuraCloud Archive[%s] is failed: %w", plan.ID.ValueString(), err))
 		return
 	}
 
@@ -285,6 +295,7 @@ func (r *archiveResource) Delete(ctx context.Context, req resource.DeleteRequest
 
 	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout20min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(state.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -298,7 +309,7 @@ func (r *archiveResource) Delete(ctx context.Context, req resource.DeleteRequest
 
 	archiveOp := iaas.NewArchiveOp(r.client)
 	if err := archiveOp.Delete(ctx, zone, archive.ID); err != nil {
-		resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("deleting SakuraCloud Archive[%s] is failed: %s", state.ID.ValueString(), err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Delete Error", fmt.Errorf("deleting SakuraCloud Archive[%s] is failed: %w", state.ID.ValueString(), err))
 		return
 	}
 }
@@ -317,11 +328,10 @@ func getArchive(ctx context.Context, client *common.APIClient, id iaastypes.ID,
 	archiveOp := iaas.NewArchiveOp(client)
 	archive, err := archiveOp.Read(ctx, zone, id)
 	if err != nil {
-		if iaas.IsNotFoundError(err) {
-			state.RemoveResource(ctx)
+		if common.HandleNotFoundOnRead(ctx, err, state, "Archive", id.String()) {
 			return nil
 		}
-		diags.AddError("API Read Error", fmt.Sprintf("could not read SakuraCloud Archive[%s]: %s", id, err))
+		common.AddAPIError(ctx, diags, "API Read Error", fmt.Errorf("could not read SakuraCloud Archive[%s]: %w", id, err))
 		return nil
 	}
 
@@ -72,6 +72,8 @@ func (d *bridgeDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	zone := common.GetZone(data.Zone, d.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -80,7 +82,7 @@ func (d *bridgeDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	bridgeOp := iaas.NewBridgeOp(d.client)
 	res, err := bridgeOp.Find(ctx, zone, common.CreateFindCondition(data.ID, data.Name, types.SetNull(types.StringType)))
 	if err != nil {
-		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("could not find SakuraCloud Bridge : %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Read Error", fmt.Errorf("could not find SakuraCloud Bridge : %w", err))
 		return
 	}
 	if res == nil || len(res.Bridges) == 0 {
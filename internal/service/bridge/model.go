@@ -17,18 +17,19 @@ package bridge
 import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
 )
 
 type bridgeBaseModel struct {
-	ID          types.String `tfsdk:"id"`
-	Name        types.String `tfsdk:"name"`
-	Description types.String `tfsdk:"description"`
-	Zone        types.String `tfsdk:"zone"`
+	ID          common.SakuraID `tfsdk:"id"`
+	Name        types.String    `tfsdk:"name"`
+	Description types.String    `tfsdk:"description"`
+	Zone        types.String    `tfsdk:"zone"`
 }
 
 func (model *bridgeBaseModel) updateState(bridge *iaas.Bridge, zone string) {
-	model.ID = types.StringValue(bridge.ID.String())
-	model.Name = types.StringValue(bridge.Name)
+	model.ID = common.NewSakuraIDValue(bridge.ID.String())
+	model.Name = common.NameFromAPI(model.Name, bridge.Name)
 	model.Description = types.StringValue(bridge.Description)
 	model.Zone = types.StringValue(zone)
 }
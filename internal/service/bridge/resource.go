@@ -38,6 +38,7 @@ var (
 	_ resource.Resource                = &bridgeResource{}
 	_ resource.ResourceWithConfigure   = &bridgeResource{}
 	_ resource.ResourceWithImportState = &bridgeResource{}
+	_ resource.ResourceWithModifyPlan  = &bridgeResource{}
 )
 
 func NewBridgeResource() resource.Resource {
@@ -68,7 +69,7 @@ func (r *bridgeResource) Schema(ctx context.Context, _ resource.SchemaRequest, r
 			"name":        common.SchemaResourceName("Bridge"),
 			"description": common.SchemaResourceDescription("Bridge"),
 			"zone":        common.SchemaResourceZone("Bridge"),
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
 				Create: true, Update: true, Delete: true,
 			}),
 		},
@@ -79,6 +80,11 @@ func (r *bridgeResource) ImportState(ctx context.Context, req resource.ImportSta
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *bridgeResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanZone(ctx, r.client, req, resp)
+	common.PlanResourceDefaults(ctx, r.client, req, resp)
+}
+
 func (r *bridgeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan bridgeResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -88,6 +94,7 @@ func (r *bridgeResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout20min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(plan.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -100,7 +107,7 @@ func (r *bridgeResource) Create(ctx context.Context, req resource.CreateRequest,
 		Description: plan.Description.ValueString(),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("Create Error", fmt.Sprintf("Could not create Bridge: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", fmt.Errorf("Could not create Bridge: %w", err))
 		return
 	}
 
@@ -115,6 +122,8 @@ func (r *bridgeResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	zone := common.GetZone(state.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -138,6 +147,7 @@ func (r *bridgeResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout20min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(plan.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -150,7 +160,7 @@ func (r *bridgeResource) Update(ctx context.Context, req resource.UpdateRequest,
 		Description: plan.Description.ValueString(),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("Update Error", fmt.Sprintf("Could not update Bridge: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", fmt.Errorf("Could not update Bridge: %w", err))
 		return
 	}
 
@@ -167,6 +177,7 @@ func (r *bridgeResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout20min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(state.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -179,7 +190,7 @@ func (r *bridgeResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	}
 
 	if err := cleanup.DeleteBridge(ctx, r.client, zone, r.client.GetZones(), bridge.ID, r.client.CheckReferencedOption()); err != nil {
-		resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("Could not delete Bridge[%s]: %s", state.ID.ValueString(), err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Delete Error", fmt.Errorf("Could not delete Bridge[%s]: %w", state.ID.ValueString(), err))
 		return
 	}
 }
@@ -188,11 +199,10 @@ func getBridge(ctx context.Context, client *common.APIClient, zone string, id ia
 	bridgeOp := iaas.NewBridgeOp(client)
 	bridge, err := bridgeOp.Read(ctx, zone, id)
 	if err != nil {
-		if iaas.IsNotFoundError(err) {
-			state.RemoveResource(ctx)
+		if common.HandleNotFoundOnRead(ctx, err, state, "Bridge", id.String()) {
 			return nil
 		}
-		diags.AddError("API Read Error", fmt.Sprintf("Could not read SakuraCloud Bridge[%s]: %s", id.String(), err))
+		common.AddAPIError(ctx, diags, "API Read Error", fmt.Errorf("Could not read SakuraCloud Bridge[%s]: %w", id.String(), err))
 		return nil
 	}
 	return bridge
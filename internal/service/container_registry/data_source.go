@@ -108,6 +108,8 @@ func (d *containerRegistryDataSource) Read(ctx context.Context, req datasource.R
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	searcher := iaas.NewContainerRegistryOp(d.client)
 	res, err := searcher.Find(ctx, common.CreateFindCondition(data.ID, data.Name, data.Tags))
 	if err != nil {
@@ -43,6 +43,7 @@ var (
 	_ resource.Resource                = &containerRegistryResource{}
 	_ resource.ResourceWithConfigure   = &containerRegistryResource{}
 	_ resource.ResourceWithImportState = &containerRegistryResource{}
+	_ resource.ResourceWithModifyPlan  = &containerRegistryResource{}
 )
 
 func NewContainerRegistryResource() resource.Resource {
@@ -133,7 +134,7 @@ func (r *containerRegistryResource) Schema(ctx context.Context, req resource.Sch
 					},
 				},
 			},
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
 				Create: true, Update: true, Delete: true,
 			}),
 		},
@@ -144,6 +145,10 @@ func (r *containerRegistryResource) ImportState(ctx context.Context, req resourc
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *containerRegistryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanResourceDefaults(ctx, r.client, req, resp)
+}
+
 func (r *containerRegistryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan containerRegistryResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -153,11 +158,12 @@ func (r *containerRegistryResource) Create(ctx context.Context, req resource.Cre
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	builder := expandContainerRegistryBuilder(&plan, r.client, "")
 	reg, err := builder.Build(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError("Create Error", fmt.Sprintf("creating SakuraCloud ContainerRegistry failed: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", fmt.Errorf("creating SakuraCloud ContainerRegistry failed: %w", err))
 		return
 	}
 
@@ -177,6 +183,8 @@ func (r *containerRegistryResource) Read(ctx context.Context, req resource.ReadR
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	reg := getContainerRegistry(ctx, r.client, common.SakuraCloudID(state.ID.ValueString()), &resp.State, &resp.Diagnostics)
 	if reg == nil {
 		return
@@ -195,17 +203,18 @@ func (r *containerRegistryResource) Update(ctx context.Context, req resource.Upd
 
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	regOp := iaas.NewContainerRegistryOp(r.client)
 	reg, err := regOp.Read(ctx, common.SakuraCloudID(plan.ID.ValueString()))
 	if err != nil {
-		resp.Diagnostics.AddError("Update error", fmt.Sprintf("could not read SakuraCloud ContainerRegistry[%s]: %s", plan.ID.ValueString(), err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update error", fmt.Errorf("could not read SakuraCloud ContainerRegistry[%s]: %w", plan.ID.ValueString(), err))
 		return
 	}
 	builder := expandContainerRegistryBuilder(&plan, r.client, reg.SettingsHash)
 	builder.ID = reg.ID
 	if _, err := builder.Build(ctx); err != nil {
-		resp.Diagnostics.AddError("Update error", fmt.Sprintf("updating SakuraCloud ContainerRegistry[%s] failed: %s", plan.ID.ValueString(), err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update error", fmt.Errorf("updating SakuraCloud ContainerRegistry[%s] failed: %w", plan.ID.ValueString(), err))
 		return
 	}
 
@@ -227,6 +236,7 @@ func (r *containerRegistryResource) Delete(ctx context.Context, req resource.Del
 
 	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	gotReg := getContainerRegistry(ctx, r.client, common.SakuraCloudID(state.ID.ValueString()), &resp.State, &resp.Diagnostics)
 	if gotReg == nil {
@@ -236,7 +246,7 @@ func (r *containerRegistryResource) Delete(ctx context.Context, req resource.Del
 	regOp := iaas.NewContainerRegistryOp(r.client)
 	err := regOp.Delete(ctx, gotReg.ID)
 	if err != nil {
-		resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("deleting SakuraCloud ContainerRegistry[%s] failed: %s", state.ID.ValueString(), err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Delete Error", fmt.Errorf("deleting SakuraCloud ContainerRegistry[%s] failed: %w", state.ID.ValueString(), err))
 	}
 }
 
@@ -259,11 +269,10 @@ func getContainerRegistry(ctx context.Context, client *common.APIClient, id iaas
 	regOp := iaas.NewContainerRegistryOp(client)
 	reg, err := regOp.Read(ctx, id)
 	if err != nil {
-		if iaas.IsNotFoundError(err) {
-			state.RemoveResource(ctx)
+		if common.HandleNotFoundOnRead(ctx, err, state, "ContainerRegistry", id.String()) {
 			return nil
 		}
-		diags.AddError("Get Container Registry Error", fmt.Sprintf("could not read SakuraCloud ContainerRegistry[%s]: %s", id, err))
+		common.AddAPIError(ctx, diags, "Get Container Registry Error", fmt.Errorf("could not read SakuraCloud ContainerRegistry[%s]: %w", id, err))
 		return nil
 	}
 
@@ -94,6 +94,8 @@ func (d *diskDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx = common.WithAPIErrorCapture(ctx)
 	zone := common.GetZone(data.Zone, d.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -102,7 +104,7 @@ func (d *diskDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 	searcher := iaas.NewDiskOp(d.client)
 	res, err := searcher.Find(ctx, zone, common.CreateFindCondition(data.ID, data.Name, data.Tags))
 	if err != nil {
-		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("could not find SakuraCloud Disk resource: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Read Error", fmt.Errorf("could not find SakuraCloud Disk resource: %w", err))
 		return
 	}
 	if res == nil || res.Count == 0 || len(res.Disks) == 0 {
@@ -25,6 +25,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/objectplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
@@ -52,6 +53,7 @@ var (
 	_ resource.Resource                = &diskResource{}
 	_ resource.ResourceWithConfigure   = &diskResource{}
 	_ resource.ResourceWithImportState = &diskResource{}
+	_ resource.ResourceWithModifyPlan  = &diskResource{}
 )
 
 func NewDiskResource() resource.Resource {
@@ -72,8 +74,17 @@ func (r *diskResource) Configure(ctx context.Context, req resource.ConfigureRequ
 
 type diskResourceModel struct {
 	diskBaseModel
-	DistantFrom types.Set      `tfsdk:"distant_from"`
-	Timeouts    timeouts.Value `tfsdk:"timeouts"`
+	DistantFrom types.Set           `tfsdk:"distant_from"`
+	EditParam   *diskEditParamModel `tfsdk:"edit_parameter"`
+	Timeouts    timeouts.Value      `tfsdk:"timeouts"`
+}
+
+type diskEditParamModel struct {
+	Hostname      types.String `tfsdk:"hostname"`
+	Password      types.String `tfsdk:"password"`
+	SSHKeyIDs     types.Set    `tfsdk:"ssh_key_ids"`
+	DisablePwAuth types.Bool   `tfsdk:"disable_pw_auth"`
+	NoteIDs       types.Set    `tfsdk:"note_ids"`
 }
 
 func (r *diskResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
@@ -85,9 +96,10 @@ func (r *diskResource) Schema(ctx context.Context, _ resource.SchemaRequest, res
 			"tags":        common.SchemaResourceTags("Disk"),
 			"zone":        common.SchemaResourceZone("Disk"),
 			"icon_id":     common.SchemaResourceIconID("Disk"),
-			"size":        common.SchemaResourceSize("Disk", 20),
-			"plan":        common.SchemaResourcePlan("Disk", iaastypes.DiskPlanNameMap[iaastypes.DiskPlans.SSD], iaastypes.DiskPlanStrings),
-			"server_id":   common.SchemaResourceServerID("Disk"),
+			// ディスクのサイズ拡張APIは提供されていないため、サイズ変更は常に再作成となる
+			"size":      common.SchemaResourceSize("Disk", 20),
+			"plan":      common.SchemaResourcePlan("Disk", iaastypes.DiskPlanNameMap[iaastypes.DiskPlans.SSD], iaastypes.DiskPlanStrings),
+			"server_id": common.SchemaResourceServerID("Disk"),
 			"connector": schema.StringAttribute{
 				Optional:    true,
 				Computed:    true,
@@ -147,7 +159,51 @@ func (r *diskResource) Schema(ctx context.Context, _ resource.SchemaRequest, res
 					setplanmodifier.RequiresReplaceIfConfigured(),
 				},
 			},
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+			"edit_parameter": schema.SingleNestedAttribute{
+				Optional:    true,
+				Description: "The parameters for editing the disk when it is created from an archive or disk",
+				PlanModifiers: []planmodifier.Object{
+					objectplanmodifier.RequiresReplace(),
+				},
+				Attributes: map[string]schema.Attribute{
+					"hostname": schema.StringAttribute{
+						Optional:    true,
+						Description: desc.Sprintf("The hostname of the Server. %s", desc.Length(1, 64)),
+						Validators: []validator.String{
+							stringvalidator.LengthBetween(1, 64),
+						},
+					},
+					"password": schema.StringAttribute{
+						Optional:    true,
+						Sensitive:   true,
+						Description: desc.Sprintf("The password of default user. %s", desc.Length(12, 128)),
+						Validators: []validator.String{
+							stringvalidator.LengthBetween(12, 128),
+						},
+					},
+					"ssh_key_ids": schema.SetAttribute{
+						ElementType: types.StringType,
+						Optional:    true,
+						Description: "A set of the SSHKey id",
+						Validators: []validator.Set{
+							setvalidator.ValueStringsAre(sacloudvalidator.SakuraIDValidator()),
+						},
+					},
+					"disable_pw_auth": schema.BoolAttribute{
+						Optional:    true,
+						Description: "The flag to disable password authentication",
+					},
+					"note_ids": schema.SetAttribute{
+						ElementType: types.StringType,
+						Optional:    true,
+						Description: "A set of the Note/StartupScript id",
+						Validators: []validator.Set{
+							setvalidator.ValueStringsAre(sacloudvalidator.SakuraIDValidator()),
+						},
+					},
+				},
+			},
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
 				Create: true, Update: true, Delete: true,
 			}),
 		},
@@ -158,6 +214,11 @@ func (r *diskResource) ImportState(ctx context.Context, req resource.ImportState
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *diskResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanZone(ctx, r.client, req, resp)
+	common.PlanResourceDefaults(ctx, r.client, req, resp)
+}
+
 func (r *diskResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan diskResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -167,6 +228,7 @@ func (r *diskResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout24hour)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(plan.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -177,6 +239,9 @@ func (r *diskResource) Create(ctx context.Context, req resource.CreateRequest, r
 	diskBuilder := &setup.RetryableSetup{
 		IsWaitForCopy: true,
 		Create: func(ctx context.Context, zone string) (accessor.ID, error) {
+			if plan.EditParam != nil {
+				return diskOp.CreateWithConfig(ctx, zone, expandDiskCreateRequest(&plan), expandDiskEditRequest(plan.EditParam), false, common.ExpandSakuraCloudIDs(plan.DistantFrom))
+			}
 			return diskOp.Create(ctx, zone, expandDiskCreateRequest(&plan), common.ExpandSakuraCloudIDs(plan.DistantFrom))
 		},
 		Read: func(ctx context.Context, zone string, id iaastypes.ID) (interface{}, error) {
@@ -192,7 +257,7 @@ func (r *diskResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 	res, err := diskBuilder.Setup(ctx, zone)
 	if err != nil {
-		resp.Diagnostics.AddError("Create Error", fmt.Sprintf("creating SakuraCloud Disk is failed: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", fmt.Errorf("creating SakuraCloud Disk is failed: %w", err))
 		return
 	}
 
@@ -213,6 +278,8 @@ func (r *diskResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	zone := common.GetZone(state.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -236,6 +303,7 @@ func (r *diskResource) Update(ctx context.Context, req resource.UpdateRequest, r
 
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout24hour)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(plan.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -245,7 +313,7 @@ func (r *diskResource) Update(ctx context.Context, req resource.UpdateRequest, r
 	diskOp := iaas.NewDiskOp(r.client)
 	_, err := diskOp.Update(ctx, zone, common.ExpandSakuraCloudID(plan.ID), expandDiskUpdateRequest(&plan))
 	if err != nil {
-		resp.Diagnostics.AddError("Update Error", fmt.Sprintf("updating SakuraCloud Disk[%s] is failed: %s", plan.ID.ValueString(), err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", fmt.Errorf("updating SakuraCloud Disk[%s] is failed: %w", plan.ID.ValueString(), err))
 		return
 	}
 
@@ -267,6 +335,7 @@ func (r *diskResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 
 	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout20min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(state.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -315,11 +384,10 @@ func getDisk(ctx context.Context, client *common.APIClient, id iaastypes.ID, zon
 	diskOp := iaas.NewDiskOp(client)
 	disk, err := diskOp.Read(ctx, zone, id)
 	if err != nil {
-		if iaas.IsNotFoundError(err) {
-			state.RemoveResource(ctx)
+		if common.HandleNotFoundOnRead(ctx, err, state, "Disk", id.String()) {
 			return nil
 		}
-		diags.AddError("Get Disk Error", fmt.Sprintf("could not read SakuraCloud Disk[%s]: %s", id.String(), err))
+		common.AddAPIError(ctx, diags, "Get Disk Error", fmt.Errorf("could not read SakuraCloud Disk[%s]: %w", id.String(), err))
 		return nil
 	}
 
@@ -342,6 +410,21 @@ func expandDiskCreateRequest(d *diskResourceModel) *iaas.DiskCreateRequest {
 	}
 }
 
+func expandDiskEditRequest(d *diskEditParamModel) *iaas.DiskEditRequest {
+	req := &iaas.DiskEditRequest{
+		HostName:      d.Hostname.ValueString(),
+		Password:      d.Password.ValueString(),
+		DisablePWAuth: d.DisablePwAuth.ValueBool(),
+	}
+	for _, id := range common.ExpandSakuraCloudIDs(d.SSHKeyIDs) {
+		req.SSHKeys = append(req.SSHKeys, &iaas.DiskEditSSHKey{ID: id})
+	}
+	for _, id := range common.ExpandSakuraCloudIDs(d.NoteIDs) {
+		req.Notes = append(req.Notes, &iaas.DiskEditNote{ID: id})
+	}
+	return req
+}
+
 func expandDiskUpdateRequest(d *diskResourceModel) *iaas.DiskUpdateRequest {
 	return &iaas.DiskUpdateRequest{
 		Connection:  iaastypes.EDiskConnection(d.Connector.ValueString()),
@@ -0,0 +1,131 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disk_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test"
+)
+
+func TestAccSakuraResourceDisk_fromArchive(t *testing.T) {
+	test.ParallelTest(t)
+
+	resourceName := "sakura_disk.foobar"
+	rand := test.RandomName(t, "disk")
+	password := test.RandomName(t, "password")
+	var disk iaas.Disk
+	var createdID string
+	basicConfig := test.BuildConfigWithMap(t, testAccSakuraDisk_fromArchive, map[string]any{
+		"name":        rand,
+		"description": "description",
+		"hostname":    rand,
+		"password":    password,
+	})
+	updateConfig := test.BuildConfigWithMap(t, testAccSakuraDisk_fromArchive, map[string]any{
+		"name":        rand + "-upd",
+		"description": "description-updated",
+		"hostname":    rand,
+		"password":    password,
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { test.AccPreCheck(t) },
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckSakuraDiskDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: basicConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraDiskExists(resourceName, &disk),
+					test.CheckFetched(&disk, func(v *iaas.Disk) error {
+						createdID = v.ID.String()
+						if !v.Availability.IsAvailable() {
+							return fmt.Errorf("disk is not available: %s", v.Availability)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttr(resourceName, "name", rand),
+					resource.TestCheckResourceAttr(resourceName, "description", "description"),
+					resource.TestCheckResourceAttr(resourceName, "plan", "ssd"),
+					resource.TestCheckResourceAttr(resourceName, "size", "20"),
+					resource.TestCheckResourceAttrPair(resourceName, "source_archive_id", "data.sakura_archive.ubuntu", "id"),
+					resource.TestCheckResourceAttr(resourceName, "edit_parameter.hostname", rand),
+				),
+			},
+			test.StablePlanStep(basicConfig, resourceName, "id", "zone"),
+			{
+				// name/descriptionの変更ではディスクは再作成されない
+				Config: updateConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraDiskExists(resourceName, &disk),
+					test.CheckFetched(&disk, func(v *iaas.Disk) error {
+						if v.ID.String() != createdID {
+							return fmt.Errorf("disk is recreated: %s -> %s", createdID, v.ID)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttr(resourceName, "name", rand+"-upd"),
+					resource.TestCheckResourceAttr(resourceName, "description", "description-updated"),
+				),
+			},
+			// edit_parameterは作成時にのみ利用されAPIから取得できない
+			test.ImportStep(resourceName, "edit_parameter", "timeouts"),
+		},
+	})
+}
+
+func testCheckSakuraDiskExists(n string, disk *iaas.Disk) resource.TestCheckFunc {
+	return test.CheckExists(n, test.ExistsCheck[iaas.Disk]{
+		Kind: "Disk",
+		Read: func(ctx context.Context, rs *terraform.ResourceState) (*iaas.Disk, error) {
+			return iaas.NewDiskOp(test.AccClientGetter()).Read(ctx, rs.Primary.Attributes["zone"], common.SakuraCloudID(rs.Primary.ID))
+		},
+		ID: func(v *iaas.Disk) string { return v.ID.String() },
+	}, disk)
+}
+
+var testCheckSakuraDiskDestroy = test.CheckDestroy("sakura_disk", func(ctx context.Context, rs *terraform.ResourceState) error {
+	zone := rs.Primary.Attributes["zone"]
+	if zone == "" {
+		return errors.New("zone is not set")
+	}
+	_, err := iaas.NewDiskOp(test.AccClientGetter()).Read(ctx, zone, common.SakuraCloudID(rs.Primary.ID))
+	return err
+})
+
+var testAccSakuraDisk_fromArchive = `
+data "sakura_archive" "ubuntu" {
+  os_type = "ubuntu"
+}
+
+resource "sakura_disk" "foobar" {
+  name              = "{{ .name }}"
+  description       = "{{ .description }}"
+  source_archive_id = data.sakura_archive.ubuntu.id
+
+  edit_parameter = {
+    hostname        = "{{ .hostname }}"
+    password        = "{{ .password }}"
+    disable_pw_auth = true
+  }
+}`
@@ -0,0 +1,113 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sacloud/iaas-api-go"
+	iaastypes "github.com/sacloud/iaas-api-go/types"
+)
+
+// dnsRecordValue はレコードのRDataを、valueとMX/SRVの優先度などに分解したもの
+type dnsRecordValue struct {
+	Value    string
+	Priority types.Int64
+	Weight   types.Int64
+	Port     types.Int64
+}
+
+// parseDNSRecordValue はレコードのRDataを分解する。MXは"優先度 値"、SRVは"優先度 重み ポート 値"の形式となる
+func parseDNSRecordValue(record *iaas.DNSRecord) (*dnsRecordValue, error) {
+	v := &dnsRecordValue{
+		Value:    record.RData,
+		Priority: types.Int64Null(),
+		Weight:   types.Int64Null(),
+		Port:     types.Int64Null(),
+	}
+
+	var fields []string
+	switch record.Type {
+	case iaastypes.DNSRecordTypes.MX:
+		fields = strings.Fields(record.RData)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid MX record value: %q", record.RData)
+		}
+	case iaastypes.DNSRecordTypes.SRV:
+		fields = strings.Fields(record.RData)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("invalid SRV record value: %q", record.RData)
+		}
+	default:
+		return v, nil
+	}
+
+	nums := make([]int64, len(fields)-1)
+	for i := range nums {
+		n, err := strconv.ParseInt(fields[i], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s record value: %q", record.Type, record.RData)
+		}
+		nums[i] = n
+	}
+	v.Value = fields[len(fields)-1]
+	v.Priority = types.Int64Value(nums[0])
+	if record.Type == iaastypes.DNSRecordTypes.SRV {
+		v.Weight = types.Int64Value(nums[1])
+		v.Port = types.Int64Value(nums[2])
+	}
+	return v, nil
+}
+
+// sameDNSRecordValue はvalueが同じかを返す。CNAMEなどの値には末尾に"."が補完されるため、末尾の"."の有無は区別しない
+func sameDNSRecordValue(a, b string) bool {
+	return strings.TrimSuffix(a, ".") == strings.TrimSuffix(b, ".")
+}
+
+// rdata はvalueとMX/SRVの優先度などを、parseDNSRecordValueで分解できるRDataの形式にまとめる
+func (v *dnsRecordValue) rdata() string {
+	switch {
+	case !v.Port.IsNull():
+		return fmt.Sprintf("%d %d %d %s", v.Priority.ValueInt64(), v.Weight.ValueInt64(), v.Port.ValueInt64(), v.Value)
+	case !v.Priority.IsNull():
+		return fmt.Sprintf("%d %s", v.Priority.ValueInt64(), v.Value)
+	default:
+		return v.Value
+	}
+}
+
+// dnsRecordID はsakura_dns_recordのIDを返す。インポートにも同じ形式を利用する。
+// 値のみが同じMX/SRVのレコードを区別するため、IDにはRData(MXは"優先度 値"、SRVは"優先度 重み ポート 値")を含める
+func dnsRecordID(dnsID, recordType, name string, value *dnsRecordValue) string {
+	return strings.Join([]string{dnsID, recordType, name, value.rdata()}, "/")
+}
+
+// parseDNSRecordID は"<dns_id>/<type>/<name>/<rdata>"の形式のIDを分解する。rdataは"/"を含んでもよい。
+// MX/SRVで優先度などを省略したIDは、どのレコードを指すかが定まらないためエラーとする
+func parseDNSRecordID(id string) (dnsID, recordType, name string, value *dnsRecordValue, err error) {
+	parts := strings.SplitN(id, "/", 4)
+	if len(parts) != 4 || parts[0] == "" || parts[1] == "" || parts[2] == "" || parts[3] == "" {
+		return "", "", "", nil, fmt.Errorf("invalid DNS record ID %q: expected <dns_id>/<type>/<name>/<value>", id)
+	}
+	recordType = strings.ToUpper(parts[1])
+	value, err = parseDNSRecordValue(&iaas.DNSRecord{Type: iaastypes.EDNSRecordType(recordType), RData: parts[3]})
+	if err != nil {
+		return "", "", "", nil, fmt.Errorf("invalid DNS record ID %q: MX records need <priority> <value> and SRV records need <priority> <weight> <port> <value> as the value, e.g. <dns_id>/MX/<name>/10 mail.example.com", id)
+	}
+	return parts[0], recordType, parts[2], value, nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sacloud/iaas-api-go"
+	iaastypes "github.com/sacloud/iaas-api-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDNSRecordValue(t *testing.T) {
+	cases := []struct {
+		name    string
+		record  *iaas.DNSRecord
+		want    *dnsRecordValue
+		wantErr bool
+	}{
+		{
+			name:   "A",
+			record: iaas.NewDNSRecord(iaastypes.DNSRecordTypes.A, "www", "192.0.2.1", 300),
+			want:   &dnsRecordValue{Value: "192.0.2.1", Priority: types.Int64Null(), Weight: types.Int64Null(), Port: types.Int64Null()},
+		},
+		{
+			name:   "MX",
+			record: iaas.NewMXRecord("@", "mail.example.com", 300, 10),
+			want:   &dnsRecordValue{Value: "mail.example.com.", Priority: types.Int64Value(10), Weight: types.Int64Null(), Port: types.Int64Null()},
+		},
+		{
+			name:   "SRV",
+			record: iaas.NewSRVRecord("_sip._tcp", "sip.example.com", 300, 1, 2, 5060),
+			want:   &dnsRecordValue{Value: "sip.example.com", Priority: types.Int64Value(1), Weight: types.Int64Value(2), Port: types.Int64Value(5060)},
+		},
+		{
+			name:    "invalid MX",
+			record:  &iaas.DNSRecord{Name: "@", Type: iaastypes.DNSRecordTypes.MX, RData: "mail.example.com."},
+			wantErr: true,
+		},
+		{
+			name:    "invalid SRV",
+			record:  &iaas.DNSRecord{Name: "_sip._tcp", Type: iaastypes.DNSRecordTypes.SRV, RData: "a 2 5060 sip.example.com"},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseDNSRecordValue(tc.record)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParseDNSRecordID(t *testing.T) {
+	dnsID, recordType, name, value, err := parseDNSRecordID("123456789012/txt/@/v=spf1 include:_spf.example.com/24 ~all")
+	require.NoError(t, err)
+	assert.Equal(t, "123456789012", dnsID)
+	assert.Equal(t, "TXT", recordType)
+	assert.Equal(t, "@", name)
+	assert.Equal(t, &dnsRecordValue{Value: "v=spf1 include:_spf.example.com/24 ~all", Priority: types.Int64Null(), Weight: types.Int64Null(), Port: types.Int64Null()}, value)
+
+	_, _, _, value, err = parseDNSRecordID("123456789012/MX/@/20 mail.example.com.")
+	require.NoError(t, err)
+	assert.Equal(t, &dnsRecordValue{Value: "mail.example.com.", Priority: types.Int64Value(20), Weight: types.Int64Null(), Port: types.Int64Null()}, value)
+
+	_, _, _, value, err = parseDNSRecordID("123456789012/srv/_sip._tcp/1 2 5060 sip.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, &dnsRecordValue{Value: "sip.example.com", Priority: types.Int64Value(1), Weight: types.Int64Value(2), Port: types.Int64Value(5060)}, value)
+
+	for _, id := range []string{
+		"", "123456789012", "123456789012/A/www", "123456789012/A//192.0.2.1",
+		// 優先度などを省略したMX/SRVのIDは、値のみが同じレコードを区別できないためエラーとする
+		"123456789012/MX/@/mail.example.com.",
+		"123456789012/SRV/_sip._tcp/1 sip.example.com",
+	} {
+		_, _, _, _, err := parseDNSRecordID(id)
+		assert.Error(t, err, id)
+	}
+}
+
+func TestDNSRecordID(t *testing.T) {
+	mx10 := iaas.NewMXRecord("@", "mail.example.com", 300, 10)
+	mx20 := iaas.NewMXRecord("@", "mail.example.com", 300, 20)
+	srv := iaas.NewSRVRecord("_sip._tcp", "sip.example.com", 300, 1, 2, 5060)
+	a := iaas.NewDNSRecord(iaastypes.DNSRecordTypes.A, "www", "192.0.2.1", 300)
+
+	ids := make(map[string]bool)
+	for _, record := range []*iaas.DNSRecord{mx10, mx20, srv, a} {
+		v, err := parseDNSRecordValue(record)
+		require.NoError(t, err)
+		id := dnsRecordID("123456789012", string(record.Type), record.Name, v)
+		ids[id] = true
+
+		// IDからレコードの値を復元できること
+		_, recordType, name, parsed, err := parseDNSRecordID(id)
+		require.NoError(t, err)
+		assert.Equal(t, string(record.Type), recordType)
+		assert.Equal(t, record.Name, name)
+		assert.Equal(t, v, parsed)
+	}
+	// 値のみが同じMXのレコードは異なるIDとなる
+	assert.Len(t, ids, 4)
+	assert.True(t, ids["123456789012/MX/@/10 mail.example.com."])
+}
+
+func TestFindDNSRecord(t *testing.T) {
+	records := iaas.DNSRecords{
+		iaas.NewDNSRecord(iaastypes.DNSRecordTypes.A, "www", "192.0.2.1", 300),
+		iaas.NewDNSRecord(iaastypes.DNSRecordTypes.CNAME, "alias", "www.example.com", 300),
+		iaas.NewMXRecord("@", "mail.example.com", 300, 10),
+		iaas.NewMXRecord("@", "mail.example.com", 300, 20),
+	}
+	model := func(recordType, name, value string, priority types.Int64) *dnsRecordResourceModel {
+		return &dnsRecordResourceModel{
+			Type:     types.StringValue(recordType),
+			Name:     types.StringValue(name),
+			Value:    types.StringValue(value),
+			Priority: priority,
+			Weight:   types.Int64Null(),
+			Port:     types.Int64Null(),
+		}
+	}
+
+	assert.Equal(t, records[0], findDNSRecord(records, model("A", "www", "192.0.2.1", types.Int64Null())))
+	assert.Nil(t, findDNSRecord(records, model("A", "www", "192.0.2.2", types.Int64Null())))
+	// 末尾の"."の有無は区別しない
+	assert.Equal(t, records[1], findDNSRecord(records, model("CNAME", "alias", "www.example.com", types.Int64Null())))
+	// 優先度が指定されている場合は優先度も比較する
+	assert.Equal(t, records[3], findDNSRecord(records, model("MX", "@", "mail.example.com.", types.Int64Value(20))))
+	// 以前の形式のIDでインポートしたstateでは優先度が不明なため、最初に一致したレコードを返す
+	assert.Equal(t, records[2], findDNSRecord(records, model("MX", "@", "mail.example.com.", types.Int64Null())))
+}
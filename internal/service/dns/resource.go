@@ -0,0 +1,226 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/sacloud/iaas-api-go"
+	iaastypes "github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+)
+
+type dnsResource struct {
+	client *common.APIClient
+}
+
+var (
+	_ resource.Resource                = &dnsResource{}
+	_ resource.ResourceWithConfigure   = &dnsResource{}
+	_ resource.ResourceWithImportState = &dnsResource{}
+	_ resource.ResourceWithModifyPlan  = &dnsResource{}
+)
+
+func NewDNSResource() resource.Resource {
+	return &dnsResource{}
+}
+
+func (r *dnsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dns"
+}
+
+func (r *dnsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	apiclient := common.GetApiClientFromProvider(req.ProviderData, &resp.Diagnostics)
+	if apiclient == nil {
+		return
+	}
+	r.client = apiclient
+}
+
+type dnsResourceModel struct {
+	dnsBaseModel
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *dnsResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": common.SchemaResourceId("DNS"),
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the DNS zone such as `example.com`. Changing this forces a new resource to be created",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"description": common.SchemaResourceDescription("DNS"),
+			"tags":        common.SchemaResourceTags("DNS"),
+			"icon_id":     common.SchemaResourceIconID("DNS"),
+			"dns_servers": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "A list of IP address of DNS server that manage this zone",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
+				Create: true, Update: true, Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *dnsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+func (r *dnsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanResourceDefaults(ctx, r.client, req, resp)
+}
+
+func (r *dnsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan dnsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout5min)
+	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
+
+	dnsOp := iaas.NewDNSOp(r.client)
+	dns, err := dnsOp.Create(ctx, &iaas.DNSCreateRequest{
+		Name:        plan.Name.ValueString(),
+		Description: plan.Description.ValueString(),
+		Tags:        common.TsetToStrings(plan.Tags),
+		IconID:      common.ExpandSakuraCloudID(plan.IconID),
+	})
+	if err != nil {
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", fmt.Errorf("creating SakuraCloud DNS is failed: %w", err))
+		return
+	}
+
+	plan.updateState(dns)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *dnsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state dnsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = common.WithAPIErrorCapture(ctx)
+
+	dns := getDNS(ctx, r.client, common.ExpandSakuraCloudID(state.ID), &resp.State, &resp.Diagnostics)
+	if dns == nil {
+		return
+	}
+
+	state.updateState(dns)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *dnsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan dnsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout5min)
+	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
+
+	// レコードはsakura_dns_recordで個別に管理するため、sakura_dns_recordの更新と排他して現在のレコードをそのまま送信する
+	dnsID := plan.ID.ValueString()
+	common.SakuraMutexKV.Lock(dnsID)
+	defer common.SakuraMutexKV.Unlock(dnsID)
+
+	dnsOp := iaas.NewDNSOp(r.client)
+	dns, err := dnsOp.Read(ctx, common.SakuraCloudID(dnsID))
+	if err != nil {
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", fmt.Errorf("could not read SakuraCloud DNS[%s]: %w", dnsID, err))
+		return
+	}
+	_, err = dnsOp.Update(ctx, dns.ID, &iaas.DNSUpdateRequest{
+		Description:  plan.Description.ValueString(),
+		Tags:         common.TsetToStrings(plan.Tags),
+		IconID:       common.ExpandSakuraCloudID(plan.IconID),
+		Records:      dns.Records,
+		SettingsHash: dns.SettingsHash,
+	})
+	if err != nil {
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", fmt.Errorf("updating SakuraCloud DNS[%s] is failed: %w", dnsID, err))
+		return
+	}
+
+	dns = getDNS(ctx, r.client, dns.ID, &resp.State, &resp.Diagnostics)
+	if dns == nil {
+		return
+	}
+
+	plan.updateState(dns)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *dnsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state dnsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout5min)
+	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
+
+	dnsOp := iaas.NewDNSOp(r.client)
+	dns := getDNS(ctx, r.client, common.ExpandSakuraCloudID(state.ID), &resp.State, &resp.Diagnostics)
+	if dns == nil {
+		return
+	}
+	if err := dnsOp.Delete(ctx, dns.ID); err != nil {
+		common.AddAPIError(ctx, &resp.Diagnostics, "Delete Error", fmt.Errorf("deleting SakuraCloud DNS[%s] is failed: %w", dns.ID.String(), err))
+		return
+	}
+}
+
+func getDNS(ctx context.Context, client *common.APIClient, id iaastypes.ID, state *tfsdk.State, diags *diag.Diagnostics) *iaas.DNS {
+	dnsOp := iaas.NewDNSOp(client)
+	dns, err := dnsOp.Read(ctx, id)
+	if err != nil {
+		if common.HandleNotFoundOnRead(ctx, err, state, "DNS", id.String()) {
+			return nil
+		}
+		common.AddAPIError(ctx, diags, "Get DNS Error", fmt.Errorf("could not read SakuraCloud DNS[%s]: %w", id.String(), err))
+		return nil
+	}
+	return dns
+}
@@ -0,0 +1,442 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/sacloud/iaas-api-go"
+	iaastypes "github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/desc"
+	sacloudvalidator "github.com/sacloud/terraform-provider-sakuracloud/internal/validator"
+)
+
+type dnsRecordResource struct {
+	client *common.APIClient
+}
+
+var (
+	_ resource.Resource                   = &dnsRecordResource{}
+	_ resource.ResourceWithConfigure      = &dnsRecordResource{}
+	_ resource.ResourceWithImportState    = &dnsRecordResource{}
+	_ resource.ResourceWithValidateConfig = &dnsRecordResource{}
+)
+
+func NewDNSRecordResource() resource.Resource {
+	return &dnsRecordResource{}
+}
+
+func (r *dnsRecordResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_dns_record"
+}
+
+func (r *dnsRecordResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	apiclient := common.GetApiClientFromProvider(req.ProviderData, &resp.Diagnostics)
+	if apiclient == nil {
+		return
+	}
+	r.client = apiclient
+}
+
+type dnsRecordResourceModel struct {
+	ID       types.String   `tfsdk:"id"`
+	DNSID    types.String   `tfsdk:"dns_id"`
+	Name     types.String   `tfsdk:"name"`
+	Type     types.String   `tfsdk:"type"`
+	Value    types.String   `tfsdk:"value"`
+	TTL      types.Int64    `tfsdk:"ttl"`
+	Priority types.Int64    `tfsdk:"priority"`
+	Weight   types.Int64    `tfsdk:"weight"`
+	Port     types.Int64    `tfsdk:"port"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *dnsRecordResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			// レコードのIDは"<dns_id>/<type>/<name>/<rdata>"の形式で、SakuraIDではない
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the DNS Record.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"dns_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The id of the DNS that the record belongs to",
+				Validators: []validator.String{
+					sacloudvalidator.SakuraIDValidator(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:    true,
+				Description: "The name of the DNS Record. Use `@` for the zone apex",
+				Validators: []validator.String{
+					stringvalidator.LengthBetween(1, 64),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"type": schema.StringAttribute{
+				Required:    true,
+				Description: desc.Sprintf("The type of the DNS Record. This must be one of [%s]", iaastypes.DNSRecordTypeStrings),
+				Validators: []validator.String{
+					stringvalidator.OneOf(iaastypes.DNSRecordTypeStrings...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"value": schema.StringAttribute{
+				Required:    true,
+				Description: "The value of the DNS Record. For MX and SRV records, this is the target host without priority, weight and port",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ttl": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(3600),
+				Description: desc.Sprintf("The number of the TTL. %s", desc.Range(10, 3600000)),
+				Validators: []validator.Int64{
+					int64validator.Between(10, 3600000),
+				},
+			},
+			"priority": schema.Int64Attribute{
+				Optional:    true,
+				Description: desc.Sprintf("The priority of target DNS Record. This is required when type is MX or SRV. %s", desc.Range(0, 65535)),
+				Validators: []validator.Int64{
+					int64validator.Between(0, 65535),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"weight": schema.Int64Attribute{
+				Optional:    true,
+				Description: desc.Sprintf("The weight of target DNS Record. This is required when type is SRV. %s", desc.Range(0, 65535)),
+				Validators: []validator.Int64{
+					int64validator.Between(0, 65535),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"port": schema.Int64Attribute{
+				Optional:    true,
+				Description: desc.Sprintf("The number of port. This is required when type is SRV. %s", desc.Range(1, 65535)),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
+				Create: true, Update: true, Delete: true,
+			}),
+		},
+	}
+}
+
+// ValidateConfig はtypeに応じてpriority/weight/portの指定を検証する
+func (r *dnsRecordResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config dnsRecordResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.Type.IsUnknown() || config.Type.IsNull() {
+		return
+	}
+
+	recordType := iaastypes.EDNSRecordType(config.Type.ValueString())
+	required := map[string]bool{
+		"priority": recordType == iaastypes.DNSRecordTypes.MX || recordType == iaastypes.DNSRecordTypes.SRV,
+		"weight":   recordType == iaastypes.DNSRecordTypes.SRV,
+		"port":     recordType == iaastypes.DNSRecordTypes.SRV,
+	}
+	values := map[string]types.Int64{
+		"priority": config.Priority,
+		"weight":   config.Weight,
+		"port":     config.Port,
+	}
+	for _, name := range []string{"priority", "weight", "port"} {
+		v := values[name]
+		switch {
+		case required[name] && v.IsNull():
+			resp.Diagnostics.AddAttributeError(path.Root(name), "Missing Attribute",
+				fmt.Sprintf("%q is required when type is %s", name, recordType))
+		case !required[name] && !v.IsNull():
+			resp.Diagnostics.AddAttributeError(path.Root(name), "Invalid Attribute",
+				fmt.Sprintf("%q cannot be specified when type is %s", name, recordType))
+		}
+	}
+}
+
+func (r *dnsRecordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	dnsID, recordType, name, value, err := parseDNSRecordID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Import Error", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), dnsRecordID(dnsID, recordType, name, value))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("dns_id"), dnsID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("type"), recordType)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("value"), value.Value)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("priority"), value.Priority)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("weight"), value.Weight)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("port"), value.Port)...)
+}
+
+func (r *dnsRecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan dnsRecordResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout5min)
+	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
+
+	record := expandDNSRecord(&plan)
+	dns, err := r.updateRecords(ctx, plan.DNSID.ValueString(), func(records *iaas.DNSRecords) error {
+		// 他の設定やsakura_dnsの外で作成されたレコードを上書きしないよう、同じレコードが存在する場合はエラーとする。
+		// CNAMEなどの値は末尾に"."が補完されて保存されるため、findDNSRecordで末尾の"."の有無を区別せずに比較する
+		if findDNSRecord(*records, &plan) != nil {
+			return fmt.Errorf("the record already exists in DNS[%s]. Import it with the ID %q to manage it", plan.DNSID.ValueString(),
+				dnsRecordID(plan.DNSID.ValueString(), plan.Type.ValueString(), plan.Name.ValueString(), plan.recordValue()))
+		}
+		records.Add(record)
+		return nil
+	})
+	if err != nil {
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", fmt.Errorf("creating SakuraCloud DNS Record is failed: %w", err))
+		return
+	}
+
+	found, err := plan.updateState(dns)
+	if err != nil {
+		resp.Diagnostics.AddError("Create Error", err.Error())
+		return
+	}
+	if !found {
+		resp.Diagnostics.AddError("Create Error", fmt.Sprintf("created record is not found in SakuraCloud DNS[%s]", plan.DNSID.ValueString()))
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *dnsRecordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state dnsRecordResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = common.WithAPIErrorCapture(ctx)
+
+	dns := getDNS(ctx, r.client, common.ExpandSakuraCloudID(state.DNSID), &resp.State, &resp.Diagnostics)
+	if dns == nil {
+		return
+	}
+
+	found, err := state.updateState(dns)
+	if err != nil {
+		resp.Diagnostics.AddError("Read Error", err.Error())
+		return
+	}
+	if !found {
+		tflog.Warn(ctx, fmt.Sprintf("DNS Record[%s] is not found. Removing it from the state", state.ID.ValueString()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *dnsRecordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state dnsRecordResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout5min)
+	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
+
+	// ttl以外の変更は再作成となるため、ここでは同じレコードのTTLのみを変更する
+	dns, err := r.updateRecords(ctx, plan.DNSID.ValueString(), func(records *iaas.DNSRecords) error {
+		current := findDNSRecord(*records, &state)
+		if current == nil {
+			return fmt.Errorf("the record is not found in DNS[%s]", plan.DNSID.ValueString())
+		}
+		current.TTL = int(plan.TTL.ValueInt64())
+		return nil
+	})
+	if err != nil {
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", fmt.Errorf("updating SakuraCloud DNS Record[%s] is failed: %w", state.ID.ValueString(), err))
+		return
+	}
+
+	if _, err := plan.updateState(dns); err != nil {
+		resp.Diagnostics.AddError("Update Error", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *dnsRecordResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state dnsRecordResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout5min)
+	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
+
+	_, err := r.updateRecords(ctx, state.DNSID.ValueString(), func(records *iaas.DNSRecords) error {
+		if current := findDNSRecord(*records, &state); current != nil {
+			records.Delete(current)
+		}
+		return nil
+	})
+	if err != nil && !common.IsNotFound(err) {
+		common.AddAPIError(ctx, &resp.Diagnostics, "Delete Error", fmt.Errorf("deleting SakuraCloud DNS Record[%s] is failed: %w", state.ID.ValueString(), err))
+		return
+	}
+}
+
+// updateRecords はDNSの最新のレコードをmodifyで変更して書き戻し、更新後のDNSを返す。
+// 他のsakura_dns_recordが管理するレコードを上書きしないよう、レコード全体ではなく読み込んだ直後のレコードに変更を加える。
+// 同じプロバイダー内ではDNSのIDで排他し、別のプロセスによる同時の更新はSettingsHashで検出して読み込みからやり直す
+func (r *dnsRecordResource) updateRecords(ctx context.Context, dnsID string, modify func(records *iaas.DNSRecords) error) (*iaas.DNS, error) {
+	common.SakuraMutexKV.Lock(dnsID)
+	defer common.SakuraMutexKV.Unlock(dnsID)
+
+	dnsOp := iaas.NewDNSOp(r.client)
+	var updated *iaas.DNS
+	err := common.RetryOnConflict(ctx, func() error {
+		dns, err := dnsOp.Read(ctx, common.SakuraCloudID(dnsID))
+		if err != nil {
+			return err
+		}
+		records := dns.Records
+		if err := modify(&records); err != nil {
+			return err
+		}
+		updated, err = dnsOp.UpdateSettings(ctx, dns.ID, &iaas.DNSUpdateSettingsRequest{
+			Records:      records,
+			SettingsHash: dns.SettingsHash,
+		})
+		return err
+	})
+	return updated, err
+}
+
+// expandDNSRecord はレコードの設定からAPIのレコードを返す。MX/SRVの優先度などはRDataに含める
+func expandDNSRecord(model *dnsRecordResourceModel) *iaas.DNSRecord {
+	recordType := iaastypes.EDNSRecordType(model.Type.ValueString())
+	name, value, ttl := model.Name.ValueString(), model.Value.ValueString(), int(model.TTL.ValueInt64())
+	switch recordType {
+	case iaastypes.DNSRecordTypes.MX:
+		return iaas.NewMXRecord(name, value, ttl, int(model.Priority.ValueInt64()))
+	case iaastypes.DNSRecordTypes.SRV:
+		return iaas.NewSRVRecord(name, value, ttl, int(model.Priority.ValueInt64()), int(model.Weight.ValueInt64()), int(model.Port.ValueInt64()))
+	default:
+		return iaas.NewDNSRecord(recordType, name, value, ttl)
+	}
+}
+
+// findDNSRecord はrecordsからmodelのレコードを探す。
+// 以前の形式のIDでインポートしたstateではpriority/weight/portが不明なため、指定されている属性のみを比較する
+func findDNSRecord(records iaas.DNSRecords, model *dnsRecordResourceModel) *iaas.DNSRecord {
+	for _, record := range records {
+		if record.Name != model.Name.ValueString() || string(record.Type) != model.Type.ValueString() {
+			continue
+		}
+		v, err := parseDNSRecordValue(record)
+		if err != nil || !sameDNSRecordValue(v.Value, model.Value.ValueString()) {
+			continue
+		}
+		if !sameOptionalInt64(model.Priority, v.Priority) || !sameOptionalInt64(model.Weight, v.Weight) || !sameOptionalInt64(model.Port, v.Port) {
+			continue
+		}
+		return record
+	}
+	return nil
+}
+
+func sameOptionalInt64(want, got types.Int64) bool {
+	return want.IsNull() || want.IsUnknown() || want.Equal(got)
+}
+
+// updateState はdnsからmodelのレコードを探してstateを更新する。見つからない場合はfalseを返す
+func (model *dnsRecordResourceModel) updateState(dns *iaas.DNS) (bool, error) {
+	if dns == nil {
+		return false, errors.New("DNS is nil")
+	}
+	record := findDNSRecord(dns.Records, model)
+	if record == nil {
+		return false, nil
+	}
+	v, err := parseDNSRecordValue(record)
+	if err != nil {
+		return false, err
+	}
+
+	model.DNSID = types.StringValue(dns.ID.String())
+	model.Name = types.StringValue(record.Name)
+	model.Type = types.StringValue(string(record.Type))
+	// 設定と末尾の"."の有無のみが異なる場合は設定の値を維持する
+	if !sameDNSRecordValue(model.Value.ValueString(), v.Value) || model.Value.IsNull() {
+		model.Value = types.StringValue(v.Value)
+	}
+	model.TTL = types.Int64Value(int64(record.TTL))
+	model.Priority = v.Priority
+	model.Weight = v.Weight
+	model.Port = v.Port
+	model.ID = types.StringValue(dnsRecordID(model.DNSID.ValueString(), model.Type.ValueString(), model.Name.ValueString(), model.recordValue()))
+	return true, nil
+}
+
+// recordValue はvalueとMX/SRVの優先度などをdnsRecordValueにまとめる
+func (model *dnsRecordResourceModel) recordValue() *dnsRecordValue {
+	return &dnsRecordValue{Value: model.Value.ValueString(), Priority: model.Priority, Weight: model.Weight, Port: model.Port}
+}
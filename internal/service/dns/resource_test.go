@@ -0,0 +1,158 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dns_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test"
+)
+
+func TestAccSakuraResourceDNS_basic(t *testing.T) {
+	test.ParallelTest(t)
+
+	resourceName := "sakura_dns.foobar"
+	zone := test.RandomName(t, "dns") + ".com"
+	var dns iaas.DNS
+	basicConfig := test.BuildConfigWithMap(t, testAccSakuraDNS_basic, map[string]any{
+		"zone":        zone,
+		"description": "description",
+		"ttl":         300,
+	})
+	updateConfig := test.BuildConfigWithMap(t, testAccSakuraDNS_basic, map[string]any{
+		"zone":        zone,
+		"description": "description-updated",
+		"ttl":         600,
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { test.AccPreCheck(t) },
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckSakuraDNSDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: basicConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraDNSExists(resourceName, &dns),
+					test.CheckFetched(&dns, func(v *iaas.DNS) error {
+						if len(v.Records) != 5 {
+							return fmt.Errorf("unexpected records: %v", v.Records)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttr(resourceName, "name", zone),
+					resource.TestCheckResourceAttr(resourceName, "description", "description"),
+					resource.TestCheckResourceAttr(resourceName, "tags.#", "2"),
+					resource.TestCheckResourceAttrSet(resourceName, "dns_servers.0"),
+					resource.TestCheckResourceAttr("sakura_dns_record.a", "value", "192.0.2.1"),
+					resource.TestCheckResourceAttr("sakura_dns_record.a", "ttl", "300"),
+					resource.TestCheckResourceAttr("sakura_dns_record.cname", "value", "www."+zone),
+					resource.TestCheckResourceAttr("sakura_dns_record.mx", "priority", "10"),
+					resource.TestCheckResourceAttr("sakura_dns_record.srv", "port", "5060"),
+				),
+			},
+			test.StablePlanStep(basicConfig, resourceName, "id", "dns_servers.#"),
+			{
+				// TTLの変更では他のレコードを変更しない
+				Config: updateConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraDNSExists(resourceName, &dns),
+					test.CheckFetched(&dns, func(v *iaas.DNS) error {
+						if len(v.Records) != 5 {
+							return fmt.Errorf("unexpected records: %v", v.Records)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttr(resourceName, "description", "description-updated"),
+					resource.TestCheckResourceAttr("sakura_dns_record.a", "ttl", "600"),
+				),
+			},
+			test.ImportStep(resourceName, "timeouts"),
+			// sakura_dns_recordのIDは"<dns_id>/<type>/<name>/<rdata>"の形式でインポートにも利用できる。MX/SRVのrdataには優先度などを含む
+			test.ImportStep("sakura_dns_record.a", "timeouts"),
+			test.ImportStep("sakura_dns_record.cname", "timeouts"),
+			test.ImportStep("sakura_dns_record.mx", "timeouts"),
+			test.ImportStep("sakura_dns_record.srv", "timeouts"),
+		},
+	})
+}
+
+func testCheckSakuraDNSExists(n string, dns *iaas.DNS) resource.TestCheckFunc {
+	return test.CheckExists(n, test.ExistsCheck[iaas.DNS]{
+		Kind: "DNS",
+		Read: func(ctx context.Context, rs *terraform.ResourceState) (*iaas.DNS, error) {
+			return iaas.NewDNSOp(test.AccClientGetter()).Read(ctx, common.SakuraCloudID(rs.Primary.ID))
+		},
+		ID: func(v *iaas.DNS) string { return v.ID.String() },
+	}, dns)
+}
+
+var testCheckSakuraDNSDestroy = test.CheckDestroy("sakura_dns", func(ctx context.Context, rs *terraform.ResourceState) error {
+	_, err := iaas.NewDNSOp(test.AccClientGetter()).Read(ctx, common.SakuraCloudID(rs.Primary.ID))
+	return err
+})
+
+var testAccSakuraDNS_basic = `
+resource "sakura_dns" "foobar" {
+  name        = "{{ .zone }}"
+  description = "{{ .description }}"
+  tags        = ["tag1", "tag2"]
+}
+
+resource "sakura_dns_record" "a" {
+  dns_id = sakura_dns.foobar.id
+  name   = "www"
+  type   = "A"
+  value  = "192.0.2.1"
+  ttl    = {{ .ttl }}
+}
+
+resource "sakura_dns_record" "cname" {
+  dns_id = sakura_dns.foobar.id
+  name   = "alias"
+  type   = "CNAME"
+  value  = "www.{{ .zone }}"
+}
+
+resource "sakura_dns_record" "mx" {
+  dns_id   = sakura_dns.foobar.id
+  name     = "@"
+  type     = "MX"
+  value    = "mail.{{ .zone }}."
+  priority = 10
+}
+
+resource "sakura_dns_record" "srv" {
+  dns_id   = sakura_dns.foobar.id
+  name     = "_sip._tcp"
+  type     = "SRV"
+  value    = "sip.{{ .zone }}."
+  priority = 1
+  weight   = 2
+  port     = 5060
+}
+
+resource "sakura_dns_record" "txt" {
+  dns_id = sakura_dns.foobar.id
+  name   = "@"
+  type   = "TXT"
+  value  = "v=spf1 -all"
+}`
@@ -73,6 +73,8 @@ func (d *iconDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	searcher := iaas.NewIconOp(d.client)
 	res, err := searcher.Find(ctx, common.CreateFindCondition(data.ID, data.Name, data.Tags))
 	if err != nil {
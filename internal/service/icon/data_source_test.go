@@ -23,7 +23,7 @@ import (
 
 func TestAccSakuraDataSourceIcon_basic(t *testing.T) {
 	resourceName := "data.sakura_icon.foobar"
-	rand := test.RandomName()
+	rand := test.RandomName(t, "icon")
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { test.AccPreCheck(t) },
@@ -21,15 +21,15 @@ import (
 )
 
 type iconBaseModel struct {
-	ID   types.String `tfsdk:"id"`
-	Name types.String `tfsdk:"name"`
-	URL  types.String `tfsdk:"url"`
-	Tags types.Set    `tfsdk:"tags"`
+	ID   common.SakuraID `tfsdk:"id"`
+	Name types.String    `tfsdk:"name"`
+	URL  types.String    `tfsdk:"url"`
+	Tags types.Set       `tfsdk:"tags"`
 }
 
 func (model *iconBaseModel) updateState(icon *iaas.Icon) {
-	model.ID = types.StringValue(icon.ID.String())
-	model.Name = types.StringValue(icon.Name)
-	model.Tags = common.StringsToTset(icon.Tags)
+	model.ID = common.NewSakuraIDValue(icon.ID.String())
+	model.Name = common.NameFromAPI(model.Name, icon.Name)
+	model.Tags = common.FlattenTags(icon.Tags)
 	model.URL = types.StringValue(icon.URL)
 }
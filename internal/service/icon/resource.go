@@ -45,6 +45,7 @@ var (
 	_ resource.Resource                = &iconResource{}
 	_ resource.ResourceWithConfigure   = &iconResource{}
 	_ resource.ResourceWithImportState = &iconResource{}
+	_ resource.ResourceWithModifyPlan  = &iconResource{}
 )
 
 func NewIconResource() resource.Resource {
@@ -94,7 +95,7 @@ func (r *iconResource) Schema(ctx context.Context, _ resource.SchemaRequest, res
 				Computed:    true,
 				Description: "The URL for getting the icon's raw data.",
 			},
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
 				Create: true, Update: true, Delete: true,
 			}),
 		},
@@ -105,6 +106,10 @@ func (r *iconResource) ImportState(ctx context.Context, req resource.ImportState
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *iconResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanResourceDefaults(ctx, r.client, req, resp)
+}
+
 func (r *iconResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan iconResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -114,6 +119,7 @@ func (r *iconResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	iconOp := iaas.NewIconOp(r.client)
 	createReq, err := expandIconCreateRequest(&plan)
@@ -123,7 +129,7 @@ func (r *iconResource) Create(ctx context.Context, req resource.CreateRequest, r
 	}
 	icon, err := iconOp.Create(ctx, createReq)
 	if err != nil {
-		resp.Diagnostics.AddError("Icon Create API Error", err.Error())
+		common.AddAPIError(ctx, &resp.Diagnostics, "Icon Create API Error", err)
 		return
 	}
 
@@ -138,6 +144,8 @@ func (r *iconResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	icon := getIcon(ctx, r.client, common.SakuraCloudID(state.ID.ValueString()), &resp.State, &resp.Diagnostics)
 	if icon == nil {
 		return
@@ -156,11 +164,12 @@ func (r *iconResource) Update(ctx context.Context, req resource.UpdateRequest, r
 
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	iconOp := iaas.NewIconOp(r.client)
 	_, err := iconOp.Update(ctx, common.ExpandSakuraCloudID(plan.ID), expandIconUpdateRequest(&plan))
 	if err != nil {
-		resp.Diagnostics.AddError("Icon Update API Error", err.Error())
+		common.AddAPIError(ctx, &resp.Diagnostics, "Icon Update API Error", err)
 		return
 	}
 
@@ -182,6 +191,7 @@ func (r *iconResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 
 	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	iconOp := iaas.NewIconOp(r.client)
 	icon := getIcon(ctx, r.client, common.SakuraCloudID(state.ID.ValueString()), &resp.State, &resp.Diagnostics)
@@ -189,7 +199,7 @@ func (r *iconResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 	if err := iconOp.Delete(ctx, icon.ID); err != nil {
-		resp.Diagnostics.AddError("Icon Delete API Error", err.Error())
+		common.AddAPIError(ctx, &resp.Diagnostics, "Icon Delete API Error", err)
 		return
 	}
 }
@@ -198,11 +208,10 @@ func getIcon(ctx context.Context, client *common.APIClient, id iaastypes.ID, sta
 	iconOp := iaas.NewIconOp(client)
 	icon, err := iconOp.Read(ctx, id)
 	if err != nil {
-		if iaas.IsNotFoundError(err) {
-			state.RemoveResource(ctx)
+		if common.HandleNotFoundOnRead(ctx, err, state, "Icon", id.String()) {
 			return nil
 		}
-		diags.AddError("Icon Read API Error", err.Error())
+		common.AddAPIError(ctx, diags, "Icon Read API Error", err)
 		return nil
 	}
 	return icon
@@ -30,7 +30,7 @@ import (
 
 func TestAccSakuraIcon_basic(t *testing.T) {
 	resourceName := "sakura_icon.foobar"
-	name := test.RandomName()
+	name := test.RandomName(t, "icon")
 
 	var icon iaas.Icon
 	resource.Test(t, resource.TestCase{
@@ -64,7 +64,7 @@ func TestAccSakuraIcon_basic(t *testing.T) {
 
 func TestAccSakuraIcon_withSwitch(t *testing.T) {
 	resourceName := "sakura_icon.foobar"
-	name := test.RandomName()
+	name := test.RandomName(t, "icon")
 
 	var icon iaas.Icon
 	resource.Test(t, resource.TestCase{
@@ -126,6 +126,8 @@ func (d *internetDataSource) Read(ctx context.Context, req datasource.ReadReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx = common.WithAPIErrorCapture(ctx)
 	zone := common.GetZone(data.Zone, d.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -134,7 +136,7 @@ func (d *internetDataSource) Read(ctx context.Context, req datasource.ReadReques
 	searcher := iaas.NewInternetOp(d.client)
 	res, err := searcher.Find(ctx, zone, common.CreateFindCondition(data.ID, data.Name, data.Tags))
 	if err != nil {
-		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("could not find SakuraCloud Internet resource: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Read Error", fmt.Errorf("could not find SakuraCloud Internet resource: %w", err))
 		return
 	}
 	if res == nil || res.Count == 0 || len(res.Internet) == 0 {
@@ -143,7 +145,10 @@ func (d *internetDataSource) Read(ctx context.Context, req datasource.ReadReques
 	}
 
 	internet := res.Internet[0]
-	data.updateState(ctx, d.client, zone, internet)
+	if err := data.updateState(ctx, d.client, zone, internet); err != nil {
+		common.AddAPIError(ctx, &resp.Diagnostics, "Read Error", err)
+		return
+	}
 	data.IconID = types.StringValue(internet.IconID.String())
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -24,9 +24,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -48,6 +51,7 @@ var (
 	_ resource.Resource                = &internetResource{}
 	_ resource.ResourceWithConfigure   = &internetResource{}
 	_ resource.ResourceWithImportState = &internetResource{}
+	_ resource.ResourceWithModifyPlan  = &internetResource{}
 )
 
 func NewInternetResource() resource.Resource {
@@ -106,7 +110,11 @@ func (r *internetResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 			"enable_ipv6": schema.BoolAttribute{
 				Optional:    true,
 				Computed:    true,
-				Description: "The flag to enable IPv6",
+				Description: "The flag to enable IPv6. If this is omitted, the current setting is kept",
+				// 省略時にunknownのままだとfalseとして扱われ、有効にしたIPv6が無効化されるため、stateの値を引き継ぐ
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"switch_id": schema.StringAttribute{
 				Computed:    true,
@@ -118,24 +126,43 @@ func (r *internetResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 				Description: desc.Sprintf("A set of the ID of Servers connected to the %s", resourceName),
 			},
 			"network_address": schema.StringAttribute{
-				Computed:    true,
+				Computed: true,
+				// 帯域の変更でもグローバルIPアドレスは変わらない
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 				Description: desc.Sprintf("The IPv4 network address assigned to the %s", resourceName),
 			},
 			"gateway": schema.StringAttribute{
-				Computed:    true,
+				Computed: true,
+				// 帯域の変更でもグローバルIPアドレスは変わらない
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 				Description: desc.Sprintf("The IP address of the gateway used by the %s", resourceName),
 			},
 			"min_ip_address": schema.StringAttribute{
-				Computed:    true,
+				Computed: true,
+				// 帯域の変更でもグローバルIPアドレスは変わらない
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 				Description: desc.Sprintf("Minimum IP address in assigned global addresses to the %s", resourceName),
 			},
 			"max_ip_address": schema.StringAttribute{
-				Computed:    true,
+				Computed: true,
+				// 帯域の変更でもグローバルIPアドレスは変わらない
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 				Description: desc.Sprintf("Maximum IP address in assigned global addresses to the %s", resourceName),
 			},
 			"ip_addresses": schema.SetAttribute{
 				ElementType: types.StringType,
 				Computed:    true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.UseStateForUnknown(),
+				},
 				Description: desc.Sprintf("A set of assigned global address to the %s", resourceName),
 			},
 			"ipv6_prefix": schema.StringAttribute{
@@ -157,7 +184,7 @@ func (r *internetResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 				Computed:    true,
 				Description: desc.Sprintf("The auto assigned tags of the %s when band_width is changed", resourceName),
 			},
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
 				Create: true, Update: true, Delete: true,
 			}),
 		},
@@ -169,6 +196,9 @@ func (r *internetResource) ImportState(ctx context.Context, req resource.ImportS
 }
 
 func (r *internetResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanZone(ctx, r.client, req, resp)
+	common.PlanResourceDefaults(ctx, r.client, req, resp)
+
 	var plan, state *internetResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -192,6 +222,7 @@ func (r *internetResource) Create(ctx context.Context, req resource.CreateReques
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout60min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(plan.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -201,11 +232,14 @@ func (r *internetResource) Create(ctx context.Context, req resource.CreateReques
 	builder := expandInternetBuilder(&plan, r.client)
 	internet, err := builder.Build(ctx, zone)
 	if err != nil {
-		resp.Diagnostics.AddError("Create Error", fmt.Sprintf("creating SakuraCloud Internet is failed: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", fmt.Errorf("creating SakuraCloud Internet is failed: %w", err))
 		return
 	}
 
-	plan.updateState(ctx, r.client, zone, internet)
+	if err := plan.updateState(ctx, r.client, zone, internet); err != nil {
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", err)
+		return
+	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -216,6 +250,8 @@ func (r *internetResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	zone := common.GetZone(state.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -226,7 +262,10 @@ func (r *internetResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	state.updateState(ctx, r.client, zone, internet)
+	if err := state.updateState(ctx, r.client, zone, internet); err != nil {
+		common.AddAPIError(ctx, &resp.Diagnostics, "Read Error", err)
+		return
+	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
@@ -240,6 +279,7 @@ func (r *internetResource) Update(ctx context.Context, req resource.UpdateReques
 
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout60min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(plan.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -251,20 +291,23 @@ func (r *internetResource) Update(ctx context.Context, req resource.UpdateReques
 	defer common.SakuraMutexKV.Unlock(internetId)
 
 	builder := expandInternetBuilder(&plan, r.client)
-	_, err := builder.Update(ctx, zone, common.SakuraCloudID(internetId))
+	updated, err := builder.Update(ctx, zone, common.SakuraCloudID(internetId))
 	if err != nil {
-		resp.Diagnostics.AddError("Update Error", fmt.Sprintf("updating SakuraCloud Internet[%s] is failed: %s", internetId, err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", fmt.Errorf("updating SakuraCloud Internet[%s] is failed: %w", internetId, err))
 		return
 	}
 
-	internet := getInternet(ctx, r.client, zone, common.SakuraCloudID(internetId), &resp.State, &resp.Diagnostics)
+	// NOTE: 帯域変更後はIDが変更になるため、Updateの戻り値のIDで読み直す
+	internet := getInternet(ctx, r.client, zone, updated.ID, &resp.State, &resp.Diagnostics)
 	if internet == nil {
 		return
 	}
 
-	// NOTE: 帯域変更後はIDが変更になる
-	state.updateState(ctx, r.client, zone, internet)
-	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	if err := plan.updateState(ctx, r.client, zone, internet); err != nil {
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", err)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
 func (r *internetResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -274,8 +317,9 @@ func (r *internetResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
-	ctx, cancel := common.SetupTimeoutUpdate(ctx, state.Timeouts, common.Timeout20min)
+	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout20min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(state.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -289,21 +333,20 @@ func (r *internetResource) Delete(ctx context.Context, req resource.DeleteReques
 	internetOp := iaas.NewInternetOp(r.client)
 	internet, err := internetOp.Read(ctx, zone, common.ExpandSakuraCloudID(state.ID))
 	if err != nil {
-		if iaas.IsNotFoundError(err) {
-			resp.State.RemoveResource(ctx)
+		if common.HandleNotFoundOnRead(ctx, err, &resp.State, "Internet", state.ID.ValueString()) {
 			return
 		}
-		resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("could not read SakuraCloud Internet[%s]: %s", internetId, err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Delete Error", fmt.Errorf("could not read SakuraCloud Internet[%s]: %w", internetId, err))
 		return
 	}
 
 	if err := query.WaitWhileSwitchIsReferenced(ctx, r.client, zone, internet.Switch.ID, r.client.CheckReferencedOption()); err != nil {
-		resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("waiting deletion is failed: Internet[%s] still used by others: %s", internet.ID, err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Delete Error", fmt.Errorf("waiting deletion is failed: Internet[%s] still used by others: %w", internet.ID, err))
 		return
 	}
 
 	if err := cleanup.DeleteInternet(ctx, internetOp, zone, internet.ID); err != nil {
-		resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("deleting SakuraCloud Internet[%s] is failed: %s", internet.ID, err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Delete Error", fmt.Errorf("deleting SakuraCloud Internet[%s] is failed: %w", internet.ID, err))
 		return
 	}
 }
@@ -316,7 +359,7 @@ func getInternet(ctx context.Context, client *common.APIClient, zone string, id
 			state.RemoveResource(ctx)
 			return nil
 		}
-		diags.AddError("Get Internet Error", fmt.Sprintf("could not read SakuraCloud Internet[%s]: %s", id, err))
+		common.AddAPIError(ctx, diags, "Get Internet Error", fmt.Errorf("could not read SakuraCloud Internet[%s]: %w", id, err))
 		return nil
 	}
 
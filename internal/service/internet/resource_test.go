@@ -0,0 +1,134 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package internet_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test"
+)
+
+func TestAccSakuraResourceInternet_basic(t *testing.T) {
+	test.ParallelTest(t)
+
+	resourceName := "sakura_internet.foobar"
+	rand := test.RandomName(t, "internet")
+	var internet iaas.Internet
+	var createdID, networkAddress string
+	basicConfig := test.BuildConfigWithMap(t, testAccSakuraInternet_basic, map[string]any{"name": rand, "band_width": 100, "enable_ipv6": false})
+	updateConfig := test.BuildConfigWithMap(t, testAccSakuraInternet_basic, map[string]any{"name": rand + "-upd", "band_width": 250, "enable_ipv6": true})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { test.AccPreCheck(t) },
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckSakuraInternetDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: basicConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraInternetExists(resourceName, &internet),
+					test.CheckFetched(&internet, func(v *iaas.Internet) error {
+						createdID = v.ID.String()
+						return nil
+					}),
+					resource.TestCheckResourceAttr(resourceName, "name", rand),
+					resource.TestCheckResourceAttr(resourceName, "netmask", "28"),
+					resource.TestCheckResourceAttr(resourceName, "band_width", "100"),
+					resource.TestCheckResourceAttr(resourceName, "enable_ipv6", "false"),
+					resource.TestCheckResourceAttrSet(resourceName, "switch_id"),
+					resource.TestCheckResourceAttrWith(resourceName, "network_address", func(v string) error {
+						if v == "" {
+							return errors.New("network_address is empty")
+						}
+						networkAddress = v
+						return nil
+					}),
+					resource.TestCheckResourceAttrSet(resourceName, "gateway"),
+					resource.TestCheckResourceAttrSet(resourceName, "min_ip_address"),
+					resource.TestCheckResourceAttrSet(resourceName, "max_ip_address"),
+					resource.TestCheckResourceAttr(resourceName, "ip_addresses.#", "11"),
+				),
+			},
+			test.StablePlanStep(basicConfig, resourceName, "id", "network_address", "ip_addresses"),
+			{
+				// 帯域とIPv6の変更は再作成ではなく更新となり、グローバルIPアドレスは維持される。
+				// 帯域の変更でAPIがIDを振り直すため、元のIDは@previous-idタグに記録される
+				Config: updateConfig,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+						test.ExpectNotUnknownValue(resourceName, tfjsonpath.New("network_address")),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraInternetExists(resourceName, &internet),
+					resource.TestCheckResourceAttr(resourceName, "name", rand+"-upd"),
+					resource.TestCheckResourceAttr(resourceName, "band_width", "250"),
+					resource.TestCheckResourceAttr(resourceName, "enable_ipv6", "true"),
+					resource.TestCheckResourceAttrSet(resourceName, "ipv6_prefix"),
+					resource.TestCheckResourceAttrWith(resourceName, "network_address", func(v string) error {
+						if v != networkAddress {
+							return fmt.Errorf("network_address is changed: %s -> %s", networkAddress, v)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttr(resourceName, "ip_addresses.#", "11"),
+					func(s *terraform.State) error {
+						return resource.TestCheckTypeSetElemAttr(resourceName, "assigned_tags.*", "@previous-id="+createdID)(s)
+					},
+				),
+			},
+			test.StablePlanStep(updateConfig, resourceName, "id", "network_address", "ip_addresses"),
+			test.ImportStep(resourceName, "timeouts"),
+		},
+	})
+}
+
+func testCheckSakuraInternetExists(n string, internet *iaas.Internet) resource.TestCheckFunc {
+	return test.CheckExists(n, test.ExistsCheck[iaas.Internet]{
+		Kind: "Internet",
+		Read: func(ctx context.Context, rs *terraform.ResourceState) (*iaas.Internet, error) {
+			return iaas.NewInternetOp(test.AccClientGetter()).Read(ctx, rs.Primary.Attributes["zone"], common.SakuraCloudID(rs.Primary.ID))
+		},
+		ID: func(v *iaas.Internet) string { return v.ID.String() },
+	}, internet)
+}
+
+var testCheckSakuraInternetDestroy = test.CheckDestroy("sakura_internet", func(ctx context.Context, rs *terraform.ResourceState) error {
+	zone := rs.Primary.Attributes["zone"]
+	if zone == "" {
+		return errors.New("zone is not set")
+	}
+	_, err := iaas.NewInternetOp(test.AccClientGetter()).Read(ctx, zone, common.SakuraCloudID(rs.Primary.ID))
+	return err
+})
+
+var testAccSakuraInternet_basic = `
+resource "sakura_internet" "foobar" {
+  name        = "{{ .name }}"
+  description = "description"
+  tags        = ["tag1", "tag2"]
+  band_width  = {{ .band_width }}
+  enable_ipv6 = {{ .enable_ipv6 }}
+}`
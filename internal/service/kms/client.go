@@ -0,0 +1,36 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kms
+
+import (
+	"context"
+
+	"github.com/sacloud/kms-api-go"
+	v1 "github.com/sacloud/kms-api-go/apis/v1"
+	smv1 "github.com/sacloud/secretmanager-api-go/apis/v1"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+)
+
+// kmsAPI はKMSのリソース/データソースが利用するAPI。テストではダブルに差し替える
+type kmsAPI interface {
+	KMSKeyOp() (kms.KeyAPI, error)
+	KMSKeyList(ctx context.Context) (keys []v1.Key, truncated bool, err error)
+	// キーの削除に失敗した場合に、キーを利用しているボールトを調べるために利用する
+	SecretManagerVaultList(ctx context.Context) (vaults []smv1.Vault, truncated bool, err error)
+	IgnoreSystemTags() bool
+	ResourceDefaults() common.ResourceDefaults
+}
+
+var _ kmsAPI = (*common.APIClient)(nil)
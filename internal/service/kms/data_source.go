@@ -16,19 +16,20 @@ package kms
 
 import (
 	"context"
-	"fmt"
-	"slices"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	kms "github.com/sacloud/kms-api-go"
 	v1 "github.com/sacloud/kms-api-go/apis/v1"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/filter"
 )
 
 type kmsDataSource struct {
-	client *v1.Client
+	client kmsAPI
 }
 
 var (
@@ -49,29 +50,43 @@ func (d *kmsDataSource) Configure(ctx context.Context, req datasource.ConfigureR
 	if apiclient == nil {
 		return
 	}
-	d.client = apiclient.KmsClient
+	d.client = apiclient
 }
 
 type kmsDataSourceModel struct {
 	common.SakuraBaseModel
-	KeyOrigin types.String `tfsdk:"key_origin"`
+	ResourceID    common.SakuraID `tfsdk:"resource_id"`
+	KeyOrigin     types.String    `tfsdk:"key_origin"`
+	IgnoreCase    types.Bool      `tfsdk:"ignore_case"`
+	NameMatch     types.String    `tfsdk:"name_match"`
+	WaitForExists types.Bool      `tfsdk:"wait_for_exists"`
+
+	Filter *common.FilterBlockModel `tfsdk:"filter"`
 }
 
 func (d *kmsDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"id":          common.SchemaDataSourceId("KMS key"),
+			"resource_id": common.SchemaDataSourceResourceID("KMS key"),
 			"description": common.SchemaDataSourceDescription("KMS key"),
 			"tags":        common.SchemaDataSourceTags("KMS key"),
 			"name": schema.StringAttribute{
 				Optional:    true,
 				Description: "The name of the KMS key.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"key_origin": schema.StringAttribute{
 				Computed:    true,
 				Description: "The key origin of the KMS key.",
 			},
+			"ignore_case":     common.SchemaDataSourceIgnoreCase("KMS key"),
+			"name_match":      common.SchemaDataSourceNameMatch("KMS key"),
+			"wait_for_exists": common.SchemaDataSourceWaitForExists("KMS key"),
 		},
+		Blocks: common.FilterSchema(nil),
 	}
 }
 
@@ -82,56 +97,93 @@ func (d *kmsDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 		return
 	}
 
-	if data.Name.IsNull() && data.ID.IsNull() {
-		resp.Diagnostics.AddError("Missing Attribute", "Either 'id' or 'name' must be specified.")
+	ctx = common.WithAPIErrorCapture(ctx)
+	// 同じ名前で検索する複数のデータソースが1回の一覧取得を共有できるよう、一覧の結果のキャッシュを利用する
+	ctx = common.WithListCache(ctx)
+
+	data.ID = common.DataSourceLookupID(data.ID, data.ResourceID)
+	// name/tags/filterはいずれも一覧からの絞り込みの条件で、指定した場合はidと組み合わせてAND条件として評価する
+	byCondition := !data.Name.IsNull() || len(data.Tags.Elements()) > 0 || data.Filter != nil
+	if !byCondition && data.ID.IsNull() {
+		resp.Diagnostics.AddError("Missing Attribute", "Either 'id', 'name', 'tags' or 'filter' must be specified.")
 		return
 	}
-
-	keyOp := kms.NewKeyOp(d.client)
-
-	var key *v1.Key
-	var err error
-	if !data.Name.IsNull() {
-		keys, err := keyOp.List(ctx)
-		if err != nil {
-			resp.Diagnostics.AddError("KMS List Error", fmt.Sprintf("could not find KMS resource: %s", err))
-			return
+	cond, err := common.ExpandFilterCondition(data.Filter, "Description", "KeyOrigin")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("filter").AtName("condition"), "Invalid Filter", err.Error())
+		return
+	}
+	cond.Name = data.Name.ValueString()
+	cond.NameMatch = data.NameMatch.ValueString()
+	cond.IgnoreCase = data.IgnoreCase.ValueBool()
+	// filterブロックのtagsと同様に、指定したすべてのタグを持つものを選ぶ
+	cond.Tags = append(cond.Tags, common.TsetToStrings(data.Tags)...)
+
+	keyOp, err := d.client.KMSKeyOp()
+	if err != nil {
+		resp.Diagnostics.AddError("KMS Client Error", err.Error())
+		return
+	}
+	var searched int
+	var truncated bool
+	lookup := func(ctx context.Context) (*v1.Key, error) {
+		// idが指定されている場合は一覧を取得せず、IDで直接参照する
+		if !data.ID.IsNull() {
+			key, err := keyOp.Read(ctx, data.ID.CanonicalValue())
+			if err != nil || !byCondition {
+				return key, err
+			}
+			return FilterKMSByName(v1.Keys{*key}, cond)
 		}
-		key, err = FilterKMSByName(keys, data.Name.ValueString())
+		keys, more, err := d.client.KMSKeyList(ctx)
 		if err != nil {
-			resp.Diagnostics.AddError("KMS Filter Error", err.Error())
-			return
+			return nil, err
 		}
+		searched, truncated = len(keys), more
+		return FilterKMSByName(keys, cond)
+	}
+
+	var key *v1.Key
+	if data.WaitForExists.ValueBool() {
+		key, err = common.WaitForExists(ctx, lookup)
 	} else {
-		key, err = keyOp.Read(ctx, data.ID.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError("KMS Read Error", "No result found")
-			return
+		key, err = lookup(ctx)
+	}
+	if truncated {
+		common.AddListTruncatedWarning(&resp.Diagnostics, "KMS keys", searched)
+	}
+	if err != nil {
+		switch {
+		case common.IsCanceled(ctx, err):
+			common.AddCanceledError(&resp.Diagnostics, "KMS Read Error", err)
+		case byCondition && common.APIStatusCode(err) == 0:
+			resp.Diagnostics.AddError("KMS Filter Error", err.Error())
+		case data.ID.IsNull():
+			common.AddAPIError(ctx, &resp.Diagnostics, "KMS List Error", err)
+		default:
+			common.AddAPIError(ctx, &resp.Diagnostics, "KMS Read Error", err)
 		}
+		return
 	}
 
-	data.UpdateBaseState(key.ID, key.Name, key.Description.Value, key.Tags)
-	data.KeyOrigin = types.StringValue(string(key.KeyOrigin))
+	data.UpdateBaseState(key.ID, key.Name, key.Description.Value, common.NormalizeTags(key.Tags))
+	data.KeyOrigin = common.FlattenEnum(ctx, "KeyOrigin", key.ID, key.KeyOrigin)
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func FilterKMSByName(keys v1.Keys, name string) (*v1.Key, error) {
-	match := slices.Collect(func(yield func(v1.Key) bool) {
-		for _, v := range keys {
-			if name != v.Name {
-				continue
-			}
-			if !yield(v) {
-				return
-			}
-		}
-	})
-	if len(match) == 0 {
-		return nil, fmt.Errorf("no result")
-	}
-	if len(match) > 1 {
-		return nil, fmt.Errorf("multiple KMS resources found with the same condition. name=%q", name)
+func FilterKMSByName(keys v1.Keys, cond filter.Condition) (*v1.Key, error) {
+	return filter.One(keys, cond, kmsKeyAttributes, "KMS key")
+}
+
+func kmsKeyAttributes(key v1.Key) filter.Attributes {
+	return filter.Attributes{
+		ID:   key.ID,
+		Name: key.Name,
+		Tags: key.Tags,
+		Fields: map[string]string{
+			"Description": key.Description.Value,
+			"KeyOrigin":   string(key.KeyOrigin),
+		},
 	}
-	return &match[0], nil
 }
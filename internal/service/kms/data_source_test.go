@@ -16,24 +16,29 @@ package kms_test
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	v1 "github.com/sacloud/kms-api-go/apis/v1"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/filter"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/service/kms"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/test"
 )
 
 func TestAccSakuraDataSourceKMS_basic(t *testing.T) {
+	test.SkipInSandbox(t, "KMS is not available in the sandbox")
+	test.ParallelTest(t)
+
 	resourceName := "data.sakura_kms.foobar"
-	rand := test.RandomName()
+	rand := test.RandomName(t, "kms")
 	var key v1.Key
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { test.AccPreCheck(t) },
 		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: test.BuildConfigWithArgs(testAccSakuraDataSourceKMS_byName, rand),
+				Config: test.BuildConfigWithMap(t, testAccSakuraDataSourceKMS_byName, map[string]any{"name": rand}),
 				Check: resource.ComposeTestCheckFunc(
 					testCheckSakuraKMSExists("sakura_kms.foobar", &key),
 					test.CheckSakuraDataSourceExists(resourceName),
@@ -46,7 +51,7 @@ func TestAccSakuraDataSourceKMS_basic(t *testing.T) {
 				),
 			},
 			{
-				Config: test.BuildConfigWithArgs(testAccSakuraDataSourceKMS_byResourceId, rand),
+				Config: test.BuildConfigWithMap(t, testAccSakuraDataSourceKMS_byResourceId, map[string]any{"name": rand}),
 				Check: resource.ComposeTestCheckFunc(
 					testCheckSakuraKMSExists("sakura_kms.foobar", &key),
 					test.CheckSakuraDataSourceExists(resourceName),
@@ -58,26 +63,63 @@ func TestAccSakuraDataSourceKMS_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "key_origin", "generated"),
 				),
 			},
+			{
+				Config: test.BuildConfigWithMap(t, testAccSakuraDataSourceKMS_byDeprecatedResourceId, map[string]any{"name": rand}),
+				Check: resource.ComposeTestCheckFunc(
+					test.CheckSakuraDataSourceExists(resourceName),
+					resource.TestCheckResourceAttrPair(resourceName, "id", "sakura_kms.foobar", "id"),
+					resource.TestCheckResourceAttr(resourceName, "name", rand),
+				),
+			},
+			{
+				Config: test.BuildConfigWithMap(t, testAccSakuraDataSourceKMS_byFilter, map[string]any{"name": rand}),
+				Check: resource.ComposeTestCheckFunc(
+					test.CheckSakuraDataSourceExists(resourceName),
+					resource.TestCheckResourceAttrPair(resourceName, "id", "sakura_kms.foobar", "id"),
+					resource.TestCheckResourceAttr(resourceName, "name", rand),
+				),
+			},
 		},
 	})
 }
 
 var testAccSakuraDataSourceKMS_byName = `
 resource "sakura_kms" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
+  description = "description"
+  tags        = ["tag1", "tag2"]
+}
+
+data "sakura_kms" "foobar" {
+  name = "{{ .name }}"
+
+  depends_on = [sakura_kms.foobar]
+}`
+
+var testAccSakuraDataSourceKMS_byFilter = `
+resource "sakura_kms" "foobar" {
+  name        = "{{ .name }}"
   description = "description"
   tags        = ["tag1", "tag2"]
 }
 
 data "sakura_kms" "foobar" {
-  name = "{{ .arg0 }}"
+  filter {
+    names = ["{{ .name }}"]
+    tags  = ["tag1", "tag2"]
+    condition {
+      name     = "KeyOrigin"
+      values   = ["generated"]
+      operator = "exact_match_or"
+    }
+  }
 
   depends_on = [sakura_kms.foobar]
 }`
 
 var testAccSakuraDataSourceKMS_byResourceId = `
 resource "sakura_kms" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description"
   tags        = ["tag1", "tag2"]
 }
@@ -88,6 +130,19 @@ data "sakura_kms" "foobar" {
   depends_on = [sakura_kms.foobar]
 }`
 
+var testAccSakuraDataSourceKMS_byDeprecatedResourceId = `
+resource "sakura_kms" "foobar" {
+  name        = "{{ .name }}"
+  description = "description"
+  tags        = ["tag1", "tag2"]
+}
+
+data "sakura_kms" "foobar" {
+  resource_id = sakura_kms.foobar.id
+
+  depends_on = [sakura_kms.foobar]
+}`
+
 func TestFilterKMSByName(t *testing.T) {
 	t.Parallel()
 
@@ -100,13 +155,20 @@ func TestFilterKMSByName(t *testing.T) {
 			Name: "test-key2",
 			Tags: []string{"tag1", "tag2"},
 		},
+		{
+			Name: "TEST-KEY1",
+		},
 	}
 
 	testCases := []struct {
-		name    string
-		keyName string
-		want    *v1.Key
-		wantErr bool
+		name       string
+		keyName    string
+		nameMatch  string
+		ignoreCase bool
+		tags       []string
+		want       *v1.Key
+		wantErr    bool
+		errNames   string
 	}{
 		{
 			name:    "found by name",
@@ -118,19 +180,94 @@ func TestFilterKMSByName(t *testing.T) {
 			keyName: "not-exist",
 			wantErr: true,
 		},
+		{
+			name:    "name is case sensitive",
+			keyName: "Test-Key2",
+			wantErr: true,
+		},
+		{
+			name:       "found by name ignoring case",
+			keyName:    "Test-Key2",
+			ignoreCase: true,
+			want:       &keys[1],
+		},
+		{
+			name:       "multiple matches ignoring case",
+			keyName:    "test-key1",
+			ignoreCase: true,
+			wantErr:    true,
+		},
+		{
+			name:       "not found ignoring case",
+			keyName:    "test-key",
+			ignoreCase: true,
+			wantErr:    true,
+		},
+		{
+			name:      "found by prefix",
+			keyName:   "test-key2",
+			nameMatch: filter.NameMatchPrefix,
+			want:      &keys[1],
+		},
+		{
+			name:      "multiple matches by prefix",
+			keyName:   "test-key",
+			nameMatch: filter.NameMatchPrefix,
+			wantErr:   true,
+		},
+		{
+			name:      "found by substring",
+			keyName:   "key2",
+			nameMatch: filter.NameMatchContains,
+			want:      &keys[1],
+		},
+		{
+			name:       "multiple matches by substring ignoring case",
+			keyName:    "KEY",
+			nameMatch:  filter.NameMatchContains,
+			ignoreCase: true,
+			wantErr:    true,
+		},
+		{
+			name: "found by tags",
+			tags: []string{"tag2"},
+			want: &keys[1],
+		},
+		{
+			name:    "found by name and tags",
+			keyName: "test-key1",
+			tags:    []string{"tag1"},
+			want:    &keys[0],
+		},
+		{
+			name:    "name and tags are combined with AND",
+			keyName: "TEST-KEY1",
+			tags:    []string{"tag1"},
+			wantErr: true,
+		},
+		{
+			name:     "multiple matches by tags",
+			tags:     []string{"tag1"},
+			wantErr:  true,
+			errNames: `"test-key1", "test-key2"`,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			got, err := kms.FilterKMSByName(keys, tc.keyName)
+			got, err := kms.FilterKMSByName(keys, filter.Condition{Name: tc.keyName, NameMatch: tc.nameMatch, IgnoreCase: tc.ignoreCase, Tags: tc.tags})
 			if tc.wantErr && err == nil {
 				t.Errorf("filterKMSByName wants error but got nil")
 			}
 			if !tc.wantErr && err != nil {
 				t.Errorf("filterKMSByName error = %v", err)
 			}
+			// 曖昧な条件の場合は、一致したリソースの名前をエラーに含める
+			if tc.errNames != "" && (err == nil || !strings.Contains(err.Error(), tc.errNames)) {
+				t.Errorf("filterKMSByName error = %v, want names %s", err, tc.errNames)
+			}
 
 			if !reflect.DeepEqual(got, tc.want) {
 				t.Errorf("filterKMSByName got = %v, want %v", got, tc.want)
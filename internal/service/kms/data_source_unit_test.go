@@ -0,0 +1,351 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	api "github.com/sacloud/api-client-go"
+	v1 "github.com/sacloud/kms-api-go/apis/v1"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newKMSDataSourceRequest(t *testing.T, name string) (datasource.ReadRequest, datasource.ReadResponse) {
+	t.Helper()
+	return newKMSDataSourceRequestWith(t, types.StringNull(), types.StringValue(name))
+}
+
+func newKMSDataSourceRequestWith(t *testing.T, id, name types.String) (datasource.ReadRequest, datasource.ReadResponse) {
+	t.Helper()
+	return newKMSDataSourceRequestWithModel(t, &kmsDataSourceModel{
+		SakuraBaseModel: common.SakuraBaseModel{
+			ID:          common.SakuraID{StringValue: id},
+			Name:        name,
+			Description: types.StringNull(),
+			Tags:        types.SetNull(types.StringType),
+		},
+		KeyOrigin:     types.StringNull(),
+		WaitForExists: types.BoolNull(),
+	})
+}
+
+func newKMSDataSourceRequestWithModel(t *testing.T, model *kmsDataSourceModel) (datasource.ReadRequest, datasource.ReadResponse) {
+	t.Helper()
+
+	ctx := context.Background()
+	var schemaResp datasource.SchemaResponse
+	NewKmsDataSource().Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError(), schemaResp.Diagnostics)
+	s := schemaResp.Schema
+
+	config := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	require.False(t, config.Set(ctx, model).HasError())
+
+	return datasource.ReadRequest{Config: tfsdk.Config{Schema: s, Raw: config.Raw}},
+		datasource.ReadResponse{State: tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}}
+}
+
+// listKeys はtotal件のキーを返すstubのlist関数を返す
+func listKeys(total int) func(ctx context.Context) (v1.Keys, error) {
+	return func(context.Context) (v1.Keys, error) {
+		keys := make(v1.Keys, 0, total)
+		for i := range total {
+			keys = append(keys, v1.Key{ID: fmt.Sprintf("%012d", 110000000000+i), Name: fmt.Sprintf("key%d", i), KeyOrigin: v1.KeyOriginEnumGenerated})
+		}
+		return keys, nil
+	}
+}
+
+func TestKMSDataSource_Read_filter(t *testing.T) {
+	ctx := context.Background()
+	keys := []v1.Key{
+		{ID: "110000000001", Name: "app-key-dev", Tags: []string{"app", "dev"}, KeyOrigin: v1.KeyOriginEnumGenerated},
+		{ID: "110000000002", Name: "app-key-prd", Tags: []string{"app", "prd"}, KeyOrigin: v1.KeyOriginEnumImported},
+		{ID: "110000000003", Name: "app-key-prd2", Tags: []string{"app", "prd"}, KeyOrigin: v1.KeyOriginEnumGenerated},
+	}
+	stub := func() *stubKMSAPI {
+		return newStubKMSAPI(&stubKeyOp{list: func(context.Context) (v1.Keys, error) {
+			return keys, nil
+		}})
+	}
+	stringList := func(values ...string) types.List {
+		elements := make([]attr.Value, 0, len(values))
+		for _, v := range values {
+			elements = append(elements, types.StringValue(v))
+		}
+		return types.ListValueMust(types.StringType, elements)
+	}
+	request := func(t *testing.T, name types.String, block *common.FilterBlockModel) (datasource.ReadRequest, datasource.ReadResponse) {
+		t.Helper()
+		if block.Names.IsNull() {
+			block.Names = types.ListNull(types.StringType)
+		}
+		if block.Tags.IsNull() {
+			block.Tags = types.SetNull(types.StringType)
+		}
+		return newKMSDataSourceRequestWithModel(t, &kmsDataSourceModel{
+			SakuraBaseModel: common.SakuraBaseModel{
+				ID:          common.NewSakuraIDNull(),
+				Name:        name,
+				Description: types.StringNull(),
+				Tags:        types.SetNull(types.StringType),
+			},
+			KeyOrigin:     types.StringNull(),
+			WaitForExists: types.BoolNull(),
+			Filter:        block,
+		})
+	}
+
+	t.Run("criteria are combined with AND", func(t *testing.T) {
+		d := &kmsDataSource{client: stub()}
+		req, resp := request(t, types.StringNull(), &common.FilterBlockModel{
+			Names: stringList("app"),
+			Tags:  types.SetValueMust(types.StringType, []attr.Value{types.StringValue("prd")}),
+			Condition: []common.FilterConditionBlockModel{
+				{Name: types.StringValue("KeyOrigin"), Values: stringList("generated"), Operator: types.StringValue("exact_match_or")},
+			},
+		})
+		d.Read(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+		var state kmsDataSourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "110000000003", state.ID.ValueString())
+	})
+
+	t.Run("combined with name", func(t *testing.T) {
+		d := &kmsDataSource{client: stub()}
+		req, resp := request(t, types.StringValue("app-key-prd"), &common.FilterBlockModel{Names: stringList("prd")})
+		d.Read(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+		var state kmsDataSourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "110000000002", state.ID.ValueString())
+	})
+
+	t.Run("multiple matches", func(t *testing.T) {
+		d := &kmsDataSource{client: stub()}
+		req, resp := request(t, types.StringNull(), &common.FilterBlockModel{Tags: types.SetValueMust(types.StringType, []attr.Value{types.StringValue("prd")})})
+		d.Read(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "KMS Filter Error", resp.Diagnostics[0].Summary())
+		assert.Equal(t, `multiple KMS key resources found with the same condition. tags=["prd"] (2 matched: "app-key-prd", "app-key-prd2")`, resp.Diagnostics[0].Detail())
+	})
+
+	t.Run("unsupported condition name", func(t *testing.T) {
+		d := &kmsDataSource{client: stub()}
+		req, resp := request(t, types.StringNull(), &common.FilterBlockModel{
+			Condition: []common.FilterConditionBlockModel{{Name: types.StringValue("Scope"), Values: stringList("shared")}},
+		})
+		d.Read(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "Invalid Filter", resp.Diagnostics[0].Summary())
+		assert.Contains(t, resp.Diagnostics[0].Detail(), `unsupported condition name "Scope"`)
+	})
+}
+
+func TestKMSDataSource_Read(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("found", func(t *testing.T) {
+		keyOp := &stubKeyOp{list: listKeys(25)}
+		d := &kmsDataSource{client: newStubKMSAPI(keyOp)}
+
+		req, resp := newKMSDataSourceRequest(t, "key15")
+		d.Read(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.Empty(t, resp.Diagnostics.Warnings())
+		assert.Equal(t, []string{"List"}, keyOp.calls)
+
+		var state kmsDataSourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "110000000015", state.ID.ValueString())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		d := &kmsDataSource{client: newStubKMSAPI(&stubKeyOp{list: listKeys(25)})}
+
+		req, resp := newKMSDataSourceRequest(t, "not-exist")
+		d.Read(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "KMS Filter Error", resp.Diagnostics[0].Summary())
+		assert.Equal(t, `no KMS key matched name="not-exist" (searched 25 KMS keys)`, resp.Diagnostics[0].Detail())
+	})
+
+	t.Run("not found in a truncated list", func(t *testing.T) {
+		client := newStubKMSAPI(&stubKeyOp{list: listKeys(25)})
+		client.keyListTruncated = true
+		d := &kmsDataSource{client: client}
+
+		req, resp := newKMSDataSourceRequest(t, "not-exist")
+		d.Read(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		// 一覧が全件ではないため、見つからなかったことに加えて警告を返す
+		require.Len(t, resp.Diagnostics.Warnings(), 1)
+		assert.Equal(t, "Incomplete List Result", resp.Diagnostics.Warnings()[0].Summary())
+		assert.Contains(t, resp.Diagnostics.Warnings()[0].Detail(), "first 25 KMS keys")
+	})
+
+	t.Run("no keys in the account", func(t *testing.T) {
+		d := &kmsDataSource{client: newStubKMSAPI(&stubKeyOp{list: listKeys(0)})}
+
+		req, resp := newKMSDataSourceRequest(t, "key0")
+		d.Read(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "KMS Filter Error", resp.Diagnostics[0].Summary())
+		assert.Equal(t, "no KMS keys exist in this account", resp.Diagnostics[0].Detail())
+	})
+
+	t.Run("name is matched exactly", func(t *testing.T) {
+		d := &kmsDataSource{client: newStubKMSAPI(&stubKeyOp{list: func(context.Context) (v1.Keys, error) {
+			return v1.Keys{
+				{ID: "110000000010", Name: "key10", KeyOrigin: v1.KeyOriginEnumGenerated},
+				{ID: "110000000001", Name: "key1", KeyOrigin: v1.KeyOriginEnumGenerated},
+			}, nil
+		}})}
+
+		req, resp := newKMSDataSourceRequest(t, "key1")
+		d.Read(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+		var state kmsDataSourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "110000000001", state.ID.ValueString())
+	})
+
+	t.Run("list fails", func(t *testing.T) {
+		d := &kmsDataSource{client: newStubKMSAPI(&stubKeyOp{list: func(context.Context) (v1.Keys, error) {
+			return nil, api.NewAPIError(http.StatusInternalServerError, "", errors.New("internal server error"))
+		}})}
+
+		req, resp := newKMSDataSourceRequest(t, "key0")
+		d.Read(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "KMS List Error", resp.Diagnostics[0].Summary())
+		assert.Contains(t, resp.Diagnostics[0].Detail(), "internal server error")
+		assert.NotContains(t, resp.Diagnostics[0].Detail(), "no KMS key")
+	})
+}
+
+func TestKMSDataSource_Read_byID(t *testing.T) {
+	ctx := context.Background()
+	newKeyOp := func() *stubKeyOp {
+		return &stubKeyOp{read: func(_ context.Context, id string) (*v1.Key, error) {
+			return &v1.Key{ID: id, Name: "key1", KeyOrigin: v1.KeyOriginEnumGenerated}, nil
+		}}
+	}
+
+	t.Run("reads by id without listing", func(t *testing.T) {
+		keyOp := newKeyOp()
+		d := &kmsDataSource{client: newStubKMSAPI(keyOp)}
+
+		req, resp := newKMSDataSourceRequestWith(t, types.StringValue("110000000001"), types.StringNull())
+		d.Read(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.Equal(t, []string{"Read"}, keyOp.calls)
+
+		var state kmsDataSourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "key1", state.Name.ValueString())
+	})
+
+	t.Run("reads by id converted from a number", func(t *testing.T) {
+		keyOp := newKeyOp()
+		d := &kmsDataSource{client: newStubKMSAPI(keyOp)}
+
+		req, resp := newKMSDataSourceRequestWith(t, types.StringValue("1.10000000001e+11"), types.StringNull())
+		d.Read(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+		// APIには正規の表記のIDを渡す
+		var state kmsDataSourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "110000000001", state.ID.ValueString())
+	})
+
+	t.Run("name is checked against the key read by id", func(t *testing.T) {
+		keyOp := newKeyOp()
+		d := &kmsDataSource{client: newStubKMSAPI(keyOp)}
+
+		req, resp := newKMSDataSourceRequestWith(t, types.StringValue("110000000001"), types.StringValue("key2"))
+		d.Read(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "KMS Filter Error", resp.Diagnostics[0].Summary())
+		assert.Equal(t, `no KMS key matched name="key2" (searched 1 KMS key)`, resp.Diagnostics[0].Detail())
+		assert.Equal(t, []string{"Read"}, keyOp.calls)
+	})
+
+	t.Run("reads by deprecated resource_id", func(t *testing.T) {
+		keyOp := newKeyOp()
+		d := &kmsDataSource{client: newStubKMSAPI(keyOp)}
+
+		req, resp := newKMSDataSourceRequestWith(t, types.StringNull(), types.StringNull())
+		config := tfsdk.State{Schema: req.Config.Schema, Raw: req.Config.Raw}
+		require.False(t, config.SetAttribute(ctx, path.Root("resource_id"), common.NewSakuraIDValue("110000000001")).HasError())
+		req.Config.Raw = config.Raw
+		d.Read(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.Equal(t, []string{"Read"}, keyOp.calls)
+
+		// idはresource_idで参照した場合も設定される
+		var state kmsDataSourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "110000000001", state.ID.ValueString())
+		assert.Equal(t, "110000000001", state.ResourceID.ValueString())
+	})
+
+	t.Run("read fails", func(t *testing.T) {
+		keyOp := &stubKeyOp{read: func(context.Context, string) (*v1.Key, error) {
+			return nil, api.NewAPIError(http.StatusNotFound, "", errors.New("not found"))
+		}}
+		d := &kmsDataSource{client: newStubKMSAPI(keyOp)}
+
+		req, resp := newKMSDataSourceRequestWith(t, types.StringValue("110000000001"), types.StringValue("key1"))
+		d.Read(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "KMS Read Error", resp.Diagnostics[0].Summary())
+		assert.Equal(t, []string{"Read"}, keyOp.calls)
+	})
+}
+
+func TestKMSDataSource_idValidation(t *testing.T) {
+	ctx := context.Background()
+	var resp datasource.SchemaResponse
+	NewKmsDataSource().Schema(ctx, datasource.SchemaRequest{}, &resp)
+	idType, ok := resp.Schema.Attributes["id"].GetType().(common.SakuraIDType)
+	require.True(t, ok)
+
+	v, diags := idType.ValueFromString(ctx, types.StringValue("my-key"))
+	require.False(t, diags.HasError(), diags)
+
+	vResp := &xattr.ValidateAttributeResponse{}
+	v.(common.SakuraID).ValidateAttribute(ctx, xattr.ValidateAttributeRequest{Path: path.Root("id")}, vResp)
+	require.True(t, vResp.Diagnostics.HasError())
+	assert.Equal(t, `expected a SakuraCloud resource ID (numeric), got "my-key" — did you mean to use the name attribute?`, vResp.Diagnostics.Errors()[0].Detail())
+}
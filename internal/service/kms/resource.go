@@ -18,34 +18,58 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
-	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 
-	api "github.com/sacloud/api-client-go"
 	"github.com/sacloud/kms-api-go"
 	v1 "github.com/sacloud/kms-api-go/apis/v1"
+	smv1 "github.com/sacloud/secretmanager-api-go/apis/v1"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	sacloudvalidator "github.com/sacloud/terraform-provider-sakuracloud/internal/validator"
 )
 
 type kmsResource struct {
-	client *v1.Client
+	client kmsAPI
 }
 
 var (
 	_ resource.Resource                = &kmsResource{}
 	_ resource.ResourceWithConfigure   = &kmsResource{}
 	_ resource.ResourceWithImportState = &kmsResource{}
+	_ resource.ResourceWithModifyPlan  = &kmsResource{}
 )
 
+// kmsAttributePaths はAPIのフィールド名とエラーを紐付ける属性の対応
+var kmsAttributePaths = common.AttributePaths{
+	"Name":        path.Root("name"),
+	"Description": path.Root("description"),
+	"Tags":        path.Root("tags"),
+	"KeyOrigin":   path.Root("key_origin"),
+	"PlainKey":    path.Root("plain_key"),
+}
+
+// kmsKeyOrigins はkey_originに指定できる値。大文字小文字を区別せずに受け付ける
+var kmsKeyOrigins = sacloudvalidator.NewStringEnum(string(v1.KeyOriginEnumGenerated), string(v1.KeyOriginEnumImported))
+
+// plainKeyUnchanged はplain_keyの変更でキーを置き換えるかを判定する。
+// インポート後に設定からplain_keyを削除してもキーは変わらないため、未指定への変更では置き換えない。
+// また、plain_keyはAPIから読み出せずterraform importの直後はnullとなるため、未設定からの変更でも置き換えない
+func plainKeyUnchanged(plan, state string) bool {
+	return plan == "" || state == "" || plan == state
+}
+
 func NewKMSResource() resource.Resource {
 	return &kmsResource{}
 }
@@ -59,38 +83,49 @@ func (r *kmsResource) Configure(ctx context.Context, req resource.ConfigureReque
 	if apiclient == nil {
 		return
 	}
-	r.client = apiclient.KmsClient
+	r.client = apiclient
 }
 
 type kmsResourceModel struct {
 	common.SakuraBaseModel
-	KeyOrigin types.String   `tfsdk:"key_origin"`
-	PlainKey  types.String   `tfsdk:"plain_key"`
-	Timeouts  timeouts.Value `tfsdk:"timeouts"`
+	common.SakuraGlobalZoneModel
+	NamePrefix types.String                 `tfsdk:"name_prefix"`
+	KeyOrigin  common.CaseInsensitiveString `tfsdk:"key_origin"`
+	PlainKey   types.String                 `tfsdk:"plain_key"`
+	Timeouts   timeouts.Value               `tfsdk:"timeouts"`
 }
 
 func (r *kmsResource) Schema(ctx context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"id":          common.SchemaResourceId("KMS key"),
-			"name":        common.SchemaResourceName("KMS key"),
+			"name":        common.SchemaResourceNameWithPrefix("KMS key"),
+			"name_prefix": common.SchemaResourceNamePrefix("KMS key"),
 			"description": common.SchemaResourceDescription("KMS key"),
 			"tags":        common.SchemaResourceTags("KMS key"),
+			"zone":        common.SchemaResourceGlobalZone("KMS key"),
 			"key_origin": schema.StringAttribute{
+				CustomType:  common.CaseInsensitiveStringType{},
 				Optional:    true,
 				Computed:    true,
 				Default:     stringdefault.StaticString("generated"),
-				Description: "Key origin of the KMS key. 'generated' or 'imported'. Default is 'generated'.",
+				Description: "Key origin of the KMS key. 'generated' or 'imported' (case-insensitive). Default is 'generated'.",
 				Validators: []validator.String{
-					stringvalidator.OneOf("generated", "imported"),
+					kmsKeyOrigins.OneOf(),
+				},
+				PlanModifiers: []planmodifier.String{
+					common.RequiresReplaceWithReason("Changing key_origin replaces the KMS key. The current key is deleted, and data encrypted with it can no longer be decrypted.", strings.EqualFold),
 				},
 			},
 			"plain_key": schema.StringAttribute{
 				Optional:    true,
 				Sensitive:   true,
 				Description: "Plain key for imported KMS key. Required when `key_origin` is 'imported'.",
+				PlanModifiers: []planmodifier.String{
+					common.RequiresReplaceWithReason("Changing plain_key replaces the KMS key with a newly imported key. The current key is deleted, and data encrypted with it can no longer be decrypted.", plainKeyUnchanged),
+				},
 			},
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
 				Create: true, Update: true, Delete: true,
 			}),
 		},
@@ -101,6 +136,10 @@ func (r *kmsResource) ImportState(ctx context.Context, req resource.ImportStateR
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *kmsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanResourceDefaults(ctx, r.client, req, resp)
+}
+
 func (r *kmsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan kmsResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -110,24 +149,46 @@ func (r *kmsResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
+	plan.Name = types.StringValue(common.GenerateName(plan.Name, plan.NamePrefix))
 	keyReq, err := expandKMSCreateKey(&plan)
 	if err != nil {
 		resp.Diagnostics.AddError("KMS Create Key Expansion Error", err.Error())
 		return
 	}
 
-	keyOp := kms.NewKeyOp(r.client)
+	keyOp, err := r.client.KMSKeyOp()
+	if err != nil {
+		resp.Diagnostics.AddError("KMS Client Error", err.Error())
+		return
+	}
 	createdKey, err := keyOp.Create(ctx, keyReq)
 	if err != nil {
-		resp.Diagnostics.AddError("KMS Create Error", err.Error())
+		common.AddAPIAttributeError(ctx, &resp.Diagnostics, "KMS Create Error", err, kmsAttributePaths)
+		return
+	}
+	resp.Diagnostics.Append(common.SetCreatedID(ctx, &resp.State, createdKey.ID)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	plan.UpdateBaseState(createdKey.ID, createdKey.Name, createdKey.Description.Value, createdKey.Tags)
-	plan.KeyOrigin = types.StringValue(string(createdKey.KeyOrigin))
+	plan.UpdateBaseState(createdKey.ID, createdKey.Name, createdKey.Description.Value, common.ManagedTags(createdKey.Tags, plan.Tags, r.client.IgnoreSystemTags()))
+	plan.UpdateGlobalZoneState()
+	plan.KeyOrigin = common.CaseInsensitiveString{StringValue: common.FlattenEnum(ctx, "KeyOrigin", createdKey.ID, createdKey.KeyOrigin)}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// 作成直後はGETで参照できない場合があるため、同じapply内のデータソースなどから参照できるようになるまで待つ
+	_, err = common.WaitForExists(ctx, func(ctx context.Context) (*v1.Key, error) {
+		return keyOp.Read(ctx, createdKey.ID)
+	})
+	if err != nil {
+		common.AddAPIError(ctx, &resp.Diagnostics, "KMS Create Error", fmt.Errorf("waiting for SakuraCloud KMS key[%s] to be visible is failed: %w", createdKey.ID, err))
+	}
 }
 
 func (r *kmsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -137,45 +198,83 @@ func (r *kmsResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 		return
 	}
 
-	key := getKMS(ctx, r.client, data.ID.ValueString(), &resp.State, &resp.Diagnostics)
+	ctx = common.WithAPIErrorCapture(ctx)
+	ctx, cond := common.WithConditionalRead(ctx, req.Private)
+
+	keyOp, err := r.client.KMSKeyOp()
+	if err != nil {
+		resp.Diagnostics.AddError("KMS Client Error", err.Error())
+		return
+	}
+	// 前回のReadから変更されていない(304)場合は前回のレスポンスでstateを更新する
+	key := getKMS(ctx, keyOp, data.ID.ValueString(), &resp.State, &resp.Diagnostics)
 	if key == nil {
 		return
 	}
 
-	data.UpdateBaseState(key.ID, key.Name, key.Description.Value, key.Tags)
-	data.KeyOrigin = types.StringValue(string(key.KeyOrigin))
+	data.UpdateBaseState(key.ID, key.Name, key.Description.Value, common.ManagedTags(key.Tags, data.Tags, r.client.IgnoreSystemTags()))
+	data.UpdateGlobalZoneState()
+	data.KeyOrigin = common.CaseInsensitiveString{StringValue: common.FlattenEnum(ctx, "KeyOrigin", key.ID, key.KeyOrigin)}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(cond.Save(ctx, resp.Private)...)
 }
 
 func (r *kmsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan kmsResourceModel
+	var plan, state kmsResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// timeoutsやplain_keyのみの変更、tagsのnullから空への変更などではAPIを呼び出さない
+	if !plan.HasChangeFrom(&state.SakuraBaseModel) {
+		tflog.Debug(ctx, "skipping KMS key update because no API attributes are changed", map[string]any{"id": state.ID.ValueString()})
+		plan.ID = state.ID
+		plan.KeyOrigin = state.KeyOrigin
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
-	keyOp := kms.NewKeyOp(r.client)
-	key := getKMS(ctx, r.client, plan.ID.ValueString(), &resp.State, &resp.Diagnostics)
+	keyOp, err := r.client.KMSKeyOp()
+	if err != nil {
+		resp.Diagnostics.AddError("KMS Client Error", err.Error())
+		return
+	}
+	key := getKMS(ctx, keyOp, plan.ID.ValueString(), &resp.State, &resp.Diagnostics)
 	if key == nil {
 		return
 	}
+	// 更新リクエストにはkey_originを含める必要があるため、未知の値のままでは置き換えた値で送信してしまう
+	if origin, ok := common.UnknownEnumValue(ctx, "KeyOrigin", key.ID); ok {
+		resp.Diagnostics.AddAttributeError(path.Root("key_origin"), "KMS Update Error",
+			fmt.Sprintf("KMS key[%s] has key_origin %q that this provider version does not support. Upgrade the provider to update this key.", key.ID, origin))
+		return
+	}
 
-	_, err := keyOp.Update(ctx, key.ID, expandKMSUpdateKey(&plan, key))
+	// name/description/tagsは1回の更新APIでまとめて送信する。属性ごとに更新APIを呼び出さないこと
+	updated, err := keyOp.Update(ctx, key.ID, expandKMSUpdateKey(&plan, state.Tags, key, r.client.IgnoreSystemTags()))
 	if err != nil {
-		resp.Diagnostics.AddError("KMS Update Error", err.Error())
+		common.AddAPIAttributeError(ctx, &resp.Diagnostics, "KMS Update Error", err, kmsAttributePaths)
 		return
 	}
 
-	key = getKMS(ctx, r.client, plan.ID.ValueString(), &resp.State, &resp.Diagnostics)
-	if key == nil {
-		return
+	// 更新APIのレスポンスがキー全体を含んでいればそれをstateに反映し、不完全な場合のみ改めて参照する
+	key = updated
+	if !isCompleteKey(key, plan.ID.ValueString()) {
+		key = getKMS(ctx, keyOp, plan.ID.ValueString(), &resp.State, &resp.Diagnostics)
+		if key == nil {
+			return
+		}
 	}
 
-	plan.UpdateBaseState(key.ID, key.Name, key.Description.Value, key.Tags)
-	plan.KeyOrigin = types.StringValue(string(key.KeyOrigin))
+	plan.UpdateBaseState(key.ID, key.Name, key.Description.Value, common.ManagedTags(key.Tags, plan.Tags, r.client.IgnoreSystemTags()))
+	plan.UpdateGlobalZoneState()
+	plan.KeyOrigin = common.CaseInsensitiveString{StringValue: common.FlattenEnum(ctx, "KeyOrigin", key.ID, key.KeyOrigin)}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -188,38 +287,85 @@ func (r *kmsResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 
 	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
-	keyOp := kms.NewKeyOp(r.client)
-	key := getKMS(ctx, r.client, state.ID.ValueString(), &resp.State, &resp.Diagnostics)
+	keyOp, err := r.client.KMSKeyOp()
+	if err != nil {
+		resp.Diagnostics.AddError("KMS Client Error", err.Error())
+		return
+	}
+	key := getKMS(ctx, keyOp, state.ID.ValueString(), &resp.State, &resp.Diagnostics)
 	if key == nil {
 		return
 	}
 
-	if err := keyOp.Delete(ctx, key.ID); err != nil {
-		resp.Diagnostics.AddError("KMS Delete Error", err.Error())
+	var users []smv1.Vault
+	err = common.DefaultBackoff.Retry(ctx, func(err error) bool {
+		if !common.IsConflict(err) {
+			return false
+		}
+		// ボールトから利用されている場合はリトライしても解消しない
+		users = vaultsUsingKey(ctx, r.client, key.ID)
+		return len(users) == 0
+	}, func() error {
+		return keyOp.Delete(ctx, key.ID)
+	})
+	if err != nil {
+		if len(users) == 0 && !common.IsConflict(err) && !common.IsCanceled(ctx, err) {
+			users = vaultsUsingKey(ctx, r.client, key.ID)
+		}
+		if len(users) > 0 {
+			err = fmt.Errorf("key is still used by vaults: %s: %w", formatVaults(users), err)
+		}
+		common.AddAPIError(ctx, &resp.Diagnostics, "KMS Delete Error", err)
 		return
 	}
 }
 
-func getKMS(ctx context.Context, client *v1.Client, id string, state *tfsdk.State, diags *diag.Diagnostics) *v1.Key {
-	keyOp := kms.NewKeyOp(client)
+// vaultsUsingKey はkeyIDのキーを利用しているシークレットマネージャのボールトを返す。
+// キーの削除に失敗した原因を示すためのもので、ボールトの一覧を取得できなかった場合はログに残してnilを返す
+func vaultsUsingKey(ctx context.Context, client kmsAPI, keyID string) []smv1.Vault {
+	vaults, _, err := client.SecretManagerVaultList(common.WithoutAPIErrorCapture(ctx))
+	if err != nil {
+		tflog.Warn(ctx, "could not list SecretManager vaults to find vaults using the KMS key", map[string]any{"id": keyID, "error": err.Error()})
+		return nil
+	}
+	return slices.DeleteFunc(vaults, func(v smv1.Vault) bool { return v.KmsKeyID != keyID })
+}
+
+func formatVaults(vaults []smv1.Vault) string {
+	names := make([]string, 0, len(vaults))
+	for _, v := range vaults {
+		names = append(names, fmt.Sprintf("%s (id %s)", v.Name, v.ID))
+	}
+	return strings.Join(names, ", ")
+}
+
+// isCompleteKey はAPIのレスポンスのkeyが、stateに反映できるidのキーの全体を含んでいるかを返す
+func isCompleteKey(key *v1.Key, id string) bool {
+	return key != nil && key.ID == id && key.Name != "" && key.KeyOrigin != ""
+}
+
+func getKMS(ctx context.Context, keyOp kms.KeyAPI, id string, state *tfsdk.State, diags *diag.Diagnostics) *v1.Key {
 	key, err := keyOp.Read(ctx, id)
 	if err != nil {
-		if api.IsNotFoundError(err) {
-			state.RemoveResource(ctx)
+		if common.HandleNotFoundOnRead(ctx, err, state, "KMS key", id) {
 			return nil
 		}
-		diags.AddError("Get KMS Key Error", fmt.Sprintf("could not read SakuraCloud KMS key[%s]: %s", id, err))
+		common.AddAPIError(ctx, diags, "Get KMS Key Error", fmt.Errorf("could not read SakuraCloud KMS key[%s]: %w", id, err))
 		return nil
 	}
+	if common.IsNotModified(ctx) {
+		tflog.Debug(ctx, "KMS key is not modified since the last read, using the cached response", map[string]any{"id": id})
+	}
 
 	return key
 }
 
 func expandKMSCreateKey(model *kmsResourceModel) (v1.CreateKey, error) {
-	keyOrig := model.KeyOrigin.ValueString()
+	keyOrig := kmsKeyOrigins.Canonical(model.KeyOrigin.ValueString())
 	var req v1.CreateKey
-	if keyOrig == "generated" {
+	if keyOrig == string(v1.KeyOriginEnumGenerated) {
 		req = v1.CreateKey{
 			Name:      model.Name.ValueString(),
 			KeyOrigin: v1.KeyOriginEnumGenerated,
@@ -237,7 +383,7 @@ func expandKMSCreateKey(model *kmsResourceModel) (v1.CreateKey, error) {
 	}
 
 	if !model.Tags.IsNull() {
-		req.Tags = common.TsetToStrings(model.Tags)
+		req.Tags = common.ExpandTags(model.Tags)
 	}
 	if !model.Description.IsNull() {
 		req.Description = v1.NewOptString(model.Description.ValueString())
@@ -246,14 +392,15 @@ func expandKMSCreateKey(model *kmsResourceModel) (v1.CreateKey, error) {
 	return req, nil
 }
 
-func expandKMSUpdateKey(model *kmsResourceModel, before *v1.Key) v1.Key {
+// expandKMSUpdateKey は更新リクエストを返す。ignoreSystemTagsがtrueの場合、Terraform外でbeforeに付与されたシステムタグを保持する
+func expandKMSUpdateKey(model *kmsResourceModel, priorTags types.Set, before *v1.Key, ignoreSystemTags bool) v1.Key {
 	req := v1.Key{
 		Name:      model.Name.ValueString(),
 		KeyOrigin: before.KeyOrigin,
 	}
 
 	if !model.Tags.IsNull() {
-		req.Tags = common.TsetToStrings(model.Tags)
+		req.Tags = common.MergeSystemTags(common.ExpandTags(model.Tags), before.Tags, priorTags, ignoreSystemTags)
 	}
 	if !model.Description.IsNull() {
 		req.Description = v1.NewOptString(model.Description.ValueString())
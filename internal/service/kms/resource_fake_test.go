@@ -0,0 +1,363 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kms_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test/fake"
+)
+
+func TestFakeSakuraResourceKMS_basic(t *testing.T) {
+	test.FakePreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+
+	resourceName := "sakura_kms.foobar"
+	rand := test.RandomName(t, "kms")
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckFakeKMSDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + test.BuildConfigWithMap(t, testAccSakuraKMS_basic, map[string]any{"name": rand}),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckFakeKMSExists(server, resourceName),
+					resource.TestCheckResourceAttr(resourceName, "name", rand),
+					resource.TestCheckResourceAttr(resourceName, "description", "description"),
+					resource.TestCheckResourceAttr(resourceName, "tags.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "key_origin", "generated"),
+					resource.TestCheckResourceAttr(resourceName, "zone", "global"),
+				),
+			},
+			{
+				Config: server.ProviderConfig() + test.BuildConfigWithMap(t, testAccSakuraKMS_update, map[string]any{"name": rand}),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckFakeKMSExists(server, resourceName),
+					resource.TestCheckResourceAttr(resourceName, "description", "description-updated"),
+					resource.TestCheckResourceAttr(resourceName, "tags.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "tags.0", "tag1-upd"),
+				),
+			},
+			test.ImportStep(resourceName),
+			test.ImportPlanStep(resourceName),
+		},
+	})
+}
+
+func TestFakeSakuraResourceKMS_namePrefix(t *testing.T) {
+	test.FakePreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+
+	resourceName := "sakura_kms.foobar"
+	rand := test.RandomName(t, "kms")
+	namePattern := regexp.MustCompile(fmt.Sprintf(`^%s-[a-z0-9]{8}$`, rand))
+	var firstName string
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckFakeKMSDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + test.BuildConfigWithMap(t, testAccSakuraKMS_namePrefix, map[string]any{"name": rand, "description": "description"}),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckFakeKMSExists(server, resourceName),
+					resource.TestMatchResourceAttr(resourceName, "name", namePattern),
+					resource.TestCheckResourceAttr(resourceName, "name_prefix", rand+"-"),
+					func(s *terraform.State) error {
+						firstName = s.RootModule().Resources[resourceName].Primary.Attributes["name"]
+						return nil
+					},
+				),
+			},
+			// 生成した名前は以降のapplyでも変わらない
+			test.StablePlanStep(server.ProviderConfig()+test.BuildConfigWithMap(t, testAccSakuraKMS_namePrefix, map[string]any{"name": rand, "description": "description"}), resourceName, "name"),
+			{
+				Config: server.ProviderConfig() + test.BuildConfigWithMap(t, testAccSakuraKMS_namePrefix, map[string]any{"name": rand, "description": "description-updated"}),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "description", "description-updated"),
+					resource.TestCheckResourceAttrWith(resourceName, "name", func(name string) error {
+						if name != firstName {
+							return fmt.Errorf("name is changed from %q to %q", firstName, name)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestFakeSakuraResourceKMS_resourceDefaults(t *testing.T) {
+	test.FakePreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+
+	resourceName := "sakura_kms.foobar"
+	rand := test.RandomName(t, "kms")
+	config := func(description string) string {
+		return server.ProviderConfig(`resource_defaults {
+    description = "managed by terraform"
+  }`) + test.BuildConfigWithMap(t, testAccSakuraKMS_description, map[string]any{"name": rand, "description": description})
+	}
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckFakeKMSDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue(resourceName, tfjsonpath.New("description"), knownvalue.StringExact("managed by terraform")),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					testCheckFakeKMSExists(server, resourceName),
+					resource.TestCheckResourceAttr(resourceName, "description", "managed by terraform"),
+				),
+			},
+			test.StablePlanStep(config(""), resourceName, "description"),
+			// リソースで指定した値がresource_defaultsより優先される
+			{
+				Config: config(`description = "description"`),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue(resourceName, tfjsonpath.New("description"), knownvalue.StringExact("description")),
+					},
+				},
+				Check: resource.TestCheckResourceAttr(resourceName, "description", "description"),
+			},
+		},
+	})
+}
+
+func TestFakeSakuraResourceKMS_defaultTags(t *testing.T) {
+	test.FakePreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+
+	resourceName := "sakura_kms.foobar"
+	rand := test.RandomName(t, "kms")
+	config := func(defaultTags, tags string) string {
+		return server.ProviderConfig(defaultTags) + test.BuildConfigWithMap(t, testAccSakuraKMS_tags, map[string]any{"name": rand, "tags": tags})
+	}
+	defaultTags := `default_tags = ["env=prod", "managed-by=terraform"]`
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckFakeKMSDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: config(defaultTags, ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue(resourceName, tfjsonpath.New("tags"), knownvalue.SetExact([]knownvalue.Check{
+							knownvalue.StringExact("env=prod"),
+							knownvalue.StringExact("managed-by=terraform"),
+						})),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					testCheckFakeKMSExists(server, resourceName),
+					resource.TestCheckResourceAttr(resourceName, "tags.#", "2"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "tags.*", "env=prod"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "tags.*", "managed-by=terraform"),
+				),
+			},
+			test.StablePlanStep(config(defaultTags, ""), resourceName, "tags"),
+			// リソースのタグと合わせて付与し、default_tagsと同じタグは重複させない
+			{
+				Config: config(defaultTags, `tags = ["tag1", "env=prod"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "tags.#", "3"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "tags.*", "tag1"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "tags.*", "env=prod"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "tags.*", "managed-by=terraform"),
+				),
+			},
+			test.StablePlanStep(config(defaultTags, `tags = ["tag1", "env=prod"]`), resourceName, "tags"),
+			// default_tagsを削除するとリソースからも取り除かれる
+			{
+				Config: config("", `tags = ["tag1"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "tags.#", "1"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "tags.*", "tag1"),
+				),
+			},
+		},
+	})
+}
+
+func TestFakeSakuraResourceKMS_keyOriginForcesReplacement(t *testing.T) {
+	test.FakePreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+
+	resourceName := "sakura_kms.foobar"
+	rand := test.RandomName(t, "kms")
+	config := func(keyOrigin, plainKey string) string {
+		return server.ProviderConfig() + test.BuildConfigWithMap(t, testAccSakuraKMS_keyOrigin, map[string]any{"name": rand, "key_origin": keyOrigin, "plain_key": plainKey})
+	}
+	plainKey := `plain_key = "AfL5zzjD4RgeFQm3vvAADwPNrurNUc616877wsa8v4w="`
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckFakeKMSDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: config("generated", ""),
+				Check:  testCheckFakeKMSExists(server, resourceName),
+			},
+			{
+				Config: config("imported", plainKey),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.TestCheckResourceAttr(resourceName, "key_origin", "imported"),
+			},
+			// 大文字小文字の違いやplain_keyの削除ではキーを置き換えない
+			{
+				Config: config("IMPORTED", ""),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestFakeSakuraResourceKMS_emptyTags(t *testing.T) {
+	test.FakePreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+
+	resourceName := "sakura_kms.foobar"
+	rand := test.RandomName(t, "kms")
+	config := func(tags string) string {
+		return server.ProviderConfig() + test.BuildConfigWithMap(t, testAccSakuraKMS_tags, map[string]any{"name": rand, "tags": tags})
+	}
+	// 未指定、nullと空のlistを切り替えても差分にならない
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckFakeKMSDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: config(""),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckFakeKMSExists(server, resourceName),
+					resource.TestCheckResourceAttr(resourceName, "tags.#", "0"),
+				),
+			},
+			test.StablePlanStep(config("tags = []"), resourceName, "tags"),
+			test.StablePlanStep(config("tags = null"), resourceName, "tags"),
+			{
+				Config: config("tags = []"),
+				Check:  resource.TestCheckResourceAttr(resourceName, "tags.#", "0"),
+			},
+			test.StablePlanStep(config(""), resourceName, "tags"),
+			test.StablePlanStep(config("tags = null"), resourceName, "tags"),
+		},
+	})
+}
+
+func TestFakeSakuraResourceKMS_imported(t *testing.T) {
+	test.FakePreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+
+	resourceName := "sakura_kms.foobar2"
+	rand := test.RandomName(t, "kms")
+	config := server.ProviderConfig() + test.BuildConfigWithMap(t, testAccSakuraKMS_imported, map[string]any{"name": rand})
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckFakeKMSDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckFakeKMSExists(server, resourceName),
+					resource.TestCheckResourceAttr(resourceName, "key_origin", "imported"),
+				),
+			},
+			// plain_keyはAPIから読み出せない
+			test.ImportStep(resourceName, "plain_key"),
+			{
+				ResourceName:       resourceName,
+				ImportState:        true,
+				ImportStatePersist: true,
+			},
+			// import後のstateではplain_keyがnullとなるが、設定のplain_keyでキーを置き換えない
+			{
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr(resourceName, "plain_key", "AfL5zzjD4RgeFQm3vvAADwPNrurNUc616877wsa8v4w="),
+			},
+			test.StablePlanStep(config, resourceName, "id"),
+		},
+	})
+}
+
+func testCheckFakeKMSDestroy(server *fake.Server) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "sakura_kms" || rs.Primary.ID == "" {
+				continue
+			}
+			if _, ok := server.KMS.Key(rs.Primary.ID); ok {
+				return fmt.Errorf("still exists KMS: %s", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+func testCheckFakeKMSExists(server *fake.Server, n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+
+		key, ok := server.KMS.Key(rs.Primary.ID)
+		if !ok {
+			return fmt.Errorf("not found KMS: %s", rs.Primary.ID)
+		}
+		if key.Name != rs.Primary.Attributes["name"] {
+			return fmt.Errorf("unexpected KMS name: want %q, got %q", rs.Primary.Attributes["name"], key.Name)
+		}
+		return nil
+	}
+}
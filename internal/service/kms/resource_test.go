@@ -16,40 +16,46 @@ package kms_test
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
-	"github.com/sacloud/kms-api-go"
 	v1 "github.com/sacloud/kms-api-go/apis/v1"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/test"
 )
 
 func TestAccSakuraResourceKMS_basic(t *testing.T) {
+	test.SkipInSandbox(t, "KMS is not available in the sandbox")
+	test.ParallelTest(t)
+
 	resourceName := "sakura_kms.foobar"
-	rand := test.RandomName()
+	rand := test.RandomName(t, "kms")
 	var key v1.Key
+	basicConfig := test.BuildConfigWithMap(t, testAccSakuraKMS_basic, map[string]any{"name": rand})
+	updateConfig := test.BuildConfigWithMap(t, testAccSakuraKMS_update, map[string]any{"name": rand})
+
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { test.AccPreCheck(t) },
 		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
 		CheckDestroy:             testCheckSakuraKMSDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: test.BuildConfigWithArgs(testAccSakuraKMS_basic, rand),
+				Config: basicConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckSakuraKMSExists(resourceName, &key),
 					resource.TestCheckResourceAttr(resourceName, "name", rand),
 					resource.TestCheckResourceAttr(resourceName, "description", "description"),
+					resource.TestCheckResourceAttr(resourceName, "zone", "global"),
 					resource.TestCheckResourceAttr(resourceName, "tags.#", "2"),
 					resource.TestCheckResourceAttr(resourceName, "tags.0", "tag1"),
 					resource.TestCheckResourceAttr(resourceName, "tags.1", "tag2"),
 					resource.TestCheckResourceAttr(resourceName, "key_origin", "generated"),
 				),
 			},
+			test.StablePlanStep(basicConfig, resourceName, "id", "key_origin"),
 			{
-				Config: test.BuildConfigWithArgs(testAccSakuraKMS_update, rand),
+				Config: updateConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckSakuraKMSExists(resourceName, &key),
 					resource.TestCheckResourceAttr(resourceName, "name", rand),
@@ -59,22 +65,31 @@ func TestAccSakuraResourceKMS_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "key_origin", "generated"),
 				),
 			},
+			test.StablePlanStep(updateConfig, resourceName, "id", "key_origin"),
+			test.ImportStep(resourceName),
+			test.ImportPlanStep(resourceName),
 		},
 	})
 }
 
 func TestAccSakuraResourceKMS_imported(t *testing.T) {
+	test.SkipInSandbox(t, "KMS is not available in the sandbox")
+	test.ParallelTest(t)
+
 	resourceName := "sakura_kms.foobar2"
-	rand := test.RandomName()
+	rand := test.RandomName(t, "kms")
 
 	var key v1.Key
+	importedConfig := test.BuildConfigWithMap(t, testAccSakuraKMS_imported, map[string]any{"name": rand})
+	importedUpdateConfig := test.BuildConfigWithMap(t, testAccSakuraKMS_importedUpdate, map[string]any{"name": rand})
+
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { test.AccPreCheck(t) },
 		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
 		CheckDestroy:             testCheckSakuraKMSDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: test.BuildConfigWithArgs(testAccSakuraKMS_imported, rand),
+				Config: importedConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckSakuraKMSExists(resourceName, &key),
 					resource.TestCheckResourceAttr(resourceName, "name", rand),
@@ -85,8 +100,9 @@ func TestAccSakuraResourceKMS_imported(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "key_origin", "imported"),
 				),
 			},
+			test.StablePlanStep(importedConfig, resourceName, "id", "key_origin"),
 			{
-				Config: test.BuildConfigWithArgs(testAccSakuraKMS_importedUpdate, rand),
+				Config: importedUpdateConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckSakuraKMSExists(resourceName, &key),
 					resource.TestCheckResourceAttr(resourceName, "name", rand),
@@ -96,76 +112,158 @@ func TestAccSakuraResourceKMS_imported(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "key_origin", "imported"),
 				),
 			},
+			test.StablePlanStep(importedUpdateConfig, resourceName, "id", "key_origin"),
+			// plain_keyはAPIから読み出せない
+			test.ImportStep(resourceName, "plain_key"),
 		},
 	})
 }
 
-func testCheckSakuraKMSDestroy(s *terraform.State) error {
-	client := test.AccClientGetter()
-	keyOp := kms.NewKeyOp(client.KmsClient)
-
-	for _, rs := range s.RootModule().Resources {
-		if rs.Type != "sakura_kms" {
-			continue
-		}
-		if rs.Primary.ID == "" {
-			continue
-		}
-
-		_, err := keyOp.Read(context.Background(), rs.Primary.ID)
-		if err == nil {
-			return fmt.Errorf("still exists KMS: %s", rs.Primary.ID)
-		}
-	}
-	return nil
-}
+// 日本語や絵文字を含む名前・説明が、作成・更新・インポートを通じて設定した値のまま保持されることを検証する
+func TestAccSakuraResourceKMS_nonASCII(t *testing.T) {
+	test.SkipInSandbox(t, "KMS is not available in the sandbox")
+	test.ParallelTest(t)
 
-func testCheckSakuraKMSExists(n string, key *v1.Key) resource.TestCheckFunc {
-	return func(s *terraform.State) error {
-		rs, ok := s.RootModule().Resources[n]
+	resourceName := "sakura_kms.foobar"
+	rand := test.RandomName(t, "kms")
+	name := rand + "-本番用の鍵🔑"
+	var key v1.Key
+	basicConfig := test.BuildConfigWithMap(t, testAccSakuraKMS_nonASCII, map[string]any{"name": name, "description": "日本語の説明😀"})
+	updateConfig := test.BuildConfigWithMap(t, testAccSakuraKMS_nonASCII, map[string]any{"name": name + "（更新）", "description": "更新後の説明🎉"})
 
-		if !ok {
-			return fmt.Errorf("not found: %s", n)
-		}
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { test.AccPreCheck(t) },
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckSakuraKMSDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: basicConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraKMSExists(resourceName, &key),
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "description", "日本語の説明😀"),
+				),
+			},
+			test.StablePlanStep(basicConfig, resourceName, "id", "key_origin"),
+			{
+				Config: updateConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraKMSExists(resourceName, &key),
+					resource.TestCheckResourceAttr(resourceName, "name", name+"（更新）"),
+					resource.TestCheckResourceAttr(resourceName, "description", "更新後の説明🎉"),
+				),
+			},
+			test.StablePlanStep(updateConfig, resourceName, "id", "key_origin"),
+			test.ImportStep(resourceName),
+		},
+	})
+}
 
-		if rs.Primary.ID == "" {
-			return errors.New("no KMS ID is set")
-		}
+var testCheckSakuraKMSDestroy = test.CheckDestroy("sakura_kms", test.ReadByExists(func(ctx context.Context, rs *terraform.ResourceState) (bool, error) {
+	return test.AccClientGetter().KMSKeyExists(ctx, rs.Primary.ID)
+}))
 
-		client := test.AccClientGetter()
-		keyOp := kms.NewKeyOp(client.KmsClient)
+func testCheckSakuraKMSExists(n string, key *v1.Key) resource.TestCheckFunc {
+	return test.CheckExists(n, test.ExistsCheck[v1.Key]{
+		Kind: "KMS key",
+		Read: func(ctx context.Context, rs *terraform.ResourceState) (*v1.Key, error) {
+			keyOp, err := test.AccClientGetter().KMSKeyOp()
+			if err != nil {
+				return nil, err
+			}
+			return keyOp.Read(ctx, rs.Primary.ID)
+		},
+		ID: func(v *v1.Key) string { return v.ID },
+	}, key)
+}
 
-		foundKey, err := keyOp.Read(context.Background(), rs.Primary.ID)
-		if err != nil {
-			return err
-		}
+func TestAccSakuraResourceKMS_defaultTags(t *testing.T) {
+	test.SkipInSandbox(t, "KMS is not available in the sandbox")
+	test.ParallelTest(t)
 
-		if foundKey.ID != rs.Primary.ID {
-			return fmt.Errorf("not found KMS: %s", rs.Primary.ID)
-		}
+	resourceName := "sakura_kms.foobar"
+	rand := test.RandomName(t, "kms")
+	var key v1.Key
+	config := testAccSakuraKMS_defaultTags + test.BuildConfigWithMap(t, testAccSakuraKMS_tags, map[string]any{"name": rand, "tags": `tags = ["tag1", "env=acctest"]`})
 
-		*key = *foundKey
-		return nil
-	}
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { test.AccPreCheck(t) },
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckSakuraKMSDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraKMSExists(resourceName, &key),
+					test.CheckFetched(&key, func(v *v1.Key) error {
+						if len(v.Tags) != 3 {
+							return fmt.Errorf("default tags are not attached to the key: %v", v.Tags)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttr(resourceName, "tags.#", "3"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "tags.*", "tag1"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "tags.*", "env=acctest"),
+					resource.TestCheckTypeSetElemAttr(resourceName, "tags.*", "managed-by=terraform"),
+				),
+			},
+			test.StablePlanStep(config, resourceName, "id", "tags"),
+		},
+	})
 }
 
 var testAccSakuraKMS_basic = `
 resource "sakura_kms" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description"
   tags        = ["tag1", "tag2"]
 }`
 
+var testAccSakuraKMS_defaultTags = `
+provider "sakura" {
+  default_tags = ["env=acctest", "managed-by=terraform"]
+}
+`
+
+var testAccSakuraKMS_namePrefix = `
+resource "sakura_kms" "foobar" {
+  name_prefix = "{{ .name }}-"
+  description = "{{ .description }}"
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}`
+
+var testAccSakuraKMS_tags = `
+resource "sakura_kms" "foobar" {
+  name = "{{ .name }}"
+  {{ .tags }}
+}`
+
+var testAccSakuraKMS_description = `
+resource "sakura_kms" "foobar" {
+  name = "{{ .name }}"
+  {{ .description }}
+}`
+
+var testAccSakuraKMS_keyOrigin = `
+resource "sakura_kms" "foobar" {
+  name       = "{{ .name }}"
+  key_origin = "{{ .key_origin }}"
+  {{ .plain_key }}
+}`
+
 var testAccSakuraKMS_update = `
 resource "sakura_kms" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description-updated"
   tags        = ["tag1-upd"]
 }`
 
 var testAccSakuraKMS_imported = `
 resource "sakura_kms" "foobar2" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description with plain key"
   tags        = ["tag1", "tag2"]
   key_origin  = "imported"
@@ -174,8 +272,15 @@ resource "sakura_kms" "foobar2" {
 
 var testAccSakuraKMS_importedUpdate = `
 resource "sakura_kms" "foobar2" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description with plain key updated"
   tags        = ["tag1"]
   key_origin  = "imported"
 }`
+
+var testAccSakuraKMS_nonASCII = `
+resource "sakura_kms" "foobar" {
+  name        = "{{ .name }}"
+  description = "{{ .description }}"
+  tags        = ["タグ1"]
+}`
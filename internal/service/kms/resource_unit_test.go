@@ -0,0 +1,713 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	ogen "github.com/ogen-go/ogen/validate"
+	api "github.com/sacloud/api-client-go"
+	v1 "github.com/sacloud/kms-api-go/apis/v1"
+	smv1 "github.com/sacloud/secretmanager-api-go/apis/v1"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func kmsResourceSchema(t *testing.T) schema.Schema {
+	t.Helper()
+
+	var resp resource.SchemaResponse
+	NewKMSResource().Schema(context.Background(), resource.SchemaRequest{}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	return resp.Schema
+}
+
+func testKMSResourceModel(s schema.Schema, id string) *kmsResourceModel {
+	timeoutTypes := s.Attributes["timeouts"].GetType().(attr.TypeWithAttributeTypes).AttributeTypes()
+	model := &kmsResourceModel{
+		SakuraBaseModel: common.SakuraBaseModel{
+			Name:        types.StringValue("foobar"),
+			Description: types.StringValue("description"),
+			Tags:        types.SetValueMust(types.StringType, []attr.Value{types.StringValue("tag1")}),
+		},
+		KeyOrigin: common.NewCaseInsensitiveStringValue("generated"),
+		PlainKey:  types.StringNull(),
+		Timeouts:  timeouts.Value{Object: types.ObjectNull(timeoutTypes)},
+	}
+	if id == "" {
+		model.ID = common.NewSakuraIDUnknown()
+	} else {
+		model.ID = common.NewSakuraIDValue(id)
+	}
+	return model
+}
+
+func emptyState(s schema.Schema) tfsdk.State {
+	return tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(context.Background()), nil)}
+}
+
+func TestKMSResource_Create(t *testing.T) {
+	ctx := context.Background()
+	s := kmsResourceSchema(t)
+
+	plan := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	require.False(t, plan.Set(ctx, testKMSResourceModel(s, "")).HasError())
+
+	t.Run("created", func(t *testing.T) {
+		var got v1.CreateKey
+		r := &kmsResource{client: newStubKMSAPI(&stubKeyOp{
+			create: func(_ context.Context, request v1.CreateKey) (*v1.CreateKey, error) {
+				got = request
+				request.ID = "110000000001"
+				return &request, nil
+			},
+			read: func(_ context.Context, id string) (*v1.Key, error) {
+				return &v1.Key{ID: id, Name: "foobar"}, nil
+			},
+		})}
+
+		resp := resource.CreateResponse{State: emptyState(s)}
+		r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.Equal(t, "foobar", got.Name)
+		assert.Equal(t, v1.KeyOriginEnumGenerated, got.KeyOrigin)
+
+		var state kmsResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "110000000001", state.ID.ValueString())
+		assert.Equal(t, "generated", state.KeyOrigin.ValueString())
+	})
+
+	t.Run("name_prefix", func(t *testing.T) {
+		model := testKMSResourceModel(s, "")
+		model.Name = types.StringUnknown()
+		model.NamePrefix = types.StringValue("foobar-")
+		plan := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+		require.False(t, plan.Set(ctx, model).HasError())
+
+		var got v1.CreateKey
+		r := &kmsResource{client: newStubKMSAPI(&stubKeyOp{
+			create: func(_ context.Context, request v1.CreateKey) (*v1.CreateKey, error) {
+				got = request
+				request.ID = "110000000001"
+				return &request, nil
+			},
+			read: func(_ context.Context, id string) (*v1.Key, error) {
+				return &v1.Key{ID: id, Name: got.Name}, nil
+			},
+		})}
+
+		resp := resource.CreateResponse{State: emptyState(s)}
+		r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.Regexp(t, `^foobar-[a-z0-9]{8}$`, got.Name)
+
+		// 生成した名前をstateに保存し、以降のplanではそれを引き継ぐ
+		var state kmsResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, got.Name, state.Name.ValueString())
+		assert.Equal(t, "foobar-", state.NamePrefix.ValueString())
+	})
+
+	t.Run("wait until visible", func(t *testing.T) {
+		reads := 0
+		r := &kmsResource{client: newStubKMSAPI(&stubKeyOp{
+			create: func(_ context.Context, request v1.CreateKey) (*v1.CreateKey, error) {
+				request.ID = "110000000001"
+				return &request, nil
+			},
+			read: func(_ context.Context, id string) (*v1.Key, error) {
+				reads++
+				if reads == 1 {
+					return nil, api.NewAPIError(http.StatusNotFound, "", errors.New("not found"))
+				}
+				return &v1.Key{ID: id, Name: "foobar"}, nil
+			},
+		})}
+
+		resp := resource.CreateResponse{State: emptyState(s)}
+		r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.Equal(t, 2, reads)
+	})
+
+	t.Run("wait error", func(t *testing.T) {
+		r := &kmsResource{client: newStubKMSAPI(&stubKeyOp{
+			create: func(_ context.Context, request v1.CreateKey) (*v1.CreateKey, error) {
+				request.ID = "110000000001"
+				return &request, nil
+			},
+			read: func(context.Context, string) (*v1.Key, error) {
+				return nil, api.NewAPIError(http.StatusForbidden, "", errors.New("forbidden"))
+			},
+		})}
+
+		resp := resource.CreateResponse{State: emptyState(s)}
+		r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Contains(t, resp.Diagnostics[0].Detail(), "110000000001")
+
+		// 作成済みのリソースはstateに残す
+		var state kmsResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "110000000001", state.ID.ValueString())
+	})
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		r := &kmsResource{client: newStubKMSAPI(&stubKeyOp{
+			create: func(_ context.Context, request v1.CreateKey) (*v1.CreateKey, error) {
+				request.ID = "110000000001"
+				return &request, nil
+			},
+			read: func(context.Context, string) (*v1.Key, error) {
+				time.AfterFunc(10*time.Millisecond, cancel)
+				return nil, api.NewAPIError(http.StatusNotFound, "", errors.New("not found"))
+			},
+		})}
+
+		start := time.Now()
+		resp := resource.CreateResponse{State: emptyState(s)}
+		r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+		assert.Less(t, time.Since(start), 5*time.Second)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "Operation Cancelled", resp.Diagnostics[0].Summary())
+
+		// 作成済みのリソースはstateに残り、taintedとして扱われる
+		var state kmsResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "110000000001", state.ID.ValueString())
+	})
+
+	t.Run("invalid field", func(t *testing.T) {
+		r := &kmsResource{client: newStubKMSAPI(&stubKeyOp{
+			create: func(context.Context, v1.CreateKey) (*v1.CreateKey, error) {
+				return nil, fmt.Errorf("validate: %w", &ogen.Error{Fields: []ogen.FieldError{
+					{Name: "Name", Error: errors.New("string: len 300 greater than maximum 255")},
+				}})
+			},
+		})}
+
+		resp := resource.CreateResponse{State: emptyState(s)}
+		r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		withPath, ok := resp.Diagnostics[0].(diag.DiagnosticWithPath)
+		require.True(t, ok, "diagnostic should be attached to the attribute")
+		assert.Equal(t, path.Root("name"), withPath.Path())
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		r := &kmsResource{client: newStubKMSAPI(&stubKeyOp{
+			create: func(context.Context, v1.CreateKey) (*v1.CreateKey, error) {
+				return nil, api.NewAPIError(http.StatusInternalServerError, "unexpected error", errors.New("internal server error"))
+			},
+		})}
+
+		resp := resource.CreateResponse{State: emptyState(s)}
+		r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "KMS Create Error", resp.Diagnostics[0].Summary())
+		assert.Contains(t, resp.Diagnostics[0].Detail(), "internal server error")
+		assert.True(t, resp.State.Raw.IsNull(), "state should not be saved")
+	})
+}
+
+func TestKMSResource_Read(t *testing.T) {
+	ctx := context.Background()
+	s := kmsResourceSchema(t)
+
+	newReadRequest := func(t *testing.T) (resource.ReadRequest, resource.ReadResponse) {
+		state := emptyState(s)
+		require.False(t, state.Set(ctx, testKMSResourceModel(s, "110000000001")).HasError())
+		return resource.ReadRequest{State: state}, resource.ReadResponse{State: state}
+	}
+
+	t.Run("found", func(t *testing.T) {
+		r := &kmsResource{client: newStubKMSAPI(&stubKeyOp{
+			read: func(_ context.Context, id string) (*v1.Key, error) {
+				return &v1.Key{ID: id, Name: "foobar-upd", KeyOrigin: v1.KeyOriginEnumGenerated, Tags: []string{"tag1"}}, nil
+			},
+		})}
+
+		req, resp := newReadRequest(t)
+		r.Read(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+		var state kmsResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "foobar-upd", state.Name.ValueString())
+		// zoneを持たない以前のstateでもReadで補完される
+		assert.Equal(t, common.GlobalZone, state.Zone.ValueString())
+	})
+
+	t.Run("duplicated tags", func(t *testing.T) {
+		r := &kmsResource{client: newStubKMSAPI(&stubKeyOp{
+			read: func(_ context.Context, id string) (*v1.Key, error) {
+				return &v1.Key{ID: id, Name: "foobar", KeyOrigin: v1.KeyOriginEnumGenerated, Tags: []string{"tag2", "@auto-reboot", "tag1", "tag2"}}, nil
+			},
+		})}
+
+		req, resp := newReadRequest(t)
+		r.Read(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+		var state kmsResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		// stateに含まれていないシステムタグは差分としない
+		assert.Equal(t, []string{"tag1", "tag2"}, common.ExpandTags(state.Tags))
+	})
+
+	t.Run("system tags with ignore_system_tags = false", func(t *testing.T) {
+		api := newStubKMSAPI(&stubKeyOp{
+			read: func(_ context.Context, id string) (*v1.Key, error) {
+				return &v1.Key{ID: id, Name: "foobar", KeyOrigin: v1.KeyOriginEnumGenerated, Tags: []string{"tag1", "@auto-reboot"}}, nil
+			},
+		})
+		api.manageSystemTags = true
+		r := &kmsResource{client: api}
+
+		req, resp := newReadRequest(t)
+		r.Read(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+		var state kmsResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, []string{"@auto-reboot", "tag1"}, common.ExpandTags(state.Tags))
+	})
+
+	t.Run("not found removes resource", func(t *testing.T) {
+		r := &kmsResource{client: newStubKMSAPI(&stubKeyOp{
+			read: func(context.Context, string) (*v1.Key, error) {
+				return nil, api.NewAPIError(http.StatusNotFound, "not found", errors.New("not found"))
+			},
+		})}
+
+		req, resp := newReadRequest(t)
+		r.Read(ctx, req, &resp)
+		assert.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.True(t, resp.State.Raw.IsNull())
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		r := &kmsResource{client: newStubKMSAPI(&stubKeyOp{
+			read: func(context.Context, string) (*v1.Key, error) {
+				return nil, api.NewAPIError(http.StatusInternalServerError, "unexpected error", errors.New("internal server error"))
+			},
+		})}
+
+		req, resp := newReadRequest(t)
+		r.Read(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "Get KMS Key Error", resp.Diagnostics[0].Summary())
+		assert.Contains(t, resp.Diagnostics[0].Detail(), "110000000001")
+		assert.Contains(t, resp.Diagnostics[0].Detail(), "internal server error")
+		assert.False(t, resp.State.Raw.IsNull(), "state should be kept on errors other than 404")
+	})
+}
+
+func TestKMSResource_Delete(t *testing.T) {
+	ctx := context.Background()
+	s := kmsResourceSchema(t)
+
+	newDeleteRequest := func(t *testing.T) (resource.DeleteRequest, resource.DeleteResponse) {
+		state := emptyState(s)
+		require.False(t, state.Set(ctx, testKMSResourceModel(s, "110000000001")).HasError())
+		return resource.DeleteRequest{State: state}, resource.DeleteResponse{State: state}
+	}
+	readKey := func(_ context.Context, id string) (*v1.Key, error) {
+		return &v1.Key{ID: id, Name: "foobar"}, nil
+	}
+
+	t.Run("deleted", func(t *testing.T) {
+		var deleted string
+		r := &kmsResource{client: newStubKMSAPI(&stubKeyOp{
+			read: readKey,
+			delete: func(_ context.Context, id string) error {
+				deleted = id
+				return nil
+			},
+		})}
+
+		req, resp := newDeleteRequest(t)
+		r.Delete(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.Equal(t, "110000000001", deleted)
+	})
+
+	t.Run("used by vaults", func(t *testing.T) {
+		calls := 0
+		stub := newStubKMSAPI(&stubKeyOp{
+			read: readKey,
+			delete: func(context.Context, string) error {
+				calls++
+				return api.NewAPIError(http.StatusConflict, "", errors.New("key is in use"))
+			},
+		})
+		stub.vaultList = func(context.Context) ([]smv1.Vault, error) {
+			return []smv1.Vault{
+				{ID: "110000000002", Name: "prod-secrets", KmsKeyID: "110000000001"},
+				{ID: "110000000003", Name: "other", KmsKeyID: "110000000009"},
+				{ID: "110000000004", Name: "dev-secrets", KmsKeyID: "110000000001"},
+			}, nil
+		}
+		r := &kmsResource{client: stub}
+
+		req, resp := newDeleteRequest(t)
+		r.Delete(ctx, req, &resp)
+		assert.Equal(t, 1, calls, "should not retry while the key is used by vaults")
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "KMS Delete Error", resp.Diagnostics[0].Summary())
+		assert.Contains(t, resp.Diagnostics[0].Detail(), "key is still used by vaults: prod-secrets (id 110000000002), dev-secrets (id 110000000004)")
+		assert.Contains(t, resp.Diagnostics[0].Detail(), "key is in use")
+		assert.NotContains(t, resp.Diagnostics[0].Detail(), "other")
+	})
+
+	t.Run("vault lookup fails", func(t *testing.T) {
+		stub := newStubKMSAPI(&stubKeyOp{
+			read: readKey,
+			delete: func(context.Context, string) error {
+				return api.NewAPIError(http.StatusBadRequest, "", errors.New("could not delete key"))
+			},
+		})
+		stub.vaultList = func(context.Context) ([]smv1.Vault, error) {
+			return nil, api.NewAPIError(http.StatusForbidden, "", errors.New("forbidden"))
+		}
+		r := &kmsResource{client: stub}
+
+		req, resp := newDeleteRequest(t)
+		r.Delete(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		require.Len(t, resp.Diagnostics, 1)
+		assert.Contains(t, resp.Diagnostics[0].Detail(), "could not delete key")
+		assert.NotContains(t, resp.Diagnostics[0].Detail(), "forbidden")
+	})
+
+	t.Run("cancelled while retrying conflict", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		calls := 0
+		r := &kmsResource{client: newStubKMSAPI(&stubKeyOp{
+			read: readKey,
+			delete: func(context.Context, string) error {
+				calls++
+				time.AfterFunc(10*time.Millisecond, cancel)
+				return api.NewAPIError(http.StatusConflict, "", errors.New("key is in use"))
+			},
+		})}
+
+		start := time.Now()
+		req, resp := newDeleteRequest(t)
+		r.Delete(ctx, req, &resp)
+		assert.Less(t, time.Since(start), 5*time.Second)
+		assert.Equal(t, 1, calls)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "Operation Cancelled", resp.Diagnostics[0].Summary())
+		assert.Contains(t, resp.Diagnostics[0].Detail(), "KMS Delete Error")
+		assert.False(t, resp.State.Raw.IsNull(), "state should be kept when delete is cancelled")
+	})
+}
+
+func TestKMSResource_Update(t *testing.T) {
+	ctx := context.Background()
+	s := kmsResourceSchema(t)
+
+	newUpdateRequest := func(t *testing.T, modify func(plan *kmsResourceModel)) (resource.UpdateRequest, resource.UpdateResponse) {
+		state := emptyState(s)
+		stateModel := testKMSResourceModel(s, "110000000001")
+		stateModel.Tags = types.SetNull(types.StringType)
+		require.False(t, state.Set(ctx, stateModel).HasError())
+
+		planModel := testKMSResourceModel(s, "110000000001")
+		planModel.Tags = types.SetValueMust(types.StringType, []attr.Value{})
+		modify(planModel)
+		plan := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+		require.False(t, plan.Set(ctx, planModel).HasError())
+		return resource.UpdateRequest{Plan: plan, State: state}, resource.UpdateResponse{State: state}
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		stub := &stubKeyOp{}
+		r := &kmsResource{client: newStubKMSAPI(stub)}
+
+		// tagsのnullと空のset、plain_keyの変更はAPIに送られる値に影響しない
+		req, resp := newUpdateRequest(t, func(plan *kmsResourceModel) {
+			plan.PlainKey = types.StringValue("AfL5zzjD4RgeFQm3vvAADwPNrurNUc616877wsa8v4w=")
+		})
+		r.Update(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.Empty(t, stub.calls)
+
+		var state kmsResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "110000000001", state.ID.ValueString())
+		assert.Equal(t, "generated", state.KeyOrigin.ValueString())
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		var got v1.Key
+		stub := &stubKeyOp{
+			read: func(_ context.Context, id string) (*v1.Key, error) {
+				return &v1.Key{ID: id, Name: "foobar", KeyOrigin: v1.KeyOriginEnumGenerated}, nil
+			},
+			update: func(_ context.Context, id string, request v1.Key) (*v1.Key, error) {
+				got = request
+				request.ID = id
+				return &request, nil
+			},
+		}
+		r := &kmsResource{client: newStubKMSAPI(stub)}
+
+		req, resp := newUpdateRequest(t, func(plan *kmsResourceModel) {
+			plan.Description = types.StringValue("description-upd")
+		})
+		r.Update(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		// 更新APIのレスポンスがキー全体を含むため、更新後に改めて参照しない
+		assert.Equal(t, []string{"Read", "Update"}, stub.calls)
+		assert.Equal(t, "description-upd", got.Description.Value)
+
+		var state kmsResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "description-upd", state.Description.ValueString())
+	})
+
+	t.Run("metadata changes in a single update", func(t *testing.T) {
+		var requests []v1.Key
+		stub := &stubKeyOp{
+			read: func(_ context.Context, id string) (*v1.Key, error) {
+				return &v1.Key{ID: id, Name: "foobar", KeyOrigin: v1.KeyOriginEnumGenerated}, nil
+			},
+			update: func(_ context.Context, id string, request v1.Key) (*v1.Key, error) {
+				requests = append(requests, request)
+				request.ID = id
+				return &request, nil
+			},
+		}
+		r := &kmsResource{client: newStubKMSAPI(stub)}
+
+		req, resp := newUpdateRequest(t, func(plan *kmsResourceModel) {
+			plan.Name = types.StringValue("foobar-upd")
+			plan.Description = types.StringValue("description-upd")
+			plan.Tags = types.SetValueMust(types.StringType, []attr.Value{types.StringValue("tag1")})
+		})
+		r.Update(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		// name/description/tagsの変更は1回の更新APIでまとめて送信する
+		assert.Equal(t, []string{"Read", "Update"}, stub.calls)
+		require.Len(t, requests, 1)
+		assert.Equal(t, "foobar-upd", requests[0].Name)
+		assert.Equal(t, "description-upd", requests[0].Description.Value)
+		assert.Equal(t, []string{"tag1"}, requests[0].Tags)
+		assert.Equal(t, v1.KeyOriginEnumGenerated, requests[0].KeyOrigin)
+	})
+
+	t.Run("system tags are preserved", func(t *testing.T) {
+		var got v1.Key
+		stub := &stubKeyOp{
+			read: func(_ context.Context, id string) (*v1.Key, error) {
+				return &v1.Key{ID: id, Name: "foobar", KeyOrigin: v1.KeyOriginEnumGenerated, Tags: []string{"tag1", "@auto-reboot"}}, nil
+			},
+			update: func(_ context.Context, id string, request v1.Key) (*v1.Key, error) {
+				got = request
+				request.ID = id
+				return &request, nil
+			},
+		}
+		r := &kmsResource{client: newStubKMSAPI(stub)}
+
+		req, resp := newUpdateRequest(t, func(plan *kmsResourceModel) {
+			plan.Tags = types.SetValueMust(types.StringType, []attr.Value{types.StringValue("tag2")})
+		})
+		r.Update(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.Equal(t, []string{"@auto-reboot", "tag2"}, got.Tags)
+
+		var state kmsResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, []string{"tag2"}, common.ExpandTags(state.Tags))
+	})
+
+	t.Run("incomplete update response", func(t *testing.T) {
+		stub := &stubKeyOp{
+			read: func(_ context.Context, id string) (*v1.Key, error) {
+				return &v1.Key{ID: id, Name: "foobar", Description: v1.NewOptString("description-upd"), KeyOrigin: v1.KeyOriginEnumGenerated}, nil
+			},
+			update: func(context.Context, string, v1.Key) (*v1.Key, error) {
+				return &v1.Key{}, nil
+			},
+		}
+		r := &kmsResource{client: newStubKMSAPI(stub)}
+
+		req, resp := newUpdateRequest(t, func(plan *kmsResourceModel) {
+			plan.Description = types.StringValue("description-upd")
+		})
+		r.Update(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.Equal(t, []string{"Read", "Update", "Read"}, stub.calls)
+
+		var state kmsResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "110000000001", state.ID.ValueString())
+		assert.Equal(t, "description-upd", state.Description.ValueString())
+	})
+
+	t.Run("non-ASCII name", func(t *testing.T) {
+		const (
+			name        = "本番用の鍵🔑"
+			description = "日本語の説明😀 <&>"
+		)
+		var stored v1.Key
+		stub := &stubKeyOp{
+			read: func(_ context.Context, id string) (*v1.Key, error) {
+				if stored.ID == "" {
+					return &v1.Key{ID: id, Name: "foobar", KeyOrigin: v1.KeyOriginEnumGenerated}, nil
+				}
+				return &stored, nil
+			},
+			update: func(_ context.Context, id string, request v1.Key) (*v1.Key, error) {
+				stored = request
+				stored.ID = id
+				return &stored, nil
+			},
+		}
+		r := &kmsResource{client: newStubKMSAPI(stub)}
+
+		req, resp := newUpdateRequest(t, func(plan *kmsResourceModel) {
+			plan.Name = types.StringValue(name)
+			plan.Description = types.StringValue(description)
+		})
+		r.Update(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.Equal(t, name, stored.Name)
+		assert.Equal(t, description, stored.Description.Value)
+
+		var state kmsResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, name, state.Name.ValueString())
+		assert.Equal(t, description, state.Description.ValueString())
+	})
+}
+
+func TestKMSResource_nameLength(t *testing.T) {
+	s := kmsResourceSchema(t)
+	name := s.Attributes["name"].(schema.StringAttribute)
+
+	testCases := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "ascii", value: strings.Repeat("a", 64)},
+		{name: "multibyte is counted by characters", value: strings.Repeat("鍵", 64)},
+		{name: "emoji", value: strings.Repeat("🔑", 64)},
+		{name: "too long", value: strings.Repeat("鍵", 65), wantErr: true},
+		{name: "empty", value: "", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			for _, v := range name.StringValidators() {
+				resp := &validator.StringResponse{}
+				v.ValidateString(context.Background(), validator.StringRequest{
+					Path:        path.Root("name"),
+					ConfigValue: types.StringValue(tc.value),
+				}, resp)
+				diags.Append(resp.Diagnostics...)
+			}
+			assert.Equal(t, tc.wantErr, diags.HasError(), diags)
+		})
+	}
+}
+
+func TestKMSResource_keyOriginCaseInsensitive(t *testing.T) {
+	ctx := context.Background()
+	s := kmsResourceSchema(t)
+	keyOrigin := s.Attributes["key_origin"].(schema.StringAttribute)
+
+	testCases := []struct {
+		value    string
+		plainKey types.String
+		want     v1.KeyOriginEnum
+	}{
+		{value: "generated", plainKey: types.StringNull(), want: v1.KeyOriginEnumGenerated},
+		{value: "Generated", plainKey: types.StringNull(), want: v1.KeyOriginEnumGenerated},
+		{value: "IMPORTED", plainKey: types.StringValue("plain"), want: v1.KeyOriginEnumImported},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			for _, v := range keyOrigin.StringValidators() {
+				resp := &validator.StringResponse{}
+				v.ValidateString(ctx, validator.StringRequest{Path: path.Root("key_origin"), ConfigValue: types.StringValue(tc.value)}, resp)
+				require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+			}
+
+			model := testKMSResourceModel(s, "")
+			model.KeyOrigin = common.NewCaseInsensitiveStringValue(tc.value)
+			model.PlainKey = tc.plainKey
+			req, err := expandKMSCreateKey(model)
+			require.NoError(t, err)
+			// APIには正規の表記で送信する
+			assert.Equal(t, tc.want, req.KeyOrigin)
+
+			// APIが返した正規の表記とは差分にならない
+			equal, diags := model.KeyOrigin.StringSemanticEquals(ctx, common.NewCaseInsensitiveStringValue(string(tc.want)))
+			require.False(t, diags.HasError(), diags)
+			assert.True(t, equal)
+		})
+	}
+}
+
+func TestKMSResource_plainKeyUnchanged(t *testing.T) {
+	testCases := []struct {
+		name  string
+		plan  string
+		state string
+		want  bool
+	}{
+		{name: "same key", plan: "key1", state: "key1", want: true},
+		{name: "different key", plan: "key2", state: "key1", want: false},
+		{name: "removed from config", plan: "", state: "key1", want: true},
+		// import直後はplain_keyがnullとなる
+		{name: "after import", plan: "key1", state: "", want: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, plainKeyUnchanged(tc.plan, tc.state))
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kms
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sacloud/kms-api-go"
+	v1 "github.com/sacloud/kms-api-go/apis/v1"
+	smv1 "github.com/sacloud/secretmanager-api-go/apis/v1"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+)
+
+// stubKMSAPI はkmsAPIのテストダブル
+type stubKMSAPI struct {
+	keyOp     *stubKeyOp
+	vaultList func(ctx context.Context) ([]smv1.Vault, error)
+	// keyListTruncatedがtrueの場合、キーの一覧取得APIが全件を返さなかったものとして扱う
+	keyListTruncated bool
+	// manageSystemTags はignore_system_tags = falseとして動作させる場合にtrueとする
+	manageSystemTags bool
+	resourceDefaults common.ResourceDefaults
+}
+
+var _ kmsAPI = (*stubKMSAPI)(nil)
+
+func newStubKMSAPI(keyOp *stubKeyOp) *stubKMSAPI {
+	return &stubKMSAPI{keyOp: keyOp}
+}
+
+func (s *stubKMSAPI) KMSKeyOp() (kms.KeyAPI, error) {
+	return s.keyOp, nil
+}
+
+func (s *stubKMSAPI) KMSKeyList(ctx context.Context) ([]v1.Key, bool, error) {
+	keys, err := s.keyOp.List(ctx)
+	return keys, s.keyListTruncated, err
+}
+
+// stubKeyOp はkms.KeyAPIのテストダブル。未設定の操作を呼び出した場合はエラーを返す
+type stubKeyOp struct {
+	list   func(ctx context.Context) (v1.Keys, error)
+	read   func(ctx context.Context, id string) (*v1.Key, error)
+	create func(ctx context.Context, request v1.CreateKey) (*v1.CreateKey, error)
+	update func(ctx context.Context, id string, request v1.Key) (*v1.Key, error)
+	delete func(ctx context.Context, id string) error
+
+	calls []string // 呼び出された操作の名前
+}
+
+var _ kms.KeyAPI = (*stubKeyOp)(nil)
+
+func (s *stubKMSAPI) SecretManagerVaultList(ctx context.Context) ([]smv1.Vault, bool, error) {
+	if s.vaultList == nil {
+		return nil, false, errNotStubbed("SecretManagerVaultList")
+	}
+	vaults, err := s.vaultList(ctx)
+	return vaults, false, err
+}
+
+func (s *stubKMSAPI) IgnoreSystemTags() bool {
+	return !s.manageSystemTags
+}
+
+func (s *stubKMSAPI) ResourceDefaults() common.ResourceDefaults {
+	return s.resourceDefaults
+}
+
+func errNotStubbed(op string) error {
+	return fmt.Errorf("stubKeyOp: %s is not stubbed", op)
+}
+
+func (s *stubKeyOp) List(ctx context.Context) (v1.Keys, error) {
+	s.calls = append(s.calls, "List")
+	if s.list == nil {
+		return nil, errNotStubbed("List")
+	}
+	return s.list(ctx)
+}
+
+func (s *stubKeyOp) Read(ctx context.Context, id string) (*v1.Key, error) {
+	s.calls = append(s.calls, "Read")
+	if s.read == nil {
+		return nil, errNotStubbed("Read")
+	}
+	return s.read(ctx, id)
+}
+
+func (s *stubKeyOp) Create(ctx context.Context, request v1.CreateKey) (*v1.CreateKey, error) {
+	s.calls = append(s.calls, "Create")
+	if s.create == nil {
+		return nil, errNotStubbed("Create")
+	}
+	return s.create(ctx, request)
+}
+
+func (s *stubKeyOp) Update(ctx context.Context, id string, request v1.Key) (*v1.Key, error) {
+	s.calls = append(s.calls, "Update")
+	if s.update == nil {
+		return nil, errNotStubbed("Update")
+	}
+	return s.update(ctx, id, request)
+}
+
+func (s *stubKeyOp) Delete(ctx context.Context, id string) error {
+	s.calls = append(s.calls, "Delete")
+	if s.delete == nil {
+		return errNotStubbed("Delete")
+	}
+	return s.delete(ctx, id)
+}
@@ -86,6 +86,8 @@ func (d *nfsDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	zone := common.GetZone(data.Zone, d.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -96,7 +98,7 @@ func (d *nfsDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 
 	res, err := searcher.Find(ctx, zone, findCondition)
 	if err != nil {
-		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("could not find SakuraCloud NFS resource: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Read Error", fmt.Errorf("could not find SakuraCloud NFS resource: %w", err))
 		return
 	}
 	if res == nil || res.Count == 0 || len(res.NFS) == 0 {
@@ -106,7 +108,7 @@ func (d *nfsDataSource) Read(ctx context.Context, req datasource.ReadRequest, re
 
 	nfs := res.NFS[0]
 	if _, err := data.updateState(ctx, d.client, nfs, zone); err != nil {
-		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("could not update state for SakuraCloud NFS resource: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Read Error", fmt.Errorf("could not update state for SakuraCloud NFS resource: %w", err))
 		return
 	}
 	data.IconID = types.StringValue(nfs.IconID.String())
@@ -48,6 +48,7 @@ var (
 	_ resource.Resource                = &nfsResource{}
 	_ resource.ResourceWithConfigure   = &nfsResource{}
 	_ resource.ResourceWithImportState = &nfsResource{}
+	_ resource.ResourceWithModifyPlan  = &nfsResource{}
 )
 
 func NewNFSResource() resource.Resource {
@@ -114,7 +115,7 @@ func (r *nfsResource) Schema(ctx context.Context, req resource.SchemaRequest, re
 					},
 				},
 			},
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
 				Create: true, Update: true, Delete: true,
 			}),
 		},
@@ -125,6 +126,11 @@ func (r *nfsResource) ImportState(ctx context.Context, req resource.ImportStateR
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *nfsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanZone(ctx, r.client, req, resp)
+	common.PlanResourceDefaults(ctx, r.client, req, resp)
+}
+
 func (r *nfsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan nfsResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -134,6 +140,7 @@ func (r *nfsResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout24hour)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(plan.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -142,7 +149,7 @@ func (r *nfsResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 	planID, err := expandNFSDiskPlanID(ctx, r.client, &plan)
 	if err != nil {
-		resp.Diagnostics.AddError("Create Error", err.Error())
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", err)
 		return
 	}
 
@@ -166,7 +173,7 @@ func (r *nfsResource) Create(ctx context.Context, req resource.CreateRequest, re
 
 	res, err := builder.Setup(ctx, zone)
 	if err != nil {
-		resp.Diagnostics.AddError("Create Error", fmt.Sprintf("creating SakuraCloud NFS is failed: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", fmt.Errorf("creating SakuraCloud NFS is failed: %w", err))
 		return
 	}
 
@@ -180,7 +187,7 @@ func (r *nfsResource) Create(ctx context.Context, req resource.CreateRequest, re
 		if rmResource {
 			resp.State.RemoveResource(ctx)
 		}
-		resp.Diagnostics.AddError("Create Error", fmt.Sprintf("could not update state for SakuraCloud NFS resource: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", fmt.Errorf("could not update state for SakuraCloud NFS resource: %w", err))
 		return
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
@@ -193,6 +200,8 @@ func (r *nfsResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	zone := common.GetZone(state.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -207,7 +216,7 @@ func (r *nfsResource) Read(ctx context.Context, req resource.ReadRequest, resp *
 		if rmResource {
 			resp.State.RemoveResource(ctx)
 		}
-		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("could not update state for SakuraCloud NFS resource: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Read Error", fmt.Errorf("could not update state for SakuraCloud NFS resource: %w", err))
 		return
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
@@ -222,6 +231,7 @@ func (r *nfsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout24hour)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(plan.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -231,7 +241,7 @@ func (r *nfsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 	nfsOp := iaas.NewNFSOp(r.client)
 	_, err := nfsOp.Update(ctx, zone, common.ExpandSakuraCloudID(plan.ID), expandNFSUpdateRequest(&plan))
 	if err != nil {
-		resp.Diagnostics.AddError("Update Error", fmt.Sprintf("updating SakuraCloud NFS[%s] is failed: %s", plan.ID.ValueString(), err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", fmt.Errorf("updating SakuraCloud NFS[%s] is failed: %w", plan.ID.ValueString(), err))
 		return
 	}
 
@@ -244,7 +254,7 @@ func (r *nfsResource) Update(ctx context.Context, req resource.UpdateRequest, re
 		if rmResource {
 			resp.State.RemoveResource(ctx)
 		}
-		resp.Diagnostics.AddError("Update Error", fmt.Sprintf("could not update state for SakuraCloud NFS resource: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", fmt.Errorf("could not update state for SakuraCloud NFS resource: %w", err))
 		return
 	}
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
@@ -259,6 +269,7 @@ func (r *nfsResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 
 	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout20min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(state.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -272,12 +283,12 @@ func (r *nfsResource) Delete(ctx context.Context, req resource.DeleteRequest, re
 
 	nfsOp := iaas.NewNFSOp(r.client)
 	if err := power.ShutdownNFS(ctx, nfsOp, zone, nfs.ID, true); err != nil {
-		resp.Diagnostics.AddError("Delete Error", err.Error())
+		common.AddAPIError(ctx, &resp.Diagnostics, "Delete Error", err)
 		return
 	}
 
 	if err := nfsOp.Delete(ctx, zone, nfs.ID); err != nil {
-		resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("deleting SakuraCloud NFS[%s] is failed: %s", state.ID.ValueString(), err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Delete Error", fmt.Errorf("deleting SakuraCloud NFS[%s] is failed: %w", state.ID.ValueString(), err))
 		return
 	}
 }
@@ -286,11 +297,10 @@ func getNFS(ctx context.Context, client *common.APIClient, zone string, id iaast
 	nfsOp := iaas.NewNFSOp(client)
 	nfs, err := nfsOp.Read(ctx, zone, id)
 	if err != nil {
-		if iaas.IsNotFoundError(err) {
-			state.RemoveResource(ctx)
+		if common.HandleNotFoundOnRead(ctx, err, state, "NFS", id.String()) {
 			return nil
 		}
-		diags.AddError("Get NFS Error", fmt.Sprintf("could not read SakuraCloud NFS[%s]: %s", id, err))
+		common.AddAPIError(ctx, diags, "Get NFS Error", fmt.Errorf("could not read SakuraCloud NFS[%s]: %w", id, err))
 		return nil
 	}
 
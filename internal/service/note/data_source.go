@@ -80,10 +80,12 @@ func (d *noteDataSource) Read(ctx context.Context, req datasource.ReadRequest, r
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	searcher := iaas.NewNoteOp(d.client)
 	result, err := searcher.Find(ctx, common.CreateFindCondition(data.ID, data.Name, data.Tags))
 	if err != nil {
-		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("could not find SakuraCloud Note resource: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Read Error", fmt.Errorf("could not find SakuraCloud Note resource: %w", err))
 		return
 	}
 	if result == nil || result.Count == 0 || len(result.Notes) == 0 {
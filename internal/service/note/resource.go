@@ -42,6 +42,7 @@ var (
 	_ resource.Resource                = &noteResource{}
 	_ resource.ResourceWithConfigure   = &noteResource{}
 	_ resource.ResourceWithImportState = &noteResource{}
+	_ resource.ResourceWithModifyPlan  = &noteResource{}
 )
 
 func NewNoteResource() resource.Resource {
@@ -89,7 +90,7 @@ func (r *noteResource) Schema(ctx context.Context, _ resource.SchemaRequest, res
 					stringvalidator.OneOf(iaastypes.NoteClassStrings...),
 				},
 			},
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
 				Create: true, Update: true, Delete: true,
 			}),
 		},
@@ -100,6 +101,10 @@ func (r *noteResource) ImportState(ctx context.Context, req resource.ImportState
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *noteResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanResourceDefaults(ctx, r.client, req, resp)
+}
+
 func (r *noteResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan noteResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -109,6 +114,7 @@ func (r *noteResource) Create(ctx context.Context, req resource.CreateRequest, r
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	noteOp := iaas.NewNoteOp(r.client)
 	note, err := noteOp.Create(ctx, &iaas.NoteCreateRequest{
@@ -119,7 +125,7 @@ func (r *noteResource) Create(ctx context.Context, req resource.CreateRequest, r
 		Class:   plan.Class.ValueString(),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("Create Error", fmt.Sprintf("creating SakuraCloud Note is failed: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", fmt.Errorf("creating SakuraCloud Note is failed: %w", err))
 		return
 	}
 
@@ -134,6 +140,8 @@ func (r *noteResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	note := getNote(ctx, r.client, common.ExpandSakuraCloudID(state.ID), &resp.State, &resp.Diagnostics)
 	if note == nil || resp.Diagnostics.HasError() {
 		return
@@ -152,6 +160,7 @@ func (r *noteResource) Update(ctx context.Context, req resource.UpdateRequest, r
 
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	noteOp := iaas.NewNoteOp(r.client)
 	note, err := noteOp.Update(ctx, common.ExpandSakuraCloudID(plan.ID), &iaas.NoteUpdateRequest{
@@ -162,7 +171,7 @@ func (r *noteResource) Update(ctx context.Context, req resource.UpdateRequest, r
 		Class:   plan.Class.ValueString(),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("Update Error", fmt.Sprintf("updating SakuraCloud Note[%s] is failed: %s", plan.ID.ValueString(), err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", fmt.Errorf("updating SakuraCloud Note[%s] is failed: %w", plan.ID.ValueString(), err))
 		return
 	}
 
@@ -179,6 +188,7 @@ func (r *noteResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 
 	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	noteOp := iaas.NewNoteOp(r.client)
 	note := getNote(ctx, r.client, common.ExpandSakuraCloudID(state.ID), &resp.State, &resp.Diagnostics)
@@ -187,7 +197,7 @@ func (r *noteResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	}
 
 	if err := noteOp.Delete(ctx, note.ID); err != nil {
-		resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("could not delete SakuraCloud Note[%s]: %s", state.ID.ValueString(), err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Delete Error", fmt.Errorf("could not delete SakuraCloud Note[%s]: %w", state.ID.ValueString(), err))
 		return
 	}
 }
@@ -196,11 +206,10 @@ func getNote(ctx context.Context, client *common.APIClient, id iaastypes.ID, sta
 	noteOp := iaas.NewNoteOp(client)
 	note, err := noteOp.Read(ctx, id)
 	if err != nil {
-		if iaas.IsNotFoundError(err) {
-			state.RemoveResource(ctx)
+		if common.HandleNotFoundOnRead(ctx, err, state, "Note", id.String()) {
 			return nil
 		}
-		diags.AddError("API Read Error", fmt.Sprintf("could not read SakuraCloud Note[%s]: %s", id.String(), err))
+		common.AddAPIError(ctx, diags, "API Read Error", fmt.Errorf("could not read SakuraCloud Note[%s]: %w", id.String(), err))
 		return nil
 	}
 
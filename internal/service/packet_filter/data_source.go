@@ -103,6 +103,8 @@ func (d *packetFilterDataSource) Read(ctx context.Context, req datasource.ReadRe
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	zone := common.GetZone(data.Zone, d.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -111,7 +113,7 @@ func (d *packetFilterDataSource) Read(ctx context.Context, req datasource.ReadRe
 	searcher := iaas.NewPacketFilterOp(d.client)
 	res, err := searcher.Find(ctx, zone, common.CreateFindCondition(data.ID, data.Name, types.SetNull(types.StringType)))
 	if err != nil {
-		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("could not find SakuraCloud PacketFilter resource: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Read Error", fmt.Errorf("could not find SakuraCloud PacketFilter resource: %w", err))
 		return
 	}
 	if res == nil || res.Count == 0 || len(res.PacketFilters) == 0 {
@@ -18,6 +18,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/sacloud/iaas-api-go"
 	iaastypes "github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
 )
 
 type packetFilterExpressionModel struct {
@@ -30,7 +31,7 @@ type packetFilterExpressionModel struct {
 }
 
 type packetFilterBaseModel struct {
-	ID          types.String                   `tfsdk:"id"`
+	ID          common.SakuraID                `tfsdk:"id"`
 	Name        types.String                   `tfsdk:"name"`
 	Description types.String                   `tfsdk:"description"`
 	Zone        types.String                   `tfsdk:"zone"`
@@ -38,14 +39,18 @@ type packetFilterBaseModel struct {
 }
 
 func (model *packetFilterBaseModel) updateState(pf *iaas.PacketFilter, zone string) {
-	model.ID = types.StringValue(pf.ID.String())
-	model.Name = types.StringValue(pf.Name)
+	model.ID = common.NewSakuraIDValue(pf.ID.String())
+	model.Name = common.NameFromAPI(model.Name, pf.Name)
 	model.Description = types.StringValue(pf.Description)
 	model.Zone = types.StringValue(zone)
-	model.Expression = flattenPacketFilterExpressions(pf)
+	model.Expression = flattenPacketFilterExpressions(pf, model.Expression)
 }
 
-func flattenPacketFilterExpressions(pf *iaas.PacketFilter) []*packetFilterExpressionModel {
+// flattenPacketFilterExpressions はルールを返す。ルールが無い場合、currentが空のリストであれば空のリスト、それ以外はnilを返す
+func flattenPacketFilterExpressions(pf *iaas.PacketFilter, current []*packetFilterExpressionModel) []*packetFilterExpressionModel {
+	if len(pf.Expression) == 0 && current != nil {
+		return []*packetFilterExpressionModel{}
+	}
 	var result []*packetFilterExpressionModel
 	for _, e := range pf.Expression {
 		result = append(result, flattenPacketFilterExpression(e))
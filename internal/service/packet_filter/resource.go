@@ -24,6 +24,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/helper/cleanup"
 	iaastypes "github.com/sacloud/iaas-api-go/types"
@@ -38,6 +39,7 @@ var (
 	_ resource.Resource                = &packetFilterResource{}
 	_ resource.ResourceWithConfigure   = &packetFilterResource{}
 	_ resource.ResourceWithImportState = &packetFilterResource{}
+	_ resource.ResourceWithModifyPlan  = &packetFilterResource{}
 )
 
 func NewPacketFilterResource() resource.Resource {
@@ -68,8 +70,8 @@ func (r *packetFilterResource) Schema(ctx context.Context, _ resource.SchemaRequ
 			"name":        common.SchemaResourceName("Packet Filter"),
 			"description": common.SchemaResourceDescription("Packet Filter"),
 			"zone":        common.SchemaResourceZone("Packet Filter"),
-			"expression":  schemaPacketFilterExpression(),
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+			"expression":  schemaPacketFilterShellExpression(),
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
 				Create: true, Update: true, Delete: true,
 			}),
 		},
@@ -80,6 +82,42 @@ func (r *packetFilterResource) ImportState(ctx context.Context, req resource.Imp
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *packetFilterResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanZone(ctx, r.client, req, resp)
+	common.PlanResourceDefaults(ctx, r.client, req, resp)
+	planUnmanagedExpression(ctx, req, resp)
+}
+
+// schemaPacketFilterShellExpression はsakura_packet_filterのexpression。
+// 省略した場合はsakura_packet_filter_rulesで管理できるよう、planUnmanagedExpressionで既存のルールを維持する
+func schemaPacketFilterShellExpression() schema.Attribute {
+	attr := schemaPacketFilterExpression()
+	attr.Computed = true
+	attr.Description += ". If this is omitted, the expressions are not managed by this resource and can be managed with `sakura_packet_filter_rules`"
+	return attr
+}
+
+// planUnmanagedExpression はexpressionが省略されている場合に、stateのルールをそのままplanに設定する。作成時はnullのままとする
+func planUnmanagedExpression(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var config types.List
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("expression"), &config)...)
+	if resp.Diagnostics.HasError() || !config.IsNull() {
+		return
+	}
+
+	expression := types.ListNull(config.ElementType(ctx))
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("expression"), &expression)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("expression"), expression)...)
+}
+
 func (r *packetFilterResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan packetFilterResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -94,6 +132,7 @@ func (r *packetFilterResource) Create(ctx context.Context, req resource.CreateRe
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	pfOp := iaas.NewPacketFilterOp(r.client)
 	pf, err := pfOp.Create(ctx, zone, &iaas.PacketFilterCreateRequest{
@@ -102,7 +141,7 @@ func (r *packetFilterResource) Create(ctx context.Context, req resource.CreateRe
 		Expression:  expandPacketFilterExpressions(plan.Expression),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("Create Error", fmt.Sprintf("creating SakuraCloud PacketFilter is failed: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", fmt.Errorf("creating SakuraCloud PacketFilter is failed: %w", err))
 		return
 	}
 
@@ -117,6 +156,8 @@ func (r *packetFilterResource) Read(ctx context.Context, req resource.ReadReques
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	zone := common.GetZone(state.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -146,17 +187,18 @@ func (r *packetFilterResource) Update(ctx context.Context, req resource.UpdateRe
 
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	pfOp := iaas.NewPacketFilterOp(r.client)
 	pf, err := pfOp.Read(ctx, zone, common.ExpandSakuraCloudID(plan.ID))
 	if err != nil {
-		resp.Diagnostics.AddError("Update Error", fmt.Sprintf("could not read SakuraCloud PacketFilter[%s]: %s", plan.ID.ValueString(), err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", fmt.Errorf("could not read SakuraCloud PacketFilter[%s]: %w", plan.ID.ValueString(), err))
 		return
 	}
 
 	_, err = pfOp.Update(ctx, zone, pf.ID, expandPacketFilterUpdateRequest(&plan, &state, pf), pf.ExpressionHash)
 	if err != nil {
-		resp.Diagnostics.AddError("Update Error", fmt.Sprintf("updating SakuraCloud PacketFilter[%s] is failed: %s", plan.ID.ValueString(), err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", fmt.Errorf("updating SakuraCloud PacketFilter[%s] is failed: %w", plan.ID.ValueString(), err))
 		return
 	}
 
@@ -183,6 +225,7 @@ func (r *packetFilterResource) Delete(ctx context.Context, req resource.DeleteRe
 
 	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout20min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	pf := getPacketFilter(ctx, r.client, common.ExpandSakuraCloudID(state.ID), zone, &resp.State, &resp.Diagnostics)
 	if pf == nil {
@@ -190,7 +233,7 @@ func (r *packetFilterResource) Delete(ctx context.Context, req resource.DeleteRe
 	}
 
 	if err := cleanup.DeletePacketFilter(ctx, r.client, zone, pf.ID, r.client.CheckReferencedOption()); err != nil {
-		resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("deleting SakuraCloud PacketFilter[%s] is failed: %s", state.ID.ValueString(), err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Delete Error", fmt.Errorf("deleting SakuraCloud PacketFilter[%s] is failed: %w", state.ID.ValueString(), err))
 		return
 	}
 }
@@ -199,11 +242,10 @@ func getPacketFilter(ctx context.Context, client *common.APIClient, id iaastypes
 	pfOp := iaas.NewPacketFilterOp(client)
 	pf, err := pfOp.Read(ctx, zone, id)
 	if err != nil {
-		if iaas.IsNotFoundError(err) {
-			state.RemoveResource(ctx)
+		if common.HandleNotFoundOnRead(ctx, err, state, "PacketFilter", id.String()) {
 			return nil
 		}
-		diag.AddError("Get PacketFilter Error", fmt.Sprintf("could not read SakuraCloud PacketFilter[%s]: %s", id, err))
+		common.AddAPIError(ctx, diag, "Get PacketFilter Error", fmt.Errorf("could not read SakuraCloud PacketFilter[%s]: %w", id, err))
 		return nil
 	}
 
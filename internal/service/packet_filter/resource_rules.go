@@ -23,6 +23,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	validator "github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -39,6 +41,7 @@ var (
 	_ resource.Resource                = &packetFilterRulesResource{}
 	_ resource.ResourceWithConfigure   = &packetFilterRulesResource{}
 	_ resource.ResourceWithImportState = &packetFilterRulesResource{}
+	_ resource.ResourceWithModifyPlan  = &packetFilterRulesResource{}
 )
 
 func NewPacketFilterRulesResource() resource.Resource {
@@ -58,7 +61,7 @@ func (r *packetFilterRulesResource) Configure(ctx context.Context, req resource.
 }
 
 type packetFilterRulesResourceModel struct {
-	ID             types.String                   `tfsdk:"id"`
+	ID             common.SakuraID                `tfsdk:"id"`
 	Zone           types.String                   `tfsdk:"zone"`
 	PacketFilterID types.String                   `tfsdk:"packet_filter_id"`
 	Expression     []*packetFilterExpressionModel `tfsdk:"expression"`
@@ -76,15 +79,23 @@ func (r *packetFilterRulesResource) Schema(ctx context.Context, _ resource.Schem
 				Validators: []validator.String{
 					sacloudvalidator.SakuraIDValidator(),
 				},
+				// 別のパケットフィルタに付け替える場合は、元のパケットフィルタのルールを削除するため再作成する
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"expression": schemaPacketFilterExpression(),
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
 				Create: true, Update: true, Delete: true,
 			}),
 		},
 	}
 }
 
+func (r *packetFilterRulesResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanZone(ctx, r.client, req, resp)
+}
+
 func (r *packetFilterRulesResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
@@ -98,6 +109,7 @@ func (r *packetFilterRulesResource) Create(ctx context.Context, req resource.Cre
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	callPacketFilterRulesUpdate(ctx, r, &plan, &resp.State, &resp.Diagnostics)
 }
@@ -109,6 +121,8 @@ func (r *packetFilterRulesResource) Read(ctx context.Context, req resource.ReadR
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	zone := common.GetZone(state.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -132,6 +146,7 @@ func (r *packetFilterRulesResource) Update(ctx context.Context, req resource.Upd
 
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	callPacketFilterRulesUpdate(ctx, r, &plan, &resp.State, &resp.Diagnostics)
 }
@@ -151,6 +166,7 @@ func (r *packetFilterRulesResource) Delete(ctx context.Context, req resource.Del
 
 	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	common.SakuraMutexKV.Lock(pfID)
 	defer common.SakuraMutexKV.Unlock(pfID)
@@ -167,16 +183,16 @@ func (r *packetFilterRulesResource) Delete(ctx context.Context, req resource.Del
 		Expression:  []*iaas.PacketFilterExpression{}, // Set empty expressions to delete all rules
 	}, pf.ExpressionHash)
 	if err != nil {
-		resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("updating SakuraCloud PacketFilter[%s] is failed: %s", pfID, err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Delete Error", fmt.Errorf("updating SakuraCloud PacketFilter[%s] is failed: %w", pfID, err))
 		return
 	}
 }
 
 func (model *packetFilterRulesResourceModel) updateState(pf *iaas.PacketFilter, zone string) {
-	model.ID = types.StringValue(pf.ID.String())
+	model.ID = common.NewSakuraIDValue(pf.ID.String())
 	model.Zone = types.StringValue(zone)
 	model.PacketFilterID = types.StringValue(pf.ID.String())
-	model.Expression = flattenPacketFilterExpressions(pf)
+	model.Expression = flattenPacketFilterExpressions(pf, model.Expression)
 }
 
 func callPacketFilterRulesUpdate(ctx context.Context, r *packetFilterRulesResource, plan *packetFilterRulesResourceModel, state *tfsdk.State, diags *diag.Diagnostics) {
@@ -192,7 +208,7 @@ func callPacketFilterRulesUpdate(ctx context.Context, r *packetFilterRulesResour
 	pfOp := iaas.NewPacketFilterOp(r.client)
 	pf, err := pfOp.Read(ctx, zone, common.SakuraCloudID(pfID))
 	if err != nil {
-		diags.AddError("Update Error", fmt.Sprintf("could not read SakuraCloud PacketFilter[%s]: %s", pfID, err))
+		common.AddAPIError(ctx, diags, "Update Error", fmt.Errorf("could not read SakuraCloud PacketFilter[%s]: %w", pfID, err))
 		return
 	}
 
@@ -202,7 +218,7 @@ func callPacketFilterRulesUpdate(ctx context.Context, r *packetFilterRulesResour
 		Expression:  expandPacketFilterExpressions(plan.Expression),
 	}, pf.ExpressionHash)
 	if err != nil {
-		diags.AddError("Update Error", fmt.Sprintf("updating SakuraCloud PacketFilter[%s] is failed: %s", pfID, err))
+		common.AddAPIError(ctx, diags, "Update Error", fmt.Errorf("updating SakuraCloud PacketFilter[%s] is failed: %w", pfID, err))
 		return
 	}
 
@@ -0,0 +1,197 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package packet_filter_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test"
+)
+
+func TestAccSandboxResourcePacketFilter_order(t *testing.T) {
+	test.ParallelTest(t)
+
+	resourceName := "sakura_packet_filter.foobar"
+	rand := test.RandomName(t, "pf")
+	var pf iaas.PacketFilter
+	basicConfig := test.BuildConfigWithMap(t, testAccSakuraPacketFilter_order, map[string]any{"name": rand, "first": "80", "second": "443"})
+	reorderedConfig := test.BuildConfigWithMap(t, testAccSakuraPacketFilter_order, map[string]any{"name": rand, "first": "443", "second": "80"})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { test.AccPreCheck(t) },
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckSakuraPacketFilterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: basicConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraPacketFilterExists(resourceName, &pf),
+					testCheckSakuraPacketFilterPorts(&pf, "80", "443", ""),
+					resource.TestCheckResourceAttr(resourceName, "name", rand),
+					resource.TestCheckResourceAttr(resourceName, "expression.#", "3"),
+					resource.TestCheckResourceAttr(resourceName, "expression.0.destination_port", "80"),
+					resource.TestCheckResourceAttr(resourceName, "expression.1.destination_port", "443"),
+					resource.TestCheckResourceAttr(resourceName, "expression.2.allow", "false"),
+				),
+			},
+			test.StablePlanStep(basicConfig, resourceName, "id", "zone"),
+			{
+				// ルールの並び替えのみの変更もAPIに反映する
+				Config: reorderedConfig,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraPacketFilterExists(resourceName, &pf),
+					testCheckSakuraPacketFilterPorts(&pf, "443", "80", ""),
+					resource.TestCheckResourceAttr(resourceName, "expression.0.destination_port", "443"),
+					resource.TestCheckResourceAttr(resourceName, "expression.1.destination_port", "80"),
+				),
+			},
+			test.ImportStep(resourceName, "timeouts"),
+		},
+	})
+}
+
+func TestAccSandboxResourcePacketFilterRules_basic(t *testing.T) {
+	test.ParallelTest(t)
+
+	resourceName := "sakura_packet_filter_rules.foobar"
+	rand := test.RandomName(t, "pf")
+	var pf iaas.PacketFilter
+	config := test.BuildConfigWithMap(t, testAccSakuraPacketFilterRules_basic, map[string]any{"name": rand})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { test.AccPreCheck(t) },
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckSakuraPacketFilterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraPacketFilterExists("sakura_packet_filter.foobar", &pf),
+					resource.TestCheckResourceAttrPair(resourceName, "packet_filter_id", "sakura_packet_filter.foobar", "id"),
+					resource.TestCheckResourceAttr(resourceName, "expression.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "expression.0.destination_port", "22"),
+				),
+			},
+			// expressionを省略したsakura_packet_filterはsakura_packet_filter_rulesのルールを変更しない
+			{
+				Config:           config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{PreApply: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()}},
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraPacketFilterExists("sakura_packet_filter.foobar", &pf),
+					testCheckSakuraPacketFilterPorts(&pf, "22", ""),
+					resource.TestCheckResourceAttr("sakura_packet_filter.foobar", "expression.#", "2"),
+					resource.TestCheckResourceAttrPair("data.sakura_packet_filter.foobar", "id", "sakura_packet_filter.foobar", "id"),
+					resource.TestCheckResourceAttr("data.sakura_packet_filter.foobar", "expression.#", "2"),
+				),
+			},
+			test.ImportStep(resourceName, "timeouts"),
+		},
+	})
+}
+
+func testCheckSakuraPacketFilterExists(n string, pf *iaas.PacketFilter) resource.TestCheckFunc {
+	return test.CheckExists(n, test.ExistsCheck[iaas.PacketFilter]{
+		Kind: "PacketFilter",
+		Read: func(ctx context.Context, rs *terraform.ResourceState) (*iaas.PacketFilter, error) {
+			return iaas.NewPacketFilterOp(test.AccClientGetter()).Read(ctx, rs.Primary.Attributes["zone"], common.SakuraCloudID(rs.Primary.ID))
+		},
+		ID: func(v *iaas.PacketFilter) string { return v.ID.String() },
+	}, pf)
+}
+
+// testCheckSakuraPacketFilterPorts はAPIのルールの宛先ポートが順に一致することを検証する
+func testCheckSakuraPacketFilterPorts(pf *iaas.PacketFilter, ports ...string) resource.TestCheckFunc {
+	return test.CheckFetched(pf, func(v *iaas.PacketFilter) error {
+		if len(v.Expression) != len(ports) {
+			return fmt.Errorf("unexpected number of expressions: want %d, got %d", len(ports), len(v.Expression))
+		}
+		for i, port := range ports {
+			if got := string(v.Expression[i].DestinationPort); got != port {
+				return fmt.Errorf("unexpected destination port of expression[%d]: want %q, got %q", i, port, got)
+			}
+		}
+		return nil
+	})
+}
+
+var testCheckSakuraPacketFilterDestroy = test.CheckDestroy("sakura_packet_filter", func(ctx context.Context, rs *terraform.ResourceState) error {
+	zone := rs.Primary.Attributes["zone"]
+	if zone == "" {
+		return errors.New("zone is not set")
+	}
+	_, err := iaas.NewPacketFilterOp(test.AccClientGetter()).Read(ctx, zone, common.SakuraCloudID(rs.Primary.ID))
+	return err
+})
+
+var testAccSakuraPacketFilter_order = `
+resource "sakura_packet_filter" "foobar" {
+  name        = "{{ .name }}"
+  description = "description"
+
+  expression = [
+    {
+      protocol         = "tcp"
+      destination_port = "{{ .first }}"
+    },
+    {
+      protocol         = "tcp"
+      destination_port = "{{ .second }}"
+    },
+    {
+      protocol    = "ip"
+      allow       = false
+      description = "deny all"
+    },
+  ]
+}`
+
+var testAccSakuraPacketFilterRules_basic = `
+resource "sakura_packet_filter" "foobar" {
+  name = "{{ .name }}"
+}
+
+resource "sakura_packet_filter_rules" "foobar" {
+  packet_filter_id = sakura_packet_filter.foobar.id
+
+  expression = [
+    {
+      protocol         = "tcp"
+      destination_port = "22"
+    },
+    {
+      protocol = "ip"
+      allow    = false
+    },
+  ]
+}
+
+data "sakura_packet_filter" "foobar" {
+  name = sakura_packet_filter.foobar.name
+
+  depends_on = [sakura_packet_filter_rules.foobar]
+}`
@@ -27,10 +27,10 @@ import (
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/desc"
 )
 
-func schemaPacketFilterExpression() schema.Attribute {
+func schemaPacketFilterExpression() schema.ListNestedAttribute {
 	return schema.ListNestedAttribute{
 		Optional:    true,
-		Description: "List of packet filter expressions",
+		Description: "List of packet filter expressions. The expressions are evaluated in the order of this list",
 		Validators: []validator.List{
 			listvalidator.SizeAtMost(30),
 		},
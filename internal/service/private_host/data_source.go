@@ -90,6 +90,8 @@ func (d *privateHostDataSource) Read(ctx context.Context, req datasource.ReadReq
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	zone := common.GetZone(data.Zone, d.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -98,7 +100,7 @@ func (d *privateHostDataSource) Read(ctx context.Context, req datasource.ReadReq
 	searcher := iaas.NewPrivateHostOp(d.client)
 	res, err := searcher.Find(ctx, zone, common.CreateFindCondition(data.ID, data.Name, data.Tags))
 	if err != nil {
-		resp.Diagnostics.AddError("Read Error", err.Error())
+		common.AddAPIError(ctx, &resp.Diagnostics, "Read Error", err)
 		return
 	}
 	if res == nil || res.Count == 0 || len(res.PrivateHosts) == 0 {
@@ -45,6 +45,7 @@ var (
 	_ resource.Resource                = &privateHostResource{}
 	_ resource.ResourceWithConfigure   = &privateHostResource{}
 	_ resource.ResourceWithImportState = &privateHostResource{}
+	_ resource.ResourceWithModifyPlan  = &privateHostResource{}
 )
 
 func NewPrivateHostResource() resource.Resource {
@@ -100,7 +101,7 @@ func (r *privateHostResource) Schema(ctx context.Context, req resource.SchemaReq
 				Computed:    true,
 				Description: "The total size of memory assigned to servers on the private host",
 			},
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
 				Create: true, Update: true, Delete: true,
 			}),
 		},
@@ -111,6 +112,11 @@ func (r *privateHostResource) ImportState(ctx context.Context, req resource.Impo
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *privateHostResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanZone(ctx, r.client, req, resp)
+	common.PlanResourceDefaults(ctx, r.client, req, resp)
+}
+
 func (r *privateHostResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan privateHostResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -120,6 +126,7 @@ func (r *privateHostResource) Create(ctx context.Context, req resource.CreateReq
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(plan.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -129,13 +136,13 @@ func (r *privateHostResource) Create(ctx context.Context, req resource.CreateReq
 	phOp := iaas.NewPrivateHostOp(r.client)
 	planID, err := expandPrivateHostPlanID(ctx, &plan, r.client, zone)
 	if err != nil {
-		resp.Diagnostics.AddError("Create Error", err.Error())
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", err)
 		return
 	}
 
 	ph, err := phOp.Create(ctx, zone, expandPrivateHostCreateRequest(&plan, planID))
 	if err != nil {
-		resp.Diagnostics.AddError("Create Error", fmt.Sprintf("creating SakuraCloud PrivateHost is failed: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", fmt.Errorf("creating SakuraCloud PrivateHost is failed: %w", err))
 		return
 	}
 
@@ -150,6 +157,8 @@ func (r *privateHostResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	zone := common.GetZone(state.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -173,6 +182,7 @@ func (r *privateHostResource) Update(ctx context.Context, req resource.UpdateReq
 
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(plan.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -182,7 +192,7 @@ func (r *privateHostResource) Update(ctx context.Context, req resource.UpdateReq
 	phOp := iaas.NewPrivateHostOp(r.client)
 	_, err := phOp.Update(ctx, zone, common.ExpandSakuraCloudID(plan.ID), expandPrivateHostUpdateRequest(&plan))
 	if err != nil {
-		resp.Diagnostics.AddError("Update Error", fmt.Sprintf("updating SakuraCloud PrivateHost[%s] is failed: %s", plan.ID.ValueString(), err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", fmt.Errorf("updating SakuraCloud PrivateHost[%s] is failed: %w", plan.ID.ValueString(), err))
 		return
 	}
 
@@ -204,6 +214,7 @@ func (r *privateHostResource) Delete(ctx context.Context, req resource.DeleteReq
 
 	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout20min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(state.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -216,7 +227,7 @@ func (r *privateHostResource) Delete(ctx context.Context, req resource.DeleteReq
 	}
 
 	if err := cleanup.DeletePrivateHost(ctx, r.client, zone, ph.ID, r.client.CheckReferencedOption()); err != nil {
-		resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("deleting SakuraCloud PrivateHost[%s] is failed: %s", state.ID.ValueString(), err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Delete Error", fmt.Errorf("deleting SakuraCloud PrivateHost[%s] is failed: %w", state.ID.ValueString(), err))
 		return
 	}
 }
@@ -225,11 +236,10 @@ func getPrivateHost(ctx context.Context, client *common.APIClient, zone string,
 	phOp := iaas.NewPrivateHostOp(client)
 	ph, err := phOp.Read(ctx, zone, id)
 	if err != nil {
-		if iaas.IsNotFoundError(err) {
-			state.RemoveResource(ctx)
+		if common.HandleNotFoundOnRead(ctx, err, state, "PrivateHost", id.String()) {
 			return nil
 		}
-		diags.AddError("Get PrivateHost Error", fmt.Sprintf("could not read SakuraCloud PrivateHost[%s]: %s", id.String(), err))
+		common.AddAPIError(ctx, diags, "Get PrivateHost Error", fmt.Errorf("could not read SakuraCloud PrivateHost[%s]: %w", id.String(), err))
 		return nil
 	}
 
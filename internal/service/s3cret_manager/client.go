@@ -0,0 +1,39 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret_manager
+
+import (
+	"context"
+
+	sm "github.com/sacloud/secretmanager-api-go"
+	v1 "github.com/sacloud/secretmanager-api-go/apis/v1"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+)
+
+// secretManagerAPI はシークレットマネージャのリソース/データソースが利用するAPI。テストではダブルに差し替える
+type secretManagerAPI interface {
+	SecretManagerVaultOp() (sm.VaultAPI, error)
+	SecretManagerSecretOp(vaultID string) (sm.SecretAPI, error)
+	SecretManagerVaultList(ctx context.Context) (vaults []v1.Vault, truncated bool, err error)
+	SecretManagerSecretList(ctx context.Context, vaultID string) (secrets []v1.Secret, truncated bool, err error)
+	BulkWriteParallelism() int
+	IgnoreSystemTags() bool
+	ResourceDefaults() common.ResourceDefaults
+	// validate_referencesが有効な場合に、kms_key_idのキーが存在するかをplan時に確認するために利用する
+	ValidateReferences() bool
+	KMSKeyExists(ctx context.Context, id string) (bool, error)
+}
+
+var _ secretManagerAPI = (*common.APIClient)(nil)
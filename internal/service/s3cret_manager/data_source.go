@@ -16,18 +16,20 @@ package secret_manager
 
 import (
 	"context"
-	"fmt"
-	"slices"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
-	sm "github.com/sacloud/secretmanager-api-go"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	v1 "github.com/sacloud/secretmanager-api-go/apis/v1"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/filter"
 )
 
 type secretManagerDataSource struct {
-	client *v1.Client
+	client secretManagerAPI
 }
 
 var (
@@ -48,29 +50,44 @@ func (d *secretManagerDataSource) Configure(ctx context.Context, req datasource.
 	if apiclient == nil {
 		return
 	}
-	d.client = apiclient.SecretManagerClient
+	d.client = apiclient
 }
 
 type secretManagerDataSourceModel struct {
 	secretManagerBaseModel
+	ResourceID    common.SakuraID `tfsdk:"resource_id"`
+	IgnoreCase    types.Bool      `tfsdk:"ignore_case"`
+	NameMatch     types.String    `tfsdk:"name_match"`
+	WaitForExists types.Bool      `tfsdk:"wait_for_exists"`
+
+	Filter *common.FilterBlockModel `tfsdk:"filter"`
 }
 
 func (d *secretManagerDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"id":          common.SchemaDataSourceId("SecretManager vault"),
+			"resource_id": common.SchemaDataSourceResourceID("SecretManager vault"),
 			"description": common.SchemaDataSourceDescription("SecretManager vault"),
 			"tags":        common.SchemaDataSourceTags("SecretManager vault"),
 			"name": schema.StringAttribute{
 				Optional:    true,
 				Computed:    true,
 				Description: "The name of the SecretManager vault.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 			"kms_key_id": schema.StringAttribute{
+				CustomType:  common.SakuraIDType{},
 				Computed:    true,
 				Description: "KMS key id for the SecretManager vault.",
 			},
+			"ignore_case":     common.SchemaDataSourceIgnoreCase("SecretManager vault"),
+			"name_match":      common.SchemaDataSourceNameMatch("SecretManager vault"),
+			"wait_for_exists": common.SchemaDataSourceWaitForExists("SecretManager vault"),
 		},
+		Blocks: common.FilterSchema(nil),
 	}
 }
 
@@ -81,29 +98,72 @@ func (d *secretManagerDataSource) Read(ctx context.Context, req datasource.ReadR
 		return
 	}
 
-	vaultOp := sm.NewVaultOp(d.client)
+	ctx = common.WithAPIErrorCapture(ctx)
+	// 同じ名前で検索する複数のデータソースが1回の一覧取得を共有できるよう、一覧の結果のキャッシュを利用する
+	ctx = common.WithListCache(ctx)
 
-	var vault *v1.Vault
-	var err error
-	if !data.Name.IsNull() {
-		vaults, err := vaultOp.List(ctx)
-		if err != nil {
-			resp.Diagnostics.AddError("SecretManager List Error", err.Error())
-			return
-		}
-		vault, err = FilterSecretManagerVaultByName(vaults, data.Name.ValueString())
-		if err != nil {
-			resp.Diagnostics.AddError("SecretManager Filter Error", err.Error())
-			return
+	data.ID = common.DataSourceLookupID(data.ID, data.ResourceID)
+	// name/tags/filterはいずれも一覧からの絞り込みの条件で、指定した場合はidと組み合わせてAND条件として評価する
+	byCondition := !data.Name.IsNull() || len(data.Tags.Elements()) > 0 || data.Filter != nil
+	if !byCondition && data.ID.IsNull() {
+		resp.Diagnostics.AddError("Missing Attribute", "Either 'id', 'name', 'tags' or 'filter' must be specified.")
+		return
+	}
+	cond, err := common.ExpandFilterCondition(data.Filter, "Description", "KmsKeyID")
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("filter").AtName("condition"), "Invalid Filter", err.Error())
+		return
+	}
+	cond.Name = data.Name.ValueString()
+	cond.NameMatch = data.NameMatch.ValueString()
+	cond.IgnoreCase = data.IgnoreCase.ValueBool()
+	// filterブロックのtagsと同様に、指定したすべてのタグを持つものを選ぶ
+	cond.Tags = append(cond.Tags, common.TsetToStrings(data.Tags)...)
+
+	vaultOp, err := d.client.SecretManagerVaultOp()
+	if err != nil {
+		resp.Diagnostics.AddError("SecretManager Client Error", err.Error())
+		return
+	}
+	var searched int
+	var truncated bool
+	lookup := func(ctx context.Context) (*v1.Vault, error) {
+		// idが指定されている場合は一覧を取得せず、IDで直接参照する
+		if !data.ID.IsNull() {
+			vault, err := vaultOp.Read(ctx, data.ID.CanonicalValue())
+			if err != nil || !byCondition {
+				return vault, err
+			}
+			return FilterSecretManagerVaultByName([]v1.Vault{*vault}, cond)
 		}
-	} else if !data.ID.IsNull() {
-		vault, err = vaultOp.Read(ctx, data.ID.ValueString())
+		vaults, more, err := d.client.SecretManagerVaultList(ctx)
 		if err != nil {
-			resp.Diagnostics.AddError("SecretManager Read Error", err.Error())
-			return
+			return nil, err
 		}
+		searched, truncated = len(vaults), more
+		return FilterSecretManagerVaultByName(vaults, cond)
+	}
+
+	var vault *v1.Vault
+	if data.WaitForExists.ValueBool() {
+		vault, err = common.WaitForExists(ctx, lookup)
 	} else {
-		resp.Diagnostics.AddError("Missing Attribute", "Either 'id' or 'name' must be specified.")
+		vault, err = lookup(ctx)
+	}
+	if truncated {
+		common.AddListTruncatedWarning(&resp.Diagnostics, "SecretManager vaults", searched)
+	}
+	if err != nil {
+		switch {
+		case common.IsCanceled(ctx, err):
+			common.AddCanceledError(&resp.Diagnostics, "SecretManager Read Error", err)
+		case byCondition && common.APIStatusCode(err) == 0:
+			resp.Diagnostics.AddError("SecretManager Filter Error", err.Error())
+		case data.ID.IsNull():
+			common.AddAPIError(ctx, &resp.Diagnostics, "SecretManager List Error", err)
+		default:
+			common.AddAPIError(ctx, &resp.Diagnostics, "SecretManager Read Error", err)
+		}
 		return
 	}
 
@@ -111,22 +171,18 @@ func (d *secretManagerDataSource) Read(ctx context.Context, req datasource.ReadR
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
-func FilterSecretManagerVaultByName(vaults []v1.Vault, name string) (*v1.Vault, error) {
-	match := slices.Collect(func(yield func(v1.Vault) bool) {
-		for _, v := range vaults {
-			if name != v.Name {
-				continue
-			}
-			if !yield(v) {
-				return
-			}
-		}
-	})
-	if len(match) == 0 {
-		return nil, fmt.Errorf("no result")
-	}
-	if len(match) > 1 {
-		return nil, fmt.Errorf("multiple SecretManager vault resources found with the same condition. name=%q", name)
+func FilterSecretManagerVaultByName(vaults []v1.Vault, cond filter.Condition) (*v1.Vault, error) {
+	return filter.One(vaults, cond, vaultAttributes, "SecretManager vault")
+}
+
+func vaultAttributes(vault v1.Vault) filter.Attributes {
+	return filter.Attributes{
+		ID:   vault.ID,
+		Name: vault.Name,
+		Tags: vault.Tags,
+		Fields: map[string]string{
+			"Description": vault.Description.Value,
+			"KmsKeyID":    vault.KmsKeyID,
+		},
 	}
-	return &match[0], nil
 }
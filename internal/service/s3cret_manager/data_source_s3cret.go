@@ -15,17 +15,23 @@ package secret_manager
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	sm "github.com/sacloud/secretmanager-api-go"
 	v1 "github.com/sacloud/secretmanager-api-go/apis/v1"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	sacloudvalidator "github.com/sacloud/terraform-provider-sakuracloud/internal/validator"
 )
 
 type secretManagerSecretDataSource struct {
-	client *v1.Client
+	client secretManagerAPI
 }
 
 var (
@@ -46,7 +52,7 @@ func (d *secretManagerSecretDataSource) Configure(ctx context.Context, req datas
 	if apiclient == nil {
 		return
 	}
-	d.client = apiclient.SecretManagerClient
+	d.client = apiclient
 }
 
 type secretManagerSecretDataSourceModel struct {
@@ -63,10 +69,16 @@ func (d *secretManagerSecretDataSource) Schema(_ context.Context, req datasource
 			"vault_id": schema.StringAttribute{
 				Required:    true,
 				Description: "The secret manager's vault id.",
+				Validators: []validator.String{
+					sacloudvalidator.SakuraIDValidator(),
+				},
 			},
 			"version": schema.Int64Attribute{
 				Optional:    true,
 				Description: "Target version to unveil stored secret. Without this parameter, latest version is used.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
 			},
 			"value": schema.StringAttribute{
 				Computed:    true,
@@ -84,15 +96,27 @@ func (d *secretManagerSecretDataSource) Read(ctx context.Context, req datasource
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	unveilReq := v1.Unveil{Name: data.Name.ValueString()}
 	if !data.Version.IsNull() {
 		unveilReq.Version = v1.NewOptNilInt(int(data.Version.ValueInt64()))
 	}
 
-	secretOp := sm.NewSecretOp(d.client, data.VaultID.ValueString())
+	secretOp, err := d.client.SecretManagerSecretOp(data.VaultID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("SecretManagerSecret Client Error", err.Error())
+		return
+	}
 	unveil, err := secretOp.Unveil(ctx, unveilReq)
 	if err != nil {
-		resp.Diagnostics.AddError("SecretManagerSecret Unveil Error", err.Error())
+		if common.APIStatusCode(err) == http.StatusNotFound {
+			if attr, detail, ok := unveilNotFoundDetail(ctx, d.client, &data); ok {
+				resp.Diagnostics.AddAttributeError(attr, "SecretManagerSecret Not Found", detail)
+				return
+			}
+		}
+		common.AddAPIError(ctx, &resp.Diagnostics, "SecretManagerSecret Unveil Error", err)
 		return
 	}
 
@@ -104,3 +128,18 @@ func (d *secretManagerSecretDataSource) Read(ctx context.Context, req datasource
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
+
+// unveilNotFoundDetail はUnveilが404を返した場合に、シークレットの一覧から存在しないのがシークレットかバージョンかを判定して説明を返す。
+// 一覧の取得に失敗した場合などはfalseを返し、呼び出し元はUnveilのエラーをそのまま報告する
+func unveilNotFoundDetail(ctx context.Context, client secretManagerAPI, data *secretManagerSecretDataSourceModel) (path.Path, string, bool) {
+	vaultID, name := data.VaultID.ValueString(), data.Name.ValueString()
+	secret, err := FilterSecretManagerSecretByName(ctx, client, vaultID, name)
+	switch {
+	case errors.Is(err, common.ErrFilterNoResult):
+		return path.Root("name"), fmt.Sprintf("secret %q does not exist in SecretManager vault[%s]", name, vaultID), true
+	case err != nil || data.Version.IsNull():
+		return path.Empty(), "", false
+	}
+	return path.Root("version"), fmt.Sprintf("version %d of secret %q does not exist in SecretManager vault[%s]: the latest version is %d",
+		data.Version.ValueInt64(), name, vaultID, secret.LatestVersion), true
+}
@@ -23,8 +23,11 @@ import (
 )
 
 func TestAccSakuraDataSourceSecretManagerSecret_basic(t *testing.T) {
+	test.SkipInSandbox(t, "SecretManager is not available in the sandbox")
+	test.ParallelTest(t)
+
 	resourceName := "data.sakura_secret_manager_secret.foobar"
-	rand := test.RandomName()
+	rand := test.RandomName(t, "secret")
 
 	var secret v1.Secret
 	resource.Test(t, resource.TestCase{
@@ -32,12 +35,12 @@ func TestAccSakuraDataSourceSecretManagerSecret_basic(t *testing.T) {
 		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: test.BuildConfigWithArgs(testAccSakuraDataSourceSecretManagerSecret_byName, rand),
+				Config: test.BuildConfigWithMap(t, testAccSakuraDataSourceSecretManagerSecret_byName, map[string]any{"name": rand}),
 				Check: resource.ComposeTestCheckFunc(
 					testCheckSakuraSecretManagerSecretExists("sakura_secret_manager_secret.foobar", &secret),
 					test.CheckSakuraDataSourceExists(resourceName),
 					resource.TestCheckResourceAttr(resourceName, "name", rand),
-					resource.TestCheckResourceAttr(resourceName, "value", "value1"),
+					resource.TestCheckResourceAttr(resourceName, "value", test.SensitiveValue("value1")),
 					resource.TestCheckResourceAttr(resourceName, "version", "1"),
 				),
 			},
@@ -48,12 +51,12 @@ func TestAccSakuraDataSourceSecretManagerSecret_basic(t *testing.T) {
 //nolint:gosec
 var testAccSakuraDataSourceSecretManagerSecret_byName = `
 resource "sakura_kms" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description"
 }
 
 resource "sakura_secret_manager" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description"
   kms_key_id  = sakura_kms.foobar.id
 
@@ -61,7 +64,7 @@ resource "sakura_secret_manager" "foobar" {
 }
 
 resource "sakura_secret_manager_secret" "foobar" {
-  name     = "{{ .arg0 }}"
+  name     = "{{ .name }}"
   value    = "value1"
   vault_id = sakura_secret_manager.foobar.id
 
@@ -69,7 +72,7 @@ resource "sakura_secret_manager_secret" "foobar" {
 }
 
 data "sakura_secret_manager_secret" "foobar" {
-  name     = "{{ .arg0 }}"
+  name     = "{{ .name }}"
   vault_id = sakura_secret_manager.foobar.id
 
   depends_on = [sakura_secret_manager_secret.foobar]
@@ -16,17 +16,22 @@ package secret_manager_test
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	v1 "github.com/sacloud/secretmanager-api-go/apis/v1"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/filter"
 	secret_manager "github.com/sacloud/terraform-provider-sakuracloud/internal/service/s3cret_manager"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/test"
 )
 
 func TestAccSakuraDataSourceSecretManager_basic(t *testing.T) {
+	test.SkipInSandbox(t, "SecretManager is not available in the sandbox")
+	test.ParallelTest(t)
+
 	resourceName := "data.sakura_secret_manager.foobar"
-	rand := test.RandomName()
+	rand := test.RandomName(t, "vault")
 
 	var vault v1.Vault
 	resource.Test(t, resource.TestCase{
@@ -34,7 +39,7 @@ func TestAccSakuraDataSourceSecretManager_basic(t *testing.T) {
 		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: test.BuildConfigWithArgs(testAccSakuraDataSourceSecretManager_byName, rand),
+				Config: test.BuildConfigWithMap(t, testAccSakuraDataSourceSecretManager_byName, map[string]any{"name": rand}),
 				Check: resource.ComposeTestCheckFunc(
 					testCheckSakuraSecretManagerExists("sakura_secret_manager.foobar", &vault),
 					test.CheckSakuraDataSourceExists(resourceName),
@@ -43,12 +48,12 @@ func TestAccSakuraDataSourceSecretManager_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "tags.#", "2"),
 					resource.TestCheckResourceAttr(resourceName, "tags.0", "tag1"),
 					resource.TestCheckResourceAttr(resourceName, "tags.1", "tag2"),
-					// 綺麗に動的にkms_key_idをテストで取得する方法があればコメントアウト
-					// resource.TestCheckResourceAttr(resourceName, "kms_key_id", vault.KmsKeyID),
+					resource.TestCheckResourceAttrPair(resourceName, "kms_key_id", "sakura_kms.foobar", "id"),
+					testCheckSakuraSecretManagerKmsKeyID(&vault, "sakura_kms.foobar"),
 				),
 			},
 			{
-				Config: test.BuildConfigWithArgs(testAccSakuraDataSourceSecretManager_byResourceId, rand),
+				Config: test.BuildConfigWithMap(t, testAccSakuraDataSourceSecretManager_byResourceId, map[string]any{"name": rand}),
 				Check: resource.ComposeTestCheckFunc(
 					testCheckSakuraSecretManagerExists("sakura_secret_manager.foobar", &vault),
 					test.CheckSakuraDataSourceExists(resourceName),
@@ -57,7 +62,17 @@ func TestAccSakuraDataSourceSecretManager_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "tags.#", "2"),
 					resource.TestCheckResourceAttr(resourceName, "tags.0", "tag1"),
 					resource.TestCheckResourceAttr(resourceName, "tags.1", "tag2"),
-					// resource.TestCheckResourceAttr(resourceName, "kms_key_id", vault.KmsKeyID),
+					resource.TestCheckResourceAttrPair(resourceName, "kms_key_id", "sakura_kms.foobar", "id"),
+					resource.TestCheckResourceAttrPair(resourceName, "id", "sakura_secret_manager.foobar", "id"),
+					testCheckSakuraSecretManagerKmsKeyID(&vault, "sakura_kms.foobar"),
+				),
+			},
+			{
+				Config: test.BuildConfigWithMap(t, testAccSakuraDataSourceSecretManager_byId, map[string]any{"name": rand}),
+				Check: resource.ComposeTestCheckFunc(
+					test.CheckSakuraDataSourceExists(resourceName),
+					resource.TestCheckResourceAttrPair(resourceName, "id", "sakura_secret_manager.foobar", "id"),
+					resource.TestCheckResourceAttr(resourceName, "name", rand),
 				),
 			},
 		},
@@ -67,13 +82,13 @@ func TestAccSakuraDataSourceSecretManager_basic(t *testing.T) {
 //nolint:gosec
 var testAccSakuraDataSourceSecretManager_byName = `
 resource "sakura_kms" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description"
   tags        = ["tag1", "tag2"]
 }
 
 resource "sakura_secret_manager" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description"
   tags        = ["tag1", "tag2"]
   kms_key_id  = sakura_kms.foobar.id
@@ -82,7 +97,7 @@ resource "sakura_secret_manager" "foobar" {
 }
 
 data "sakura_secret_manager" "foobar" {
-  name = "{{ .arg0 }}"
+  name = "{{ .name }}"
 
   depends_on = [sakura_secret_manager.foobar]
 }`
@@ -90,13 +105,13 @@ data "sakura_secret_manager" "foobar" {
 //nolint:gosec
 var testAccSakuraDataSourceSecretManager_byResourceId = `
 resource "sakura_kms" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description"
   tags        = ["tag1", "tag2"]
 }
 
 resource "sakura_secret_manager" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description"
   tags        = ["tag1", "tag2"]
   kms_key_id  = sakura_kms.foobar.id
@@ -110,6 +125,29 @@ data "sakura_secret_manager" "foobar" {
   depends_on = [sakura_secret_manager.foobar]
 }`
 
+//nolint:gosec
+var testAccSakuraDataSourceSecretManager_byId = `
+resource "sakura_kms" "foobar" {
+  name        = "{{ .name }}"
+  description = "description"
+  tags        = ["tag1", "tag2"]
+}
+
+resource "sakura_secret_manager" "foobar" {
+  name        = "{{ .name }}"
+  description = "description"
+  tags        = ["tag1", "tag2"]
+  kms_key_id  = sakura_kms.foobar.id
+
+  depends_on = [sakura_kms.foobar]
+}
+
+data "sakura_secret_manager" "foobar" {
+  id = sakura_secret_manager.foobar.id
+
+  depends_on = [sakura_secret_manager.foobar]
+}`
+
 func TestFilterSecretManagerByName(t *testing.T) {
 	t.Parallel()
 
@@ -122,13 +160,20 @@ func TestFilterSecretManagerByName(t *testing.T) {
 			Name: "test-key2",
 			Tags: []string{"tag1", "tag2"},
 		},
+		{
+			Name: "TEST-KEY1",
+		},
 	}
 
 	testCases := []struct {
-		name    string
-		keyName string
-		want    *v1.Vault
-		wantErr bool
+		name       string
+		keyName    string
+		nameMatch  string
+		ignoreCase bool
+		tags       []string
+		want       *v1.Vault
+		wantErr    bool
+		errNames   string
 	}{
 		{
 			name:    "found by name",
@@ -140,19 +185,94 @@ func TestFilterSecretManagerByName(t *testing.T) {
 			keyName: "not-exist",
 			wantErr: true,
 		},
+		{
+			name:    "name is case sensitive",
+			keyName: "Test-Key2",
+			wantErr: true,
+		},
+		{
+			name:       "found by name ignoring case",
+			keyName:    "Test-Key2",
+			ignoreCase: true,
+			want:       &vaults[1],
+		},
+		{
+			name:       "multiple matches ignoring case",
+			keyName:    "test-key1",
+			ignoreCase: true,
+			wantErr:    true,
+		},
+		{
+			name:       "not found ignoring case",
+			keyName:    "test-key",
+			ignoreCase: true,
+			wantErr:    true,
+		},
+		{
+			name:      "found by prefix",
+			keyName:   "test-key2",
+			nameMatch: filter.NameMatchPrefix,
+			want:      &vaults[1],
+		},
+		{
+			name:      "multiple matches by prefix",
+			keyName:   "test-key",
+			nameMatch: filter.NameMatchPrefix,
+			wantErr:   true,
+		},
+		{
+			name:      "found by substring",
+			keyName:   "key2",
+			nameMatch: filter.NameMatchContains,
+			want:      &vaults[1],
+		},
+		{
+			name:       "multiple matches by substring ignoring case",
+			keyName:    "KEY",
+			nameMatch:  filter.NameMatchContains,
+			ignoreCase: true,
+			wantErr:    true,
+		},
+		{
+			name: "found by tags",
+			tags: []string{"tag2"},
+			want: &vaults[1],
+		},
+		{
+			name:    "found by name and tags",
+			keyName: "test-key1",
+			tags:    []string{"tag1"},
+			want:    &vaults[0],
+		},
+		{
+			name:    "name and tags are combined with AND",
+			keyName: "TEST-KEY1",
+			tags:    []string{"tag1"},
+			wantErr: true,
+		},
+		{
+			name:     "multiple matches by tags",
+			tags:     []string{"tag1"},
+			wantErr:  true,
+			errNames: `"test-key1", "test-key2"`,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			got, err := secret_manager.FilterSecretManagerVaultByName(vaults, tc.keyName)
+			got, err := secret_manager.FilterSecretManagerVaultByName(vaults, filter.Condition{Name: tc.keyName, NameMatch: tc.nameMatch, IgnoreCase: tc.ignoreCase, Tags: tc.tags})
 			if tc.wantErr && err == nil {
 				t.Errorf("filterSecretManagerByName wants error but got nil")
 			}
 			if !tc.wantErr && err != nil {
 				t.Errorf("filterSecretManagerByName error = %v", err)
 			}
+			// 曖昧な条件の場合は、一致したリソースの名前をエラーに含める
+			if tc.errNames != "" && (err == nil || !strings.Contains(err.Error(), tc.errNames)) {
+				t.Errorf("filterSecretManagerByName error = %v, want names %s", err, tc.errNames)
+			}
 
 			if !reflect.DeepEqual(got, tc.want) {
 				t.Errorf("filterSecretManagerByName got = %v, want %v", got, tc.want)
@@ -0,0 +1,227 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	api "github.com/sacloud/api-client-go"
+	v1 "github.com/sacloud/secretmanager-api-go/apis/v1"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSecretManagerDataSourceRequest(t *testing.T, name string) (datasource.ReadRequest, datasource.ReadResponse) {
+	t.Helper()
+	return newSecretManagerDataSourceRequestWith(t, types.StringNull(), types.StringValue(name))
+}
+
+func newSecretManagerDataSourceRequestWith(t *testing.T, id, name types.String) (datasource.ReadRequest, datasource.ReadResponse) {
+	t.Helper()
+
+	ctx := context.Background()
+	var schemaResp datasource.SchemaResponse
+	NewSecretManagerDataSource().Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError(), schemaResp.Diagnostics)
+	s := schemaResp.Schema
+
+	config := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	require.False(t, config.Set(ctx, &secretManagerDataSourceModel{
+		secretManagerBaseModel: secretManagerBaseModel{
+			SakuraBaseModel: common.SakuraBaseModel{
+				ID:          common.SakuraID{StringValue: id},
+				Name:        name,
+				Description: types.StringNull(),
+				Tags:        types.SetNull(types.StringType),
+			},
+			KmsKeyID: common.NewSakuraIDNull(),
+		},
+		WaitForExists: types.BoolNull(),
+	}).HasError())
+
+	return datasource.ReadRequest{Config: tfsdk.Config{Schema: s, Raw: config.Raw}},
+		datasource.ReadResponse{State: tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}}
+}
+
+func TestSecretManagerDataSource_Read(t *testing.T) {
+	ctx := context.Background()
+	vaults := func(names ...string) func(context.Context) ([]v1.Vault, error) {
+		return func(context.Context) ([]v1.Vault, error) {
+			var ret []v1.Vault
+			for i, name := range names {
+				ret = append(ret, v1.Vault{ID: fmt.Sprintf("%d00000000000", i+1), Name: name, KmsKeyID: "110000000000"})
+			}
+			return ret, nil
+		}
+	}
+
+	t.Run("found", func(t *testing.T) {
+		d := &secretManagerDataSource{client: &stubSecretManagerAPI{vaultOp: &stubVaultOp{list: vaults("foo", "bar")}}}
+
+		req, resp := newSecretManagerDataSourceRequest(t, "bar")
+		d.Read(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+		var state secretManagerDataSourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "200000000000", state.ID.ValueString())
+	})
+
+	t.Run("no vault matched", func(t *testing.T) {
+		d := &secretManagerDataSource{client: &stubSecretManagerAPI{vaultOp: &stubVaultOp{list: vaults("foo", "bar")}}}
+
+		req, resp := newSecretManagerDataSourceRequest(t, "baz")
+		d.Read(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "SecretManager Filter Error", resp.Diagnostics[0].Summary())
+		assert.Equal(t, `no SecretManager vault matched name="baz" (searched 2 SecretManager vaults)`, resp.Diagnostics[0].Detail())
+	})
+
+	t.Run("no vaults in the account", func(t *testing.T) {
+		d := &secretManagerDataSource{client: &stubSecretManagerAPI{vaultOp: &stubVaultOp{list: vaults()}}}
+
+		req, resp := newSecretManagerDataSourceRequest(t, "baz")
+		d.Read(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "SecretManager Filter Error", resp.Diagnostics[0].Summary())
+		assert.Equal(t, "no SecretManager vaults exist in this account", resp.Diagnostics[0].Detail())
+	})
+
+	t.Run("list fails", func(t *testing.T) {
+		d := &secretManagerDataSource{client: &stubSecretManagerAPI{vaultOp: &stubVaultOp{list: func(context.Context) ([]v1.Vault, error) {
+			return nil, api.NewAPIError(http.StatusForbidden, "", errors.New("forbidden"))
+		}}}}
+
+		req, resp := newSecretManagerDataSourceRequest(t, "baz")
+		d.Read(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "SecretManager List Error", resp.Diagnostics[0].Summary())
+		assert.Contains(t, resp.Diagnostics[0].Detail(), "forbidden")
+		assert.NotContains(t, resp.Diagnostics[0].Detail(), "no SecretManager vault")
+	})
+}
+
+func TestSecretManagerDataSource_Read_byID(t *testing.T) {
+	ctx := context.Background()
+	vaultOp := &stubVaultOp{read: func(_ context.Context, id string) (*v1.Vault, error) {
+		return &v1.Vault{ID: id, Name: "foo", KmsKeyID: "110000000000"}, nil
+	}}
+	d := &secretManagerDataSource{client: &stubSecretManagerAPI{vaultOp: vaultOp}}
+
+	req, resp := newSecretManagerDataSourceRequestWith(t, types.StringValue("100000000000"), types.StringNull())
+	d.Read(ctx, req, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	assert.Equal(t, []string{"Read"}, vaultOp.calls)
+
+	var state secretManagerDataSourceModel
+	require.False(t, resp.State.Get(ctx, &state).HasError())
+	assert.Equal(t, "foo", state.Name.ValueString())
+}
+
+func newSecretManagerSecretDataSourceRequest(t *testing.T, name string, version types.Int64) (datasource.ReadRequest, datasource.ReadResponse) {
+	t.Helper()
+
+	ctx := context.Background()
+	var schemaResp datasource.SchemaResponse
+	NewSecretManagerSecretDataSource().Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	require.False(t, schemaResp.Diagnostics.HasError(), schemaResp.Diagnostics)
+	s := schemaResp.Schema
+
+	config := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	require.False(t, config.Set(ctx, &secretManagerSecretDataSourceModel{
+		secretManagerSecretBaseModel: secretManagerSecretBaseModel{
+			Name:    types.StringValue(name),
+			VaultID: types.StringValue("110000000001"),
+			Version: version,
+			Value:   types.StringNull(),
+		},
+	}).HasError())
+
+	return datasource.ReadRequest{Config: tfsdk.Config{Schema: s, Raw: config.Raw}},
+		datasource.ReadResponse{State: tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}}
+}
+
+func TestSecretManagerSecretDataSource_Read(t *testing.T) {
+	ctx := context.Background()
+	secrets := func(context.Context) ([]v1.Secret, error) {
+		return []v1.Secret{{Name: "foo", LatestVersion: 3}}, nil
+	}
+	notFound := func(context.Context, v1.Unveil) (*v1.Unveil, error) {
+		return nil, api.NewAPIError(http.StatusNotFound, "", errors.New("not found"))
+	}
+
+	t.Run("found", func(t *testing.T) {
+		d := &secretManagerSecretDataSource{client: &stubSecretManagerAPI{secretOp: &stubSecretOp{
+			unveil: func(_ context.Context, req v1.Unveil) (*v1.Unveil, error) {
+				assert.Equal(t, 2, req.Version.Value)
+				return &v1.Unveil{Name: req.Name, Version: req.Version, Value: "secret"}, nil
+			},
+		}}}
+
+		req, resp := newSecretManagerSecretDataSourceRequest(t, "foo", types.Int64Value(2))
+		d.Read(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+		var state secretManagerSecretDataSourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, int64(2), state.Version.ValueInt64())
+		assert.Equal(t, "secret", state.Value.ValueString())
+	})
+
+	t.Run("version not found", func(t *testing.T) {
+		d := &secretManagerSecretDataSource{client: &stubSecretManagerAPI{secretOp: &stubSecretOp{unveil: notFound, list: secrets}}}
+
+		req, resp := newSecretManagerSecretDataSourceRequest(t, "foo", types.Int64Value(5))
+		d.Read(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "SecretManagerSecret Not Found", resp.Diagnostics[0].Summary())
+		assert.Equal(t, `version 5 of secret "foo" does not exist in SecretManager vault[110000000001]: the latest version is 3`, resp.Diagnostics[0].Detail())
+	})
+
+	t.Run("secret not found", func(t *testing.T) {
+		d := &secretManagerSecretDataSource{client: &stubSecretManagerAPI{secretOp: &stubSecretOp{unveil: notFound, list: secrets}}}
+
+		req, resp := newSecretManagerSecretDataSourceRequest(t, "bar", types.Int64Null())
+		d.Read(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, "SecretManagerSecret Not Found", resp.Diagnostics[0].Summary())
+		assert.Equal(t, `secret "bar" does not exist in SecretManager vault[110000000001]`, resp.Diagnostics[0].Detail())
+	})
+
+	t.Run("list fails", func(t *testing.T) {
+		d := &secretManagerSecretDataSource{client: &stubSecretManagerAPI{secretOp: &stubSecretOp{
+			unveil: notFound,
+			list: func(context.Context) ([]v1.Secret, error) {
+				return nil, api.NewAPIError(http.StatusForbidden, "", errors.New("forbidden"))
+			},
+		}}}
+
+		req, resp := newSecretManagerSecretDataSourceRequest(t, "foo", types.Int64Value(5))
+		d.Read(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		// 一覧を取得できない場合はUnveilのエラーをそのまま報告する
+		assert.Equal(t, "SecretManagerSecret Unveil Error", resp.Diagnostics[0].Summary())
+		assert.Contains(t, resp.Diagnostics[0].Detail(), "not found")
+	})
+}
@@ -0,0 +1,97 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret_manager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// secretの複合Import ID(<vault_id>/<secret_name>)の区切り文字
+const secretImportIDDelimiter = "/"
+
+type secretImportIDFunction struct{}
+
+var _ function.Function = &secretImportIDFunction{}
+
+func NewSecretImportIDFunction() function.Function {
+	return &secretImportIDFunction{}
+}
+
+func (f *secretImportIDFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "secret_import_id"
+}
+
+func (f *secretImportIDFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Build an import ID for sakura_secret_manager_secret",
+		Description: fmt.Sprintf("Returns the composite import ID (`<vault_id>%s<secret_name>`) used by the sakura_secret_manager_secret resource.", secretImportIDDelimiter),
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:        "vault_id",
+				Description: "The Secret Manager's vault id.",
+			},
+			function.StringParameter{
+				Name:        "secret_name",
+				Description: "The name of the secret.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *secretImportIDFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var vaultID, name string
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &vaultID, &name))
+	if resp.Error != nil {
+		return
+	}
+
+	if err := validateSecretImportIDPart(vaultID); err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("invalid vault_id: %s", err))
+		return
+	}
+	if err := validateSecretImportIDPart(name); err != nil {
+		resp.Error = function.NewArgumentFuncError(1, fmt.Sprintf("invalid secret_name: %s", err))
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, buildSecretImportID(vaultID, name)))
+}
+
+func buildSecretImportID(vaultID, name string) string {
+	return vaultID + secretImportIDDelimiter + name
+}
+
+func parseSecretImportID(id string) (string, string, error) {
+	parts := strings.Split(id, secretImportIDDelimiter)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("unexpected format of ID (%q), expected <vault_id>%s<secret_name>", id, secretImportIDDelimiter)
+	}
+	return parts[0], parts[1], nil
+}
+
+func validateSecretImportIDPart(v string) error {
+	if v == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	if strings.Contains(v, secretImportIDDelimiter) {
+		return fmt.Errorf("must not contain %q", secretImportIDDelimiter)
+	}
+	return nil
+}
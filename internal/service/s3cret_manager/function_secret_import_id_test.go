@@ -0,0 +1,99 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret_manager_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	secret_manager "github.com/sacloud/terraform-provider-sakuracloud/internal/service/s3cret_manager"
+)
+
+func TestSecretImportIDFunction(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		vaultID    string
+		secretName string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "valid",
+			vaultID:    "110000000000",
+			secretName: "db-password",
+			want:       "110000000000/db-password",
+		},
+		{
+			name:       "delimiter in vault_id",
+			vaultID:    "1100/00000000",
+			secretName: "db-password",
+			wantErr:    true,
+		},
+		{
+			name:       "delimiter in secret_name",
+			vaultID:    "110000000000",
+			secretName: "db/password",
+			wantErr:    true,
+		},
+		{
+			name:       "empty vault_id",
+			vaultID:    "",
+			secretName: "db-password",
+			wantErr:    true,
+		},
+		{
+			name:       "empty secret_name",
+			vaultID:    "110000000000",
+			secretName: "",
+			wantErr:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := function.RunRequest{
+				Arguments: function.NewArgumentsData([]attr.Value{
+					types.StringValue(tc.vaultID),
+					types.StringValue(tc.secretName),
+				}),
+			}
+			resp := &function.RunResponse{
+				Result: function.NewResultData(types.StringUnknown()),
+			}
+			secret_manager.NewSecretImportIDFunction().Run(context.Background(), req, resp)
+
+			if tc.wantErr {
+				if resp.Error == nil {
+					t.Errorf("secret_import_id wants error but got nil")
+				}
+				return
+			}
+			if resp.Error != nil {
+				t.Fatalf("secret_import_id error = %v", resp.Error)
+			}
+			if got := resp.Result.Value(); !got.Equal(types.StringValue(tc.want)) {
+				t.Errorf("secret_import_id got = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -22,12 +22,12 @@ import (
 
 type secretManagerBaseModel struct {
 	common.SakuraBaseModel
-	KmsKeyID types.String `tfsdk:"kms_key_id"`
+	KmsKeyID common.SakuraID `tfsdk:"kms_key_id"`
 }
 
 func (model *secretManagerBaseModel) updateState(vault *v1.Vault) {
-	model.UpdateBaseState(vault.ID, vault.Name, vault.Description.Value, vault.Tags)
-	model.KmsKeyID = types.StringValue(vault.KmsKeyID)
+	model.UpdateBaseState(vault.ID, vault.Name, vault.Description.Value, common.NormalizeTags(vault.Tags))
+	model.KmsKeyID = common.NewSakuraIDValue(vault.KmsKeyID)
 }
 
 type secretManagerSecretBaseModel struct {
@@ -16,29 +16,41 @@ package secret_manager
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
-	api "github.com/sacloud/api-client-go"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	sm "github.com/sacloud/secretmanager-api-go"
 	v1 "github.com/sacloud/secretmanager-api-go/apis/v1"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
 )
 
 type secretManagerResource struct {
-	client *v1.Client
+	client secretManagerAPI
 }
 
 var (
 	_ resource.Resource                = &secretManagerResource{}
 	_ resource.ResourceWithConfigure   = &secretManagerResource{}
 	_ resource.ResourceWithImportState = &secretManagerResource{}
+	_ resource.ResourceWithModifyPlan  = &secretManagerResource{}
 )
 
+// vaultAttributePaths はAPIのフィールド名とエラーを紐付ける属性の対応
+var vaultAttributePaths = common.AttributePaths{
+	"Name":        path.Root("name"),
+	"Description": path.Root("description"),
+	"Tags":        path.Root("tags"),
+	"KmsKeyID":    path.Root("kms_key_id"),
+}
+
 func NewSecretManagerResource() resource.Resource {
 	return &secretManagerResource{}
 }
@@ -52,26 +64,42 @@ func (r *secretManagerResource) Configure(ctx context.Context, req resource.Conf
 	if apiclient == nil {
 		return
 	}
-	r.client = apiclient.SecretManagerClient
+	r.client = apiclient
 }
 
 type secretManagerResourceModel struct {
 	secretManagerBaseModel
-	Timeouts timeouts.Value `tfsdk:"timeouts"`
+	common.SakuraGlobalZoneModel
+	NamePrefix types.String   `tfsdk:"name_prefix"`
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
+}
+
+// updateResourceState はupdateStateに加えて、stateに含まれていないシステムタグをtagsから取り除く
+func (model *secretManagerResourceModel) updateResourceState(vault *v1.Vault, ignoreSystemTags bool) {
+	tags := common.ManagedTags(vault.Tags, model.Tags, ignoreSystemTags)
+	model.updateState(vault)
+	model.Tags = common.FlattenTags(tags)
+	model.UpdateGlobalZoneState()
 }
 
 func (r *secretManagerResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"id":          common.SchemaResourceId("SecretManager vault"),
-			"name":        common.SchemaResourceName("SecretManager vault"),
+			"name":        common.SchemaResourceNameWithPrefix("SecretManager vault"),
+			"name_prefix": common.SchemaResourceNamePrefix("SecretManager vault"),
 			"description": common.SchemaResourceDescription("SecretManager vault"),
 			"tags":        common.SchemaResourceTags("SecretManager vault"),
+			"zone":        common.SchemaResourceGlobalZone("SecretManager vault"),
 			"kms_key_id": schema.StringAttribute{
+				CustomType:  common.SakuraIDType{},
 				Required:    true,
-				Description: "KMS key ID for the SecretManager vault.",
+				Description: "KMS key ID for the SecretManager vault. A number is also accepted",
+				PlanModifiers: []planmodifier.String{
+					common.RequiresReplaceWithReason("Changing kms_key_id replaces the SecretManager vault. Replacing this vault deletes all contained secrets.", common.SameSakuraID),
+				},
 			},
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
 				Create: true, Update: true, Delete: true,
 			}),
 		},
@@ -82,6 +110,49 @@ func (r *secretManagerResource) ImportState(ctx context.Context, req resource.Im
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *secretManagerResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanResourceDefaults(ctx, r.client, req, resp)
+	r.validateKMSKeyReference(ctx, req, resp)
+}
+
+// validateKMSKeyReference はvalidate_referencesが有効な場合に、kms_key_idのキーが存在するかを確認する。
+// 値が未確定の場合やstateから変更されていない場合は、APIを呼び出さない
+func (r *secretManagerResource) validateKMSKeyReference(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil || !r.client.ValidateReferences() {
+		return
+	}
+
+	attrPath := path.Root("kms_key_id")
+	var planned common.SakuraID
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, attrPath, &planned)...)
+	if resp.Diagnostics.HasError() || planned.IsNull() || planned.IsUnknown() {
+		return
+	}
+	if !req.State.Raw.IsNull() {
+		var prior common.SakuraID
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, attrPath, &prior)...)
+		if resp.Diagnostics.HasError() || common.SameSakuraID(prior.ValueString(), planned.ValueString()) {
+			return
+		}
+	}
+
+	id, ok := common.CanonicalSakuraID(planned.ValueString())
+	if !ok {
+		// 形式の誤りはスキーマの検証で報告される
+		return
+	}
+	exists, err := r.client.KMSKeyExists(ctx, id)
+	if err != nil {
+		resp.Diagnostics.AddAttributeWarning(attrPath, "Unable to validate kms_key_id",
+			fmt.Sprintf("checking that KMS key %q exists is failed: %s", id, err))
+		return
+	}
+	if !exists {
+		resp.Diagnostics.AddAttributeError(attrPath, "KMS key not found",
+			fmt.Sprintf("KMS key %q referenced by kms_key_id does not exist. Check the ID, or set validate_references = false in the provider to skip this check.", id))
+	}
+}
+
 func (r *secretManagerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan secretManagerResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -91,22 +162,43 @@ func (r *secretManagerResource) Create(ctx context.Context, req resource.CreateR
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
-	vaultOp := sm.NewVaultOp(r.client)
+	vaultOp, err := r.client.SecretManagerVaultOp()
+	if err != nil {
+		resp.Diagnostics.AddError("SecretManager Client Error", err.Error())
+		return
+	}
+	plan.Name = types.StringValue(common.GenerateName(plan.Name, plan.NamePrefix))
 	createdVault, err := vaultOp.Create(ctx, expandSecretManagerCreateVault(&plan))
 	if err != nil {
-		resp.Diagnostics.AddError("SecretManager Create Error", err.Error())
+		common.AddAPIAttributeError(ctx, &resp.Diagnostics, "SecretManager Create Error", err, vaultAttributePaths)
+		return
+	}
+	resp.Diagnostics.Append(common.SetCreatedID(ctx, &resp.State, createdVault.ID)...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	plan.updateState(&v1.Vault{
+	plan.updateResourceState(&v1.Vault{
 		ID:          createdVault.ID,
 		Name:        createdVault.Name,
 		Description: v1.NewOptString(createdVault.Description.Value),
 		Tags:        createdVault.Tags,
 		KmsKeyID:    createdVault.KmsKeyID,
-	})
+	}, r.client.IgnoreSystemTags())
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// 作成直後はGETで参照できない場合があるため、同じapply内のデータソースなどから参照できるようになるまで待つ
+	_, err = common.WaitForExists(ctx, func(ctx context.Context) (*v1.Vault, error) {
+		return vaultOp.Read(ctx, createdVault.ID)
+	})
+	if err != nil {
+		common.AddAPIError(ctx, &resp.Diagnostics, "SecretManager Create Error", fmt.Errorf("waiting for SecretManager vault[%s] to be visible is failed: %w", createdVault.ID, err))
+	}
 }
 
 func (r *secretManagerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -116,43 +208,72 @@ func (r *secretManagerResource) Read(ctx context.Context, req resource.ReadReque
 		return
 	}
 
-	vault := getSecretManagerVault(ctx, r.client, state.ID.ValueString(), &resp.State, &resp.Diagnostics)
+	ctx = common.WithAPIErrorCapture(ctx)
+	ctx, cond := common.WithConditionalRead(ctx, req.Private)
+
+	vaultOp, err := r.client.SecretManagerVaultOp()
+	if err != nil {
+		resp.Diagnostics.AddError("SecretManager Client Error", err.Error())
+		return
+	}
+	// 前回のReadから変更されていない(304)場合は前回のレスポンスでstateを更新する
+	vault := getSecretManagerVault(ctx, vaultOp, state.ID.ValueString(), &resp.State, &resp.Diagnostics)
 	if vault == nil {
 		return
 	}
 
-	state.updateState(vault)
+	state.updateResourceState(vault, r.client.IgnoreSystemTags())
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+	resp.Diagnostics.Append(cond.Save(ctx, resp.Private)...)
 }
 
 func (r *secretManagerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var plan secretManagerResourceModel
+	var plan, state secretManagerResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// timeoutsのみの変更、tagsのnullから空への変更などではAPIを呼び出さない
+	if !plan.HasChangeFrom(&state.SakuraBaseModel) && plan.KmsKeyID.CanonicalValue() == state.KmsKeyID.CanonicalValue() {
+		tflog.Debug(ctx, "skipping SecretManager vault update because no API attributes are changed", map[string]any{"id": state.ID.ValueString()})
+		plan.ID = state.ID
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
-	vault := getSecretManagerVault(ctx, r.client, plan.ID.ValueString(), &resp.State, &resp.Diagnostics)
+	vaultOp, err := r.client.SecretManagerVaultOp()
+	if err != nil {
+		resp.Diagnostics.AddError("SecretManager Client Error", err.Error())
+		return
+	}
+	vault := getSecretManagerVault(ctx, vaultOp, plan.ID.ValueString(), &resp.State, &resp.Diagnostics)
 	if vault == nil {
 		return
 	}
 
-	vaultOp := sm.NewVaultOp(r.client)
-	_, err := vaultOp.Update(ctx, vault.ID, expandSecretManagerUpdateVault(&plan, vault))
+	// name/description/tagsは1回の更新APIでまとめて送信する。属性ごとに更新APIを呼び出さないこと
+	updated, err := vaultOp.Update(ctx, vault.ID, expandSecretManagerUpdateVault(&plan, state.Tags, vault, r.client.IgnoreSystemTags()))
 	if err != nil {
-		resp.Diagnostics.AddError("SecretManager Update Error", err.Error())
+		common.AddAPIAttributeError(ctx, &resp.Diagnostics, "SecretManager Update Error", err, vaultAttributePaths)
 		return
 	}
 
-	vault = getSecretManagerVault(ctx, r.client, vault.ID, &resp.State, &resp.Diagnostics)
-	if vault == nil {
-		return
+	// 更新APIのレスポンスがボールト全体を含んでいればそれをstateに反映し、不完全な場合のみ改めて参照する
+	if !isCompleteVault(updated, vault.ID) {
+		updated = getSecretManagerVault(ctx, vaultOp, vault.ID, &resp.State, &resp.Diagnostics)
+		if updated == nil {
+			return
+		}
 	}
+	vault = updated
 
-	plan.updateState(vault)
+	plan.updateResourceState(vault, r.client.IgnoreSystemTags())
 	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
@@ -165,31 +286,45 @@ func (r *secretManagerResource) Delete(ctx context.Context, req resource.DeleteR
 
 	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
-	vault := getSecretManagerVault(ctx, r.client, state.ID.ValueString(), &resp.State, &resp.Diagnostics)
+	vaultOp, err := r.client.SecretManagerVaultOp()
+	if err != nil {
+		resp.Diagnostics.AddError("SecretManager Client Error", err.Error())
+		return
+	}
+	vault := getSecretManagerVault(ctx, vaultOp, state.ID.ValueString(), &resp.State, &resp.Diagnostics)
 	if vault == nil {
 		return
 	}
 
-	vaultOp := sm.NewVaultOp(r.client)
-	err := vaultOp.Delete(ctx, vault.ID)
+	// 同じapplyでシークレットを削除した直後は、バックエンドに反映されるまで競合エラーとなる場合がある
+	err = common.RetryOnConflict(ctx, func() error {
+		return vaultOp.Delete(ctx, vault.ID)
+	})
 	if err != nil {
-		resp.Diagnostics.AddError("SecretManager Delete Error", err.Error())
+		common.AddAPIError(ctx, &resp.Diagnostics, "SecretManager Delete Error", err)
 		return
 	}
 }
 
-func getSecretManagerVault(ctx context.Context, client *v1.Client, id string, state *tfsdk.State, diag *diag.Diagnostics) *v1.Vault {
-	vaultOp := sm.NewVaultOp(client)
+// isCompleteVault はAPIのレスポンスのvaultが、stateに反映できるidのボールトの全体を含んでいるかを返す
+func isCompleteVault(vault *v1.Vault, id string) bool {
+	return vault != nil && vault.ID == id && vault.Name != "" && vault.KmsKeyID != ""
+}
+
+func getSecretManagerVault(ctx context.Context, vaultOp sm.VaultAPI, id string, state *tfsdk.State, diag *diag.Diagnostics) *v1.Vault {
 	vault, err := vaultOp.Read(ctx, id)
 	if err != nil {
-		if api.IsNotFoundError(err) {
-			state.RemoveResource(ctx)
+		if common.HandleNotFoundOnRead(ctx, err, state, "SecretManager vault", id) {
 			return nil
 		}
 		diag.AddError("Get SecretManager Vault Error", err.Error())
 		return nil
 	}
+	if common.IsNotModified(ctx) {
+		tflog.Debug(ctx, "SecretManager vault is not modified since the last read, using the cached response", map[string]any{"id": id})
+	}
 
 	return vault
 }
@@ -197,22 +332,23 @@ func getSecretManagerVault(ctx context.Context, client *v1.Client, id string, st
 func expandSecretManagerCreateVault(model *secretManagerResourceModel) v1.CreateVault {
 	return v1.CreateVault{
 		Name:        model.Name.ValueString(),
-		KmsKeyID:    model.KmsKeyID.ValueString(),
+		KmsKeyID:    model.KmsKeyID.CanonicalValue(),
 		Description: v1.NewOptString(model.Description.ValueString()),
-		Tags:        common.TsetToStrings(model.Tags),
+		Tags:        common.ExpandTags(model.Tags),
 	}
 }
 
-func expandSecretManagerUpdateVault(model *secretManagerResourceModel, before *v1.Vault) v1.Vault {
+// expandSecretManagerUpdateVault は更新リクエストを返す。ignoreSystemTagsがtrueの場合、Terraform外でbeforeに付与されたシステムタグを保持する
+func expandSecretManagerUpdateVault(model *secretManagerResourceModel, priorTags types.Set, before *v1.Vault, ignoreSystemTags bool) v1.Vault {
 	req := v1.Vault{
 		Name:     model.Name.ValueString(),
 		KmsKeyID: before.KmsKeyID,
 	}
 
 	if model.Tags.IsNull() {
-		req.Tags = before.Tags
+		req.Tags = common.NormalizeTags(before.Tags)
 	} else {
-		req.Tags = common.TsetToStrings(model.Tags)
+		req.Tags = common.MergeSystemTags(common.ExpandTags(model.Tags), before.Tags, priorTags, ignoreSystemTags)
 	}
 	if model.Description.IsNull() {
 		req.Description = before.Description
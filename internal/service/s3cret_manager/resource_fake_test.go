@@ -0,0 +1,189 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret_manager_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test/fake"
+)
+
+func TestFakeSakuraSecretManagerSecret_basic(t *testing.T) {
+	test.FakePreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+
+	resourceName := "sakura_secret_manager_secret.foobar"
+	dataSourceName := "data.sakura_secret_manager_secret.foobar"
+	rand := test.RandomName(t, "secret")
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckFakeSecretManagerDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + test.BuildConfigWithMap(t, testAccSakuraDataSourceSecretManagerSecret_byName, map[string]any{"name": rand}),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckFakeSecretManagerSecretValue(server, resourceName, 1, "value1"),
+					resource.TestCheckResourceAttr(resourceName, "version", "1"),
+					resource.TestCheckResourceAttr(dataSourceName, "value", "value1"),
+					resource.TestCheckResourceAttr(dataSourceName, "version", "1"),
+				),
+			},
+			{
+				Config: server.ProviderConfig() + test.BuildConfigWithMap(t, testAccSakuraSecretManagerSecret_update, map[string]any{"name": rand}),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckFakeSecretManagerSecretValue(server, resourceName, 2, "value2"),
+					resource.TestCheckResourceAttr(resourceName, "version", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestFakeSakuraSecretManager_emptyTags(t *testing.T) {
+	test.FakePreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+
+	resourceName := "sakura_secret_manager.foobar"
+	rand := test.RandomName(t, "vault")
+	config := func(tags string) string {
+		return server.ProviderConfig() + test.BuildConfigWithMap(t, testAccSakuraSecretManager_tags, map[string]any{"name": rand, "tags": tags})
+	}
+	// 未指定、nullと空のlistを切り替えても差分にならない
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckFakeSecretManagerDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: config("tags = []"),
+				Check:  resource.TestCheckResourceAttr(resourceName, "tags.#", "0"),
+			},
+			test.StablePlanStep(config(""), resourceName, "tags"),
+			test.StablePlanStep(config("tags = null"), resourceName, "tags"),
+			{
+				Config: config(""),
+				Check:  resource.TestCheckResourceAttr(resourceName, "tags.#", "0"),
+			},
+			test.StablePlanStep(config("tags = []"), resourceName, "tags"),
+		},
+	})
+}
+
+func TestFakeSakuraSecretManager_kmsKeyIDForcesReplacement(t *testing.T) {
+	test.FakePreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+
+	resourceName := "sakura_secret_manager.foobar"
+	rand := test.RandomName(t, "vault")
+	config := func(kmsKeyID string) string {
+		return server.ProviderConfig() + test.BuildConfigWithMap(t, testAccSakuraSecretManager_kmsKey, map[string]any{"name": rand, "kms_key_id": kmsKeyID})
+	}
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckFakeSecretManagerDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: config("sakura_kms.foobar.id"),
+				Check:  resource.TestCheckResourceAttrPair(resourceName, "kms_key_id", "sakura_kms.foobar", "id"),
+			},
+			// ボールトを置き換えると含まれるシークレットがすべて削除される
+			{
+				Config: config("sakura_kms.foobar2.id"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.TestCheckResourceAttrPair(resourceName, "kms_key_id", "sakura_kms.foobar2", "id"),
+			},
+		},
+	})
+}
+
+func TestFakeSakuraSecretManagerSecret_writeOnly(t *testing.T) {
+	test.FakePreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+
+	resourceName := "sakura_secret_manager_secret.foobar"
+	rand := test.RandomName(t, "secret")
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		CheckDestroy: testCheckFakeSecretManagerDestroy(server),
+		Steps: []resource.TestStep{
+			{
+				Config: server.ProviderConfig() + test.BuildConfigWithMap(t, testAccSakuraSecretManagerSecret_writeOnly, map[string]any{"name": rand, "value": "value1", "version": 1}),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckFakeSecretManagerSecretValue(server, resourceName, 1, "value1"),
+					resource.TestCheckNoResourceAttr(resourceName, "value_wo"),
+				),
+			},
+			{
+				Config: server.ProviderConfig() + test.BuildConfigWithMap(t, testAccSakuraSecretManagerSecret_writeOnly, map[string]any{"name": rand, "value": "value2", "version": 2}),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckFakeSecretManagerSecretValue(server, resourceName, 2, "value2"),
+					resource.TestCheckResourceAttr(resourceName, "version", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testCheckFakeSecretManagerDestroy(server *fake.Server) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != "sakura_secret_manager" || rs.Primary.ID == "" {
+				continue
+			}
+			if _, ok := server.SecretManager.Vault(rs.Primary.ID); ok {
+				return fmt.Errorf("still exists SecretManager: %s", rs.Primary.ID)
+			}
+		}
+		return nil
+	}
+}
+
+func testCheckFakeSecretManagerSecretValue(server *fake.Server, n string, version int, want string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+
+		got, ok := server.SecretManager.SecretValue(rs.Primary.Attributes["vault_id"], rs.Primary.Attributes["name"], version)
+		if !ok {
+			return fmt.Errorf("not found SecretManagerSecret: %s (version %d)", rs.Primary.Attributes["name"], version)
+		}
+		if got != want {
+			return fmt.Errorf("unexpected SecretManagerSecret value: want %q, got %q", want, got)
+		}
+		return nil
+	}
+}
@@ -16,30 +16,61 @@ package secret_manager
 
 import (
 	"context"
-	"slices"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/resourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	sm "github.com/sacloud/secretmanager-api-go"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	v1 "github.com/sacloud/secretmanager-api-go/apis/v1"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/desc"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/filter"
+	sacloudvalidator "github.com/sacloud/terraform-provider-sakuracloud/internal/validator"
 )
 
 type secretManagerSecretResource struct {
-	client *v1.Client
+	client secretManagerAPI
 }
 
 var (
-	_ resource.Resource                = &secretManagerSecretResource{}
-	_ resource.ResourceWithConfigure   = &secretManagerSecretResource{}
-	_ resource.ResourceWithImportState = &secretManagerSecretResource{}
+	_ resource.Resource                     = &secretManagerSecretResource{}
+	_ resource.ResourceWithConfigure        = &secretManagerSecretResource{}
+	_ resource.ResourceWithImportState      = &secretManagerSecretResource{}
+	_ resource.ResourceWithModifyPlan       = &secretManagerSecretResource{}
+	_ resource.ResourceWithConfigValidators = &secretManagerSecretResource{}
 )
 
+var secretValueWriteOnly = common.NewWriteOnlyAttribute("value")
+
+func secretNameValidators() []validator.String {
+	return []validator.String{
+		stringvalidator.UTF8LengthBetween(1, 255),
+	}
+}
+
+// secretAttributePaths はAPIのフィールド名とエラーを紐付ける属性の対応。
+// 値はvalueとvalue_woのどちらで指定されたかによって紐付ける属性が異なる
+func secretAttributePaths(plan *secretManagerSecretResourceModel) common.AttributePaths {
+	valuePath := path.Root(secretValueWriteOnly.Name)
+	if plan.Value.IsNull() {
+		valuePath = path.Root(secretValueWriteOnly.WriteOnlyName())
+	}
+	return common.AttributePaths{
+		"Name":  path.Root("name"),
+		"Value": valuePath,
+	}
+}
+
 func NewSecretManagerSecretResource() resource.Resource {
 	return &secretManagerSecretResource{}
 }
@@ -53,40 +84,94 @@ func (r *secretManagerSecretResource) Configure(ctx context.Context, req resourc
 	if apiclient == nil {
 		return
 	}
-	r.client = apiclient.SecretManagerClient
+	r.client = apiclient
 }
 
 type secretManagerSecretResourceModel struct {
 	secretManagerSecretBaseModel
-	Timeouts timeouts.Value `tfsdk:"timeouts"`
+	ValueWO        types.String   `tfsdk:"value_wo"`
+	ValueWOVersion types.Int64    `tfsdk:"value_wo_version"`
+	Timeouts       timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *secretManagerSecretResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
-	resp.Schema = schema.Schema{
-		Attributes: map[string]schema.Attribute{
-			"name": common.SchemaResourceName("Secret Manager's secret"),
-			"vault_id": schema.StringAttribute{
-				Required:    true,
-				Description: "The Secret Manager's vault id.",
-			},
-			"version": schema.Int64Attribute{
-				Computed:    true,
-				Description: "Version of secret value. This value is incremented by create/update.",
+	attrs := map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Required:    true,
+			Description: desc.Sprintf("The name of the Secret Manager's secret. %s.", desc.Length(1, 255)),
+			Validators:  secretNameValidators(),
+		},
+		"vault_id": schema.StringAttribute{
+			Required:    true,
+			Description: "The Secret Manager's vault id.",
+			Validators: []validator.String{
+				sacloudvalidator.SakuraIDValidator(),
 			},
-			"value": schema.StringAttribute{
-				Required:    true,
-				Sensitive:   true,
-				Description: "Secret value.",
+		},
+		"version": schema.Int64Attribute{
+			Computed:    true,
+			Description: "Version of secret value. This value is incremented by create/update.",
+			PlanModifiers: []planmodifier.Int64{
+				int64planmodifier.UseStateForUnknown(),
 			},
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
-				Create: true, Update: true, Delete: true,
-			}),
 		},
+		"value": schema.StringAttribute{
+			Optional:    true,
+			Sensitive:   true,
+			Description: desc.Sprintf("Secret value. Either this or `%s` is required.", secretValueWriteOnly.WriteOnlyName()),
+		},
+		"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
+			Create: true, Update: true, Delete: true,
+		}),
+	}
+	for k, v := range secretValueWriteOnly.Schema("Secret value.") {
+		attrs[k] = v
+	}
+
+	resp.Schema = schema.Schema{Attributes: attrs}
+}
+
+func (r *secretManagerSecretResource) ConfigValidators(_ context.Context) []resource.ConfigValidator {
+	return append(secretValueWriteOnly.ConfigValidators(),
+		resourcevalidator.AtLeastOneOf(path.MatchRoot("value"), path.MatchRoot(secretValueWriteOnly.WriteOnlyName())),
+	)
+}
+
+func (r *secretManagerSecretResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
 	}
+
+	changed, diags := secretValueWriteOnly.HasChange(ctx, req.Plan, req.State)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 値の書き込みで新しいバージョンが作成されるため、versionを(known after apply)にする
+	if changed {
+		resp.Plan.SetAttribute(ctx, path.Root("version"), types.Int64Unknown())
+	}
+}
+
+// validateSecretWrite は値を書き込む直前に、name/vault_idをまとめて検証する。
+// 他のリソースの属性を参照していてplanの時点で未知だった値はスキーマのバリデータで検証されないため、
+// APIを呼び出す前に検証し、最初のエラーで中断せずにすべての問題を1度に報告する
+func validateSecretWrite(ctx context.Context, plan *secretManagerSecretResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	diags.Append(sacloudvalidator.ValidateString(ctx, path.Root("name"), plan.Name, secretNameValidators()...)...)
+	diags.Append(sacloudvalidator.ValidateString(ctx, path.Root("vault_id"), plan.VaultID, sacloudvalidator.SakuraIDValidator())...)
+	return diags
 }
 
 func (r *secretManagerSecretResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughWithIdentity(ctx, path.Root("name"), path.Root("name"), req, resp)
+	vaultID, name, err := parseSecretImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Import Error", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("vault_id"), vaultID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
 }
 
 func (r *secretManagerSecretResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -98,14 +183,29 @@ func (r *secretManagerSecretResource) Create(ctx context.Context, req resource.C
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
-	secretOp := sm.NewSecretOp(r.client, plan.VaultID.ValueString())
+	value, diags := secretValueWriteOnly.ValueFromConfig(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(validateSecretWrite(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secretOp, err := r.client.SecretManagerSecretOp(plan.VaultID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("SecretManagerSecret Client Error", err.Error())
+		return
+	}
 	createdSec, err := secretOp.Create(ctx, v1.CreateSecret{
 		Name:  plan.Name.ValueString(),
-		Value: plan.Value.ValueString(),
+		Value: value.ValueString(),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("SecretManagerSecret Create Error", err.Error())
+		common.AddAPIAttributeError(ctx, &resp.Diagnostics, "SecretManagerSecret Create Error", err, secretAttributePaths(&plan))
 		return
 	}
 
@@ -121,34 +221,88 @@ func (r *secretManagerSecretResource) Read(ctx context.Context, req resource.Rea
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	secret := getSecretManagerSecret(ctx, r.client, &state, &resp.State, &resp.Diagnostics)
 	if secret == nil {
 		return
 	}
 
 	state.Name = types.StringValue(secret.Name)
-	state.Version = types.Int64Value(int64(secret.LatestVersion))
+	state.updateVersion(ctx, secret.LatestVersion)
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
+// updateVersion はAPIから取得した最新バージョンをstateに反映する。
+// 古いバージョンの削除などでバージョン番号がstateより小さくなった場合は書き込んだ値が失われている可能性があるため、
+// value/value_wo_versionをnullにして次回のplanで値を一度だけ書き直す。バージョンの比較によってReadをエラーにはしない
+func (model *secretManagerSecretResourceModel) updateVersion(ctx context.Context, latest int) {
+	fields := map[string]any{
+		"vault_id":       model.VaultID.ValueString(),
+		"name":           model.Name.ValueString(),
+		"state_version":  model.Version.ValueInt64(),
+		"remote_version": latest,
+	}
+	switch {
+	case latest <= 0:
+		tflog.Warn(ctx, "SecretManager secret has no version. Keeping the version in the state", fields)
+		return
+	case model.Version.IsNull() || model.Version.IsUnknown():
+	case int64(latest) < model.Version.ValueInt64():
+		tflog.Warn(ctx, "SecretManager secret version is lower than the version in the state. The secret value will be rewritten", fields)
+		model.Value = types.StringNull()
+		model.ValueWOVersion = types.Int64Null()
+	}
+	model.Version = types.Int64Value(int64(latest))
+}
+
 func (r *secretManagerSecretResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	// TODO: This is same as Create, consider refactoring
-	var plan secretManagerSecretResourceModel
+	var plan, state secretManagerSecretResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	// 値の書き込みは新しいバージョンを作成するため、値が変更されていなければAPIを呼び出さない
+	changed, diags := secretValueWriteOnly.HasChange(ctx, req.Plan, req.State)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !changed {
+		tflog.Debug(ctx, "skipping SecretManager secret update because the value is not changed", map[string]any{"name": state.Name.ValueString()})
+		plan.Version = state.Version
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
-	secretOp := sm.NewSecretOp(r.client, plan.VaultID.ValueString())
+	value, diags := secretValueWriteOnly.ValueFromConfig(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(validateSecretWrite(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secretOp, err := r.client.SecretManagerSecretOp(plan.VaultID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("SecretManagerSecret Client Error", err.Error())
+		return
+	}
 	createdSec, err := secretOp.Create(ctx, v1.CreateSecret{
 		Name:  plan.Name.ValueString(),
-		Value: plan.Value.ValueString(),
+		Value: value.ValueString(),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("SecretManagerSecret Create Error", err.Error())
+		common.AddAPIAttributeError(ctx, &resp.Diagnostics, "SecretManagerSecret Create Error", err, secretAttributePaths(&plan))
 		return
 	}
 
@@ -166,53 +320,56 @@ func (r *secretManagerSecretResource) Delete(ctx context.Context, req resource.D
 
 	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	sec := getSecretManagerSecret(ctx, r.client, &state, &resp.State, &resp.Diagnostics)
 	if sec == nil {
 		return
 	}
 
-	secretOp := sm.NewSecretOp(r.client, state.VaultID.ValueString())
-	err := secretOp.Delete(ctx, v1.DeleteSecret{Name: state.Name.ValueString()})
+	secretOp, err := r.client.SecretManagerSecretOp(state.VaultID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("SecretManagerSecret Client Error", err.Error())
+		return
+	}
+	err = common.RetryOnConflict(ctx, func() error {
+		return secretOp.Delete(ctx, v1.DeleteSecret{Name: state.Name.ValueString()})
+	})
 	if err != nil {
-		resp.Diagnostics.AddError("SecretManagerSecret Delete Error", err.Error())
+		common.AddAPIError(ctx, &resp.Diagnostics, "SecretManagerSecret Delete Error", err)
 		return
 	}
 }
 
-func getSecretManagerSecret(ctx context.Context, client *v1.Client, model *secretManagerSecretResourceModel, state *tfsdk.State, diags *diag.Diagnostics) *v1.Secret {
-	secretOp := sm.NewSecretOp(client, model.VaultID.ValueString())
-	secret, err := FilterSecretManagerSecretByName(ctx, secretOp, model.Name.ValueString())
+func getSecretManagerSecret(ctx context.Context, client secretManagerAPI, model *secretManagerSecretResourceModel, state *tfsdk.State, diags *diag.Diagnostics) *v1.Secret {
+	secret, err := FilterSecretManagerSecretByName(ctx, client, model.VaultID.ValueString(), model.Name.ValueString())
 	if err != nil {
-		if err == common.ErrFilterNoResult {
-			state.RemoveResource(ctx)
+		if common.HandleNotFoundOnRead(ctx, err, state, "SecretManager secret", model.VaultID.ValueString()+"/"+model.Name.ValueString()) {
 			return nil
 		}
-		diags.AddError("SecretManagerSecret Read Error", err.Error())
+		common.AddAPIError(ctx, diags, "SecretManagerSecret Read Error", err)
 		return nil
 	}
 
 	return secret
 }
 
-func FilterSecretManagerSecretByName(ctx context.Context, secretOp sm.SecretAPI, name string) (*v1.Secret, error) {
-	secrets, err := secretOp.List(ctx)
+// FilterSecretManagerSecretByName はボールト内のシークレットの一覧を取得し、nameに一致するシークレットを返す。
+// APIが一覧の全件を返さなかった場合は、削除済みと誤認しないようErrFilterNoResult以外のエラーを返す
+func FilterSecretManagerSecretByName(ctx context.Context, client secretManagerAPI, vaultID, name string) (*v1.Secret, error) {
+	secrets, truncated, err := client.SecretManagerSecretList(ctx, vaultID)
 	if err != nil {
 		return nil, err
 	}
 
-	match := slices.Collect(func(yield func(v1.Secret) bool) {
-		for _, v := range secrets {
-			if name != v.Name {
-				continue
-			}
-			if !yield(v) {
-				return
-			}
-		}
+	match := filter.Select(secrets, filter.Condition{Name: name}, func(v v1.Secret) filter.Attributes {
+		return filter.Attributes{Name: v.Name}
 	})
 
 	if len(match) == 0 {
+		if truncated {
+			return nil, fmt.Errorf("secret %q was not found in the first %d secrets of vault[%s]: the API did not return all secrets", name, len(secrets), vaultID)
+		}
 		return nil, common.ErrFilterNoResult
 	}
 
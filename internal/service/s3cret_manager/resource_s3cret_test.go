@@ -16,13 +16,11 @@ package secret_manager_test
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
-	sm "github.com/sacloud/secretmanager-api-go"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 	v1 "github.com/sacloud/secretmanager-api-go/apis/v1"
 
 	secret_manager "github.com/sacloud/terraform-provider-sakuracloud/internal/service/s3cret_manager"
@@ -30,17 +28,23 @@ import (
 )
 
 func TestAccSakuraSecretManagerSecret_basic(t *testing.T) {
+	test.SkipInSandbox(t, "SecretManager is not available in the sandbox")
+	test.ParallelTest(t)
+
 	resourceName := "sakura_secret_manager_secret.foobar"
-	rand := test.RandomName()
+	rand := test.RandomName(t, "secret")
 
 	var secret v1.Secret
+	basicConfig := test.BuildConfigWithMap(t, testAccSakuraSecretManagerSecret_basic, map[string]any{"name": rand})
+	updateConfig := test.BuildConfigWithMap(t, testAccSakuraSecretManagerSecret_update, map[string]any{"name": rand})
+
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { test.AccPreCheck(t) },
 		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
 		CheckDestroy:             testCheckSakuraSecretManagerSecretDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: test.BuildConfigWithArgs(testAccSakuraSecretManagerSecret_basic, rand),
+				Config: basicConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckSakuraSecretManagerSecretExists(resourceName, &secret),
 					resource.TestCheckResourceAttr(resourceName, "name", rand),
@@ -48,8 +52,9 @@ func TestAccSakuraSecretManagerSecret_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "version", "1"),
 				),
 			},
+			test.StablePlanStep(basicConfig, resourceName, "version"),
 			{
-				Config: test.BuildConfigWithArgs(testAccSakuraSecretManagerSecret_update, rand),
+				Config: updateConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckSakuraSecretManagerSecretExists(resourceName, &secret),
 					resource.TestCheckResourceAttr(resourceName, "name", rand),
@@ -57,67 +62,84 @@ func TestAccSakuraSecretManagerSecret_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "version", "2"),
 				),
 			},
+			test.StablePlanStep(updateConfig, resourceName, "version"),
+			// シークレットの値はunveilしない限り読み出せない
+			test.ImportStepByAttributes(resourceName, []string{"vault_id", "name"}, "value"),
 		},
 	})
 }
 
-func testCheckSakuraSecretManagerSecretDestroy(s *terraform.State) error {
-	client := test.AccClientGetter()
-	ctx := context.Background()
-
-	for _, rs := range s.RootModule().Resources {
-		if rs.Type != "sakura_secret_manager_secret" {
-			continue
-		}
-		if rs.Primary.ID == "" {
-			continue
-		}
-
-		secretOp := sm.NewSecretOp(client.SecretManagerClient, rs.Primary.Attributes["vault_id"])
-
-		_, err := secret_manager.FilterSecretManagerSecretByName(ctx, secretOp, rs.Primary.Attributes["name"])
-		if err == nil {
-			return fmt.Errorf("still exists SecretManagerSecret: %s", rs.Primary.ID)
-		}
-	}
-	return nil
-}
-
-func testCheckSakuraSecretManagerSecretExists(n string, secret *v1.Secret) resource.TestCheckFunc {
-	return func(s *terraform.State) error {
-		rs, ok := s.RootModule().Resources[n]
+func TestAccSakuraSecretManagerSecret_writeOnly(t *testing.T) {
+	test.SkipInSandbox(t, "SecretManager is not available in the sandbox")
+	test.ParallelTest(t)
 
-		if !ok {
-			return fmt.Errorf("not found: %s", n)
-		}
+	resourceName := "sakura_secret_manager_secret.foobar"
+	rand := test.RandomName(t, "secret")
 
-		if rs.Primary.ID == "" {
-			return errors.New("no SecretManagerSecret vault ID is set")
-		}
+	var secret v1.Secret
+	writeOnlyV1Config := test.BuildConfigWithMap(t, testAccSakuraSecretManagerSecret_writeOnly, map[string]any{"name": rand, "value": "value1", "version": 1})
+	writeOnlyV2Config := test.BuildConfigWithMap(t, testAccSakuraSecretManagerSecret_writeOnly, map[string]any{"name": rand, "value": "value2", "version": 2})
 
-		client := test.AccClientGetter()
-		ctx := context.Background()
-		secretOp := sm.NewSecretOp(client.SecretManagerClient, rs.Primary.Attributes["vault_id"])
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { test.AccPreCheck(t) },
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		CheckDestroy: testCheckSakuraSecretManagerSecretDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: writeOnlyV1Config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraSecretManagerSecretExists(resourceName, &secret),
+					resource.TestCheckNoResourceAttr(resourceName, "value"),
+					resource.TestCheckNoResourceAttr(resourceName, "value_wo"),
+					resource.TestCheckResourceAttr(resourceName, "value_wo_version", "1"),
+					resource.TestCheckResourceAttr(resourceName, "version", "1"),
+				),
+			},
+			test.StablePlanStep(writeOnlyV1Config, resourceName, "version"),
+			{
+				Config: writeOnlyV2Config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraSecretManagerSecretExists(resourceName, &secret),
+					resource.TestCheckNoResourceAttr(resourceName, "value_wo"),
+					resource.TestCheckResourceAttr(resourceName, "value_wo_version", "2"),
+					resource.TestCheckResourceAttr(resourceName, "version", "2"),
+				),
+			},
+			test.StablePlanStep(writeOnlyV2Config, resourceName, "version"),
+			// value_wo_versionはstateにのみ保持される
+			test.ImportStepByAttributes(resourceName, []string{"vault_id", "name"}, "value_wo_version"),
+		},
+	})
+}
 
-		foundSecret, err := secret_manager.FilterSecretManagerSecretByName(ctx, secretOp, rs.Primary.Attributes["name"])
-		if err != nil {
-			return err
-		}
+var testCheckSakuraSecretManagerSecretDestroy = test.CheckDestroy("sakura_secret_manager_secret", func(ctx context.Context, rs *terraform.ResourceState) error {
+	_, err := secret_manager.FilterSecretManagerSecretByName(ctx, test.AccClientGetter(), rs.Primary.Attributes["vault_id"], rs.Primary.Attributes["name"])
+	return err
+})
 
-		*secret = *foundSecret
-		return nil
-	}
+func testCheckSakuraSecretManagerSecretExists(n string, secret *v1.Secret) resource.TestCheckFunc {
+	return test.CheckExists(n, test.ExistsCheck[v1.Secret]{
+		Kind: "SecretManager secret",
+		Read: func(ctx context.Context, rs *terraform.ResourceState) (*v1.Secret, error) {
+			return secret_manager.FilterSecretManagerSecretByName(ctx, test.AccClientGetter(), rs.Primary.Attributes["vault_id"], rs.Primary.Attributes["name"])
+		},
+		ID:          func(v *v1.Secret) string { return v.Name },
+		IDAttribute: "name",
+	}, secret)
 }
 
 //nolint:gosec
 var testAccSakuraSecretManagerSecret_basic = `
 resource "sakura_kms" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description"
 }
 
 resource "sakura_secret_manager" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description"
   kms_key_id  = sakura_kms.foobar.id
 
@@ -125,7 +147,7 @@ resource "sakura_secret_manager" "foobar" {
 }
 
 resource "sakura_secret_manager_secret" "foobar" {
-  name     = "{{ .arg0 }}"
+  name     = "{{ .name }}"
   value    = "value1"
   vault_id = sakura_secret_manager.foobar.id
 
@@ -135,12 +157,12 @@ resource "sakura_secret_manager_secret" "foobar" {
 //nolint:gosec
 var testAccSakuraSecretManagerSecret_update = `
 resource "sakura_kms" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description"
 }
 
 resource "sakura_secret_manager" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description"
   kms_key_id  = sakura_kms.foobar.id
 
@@ -148,9 +170,33 @@ resource "sakura_secret_manager" "foobar" {
 }
 
 resource "sakura_secret_manager_secret" "foobar" {
-  name     = "{{ .arg0 }}"
+  name     = "{{ .name }}"
   value    = "value2"
   vault_id = sakura_secret_manager.foobar.id
 
   depends_on = [sakura_secret_manager.foobar]
 }`
+
+//nolint:gosec
+var testAccSakuraSecretManagerSecret_writeOnly = `
+resource "sakura_kms" "foobar" {
+  name        = "{{ .name }}"
+  description = "description"
+}
+
+resource "sakura_secret_manager" "foobar" {
+  name        = "{{ .name }}"
+  description = "description"
+  kms_key_id  = sakura_kms.foobar.id
+
+  depends_on = [sakura_kms.foobar]
+}
+
+resource "sakura_secret_manager_secret" "foobar" {
+  name             = "{{ .name }}"
+  value_wo         = "{{ .value }}"
+  value_wo_version = {{ .version }}
+  vault_id         = sakura_secret_manager.foobar.id
+
+  depends_on = [sakura_secret_manager.foobar]
+}`
@@ -0,0 +1,371 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret_manager
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	sm "github.com/sacloud/secretmanager-api-go"
+	v1 "github.com/sacloud/secretmanager-api-go/apis/v1"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	sacloudvalidator "github.com/sacloud/terraform-provider-sakuracloud/internal/validator"
+)
+
+// secretManagerSecretsResource はボールト内の複数のシークレットをmapでまとめて管理するリソース。
+// シークレットマネージャのAPIには一括で書き込むエンドポイントが無いため、シークレットごとの書き込みを並行して行う
+type secretManagerSecretsResource struct {
+	client secretManagerAPI
+}
+
+var (
+	_ resource.Resource               = &secretManagerSecretsResource{}
+	_ resource.ResourceWithConfigure  = &secretManagerSecretsResource{}
+	_ resource.ResourceWithModifyPlan = &secretManagerSecretsResource{}
+)
+
+func NewSecretManagerSecretsResource() resource.Resource {
+	return &secretManagerSecretsResource{}
+}
+
+func (r *secretManagerSecretsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_secret_manager_secrets"
+}
+
+func (r *secretManagerSecretsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	apiclient := common.GetApiClientFromProvider(req.ProviderData, &resp.Diagnostics)
+	if apiclient == nil {
+		return
+	}
+	r.client = apiclient
+}
+
+type secretManagerSecretsResourceModel struct {
+	VaultID  types.String   `tfsdk:"vault_id"`
+	Values   types.Map      `tfsdk:"values"`
+	Versions types.Map      `tfsdk:"versions"`
+	Timeouts timeouts.Value `tfsdk:"timeouts"`
+}
+
+func (r *secretManagerSecretsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"vault_id": schema.StringAttribute{
+				Required:    true,
+				Description: "The Secret Manager's vault id.",
+				Validators: []validator.String{
+					sacloudvalidator.SakuraIDValidator(),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"values": schema.MapAttribute{
+				ElementType: types.StringType,
+				Required:    true,
+				Sensitive:   true,
+				Description: "A map of secret names to secret values. Only the secrets whose values are changed are written.",
+				Validators: []validator.Map{
+					mapvalidator.SizeAtLeast(1),
+				},
+			},
+			"versions": schema.MapAttribute{
+				ElementType: types.Int64Type,
+				Computed:    true,
+				Description: "A map of secret names to the versions of the secret values.",
+			},
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
+				Create: true, Update: true, Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *secretManagerSecretsResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan, state secretManagerSecretsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// 値が変更されていなければバージョンも変わらないため、versionsを(known after apply)にしない
+	if plan.Values.Equal(state.Values) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("versions"), state.Versions)...)
+	}
+}
+
+func (r *secretManagerSecretsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan secretManagerSecretsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout20min)
+	defer cancel()
+
+	values := secretValues(ctx, plan.Values, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secretOp, err := r.client.SecretManagerSecretOp(plan.VaultID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("SecretManagerSecrets Client Error", err.Error())
+		return
+	}
+
+	state := secretsState{values: map[string]string{}, versions: map[string]int64{}}
+	errs := state.write(ctx, secretOp, r.client.BulkWriteParallelism(), values)
+	common.AddKeyErrors(&resp.Diagnostics, "SecretManagerSecrets Write Error", errs, secretValuePath)
+	// 書き込みに成功したシークレットはstateに記録する。一部が失敗した場合、リソースはtaintedとして残る
+	if len(state.values) == 0 {
+		return
+	}
+	resp.Diagnostics.Append(state.apply(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *secretManagerSecretsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state secretManagerSecretsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx = common.WithAPIErrorCapture(ctx)
+
+	vaultID := state.VaultID.ValueString()
+	secrets, truncated, err := r.client.SecretManagerSecretList(ctx, vaultID)
+	if err != nil {
+		if common.HandleNotFoundOnRead(ctx, err, &resp.State, "SecretManager vault", vaultID) {
+			return
+		}
+		common.AddAPIError(ctx, &resp.Diagnostics, "SecretManagerSecrets Read Error", err)
+		return
+	}
+	if truncated {
+		common.AddListTruncatedWarning(&resp.Diagnostics, "SecretManager secrets", len(secrets))
+	}
+	latest := make(map[string]int, len(secrets))
+	for _, secret := range secrets {
+		latest[secret.Name] = secret.LatestVersion
+	}
+
+	current, diags := loadSecretsState(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	for name := range current.values {
+		version, ok := latest[name]
+		switch {
+		case !ok && truncated:
+			// 一覧を全件取得できなかった場合は、削除済みと誤認しないようstateを維持する
+		case !ok:
+			tflog.Warn(ctx, "SecretManager secret is not found. The secret will be written again", map[string]any{"vault_id": vaultID, "name": name})
+			current.remove(name)
+		case version <= 0:
+		case int64(version) < current.versions[name]:
+			// 古いバージョンの削除などで書き込んだ値が失われている可能性があるため、次回のplanで値を書き直す
+			tflog.Warn(ctx, "SecretManager secret version is lower than the version in the state. The secret value will be rewritten", map[string]any{"vault_id": vaultID, "name": name})
+			current.remove(name)
+		default:
+			current.versions[name] = int64(version)
+		}
+	}
+
+	resp.Diagnostics.Append(current.apply(ctx, &state)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (r *secretManagerSecretsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state secretManagerSecretsResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout20min)
+	defer cancel()
+
+	values := secretValues(ctx, plan.Values, &resp.Diagnostics)
+	current, diags := loadSecretsState(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// 値の書き込みは新しいバージョンを作成するため、値が変更されたシークレットのみを書き込む
+	changed := map[string]string{}
+	for name, value := range values {
+		if old, ok := current.values[name]; !ok || old != value {
+			changed[name] = value
+		}
+	}
+	var removed []string
+	for name := range current.values {
+		if _, ok := values[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+
+	secretOp, err := r.client.SecretManagerSecretOp(plan.VaultID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("SecretManagerSecrets Client Error", err.Error())
+		return
+	}
+
+	parallelism := r.client.BulkWriteParallelism()
+	errs := current.write(ctx, secretOp, parallelism, changed)
+	common.AddKeyErrors(&resp.Diagnostics, "SecretManagerSecrets Write Error", errs, secretValuePath)
+	errs = current.delete(ctx, secretOp, parallelism, removed)
+	common.AddKeyErrors(&resp.Diagnostics, "SecretManagerSecrets Delete Error", errs, secretValuePath)
+
+	// 一部のシークレットの書き込みに失敗した場合も、成功したシークレットの変更はstateに記録する
+	resp.Diagnostics.Append(current.apply(ctx, &plan)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *secretManagerSecretsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state secretManagerSecretsResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout20min)
+	defer cancel()
+
+	current, diags := loadSecretsState(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	secretOp, err := r.client.SecretManagerSecretOp(state.VaultID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("SecretManagerSecrets Client Error", err.Error())
+		return
+	}
+
+	errs := current.delete(ctx, secretOp, r.client.BulkWriteParallelism(), slices.Collect(maps.Keys(current.values)))
+	if len(errs) == 0 {
+		return
+	}
+	// 削除に失敗したシークレットのみをstateに残し、次回のapplyで削除し直す
+	common.AddKeyErrors(&resp.Diagnostics, "SecretManagerSecrets Delete Error", errs, secretValuePath)
+	resp.Diagnostics.Append(current.apply(ctx, &state)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func secretValuePath(name string) path.Path {
+	return path.Root("values").AtMapKey(name)
+}
+
+func secretValues(ctx context.Context, m types.Map, diags *diag.Diagnostics) map[string]string {
+	values := map[string]string{}
+	diags.Append(m.ElementsAs(ctx, &values, false)...)
+	return values
+}
+
+// secretsState はsakura_secret_manager_secretsのstateに記録するシークレットの値とバージョン
+type secretsState struct {
+	values   map[string]string
+	versions map[string]int64
+}
+
+func loadSecretsState(ctx context.Context, model *secretManagerSecretsResourceModel) (*secretsState, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	s := &secretsState{values: secretValues(ctx, model.Values, &diags), versions: map[string]int64{}}
+	if !model.Versions.IsNull() && !model.Versions.IsUnknown() {
+		diags.Append(model.Versions.ElementsAs(ctx, &s.versions, false)...)
+	}
+	return s, diags
+}
+
+func (s *secretsState) remove(name string) {
+	delete(s.values, name)
+	delete(s.versions, name)
+}
+
+// apply はvaluesとversionsをmodelに設定する
+func (s *secretsState) apply(ctx context.Context, model *secretManagerSecretsResourceModel) diag.Diagnostics {
+	var diags, d diag.Diagnostics
+	model.Values, d = types.MapValueFrom(ctx, types.StringType, s.values)
+	diags.Append(d...)
+	model.Versions, d = types.MapValueFrom(ctx, types.Int64Type, s.versions)
+	diags.Append(d...)
+	return diags
+}
+
+// write はvaluesのシークレットを並行して書き込み、成功したシークレットの値とバージョンを記録する
+func (s *secretsState) write(ctx context.Context, secretOp sm.SecretAPI, parallelism int, values map[string]string) []*common.KeyError {
+	names := slices.Sorted(maps.Keys(values))
+	versions := make([]int, len(names))
+	errs := common.ForEachKey(ctx, names, parallelism, func(ctx context.Context, name string) error {
+		secret, err := secretOp.Create(ctx, v1.CreateSecret{Name: name, Value: values[name]})
+		if err != nil {
+			return err
+		}
+		versions[slices.Index(names, name)] = secret.LatestVersion
+		return nil
+	})
+	for i, name := range names {
+		if slices.ContainsFunc(errs, func(err *common.KeyError) bool { return err.Key == name }) {
+			continue
+		}
+		s.values[name] = values[name]
+		s.versions[name] = int64(versions[i])
+	}
+	return errs
+}
+
+// delete はnamesのシークレットを並行して削除し、成功したシークレットを記録から取り除く。既に削除済みのシークレットは成功として扱う
+func (s *secretsState) delete(ctx context.Context, secretOp sm.SecretAPI, parallelism int, names []string) []*common.KeyError {
+	slices.Sort(names)
+	errs := common.ForEachKey(ctx, names, parallelism, func(ctx context.Context, name string) error {
+		err := common.RetryOnConflict(ctx, func() error {
+			return secretOp.Delete(ctx, v1.DeleteSecret{Name: name})
+		})
+		if common.IsNotFound(err) {
+			return nil
+		}
+		return err
+	})
+	for _, name := range names {
+		if !slices.ContainsFunc(errs, func(err *common.KeyError) bool { return err.Key == name }) {
+			s.remove(name)
+		}
+	}
+	return errs
+}
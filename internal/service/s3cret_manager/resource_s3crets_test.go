@@ -0,0 +1,89 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret_manager_test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test"
+)
+
+func TestAccSakuraSecretManagerSecrets_basic(t *testing.T) {
+	test.SkipInSandbox(t, "SecretManager is not available in the sandbox")
+	test.ParallelTest(t)
+
+	resourceName := "sakura_secret_manager_secrets.foobar"
+	rand := test.RandomName(t, "secrets")
+
+	basicConfig := test.BuildConfigWithMap(t, testAccSakuraSecretManagerSecrets_basic, map[string]any{"name": rand, "value": "value1", "extra": false})
+	updateConfig := test.BuildConfigWithMap(t, testAccSakuraSecretManagerSecrets_basic, map[string]any{"name": rand, "value": "value2", "extra": true})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { test.AccPreCheck(t) },
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: basicConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "values.%", "2"),
+					resource.TestCheckResourceAttr(resourceName, "versions.first", "1"),
+					resource.TestCheckResourceAttr(resourceName, "versions.second", "1"),
+				),
+			},
+			test.StablePlanStep(basicConfig, resourceName, "versions"),
+			{
+				// 値を変更したシークレットのみ新しいバージョンを作成する
+				Config: updateConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "values.%", "3"),
+					resource.TestCheckResourceAttr(resourceName, "versions.first", "2"),
+					resource.TestCheckResourceAttr(resourceName, "versions.second", "1"),
+					resource.TestCheckResourceAttr(resourceName, "versions.third", "1"),
+				),
+			},
+			test.StablePlanStep(updateConfig, resourceName, "versions"),
+		},
+	})
+}
+
+//nolint:gosec
+var testAccSakuraSecretManagerSecrets_basic = `
+resource "sakura_kms" "foobar" {
+  name        = "{{ .name }}"
+  description = "description"
+}
+
+resource "sakura_secret_manager" "foobar" {
+  name        = "{{ .name }}"
+  description = "description"
+  kms_key_id  = sakura_kms.foobar.id
+
+  depends_on = [sakura_kms.foobar]
+}
+
+resource "sakura_secret_manager_secrets" "foobar" {
+  vault_id = sakura_secret_manager.foobar.id
+  values = {
+    first  = "{{ .value }}"
+    second = "value1"
+{{- if .extra }}
+    third  = "value1"
+{{- end }}
+  }
+
+  depends_on = [sakura_secret_manager.foobar]
+}`
@@ -16,33 +16,37 @@ package secret_manager_test
 
 import (
 	"context"
-	"errors"
-	"fmt"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
-	sm "github.com/sacloud/secretmanager-api-go"
 	v1 "github.com/sacloud/secretmanager-api-go/apis/v1"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/test"
 )
 
 func TestAccSakuraSecretManager_basic(t *testing.T) {
+	test.SkipInSandbox(t, "SecretManager is not available in the sandbox")
+	test.ParallelTest(t)
+
 	resourceName := "sakura_secret_manager.foobar"
-	rand := test.RandomName()
+	rand := test.RandomName(t, "vault")
 
 	var vault v1.Vault
+	basicConfig := test.BuildConfigWithMap(t, testAccSakuraSecretManager_basic, map[string]any{"name": rand})
+	updateConfig := test.BuildConfigWithMap(t, testAccSakuraSecretManager_update, map[string]any{"name": rand})
+
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { test.AccPreCheck(t) },
 		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
 		CheckDestroy:             testCheckSakuraSecretManagerDestroy,
 		Steps: []resource.TestStep{
 			{
-				Config: test.BuildConfigWithArgs(testAccSakuraSecretManager_basic, rand),
+				Config: basicConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckSakuraSecretManagerExists(resourceName, &vault),
 					resource.TestCheckResourceAttr(resourceName, "name", rand),
 					resource.TestCheckResourceAttr(resourceName, "description", "description"),
+					resource.TestCheckResourceAttr(resourceName, "zone", "global"),
 					resource.TestCheckResourceAttr(resourceName, "tags.#", "2"),
 					resource.TestCheckResourceAttr(resourceName, "tags.0", "tag1"),
 					resource.TestCheckResourceAttr(resourceName, "tags.1", "tag2"),
@@ -50,8 +54,9 @@ func TestAccSakuraSecretManager_basic(t *testing.T) {
 					// resource.TestCheckResourceAttr(resourceName, "kms_key_id", vault.KmsKeyID),
 				),
 			},
+			test.StablePlanStep(basicConfig, resourceName, "id"),
 			{
-				Config: test.BuildConfigWithArgs(testAccSakuraSecretManager_update, rand),
+				Config: updateConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testCheckSakuraSecretManagerExists(resourceName, &vault),
 					resource.TestCheckResourceAttr(resourceName, "name", rand),
@@ -61,69 +66,118 @@ func TestAccSakuraSecretManager_basic(t *testing.T) {
 					// resource.TestCheckResourceAttr(resourceName, "kms_key_id", vault.KmsKeyID),
 				),
 			},
+			test.StablePlanStep(updateConfig, resourceName, "id"),
+			test.ImportStep(resourceName),
+			test.ImportPlanStep(resourceName),
 		},
 	})
 }
 
-func testCheckSakuraSecretManagerDestroy(s *terraform.State) error {
-	client := test.AccClientGetter()
-	vaultOp := sm.NewVaultOp(client.SecretManagerClient)
-
-	for _, rs := range s.RootModule().Resources {
-		if rs.Type != "sakura_secret_manager" {
-			continue
-		}
-		if rs.Primary.ID == "" {
-			continue
-		}
-
-		_, err := vaultOp.Read(context.Background(), rs.Primary.ID)
-		if err == nil {
-			return fmt.Errorf("still exists SecretManager: %s", rs.Primary.ID)
-		}
-	}
-	return nil
+// descriptionとtagsを指定しないボールトをimportしても、planに差分が出ないことを検証する
+func TestAccSakuraSecretManager_importMinimal(t *testing.T) {
+	test.SkipInSandbox(t, "SecretManager is not available in the sandbox")
+	test.ParallelTest(t)
+
+	resourceName := "sakura_secret_manager.foobar"
+	rand := test.RandomName(t, "vault")
+
+	var vault v1.Vault
+	config := test.BuildConfigWithMap(t, testAccSakuraSecretManager_minimal, map[string]any{"name": rand})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { test.AccPreCheck(t) },
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckSakuraSecretManagerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraSecretManagerExists(resourceName, &vault),
+					resource.TestCheckResourceAttr(resourceName, "description", ""),
+					resource.TestCheckResourceAttr(resourceName, "tags.#", "0"),
+				),
+			},
+			test.StablePlanStep(config, resourceName, "id"),
+			test.ImportStep(resourceName),
+			test.ImportPlanStep(resourceName),
+		},
+	})
 }
 
-func testCheckSakuraSecretManagerExists(n string, vault *v1.Vault) resource.TestCheckFunc {
-	return func(s *terraform.State) error {
-		rs, ok := s.RootModule().Resources[n]
+// 日本語や絵文字を含む名前・説明が、作成・更新・インポートを通じて設定した値のまま保持されることを検証する
+func TestAccSakuraSecretManager_nonASCII(t *testing.T) {
+	test.SkipInSandbox(t, "SecretManager is not available in the sandbox")
+	test.ParallelTest(t)
 
-		if !ok {
-			return fmt.Errorf("not found: %s", n)
-		}
+	resourceName := "sakura_secret_manager.foobar"
+	rand := test.RandomName(t, "vault")
+	name := rand + "-本番用の金庫🔐"
 
-		if rs.Primary.ID == "" {
-			return errors.New("no SecretManager vault ID is set")
-		}
+	var vault v1.Vault
+	basicConfig := test.BuildConfigWithMap(t, testAccSakuraSecretManager_nonASCII, map[string]any{"name": name, "description": "日本語の説明😀"})
+	updateConfig := test.BuildConfigWithMap(t, testAccSakuraSecretManager_nonASCII, map[string]any{"name": name + "（更新）", "description": "更新後の説明🎉"})
 
-		client := test.AccClientGetter()
-		vaultOp := sm.NewVaultOp(client.SecretManagerClient)
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { test.AccPreCheck(t) },
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckSakuraSecretManagerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: basicConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraSecretManagerExists(resourceName, &vault),
+					resource.TestCheckResourceAttr(resourceName, "name", name),
+					resource.TestCheckResourceAttr(resourceName, "description", "日本語の説明😀"),
+				),
+			},
+			test.StablePlanStep(basicConfig, resourceName, "id"),
+			{
+				Config: updateConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraSecretManagerExists(resourceName, &vault),
+					resource.TestCheckResourceAttr(resourceName, "name", name+"（更新）"),
+					resource.TestCheckResourceAttr(resourceName, "description", "更新後の説明🎉"),
+				),
+			},
+			test.StablePlanStep(updateConfig, resourceName, "id"),
+			test.ImportStep(resourceName),
+		},
+	})
+}
 
-		foundVault, err := vaultOp.Read(context.Background(), rs.Primary.ID)
-		if err != nil {
-			return err
-		}
+var testCheckSakuraSecretManagerDestroy = test.CheckDestroy("sakura_secret_manager", test.ReadByExists(func(ctx context.Context, rs *terraform.ResourceState) (bool, error) {
+	return test.AccClientGetter().SecretManagerVaultExists(ctx, rs.Primary.ID)
+}))
 
-		if foundVault.ID != rs.Primary.ID {
-			return fmt.Errorf("not found SecretManager: %s", rs.Primary.ID)
-		}
+func testCheckSakuraSecretManagerExists(n string, vault *v1.Vault) resource.TestCheckFunc {
+	return test.CheckExists(n, test.ExistsCheck[v1.Vault]{
+		Kind: "SecretManager vault",
+		Read: func(ctx context.Context, rs *terraform.ResourceState) (*v1.Vault, error) {
+			vaultOp, err := test.AccClientGetter().SecretManagerVaultOp()
+			if err != nil {
+				return nil, err
+			}
+			return vaultOp.Read(ctx, rs.Primary.ID)
+		},
+		ID: func(v *v1.Vault) string { return v.ID },
+	}, vault)
+}
 
-		*vault = *foundVault
-		return nil
-	}
+// testCheckSakuraSecretManagerKmsKeyID はAPIから取得したボールトのKMSキーIDが、参照先のKMSリソースのIDと一致することを検証する
+func testCheckSakuraSecretManagerKmsKeyID(vault *v1.Vault, kmsResourceName string) resource.TestCheckFunc {
+	return test.CheckFetchedAttrPair(vault, "kms_key_id", func(v *v1.Vault) string { return v.KmsKeyID }, kmsResourceName, "id")
 }
 
 //nolint:gosec
 var testAccSakuraSecretManager_basic = `
 resource "sakura_kms" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description"
   tags        = ["tag1", "tag2"]
 }
 
 resource "sakura_secret_manager" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description"
   tags        = ["tag1", "tag2"]
   kms_key_id  = sakura_kms.foobar.id
@@ -134,16 +188,66 @@ resource "sakura_secret_manager" "foobar" {
 //nolint:gosec
 var testAccSakuraSecretManager_update = `
 resource "sakura_kms" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description"
   tags        = ["tag1", "tag2"]
 }
 
 resource "sakura_secret_manager" "foobar" {
-  name        = "{{ .arg0 }}"
+  name        = "{{ .name }}"
   description = "description-updated"
   tags        = ["tag1-upd"]
   kms_key_id  = sakura_kms.foobar.id
 
   depends_on = [sakura_kms.foobar]
 }`
+
+//nolint:gosec
+var testAccSakuraSecretManager_nonASCII = `
+resource "sakura_kms" "foobar" {
+  name = "{{ .name }}"
+}
+
+resource "sakura_secret_manager" "foobar" {
+  name        = "{{ .name }}"
+  description = "{{ .description }}"
+  tags        = ["タグ1"]
+  kms_key_id  = sakura_kms.foobar.id
+}`
+
+//nolint:gosec
+var testAccSakuraSecretManager_tags = `
+resource "sakura_kms" "foobar" {
+  name = "{{ .name }}"
+}
+
+resource "sakura_secret_manager" "foobar" {
+  name       = "{{ .name }}"
+  kms_key_id = sakura_kms.foobar.id
+  {{ .tags }}
+}`
+
+var testAccSakuraSecretManager_kmsKey = `
+resource "sakura_kms" "foobar" {
+  name = "{{ .name }}"
+}
+
+resource "sakura_kms" "foobar2" {
+  name = "{{ .name }}-2"
+}
+
+resource "sakura_secret_manager" "foobar" {
+  name       = "{{ .name }}"
+  kms_key_id = {{ .kms_key_id }}
+}`
+
+//nolint:gosec
+var testAccSakuraSecretManager_minimal = `
+resource "sakura_kms" "foobar" {
+  name = "{{ .name }}"
+}
+
+resource "sakura_secret_manager" "foobar" {
+  name       = "{{ .name }}"
+  kms_key_id = sakura_kms.foobar.id
+}`
@@ -0,0 +1,879 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/attr/xattr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	api "github.com/sacloud/api-client-go"
+	v1 "github.com/sacloud/secretmanager-api-go/apis/v1"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	sacloudvalidator "github.com/sacloud/terraform-provider-sakuracloud/internal/validator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func resourceSchema(t *testing.T, r resource.Resource) schema.Schema {
+	t.Helper()
+
+	var resp resource.SchemaResponse
+	r.Schema(context.Background(), resource.SchemaRequest{}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	return resp.Schema
+}
+
+func nullTimeouts(s schema.Schema) timeouts.Value {
+	timeoutTypes := s.Attributes["timeouts"].GetType().(attr.TypeWithAttributeTypes).AttributeTypes()
+	return timeouts.Value{Object: types.ObjectNull(timeoutTypes)}
+}
+
+// newUpdateRequest はstateとplanを設定したUpdateリクエストを組み立てる
+func newUpdateRequest(t *testing.T, s schema.Schema, state, plan, config any) (resource.UpdateRequest, resource.UpdateResponse) {
+	t.Helper()
+
+	ctx := context.Background()
+	newRaw := func() tftypes.Value { return tftypes.NewValue(s.Type().TerraformType(ctx), nil) }
+	req := resource.UpdateRequest{
+		State:  tfsdk.State{Schema: s, Raw: newRaw()},
+		Plan:   tfsdk.Plan{Schema: s, Raw: newRaw()},
+		Config: tfsdk.Config{Schema: s, Raw: newRaw()},
+	}
+	require.False(t, req.State.Set(ctx, state).HasError())
+	require.False(t, req.Plan.Set(ctx, plan).HasError())
+	configState := tfsdk.State{Schema: s, Raw: newRaw()}
+	require.False(t, configState.Set(ctx, config).HasError())
+	req.Config.Raw = configState.Raw
+	return req, resource.UpdateResponse{State: req.State}
+}
+
+func TestSecretManagerResource_Create(t *testing.T) {
+	ctx := context.Background()
+	s := resourceSchema(t, NewSecretManagerResource())
+
+	plan := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	require.False(t, plan.Set(ctx, &secretManagerResourceModel{
+		secretManagerBaseModel: secretManagerBaseModel{
+			SakuraBaseModel: common.SakuraBaseModel{
+				ID:          common.NewSakuraIDUnknown(),
+				Name:        types.StringValue("foobar"),
+				Description: types.StringValue("description"),
+				Tags:        types.SetNull(types.StringType),
+			},
+			KmsKeyID: common.NewSakuraIDValue("110000000002"),
+		},
+		Timeouts: nullTimeouts(s),
+	}).HasError())
+
+	t.Run("secondary call fails", func(t *testing.T) {
+		stub := &stubSecretManagerAPI{vaultOp: &stubVaultOp{
+			create: func(_ context.Context, request v1.CreateVault) (*v1.CreateVault, error) {
+				request.ID = "110000000001"
+				return &request, nil
+			},
+			read: func(context.Context, string) (*v1.Vault, error) {
+				return nil, api.NewAPIError(http.StatusInternalServerError, "", errors.New("internal server error"))
+			},
+		}}
+		r := &secretManagerResource{client: stub}
+
+		resp := resource.CreateResponse{State: tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}}
+		r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, []string{"Create", "Read"}, stub.vaultOp.calls)
+
+		// 作成済みのボールトはtaintedとしてstateで追跡され、次回のapplyで重複して作成されない
+		var state secretManagerResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "110000000001", state.ID.ValueString())
+	})
+}
+
+func TestSecretManagerResource_ReadAfterImport(t *testing.T) {
+	ctx := context.Background()
+	s := resourceSchema(t, NewSecretManagerResource())
+
+	// ImportStatePassthroughIDの直後はid以外の属性がnullになっている
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	require.False(t, state.SetAttribute(ctx, path.Root("id"), "110000000001").HasError())
+
+	r := &secretManagerResource{client: &stubSecretManagerAPI{vaultOp: &stubVaultOp{
+		read: func(_ context.Context, id string) (*v1.Vault, error) {
+			// descriptionとtagsを持たないボールト
+			return &v1.Vault{ID: id, Name: "foobar", KmsKeyID: "110000000002"}, nil
+		},
+	}}}
+
+	resp := resource.ReadResponse{State: state}
+	r.Read(ctx, resource.ReadRequest{State: state}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	var got secretManagerResourceModel
+	require.False(t, resp.State.Get(ctx, &got).HasError())
+	assert.Equal(t, "foobar", got.Name.ValueString())
+	assert.Equal(t, types.StringValue(""), got.Description)
+	assert.Equal(t, types.SetValueMust(types.StringType, []attr.Value{}), got.Tags)
+	assert.Equal(t, "110000000002", got.KmsKeyID.ValueString())
+	// zoneを持たない以前のstateでもReadで補完される
+	assert.Equal(t, common.GlobalZone, got.Zone.ValueString())
+}
+
+func TestSecretManagerResource_Update(t *testing.T) {
+	ctx := context.Background()
+	s := resourceSchema(t, NewSecretManagerResource())
+
+	testModel := func() *secretManagerResourceModel {
+		return &secretManagerResourceModel{
+			secretManagerBaseModel: secretManagerBaseModel{
+				SakuraBaseModel: common.SakuraBaseModel{
+					ID:          common.NewSakuraIDValue("110000000001"),
+					Name:        types.StringValue("foobar"),
+					Description: types.StringValue("description"),
+					Tags:        types.SetNull(types.StringType),
+				},
+				KmsKeyID: common.NewSakuraIDValue("110000000002"),
+			},
+			Timeouts: nullTimeouts(s),
+		}
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		stub := &stubSecretManagerAPI{vaultOp: &stubVaultOp{}}
+		r := &secretManagerResource{client: stub}
+
+		plan := testModel()
+		plan.Tags = types.SetValueMust(types.StringType, []attr.Value{})
+		req, resp := newUpdateRequest(t, s, testModel(), plan, plan)
+		r.Update(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.Empty(t, stub.vaultOp.calls)
+
+		var state secretManagerResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "110000000001", state.ID.ValueString())
+	})
+
+	newVaultOp := func(updated func(request v1.Vault) *v1.Vault) *stubVaultOp {
+		return &stubVaultOp{
+			read: func(_ context.Context, id string) (*v1.Vault, error) {
+				return &v1.Vault{ID: id, Name: "foobar", Description: v1.NewOptString("description-upd"), KmsKeyID: "110000000002"}, nil
+			},
+			update: func(_ context.Context, id string, request v1.Vault) (*v1.Vault, error) {
+				request.ID = id
+				return updated(request), nil
+			},
+		}
+	}
+
+	t.Run("changed", func(t *testing.T) {
+		stub := &stubSecretManagerAPI{vaultOp: newVaultOp(func(request v1.Vault) *v1.Vault { return &request })}
+		r := &secretManagerResource{client: stub}
+
+		plan := testModel()
+		plan.Description = types.StringValue("description-upd")
+		req, resp := newUpdateRequest(t, s, testModel(), plan, plan)
+		r.Update(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		// 更新APIのレスポンスがボールト全体を含むため、更新後に改めて参照しない
+		assert.Equal(t, []string{"Read", "Update"}, stub.vaultOp.calls)
+
+		var state secretManagerResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "description-upd", state.Description.ValueString())
+	})
+
+	t.Run("metadata changes in a single update", func(t *testing.T) {
+		var requests []v1.Vault
+		stub := &stubSecretManagerAPI{vaultOp: newVaultOp(func(request v1.Vault) *v1.Vault {
+			requests = append(requests, request)
+			return &request
+		})}
+		r := &secretManagerResource{client: stub}
+
+		plan := testModel()
+		plan.Name = types.StringValue("foobar-upd")
+		plan.Description = types.StringValue("description-upd")
+		plan.Tags = types.SetValueMust(types.StringType, []attr.Value{types.StringValue("tag1")})
+		req, resp := newUpdateRequest(t, s, testModel(), plan, plan)
+		r.Update(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		// name/description/tagsの変更は1回の更新APIでまとめて送信する
+		assert.Equal(t, []string{"Read", "Update"}, stub.vaultOp.calls)
+		require.Len(t, requests, 1)
+		assert.Equal(t, "foobar-upd", requests[0].Name)
+		assert.Equal(t, "description-upd", requests[0].Description.Value)
+		assert.Equal(t, []string{"tag1"}, requests[0].Tags)
+		assert.Equal(t, "110000000002", requests[0].KmsKeyID)
+
+		var state secretManagerResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "foobar-upd", state.Name.ValueString())
+		assert.Len(t, state.Tags.Elements(), 1)
+	})
+
+	t.Run("incomplete update response", func(t *testing.T) {
+		stub := &stubSecretManagerAPI{vaultOp: newVaultOp(func(v1.Vault) *v1.Vault { return nil })}
+		r := &secretManagerResource{client: stub}
+
+		plan := testModel()
+		plan.Description = types.StringValue("description-upd")
+		req, resp := newUpdateRequest(t, s, testModel(), plan, plan)
+		r.Update(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.Equal(t, []string{"Read", "Update", "Read"}, stub.vaultOp.calls)
+
+		var state secretManagerResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, "description-upd", state.Description.ValueString())
+	})
+}
+
+func TestSecretManagerResource_validateReferences(t *testing.T) {
+	ctx := context.Background()
+	s := resourceSchema(t, NewSecretManagerResource())
+
+	testModel := func(id types.String, kmsKeyID common.SakuraID) *secretManagerResourceModel {
+		return &secretManagerResourceModel{
+			secretManagerBaseModel: secretManagerBaseModel{
+				SakuraBaseModel: common.SakuraBaseModel{
+					ID:          common.SakuraID{StringValue: id},
+					Name:        types.StringValue("foobar"),
+					Description: types.StringValue("description"),
+					Tags:        types.SetValueMust(types.StringType, []attr.Value{}),
+				},
+				KmsKeyID: kmsKeyID,
+			},
+			SakuraGlobalZoneModel: common.SakuraGlobalZoneModel{Zone: types.StringValue(common.GlobalZone)},
+			Timeouts:              nullTimeouts(s),
+		}
+	}
+	modifyPlan := func(t *testing.T, stub *stubSecretManagerAPI, state, plan *secretManagerResourceModel) *resource.ModifyPlanResponse {
+		t.Helper()
+
+		newRaw := func() tftypes.Value { return tftypes.NewValue(s.Type().TerraformType(ctx), nil) }
+		req := resource.ModifyPlanRequest{
+			State:  tfsdk.State{Schema: s, Raw: newRaw()},
+			Plan:   tfsdk.Plan{Schema: s, Raw: newRaw()},
+			Config: tfsdk.Config{Schema: s, Raw: newRaw()},
+		}
+		if state != nil {
+			require.False(t, req.State.Set(ctx, state).HasError())
+		}
+		require.False(t, req.Plan.Set(ctx, plan).HasError())
+		resp := &resource.ModifyPlanResponse{Plan: req.Plan}
+		(&secretManagerResource{client: stub}).validateKMSKeyReference(ctx, req, resp)
+		return resp
+	}
+	created := testModel(types.StringUnknown(), common.NewSakuraIDValue("110000000002"))
+
+	t.Run("disabled", func(t *testing.T) {
+		stub := &stubSecretManagerAPI{}
+		resp := modifyPlan(t, stub, nil, created)
+		assert.Empty(t, resp.Diagnostics)
+		assert.Empty(t, stub.kmsKeyExistsCalls)
+	})
+
+	t.Run("existing key", func(t *testing.T) {
+		stub := &stubSecretManagerAPI{validateReferences: true, kmsKeys: []string{"110000000002"}}
+		resp := modifyPlan(t, stub, nil, created)
+		assert.Empty(t, resp.Diagnostics)
+		assert.Equal(t, []string{"110000000002"}, stub.kmsKeyExistsCalls)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		stub := &stubSecretManagerAPI{validateReferences: true}
+		resp := modifyPlan(t, stub, nil, testModel(types.StringUnknown(), common.NewSakuraIDValue("1.10000000003e11")))
+		require.Len(t, resp.Diagnostics.Errors(), 1)
+		assert.Equal(t, path.Root("kms_key_id"), resp.Diagnostics.Errors()[0].(diag.DiagnosticWithPath).Path())
+		assert.Contains(t, resp.Diagnostics.Errors()[0].Detail(), `"110000000003"`)
+		assert.Equal(t, []string{"110000000003"}, stub.kmsKeyExistsCalls)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		stub := &stubSecretManagerAPI{validateReferences: true}
+		resp := modifyPlan(t, stub, nil, testModel(types.StringUnknown(), common.SakuraID{StringValue: types.StringUnknown()}))
+		assert.Empty(t, resp.Diagnostics)
+		assert.Empty(t, stub.kmsKeyExistsCalls)
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		stub := &stubSecretManagerAPI{validateReferences: true}
+		state := testModel(types.StringValue("110000000001"), common.NewSakuraIDValue("110000000002"))
+		plan := testModel(types.StringValue("110000000001"), common.NewSakuraIDValue("1.10000000002e11"))
+		resp := modifyPlan(t, stub, state, plan)
+		assert.Empty(t, resp.Diagnostics)
+		assert.Empty(t, stub.kmsKeyExistsCalls)
+	})
+
+	t.Run("changed to missing key", func(t *testing.T) {
+		stub := &stubSecretManagerAPI{validateReferences: true}
+		state := testModel(types.StringValue("110000000001"), common.NewSakuraIDValue("110000000002"))
+		plan := testModel(types.StringValue("110000000001"), common.NewSakuraIDValue("110000000003"))
+		resp := modifyPlan(t, stub, state, plan)
+		assert.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, []string{"110000000003"}, stub.kmsKeyExistsCalls)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		stub := &stubSecretManagerAPI{validateReferences: true, kmsKeyExistsErr: errors.New("internal server error")}
+		resp := modifyPlan(t, stub, nil, created)
+		assert.False(t, resp.Diagnostics.HasError())
+		require.Len(t, resp.Diagnostics.Warnings(), 1)
+		assert.Contains(t, resp.Diagnostics.Warnings()[0].Detail(), "internal server error")
+	})
+}
+
+func TestSecretManagerSecretResource_Update(t *testing.T) {
+	ctx := context.Background()
+	s := resourceSchema(t, NewSecretManagerSecretResource())
+
+	testModel := func(value string) *secretManagerSecretResourceModel {
+		return &secretManagerSecretResourceModel{
+			secretManagerSecretBaseModel: secretManagerSecretBaseModel{
+				Name:    types.StringValue("foobar"),
+				VaultID: types.StringValue("110000000001"),
+				Version: types.Int64Value(1),
+				Value:   types.StringValue(value),
+			},
+			ValueWO:        types.StringNull(),
+			ValueWOVersion: types.Int64Null(),
+			Timeouts:       nullTimeouts(s),
+		}
+	}
+
+	t.Run("no changes", func(t *testing.T) {
+		stub := &stubSecretManagerAPI{secretOp: &stubSecretOp{}}
+		r := &secretManagerSecretResource{client: stub}
+
+		req, resp := newUpdateRequest(t, s, testModel("value1"), testModel("value1"), testModel("value1"))
+		r.Update(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.Empty(t, stub.secretOp.calls)
+
+		var state secretManagerSecretResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, int64(1), state.Version.ValueInt64())
+	})
+
+	t.Run("value changed", func(t *testing.T) {
+		var got v1.CreateSecret
+		stub := &stubSecretManagerAPI{secretOp: &stubSecretOp{
+			create: func(_ context.Context, request v1.CreateSecret) (*v1.Secret, error) {
+				got = request
+				return &v1.Secret{Name: request.Name, LatestVersion: 2}, nil
+			},
+		}}
+		r := &secretManagerSecretResource{client: stub}
+
+		plan := testModel("value2")
+		plan.Version = types.Int64Unknown()
+		req, resp := newUpdateRequest(t, s, testModel("value1"), plan, testModel("value2"))
+		r.Update(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.Equal(t, []string{"Create"}, stub.secretOp.calls)
+		assert.Equal(t, "value2", got.Value)
+
+		var state secretManagerSecretResourceModel
+		require.False(t, resp.State.Get(ctx, &state).HasError())
+		assert.Equal(t, int64(2), state.Version.ValueInt64())
+	})
+
+	t.Run("invalid values are reported together", func(t *testing.T) {
+		stub := &stubSecretManagerAPI{secretOp: &stubSecretOp{}}
+		r := &secretManagerSecretResource{client: stub}
+
+		// planの時点で未知だった値はCreate/Updateでまとめて検証する
+		invalid := testModel("value2")
+		invalid.Name = types.StringValue(strings.Repeat("あ", 256))
+		invalid.VaultID = types.StringValue("my-vault")
+		req, resp := newUpdateRequest(t, s, testModel("value1"), invalid, invalid)
+		r.Update(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Empty(t, stub.secretOp.calls)
+		assert.ElementsMatch(t, []path.Path{path.Root("name"), path.Root("vault_id")}, diagnosticPaths(t, resp.Diagnostics))
+	})
+}
+
+func TestSecretManagerSecretResource_schemaValidators(t *testing.T) {
+	ctx := context.Background()
+	s := resourceSchema(t, NewSecretManagerSecretResource())
+
+	type stringAttribute interface {
+		StringValidators() []validator.String
+	}
+	validate := func(t *testing.T, config map[string]string) diag.Diagnostics {
+		var diags diag.Diagnostics
+		for name, value := range config {
+			attr, ok := s.Attributes[name].(stringAttribute)
+			require.True(t, ok, name)
+			diags.Append(sacloudvalidator.ValidateString(ctx, path.Root(name), types.StringValue(value), attr.StringValidators()...)...)
+		}
+		return diags
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		diags := validate(t, map[string]string{
+			"name":     strings.Repeat("あ", 255),
+			"vault_id": "110000000001",
+			"value":    "value",
+			"value_wo": "value",
+		})
+		assert.False(t, diags.HasError(), diags)
+	})
+
+	t.Run("all problems in one config", func(t *testing.T) {
+		diags := validate(t, map[string]string{
+			"name":     "",
+			"vault_id": "my-vault",
+		})
+		assert.ElementsMatch(t, []path.Path{
+			path.Root("name"), path.Root("vault_id"),
+		}, diagnosticPaths(t, diags))
+	})
+}
+
+func TestSecretManagerSecretResource_Read(t *testing.T) {
+	ctx := context.Background()
+	s := resourceSchema(t, NewSecretManagerSecretResource())
+
+	testCases := []struct {
+		name           string
+		stateVersion   types.Int64
+		remoteVersion  int
+		wantVersion    types.Int64
+		wantRewrite    bool
+		writeOnlyValue bool
+	}{
+		{name: "greater", stateVersion: types.Int64Value(2), remoteVersion: 3, wantVersion: types.Int64Value(3)},
+		{name: "equal", stateVersion: types.Int64Value(2), remoteVersion: 2, wantVersion: types.Int64Value(2)},
+		{name: "lower", stateVersion: types.Int64Value(5), remoteVersion: 1, wantVersion: types.Int64Value(1), wantRewrite: true},
+		{name: "lower with write-only value", stateVersion: types.Int64Value(5), remoteVersion: 1, wantVersion: types.Int64Value(1), wantRewrite: true, writeOnlyValue: true},
+		{name: "missing", stateVersion: types.Int64Value(2), remoteVersion: 0, wantVersion: types.Int64Value(2)},
+		{name: "imported", stateVersion: types.Int64Null(), remoteVersion: 3, wantVersion: types.Int64Value(3)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			model := &secretManagerSecretResourceModel{
+				secretManagerSecretBaseModel: secretManagerSecretBaseModel{
+					Name:    types.StringValue("foobar"),
+					VaultID: types.StringValue("110000000001"),
+					Version: tc.stateVersion,
+					Value:   types.StringValue("value1"),
+				},
+				ValueWO:        types.StringNull(),
+				ValueWOVersion: types.Int64Null(),
+				Timeouts:       nullTimeouts(s),
+			}
+			if tc.writeOnlyValue {
+				model.Value = types.StringNull()
+				model.ValueWOVersion = types.Int64Value(1)
+			}
+			state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+			require.False(t, state.Set(ctx, model).HasError())
+
+			r := &secretManagerSecretResource{client: &stubSecretManagerAPI{secretOp: &stubSecretOp{
+				list: func(context.Context) ([]v1.Secret, error) {
+					return []v1.Secret{{Name: "foobar", LatestVersion: tc.remoteVersion}}, nil
+				},
+			}}}
+
+			resp := resource.ReadResponse{State: state}
+			r.Read(ctx, resource.ReadRequest{State: state}, &resp)
+			require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+			var got secretManagerSecretResourceModel
+			require.False(t, resp.State.Get(ctx, &got).HasError())
+			assert.Equal(t, tc.wantVersion, got.Version)
+
+			// 書き直しが必要な場合はvalue/value_wo_versionをnullにして、configとの差分で一度だけUpdateさせる
+			switch {
+			case tc.wantRewrite:
+				assert.True(t, got.Value.IsNull())
+				assert.True(t, got.ValueWOVersion.IsNull())
+			case tc.writeOnlyValue:
+				assert.Equal(t, types.Int64Value(1), got.ValueWOVersion)
+			default:
+				assert.Equal(t, "value1", got.Value.ValueString())
+			}
+
+			// 同じバージョンで再度Readしても、それ以上の書き直しは発生しない
+			again := resource.ReadResponse{State: resp.State}
+			r.Read(ctx, resource.ReadRequest{State: resp.State}, &again)
+			require.False(t, again.Diagnostics.HasError(), again.Diagnostics)
+			assert.Equal(t, resp.State.Raw, again.State.Raw)
+		})
+	}
+}
+
+// newSecretsModel はsakura_secret_manager_secretsのモデルを組み立てる。versionsがnilの場合はunknownとする
+func newSecretsModel(t *testing.T, s schema.Schema, values map[string]string, versions map[string]int64) *secretManagerSecretsResourceModel {
+	t.Helper()
+
+	ctx := context.Background()
+	model := &secretManagerSecretsResourceModel{
+		VaultID:  types.StringValue("110000000001"),
+		Versions: types.MapUnknown(types.Int64Type),
+		Timeouts: nullTimeouts(s),
+	}
+	var diags diag.Diagnostics
+	model.Values, diags = types.MapValueFrom(ctx, types.StringType, values)
+	require.False(t, diags.HasError(), diags)
+	if versions != nil {
+		model.Versions, diags = types.MapValueFrom(ctx, types.Int64Type, versions)
+		require.False(t, diags.HasError(), diags)
+	}
+	return model
+}
+
+func secretsFromState(t *testing.T, state tfsdk.State) (map[string]string, map[string]int64) {
+	t.Helper()
+
+	ctx := context.Background()
+	var model secretManagerSecretsResourceModel
+	require.False(t, state.Get(ctx, &model).HasError())
+	s, diags := loadSecretsState(ctx, &model)
+	require.False(t, diags.HasError(), diags)
+	return s.values, s.versions
+}
+
+func diagnosticPaths(t *testing.T, diags diag.Diagnostics) []path.Path {
+	t.Helper()
+
+	var paths []path.Path
+	for _, d := range diags.Errors() {
+		withPath, ok := d.(diag.DiagnosticWithPath)
+		require.True(t, ok, d)
+		paths = append(paths, withPath.Path())
+	}
+	return paths
+}
+
+func TestSecretManagerSecretsResource_Create(t *testing.T) {
+	ctx := context.Background()
+	s := resourceSchema(t, NewSecretManagerSecretsResource())
+
+	values := map[string]string{}
+	for i := range 10 {
+		values[fmt.Sprintf("secret%02d", i)] = fmt.Sprintf("value%d", i)
+	}
+	plan := tfsdk.Plan{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	require.False(t, plan.Set(ctx, newSecretsModel(t, s, values, nil)).HasError())
+	newResponse := func() resource.CreateResponse {
+		return resource.CreateResponse{State: tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}}
+	}
+
+	t.Run("writes concurrently", func(t *testing.T) {
+		var current, maxInFlight atomic.Int32
+		stub := &stubSecretManagerAPI{parallelism: 3, secretOp: &stubSecretOp{
+			create: func(_ context.Context, request v1.CreateSecret) (*v1.Secret, error) {
+				n := current.Add(1)
+				defer current.Add(-1)
+				for {
+					m := maxInFlight.Load()
+					if n <= m || maxInFlight.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				return &v1.Secret{Name: request.Name, LatestVersion: 1}, nil
+			},
+		}}
+		r := &secretManagerSecretsResource{client: stub}
+
+		resp := newResponse()
+		r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.Len(t, stub.secretOp.calls, len(values))
+		assert.EqualValues(t, 3, maxInFlight.Load())
+
+		gotValues, gotVersions := secretsFromState(t, resp.State)
+		assert.Equal(t, values, gotValues)
+		assert.Len(t, gotVersions, len(values))
+	})
+
+	t.Run("partial failure records written secrets", func(t *testing.T) {
+		stub := &stubSecretManagerAPI{secretOp: &stubSecretOp{
+			create: func(_ context.Context, request v1.CreateSecret) (*v1.Secret, error) {
+				if request.Name == "secret03" || request.Name == "secret07" {
+					return nil, api.NewAPIError(http.StatusInternalServerError, "", errors.New("internal server error"))
+				}
+				return &v1.Secret{Name: request.Name, LatestVersion: 1}, nil
+			},
+		}}
+		r := &secretManagerSecretsResource{client: stub}
+
+		resp := newResponse()
+		r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		// どのシークレットの書き込みが失敗したかを属性のパスで示す
+		assert.Equal(t, []path.Path{secretValuePath("secret03"), secretValuePath("secret07")}, diagnosticPaths(t, resp.Diagnostics))
+
+		gotValues, gotVersions := secretsFromState(t, resp.State)
+		assert.Len(t, gotValues, len(values)-2)
+		assert.NotContains(t, gotValues, "secret03")
+		assert.NotContains(t, gotVersions, "secret07")
+		assert.Equal(t, "value0", gotValues["secret00"])
+	})
+
+	t.Run("all failed", func(t *testing.T) {
+		stub := &stubSecretManagerAPI{secretOp: &stubSecretOp{}}
+		r := &secretManagerSecretsResource{client: stub}
+
+		resp := newResponse()
+		r.Create(ctx, resource.CreateRequest{Plan: plan}, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Len(t, resp.Diagnostics.Errors(), len(values))
+		assert.True(t, resp.State.Raw.IsNull())
+	})
+}
+
+func TestSecretManagerSecretsResource_Update(t *testing.T) {
+	ctx := context.Background()
+	s := resourceSchema(t, NewSecretManagerSecretsResource())
+
+	state := newSecretsModel(t, s, map[string]string{"a": "1", "b": "1", "c": "1"}, map[string]int64{"a": 1, "b": 1, "c": 1})
+
+	t.Run("writes changed and deletes removed", func(t *testing.T) {
+		var created, deleted []string
+		var mu sync.Mutex
+		stub := &stubSecretManagerAPI{secretOp: &stubSecretOp{
+			create: func(_ context.Context, request v1.CreateSecret) (*v1.Secret, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				created = append(created, request.Name)
+				return &v1.Secret{Name: request.Name, LatestVersion: 2}, nil
+			},
+			delete: func(_ context.Context, request v1.DeleteSecret) error {
+				mu.Lock()
+				defer mu.Unlock()
+				deleted = append(deleted, request.Name)
+				return nil
+			},
+		}}
+		r := &secretManagerSecretsResource{client: stub}
+
+		plan := newSecretsModel(t, s, map[string]string{"a": "1", "b": "2", "d": "1"}, nil)
+		req, resp := newUpdateRequest(t, s, state, plan, plan)
+		r.Update(ctx, req, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		assert.ElementsMatch(t, []string{"b", "d"}, created)
+		assert.Equal(t, []string{"c"}, deleted)
+
+		gotValues, gotVersions := secretsFromState(t, resp.State)
+		assert.Equal(t, map[string]string{"a": "1", "b": "2", "d": "1"}, gotValues)
+		assert.Equal(t, map[string]int64{"a": 1, "b": 2, "d": 2}, gotVersions)
+	})
+
+	t.Run("partial failure keeps previous values of failed secrets", func(t *testing.T) {
+		stub := &stubSecretManagerAPI{secretOp: &stubSecretOp{
+			create: func(_ context.Context, request v1.CreateSecret) (*v1.Secret, error) {
+				if request.Name == "b" {
+					return nil, api.NewAPIError(http.StatusBadRequest, "", errors.New("bad request"))
+				}
+				return &v1.Secret{Name: request.Name, LatestVersion: 2}, nil
+			},
+			delete: func(context.Context, v1.DeleteSecret) error {
+				return api.NewAPIError(http.StatusInternalServerError, "", errors.New("internal server error"))
+			},
+		}}
+		r := &secretManagerSecretsResource{client: stub}
+
+		plan := newSecretsModel(t, s, map[string]string{"a": "2", "b": "2"}, nil)
+		req, resp := newUpdateRequest(t, s, state, plan, plan)
+		r.Update(ctx, req, &resp)
+		require.True(t, resp.Diagnostics.HasError())
+		assert.Equal(t, []path.Path{secretValuePath("b"), secretValuePath("c")}, diagnosticPaths(t, resp.Diagnostics))
+
+		// 書き込めたaは新しい値を、失敗したb/cは変更前の値を記録し、次回のplanで再度変更を検出する
+		gotValues, gotVersions := secretsFromState(t, resp.State)
+		assert.Equal(t, map[string]string{"a": "2", "b": "1", "c": "1"}, gotValues)
+		assert.Equal(t, map[string]int64{"a": 2, "b": 1, "c": 1}, gotVersions)
+	})
+}
+
+func TestSecretManagerSecretsResource_Read(t *testing.T) {
+	ctx := context.Background()
+	s := resourceSchema(t, NewSecretManagerSecretsResource())
+
+	model := newSecretsModel(t, s, map[string]string{"a": "1", "b": "1", "c": "1"}, map[string]int64{"a": 1, "b": 3, "c": 1})
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	require.False(t, state.Set(ctx, model).HasError())
+
+	r := &secretManagerSecretsResource{client: &stubSecretManagerAPI{secretOp: &stubSecretOp{
+		list: func(context.Context) ([]v1.Secret, error) {
+			return []v1.Secret{{Name: "a", LatestVersion: 2}, {Name: "b", LatestVersion: 1}}, nil
+		},
+	}}}
+
+	resp := resource.ReadResponse{State: state}
+	r.Read(ctx, resource.ReadRequest{State: state}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+
+	// バージョンが戻ったbと削除されたcは、次回のplanで書き直す
+	gotValues, gotVersions := secretsFromState(t, resp.State)
+	assert.Equal(t, map[string]string{"a": "1"}, gotValues)
+	assert.Equal(t, map[string]int64{"a": 2}, gotVersions)
+}
+
+func TestSecretManagerSecretsResource_Read_truncated(t *testing.T) {
+	ctx := context.Background()
+	s := resourceSchema(t, NewSecretManagerSecretsResource())
+
+	model := newSecretsModel(t, s, map[string]string{"a": "1", "c": "1"}, map[string]int64{"a": 1, "c": 1})
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	require.False(t, state.Set(ctx, model).HasError())
+
+	r := &secretManagerSecretsResource{client: &stubSecretManagerAPI{listTruncated: true, secretOp: &stubSecretOp{
+		list: func(context.Context) ([]v1.Secret, error) {
+			return []v1.Secret{{Name: "a", LatestVersion: 1}}, nil
+		},
+	}}}
+
+	resp := resource.ReadResponse{State: state}
+	r.Read(ctx, resource.ReadRequest{State: state}, &resp)
+	require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+	require.Len(t, resp.Diagnostics.Warnings(), 1)
+	assert.Equal(t, "Incomplete List Result", resp.Diagnostics.Warnings()[0].Summary())
+
+	// 一覧に含まれなかったcは削除されたとは判断せず、stateを維持する
+	gotValues, gotVersions := secretsFromState(t, resp.State)
+	assert.Equal(t, map[string]string{"a": "1", "c": "1"}, gotValues)
+	assert.Equal(t, map[string]int64{"a": 1, "c": 1}, gotVersions)
+}
+
+func TestSecretManagerSecretsResource_Delete(t *testing.T) {
+	ctx := context.Background()
+	s := resourceSchema(t, NewSecretManagerSecretsResource())
+
+	model := newSecretsModel(t, s, map[string]string{"a": "1", "b": "1", "c": "1"}, map[string]int64{"a": 1, "b": 1, "c": 1})
+	state := tfsdk.State{Schema: s, Raw: tftypes.NewValue(s.Type().TerraformType(ctx), nil)}
+	require.False(t, state.Set(ctx, model).HasError())
+
+	r := &secretManagerSecretsResource{client: &stubSecretManagerAPI{secretOp: &stubSecretOp{
+		delete: func(_ context.Context, request v1.DeleteSecret) error {
+			switch request.Name {
+			case "a":
+				return api.NewAPIError(http.StatusNotFound, "", errors.New("not found"))
+			case "b":
+				return api.NewAPIError(http.StatusInternalServerError, "", errors.New("internal server error"))
+			}
+			return nil
+		},
+	}}}
+
+	resp := resource.DeleteResponse{State: state}
+	r.Delete(ctx, resource.DeleteRequest{State: state}, &resp)
+	require.True(t, resp.Diagnostics.HasError())
+	assert.Equal(t, []path.Path{secretValuePath("b")}, diagnosticPaths(t, resp.Diagnostics))
+
+	// 削除済みのaと削除できたcはstateから取り除き、失敗したbのみを残す
+	gotValues, _ := secretsFromState(t, resp.State)
+	assert.Equal(t, map[string]string{"b": "1"}, gotValues)
+}
+
+func TestSchema_idAttributes(t *testing.T) {
+	type stringAttribute interface {
+		GetType() attr.Type
+		StringValidators() []validator.String
+	}
+	dataSourceAttribute := func(t *testing.T, d datasource.DataSource, name string) any {
+		var resp datasource.SchemaResponse
+		d.Schema(context.Background(), datasource.SchemaRequest{}, &resp)
+		require.False(t, resp.Diagnostics.HasError(), resp.Diagnostics)
+		return resp.Schema.Attributes[name]
+	}
+
+	testCases := []struct {
+		name string
+		attr any
+	}{
+		{name: "sakura_secret_manager_secret.vault_id", attr: resourceSchema(t, NewSecretManagerSecretResource()).Attributes["vault_id"]},
+		{name: "sakura_secret_manager_secrets.vault_id", attr: resourceSchema(t, NewSecretManagerSecretsResource()).Attributes["vault_id"]},
+		{name: "data.sakura_secret_manager.id", attr: dataSourceAttribute(t, NewSecretManagerDataSource(), "id")},
+		{name: "data.sakura_secret_manager_secret.vault_id", attr: dataSourceAttribute(t, NewSecretManagerSecretDataSource(), "vault_id")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			attr, ok := tc.attr.(stringAttribute)
+			require.True(t, ok)
+
+			var diags diag.Diagnostics
+			for _, value := range []string{"110000000001", "my-key"} {
+				for _, v := range attr.StringValidators() {
+					resp := &validator.StringResponse{}
+					v.ValidateString(context.Background(), validator.StringRequest{
+						Path:        path.Root(tc.name),
+						ConfigValue: types.StringValue(value),
+					}, resp)
+					diags.Append(resp.Diagnostics...)
+				}
+				// SakuraIDTypeの属性は値の型で検証する
+				if _, ok := attr.GetType().(common.SakuraIDType); ok {
+					resp := &xattr.ValidateAttributeResponse{}
+					common.NewSakuraIDValue(value).ValidateAttribute(context.Background(), xattr.ValidateAttributeRequest{Path: path.Root(tc.name)}, resp)
+					diags.Append(resp.Diagnostics...)
+				}
+			}
+			require.Len(t, diags, 1)
+			assert.Contains(t, diags[0].Detail(), `got "my-key"`)
+		})
+	}
+}
+
+// kms_key_idはモジュールの出力などから数値として渡された値も受け付ける
+func TestSchema_kmsKeyIDAcceptsNumbers(t *testing.T) {
+	ctx := context.Background()
+	attr := resourceSchema(t, NewSecretManagerResource()).Attributes["kms_key_id"]
+	idType, ok := attr.GetType().(common.SakuraIDType)
+	require.True(t, ok)
+
+	for value, wantErr := range map[string]bool{
+		"110000000001":      false,
+		"1.10000000001e+11": false,
+		"110000000001.0":    false,
+		"my-key":            true,
+		"1.5":               true,
+	} {
+		t.Run(value, func(t *testing.T) {
+			v, diags := idType.ValueFromString(ctx, types.StringValue(value))
+			require.False(t, diags.HasError(), diags)
+
+			resp := &xattr.ValidateAttributeResponse{}
+			v.(common.SakuraID).ValidateAttribute(ctx, xattr.ValidateAttributeRequest{Path: path.Root("kms_key_id")}, resp)
+			assert.Equal(t, wantErr, resp.Diagnostics.HasError(), resp.Diagnostics)
+		})
+	}
+}
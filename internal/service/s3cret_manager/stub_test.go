@@ -0,0 +1,209 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secret_manager
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	sm "github.com/sacloud/secretmanager-api-go"
+	v1 "github.com/sacloud/secretmanager-api-go/apis/v1"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+)
+
+// stubSecretManagerAPI はsecretManagerAPIのテストダブル
+type stubSecretManagerAPI struct {
+	vaultOp     *stubVaultOp
+	secretOp    *stubSecretOp
+	parallelism int
+	// manageSystemTags はignore_system_tags = falseとして動作させる場合にtrueとする
+	manageSystemTags bool
+	resourceDefaults common.ResourceDefaults
+	// validateReferencesがtrueの場合、KMSKeyExistsはkmsKeysに含まれるIDのみ存在するものとして扱う
+	validateReferences bool
+	kmsKeys            []string
+	kmsKeyExistsErr    error
+	kmsKeyExistsCalls  []string
+	// listTruncatedがtrueの場合、一覧取得APIが全件を返さなかったものとして扱う
+	listTruncated bool
+}
+
+var _ secretManagerAPI = (*stubSecretManagerAPI)(nil)
+
+func (s *stubSecretManagerAPI) SecretManagerVaultOp() (sm.VaultAPI, error) {
+	return s.vaultOp, nil
+}
+
+func (s *stubSecretManagerAPI) SecretManagerSecretOp(string) (sm.SecretAPI, error) {
+	return s.secretOp, nil
+}
+
+func (s *stubSecretManagerAPI) SecretManagerVaultList(ctx context.Context) ([]v1.Vault, bool, error) {
+	vaults, err := s.vaultOp.List(ctx)
+	return vaults, s.listTruncated, err
+}
+
+func (s *stubSecretManagerAPI) SecretManagerSecretList(ctx context.Context, _ string) ([]v1.Secret, bool, error) {
+	secrets, err := s.secretOp.List(ctx)
+	return secrets, s.listTruncated, err
+}
+
+func (s *stubSecretManagerAPI) IgnoreSystemTags() bool {
+	return !s.manageSystemTags
+}
+
+func (s *stubSecretManagerAPI) ResourceDefaults() common.ResourceDefaults {
+	return s.resourceDefaults
+}
+
+func (s *stubSecretManagerAPI) ValidateReferences() bool {
+	return s.validateReferences
+}
+
+func (s *stubSecretManagerAPI) KMSKeyExists(_ context.Context, id string) (bool, error) {
+	s.kmsKeyExistsCalls = append(s.kmsKeyExistsCalls, id)
+	if s.kmsKeyExistsErr != nil {
+		return false, s.kmsKeyExistsErr
+	}
+	return slices.Contains(s.kmsKeys, id), nil
+}
+
+// BulkWriteParallelism は並行して書き込む数を返す。parallelismが未設定の場合はcommon.MaxParallelBulkWritesを返す
+func (s *stubSecretManagerAPI) BulkWriteParallelism() int {
+	if s.parallelism > 0 {
+		return s.parallelism
+	}
+	return common.MaxParallelBulkWrites
+}
+
+func errNotStubbed(op string) error {
+	return fmt.Errorf("stub: %s is not stubbed", op)
+}
+
+// stubVaultOp はsm.VaultAPIのテストダブル。未設定の操作を呼び出した場合はエラーを返す
+type stubVaultOp struct {
+	list   func(ctx context.Context) ([]v1.Vault, error)
+	read   func(ctx context.Context, id string) (*v1.Vault, error)
+	create func(ctx context.Context, request v1.CreateVault) (*v1.CreateVault, error)
+	update func(ctx context.Context, id string, request v1.Vault) (*v1.Vault, error)
+	delete func(ctx context.Context, id string) error
+
+	calls []string // 呼び出された操作の名前
+}
+
+var _ sm.VaultAPI = (*stubVaultOp)(nil)
+
+func (s *stubVaultOp) List(ctx context.Context) ([]v1.Vault, error) {
+	s.calls = append(s.calls, "List")
+	if s.list == nil {
+		return nil, errNotStubbed("VaultAPI.List")
+	}
+	return s.list(ctx)
+}
+
+func (s *stubVaultOp) Read(ctx context.Context, id string) (*v1.Vault, error) {
+	s.calls = append(s.calls, "Read")
+	if s.read == nil {
+		return nil, errNotStubbed("VaultAPI.Read")
+	}
+	return s.read(ctx, id)
+}
+
+func (s *stubVaultOp) Create(ctx context.Context, request v1.CreateVault) (*v1.CreateVault, error) {
+	s.calls = append(s.calls, "Create")
+	if s.create == nil {
+		return nil, errNotStubbed("VaultAPI.Create")
+	}
+	return s.create(ctx, request)
+}
+
+func (s *stubVaultOp) Update(ctx context.Context, id string, request v1.Vault) (*v1.Vault, error) {
+	s.calls = append(s.calls, "Update")
+	if s.update == nil {
+		return nil, errNotStubbed("VaultAPI.Update")
+	}
+	return s.update(ctx, id, request)
+}
+
+func (s *stubVaultOp) Delete(ctx context.Context, id string) error {
+	s.calls = append(s.calls, "Delete")
+	if s.delete == nil {
+		return errNotStubbed("VaultAPI.Delete")
+	}
+	return s.delete(ctx, id)
+}
+
+// stubSecretOp はsm.SecretAPIのテストダブル。未設定の操作を呼び出した場合はエラーを返す
+type stubSecretOp struct {
+	list   func(ctx context.Context) ([]v1.Secret, error)
+	create func(ctx context.Context, request v1.CreateSecret) (*v1.Secret, error)
+	update func(ctx context.Context, request v1.CreateSecret) (*v1.Secret, error)
+	delete func(ctx context.Context, request v1.DeleteSecret) error
+	unveil func(ctx context.Context, request v1.Unveil) (*v1.Unveil, error)
+
+	mu    sync.Mutex
+	calls []string // 呼び出された操作の名前
+}
+
+// record は呼び出された操作の名前を記録する。sakura_secret_manager_secretsは書き込みを並行して行うため、排他して記録する
+func (s *stubSecretOp) record(op string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, op)
+}
+
+var _ sm.SecretAPI = (*stubSecretOp)(nil)
+
+func (s *stubSecretOp) List(ctx context.Context) ([]v1.Secret, error) {
+	s.record("List")
+	if s.list == nil {
+		return nil, errNotStubbed("SecretAPI.List")
+	}
+	return s.list(ctx)
+}
+
+func (s *stubSecretOp) Create(ctx context.Context, request v1.CreateSecret) (*v1.Secret, error) {
+	s.record("Create")
+	if s.create == nil {
+		return nil, errNotStubbed("SecretAPI.Create")
+	}
+	return s.create(ctx, request)
+}
+
+func (s *stubSecretOp) Update(ctx context.Context, request v1.CreateSecret) (*v1.Secret, error) {
+	s.record("Update")
+	if s.update == nil {
+		return nil, errNotStubbed("SecretAPI.Update")
+	}
+	return s.update(ctx, request)
+}
+
+func (s *stubSecretOp) Delete(ctx context.Context, request v1.DeleteSecret) error {
+	s.record("Delete")
+	if s.delete == nil {
+		return errNotStubbed("SecretAPI.Delete")
+	}
+	return s.delete(ctx, request)
+}
+
+func (s *stubSecretOp) Unveil(ctx context.Context, request v1.Unveil) (*v1.Unveil, error) {
+	s.record("Unveil")
+	if s.unveil == nil {
+		return nil, errNotStubbed("SecretAPI.Unveil")
+	}
+	return s.unveil(ctx, request)
+}
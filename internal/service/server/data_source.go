@@ -16,6 +16,7 @@ package server
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
@@ -165,6 +166,8 @@ func (d *serverDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	zone := common.GetZone(data.Zone, d.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -173,7 +176,7 @@ func (d *serverDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	searcher := iaas.NewServerOp(d.client)
 	res, err := searcher.Find(ctx, zone, common.CreateFindCondition(data.ID, data.Name, data.Tags))
 	if err != nil {
-		resp.Diagnostics.AddError("Search Error", "cloud not find SakuraCloud Server: "+err.Error())
+		common.AddAPIError(ctx, &resp.Diagnostics, "Search Error", fmt.Errorf("could not find SakuraCloud Server: %w", err))
 		return
 	}
 	if res == nil || res.Count == 0 || len(res.Servers) == 0 {
@@ -55,6 +55,7 @@ var (
 	_ resource.Resource                = &serverResource{}
 	_ resource.ResourceWithConfigure   = &serverResource{}
 	_ resource.ResourceWithImportState = &serverResource{}
+	_ resource.ResourceWithModifyPlan  = &serverResource{}
 )
 
 func NewServerResource() resource.Resource {
@@ -346,7 +347,7 @@ func (r *serverResource) Schema(ctx context.Context, _ resource.SchemaRequest, r
 				Optional:    true,
 				Description: "The flag to use force shutdown when need to reboot/shutdown while applying",
 			},
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
 				Create: true, Update: true, Delete: true,
 			}),
 		},
@@ -357,6 +358,11 @@ func (r *serverResource) ImportState(ctx context.Context, req resource.ImportSta
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *serverResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanZone(ctx, r.client, req, resp)
+	common.PlanResourceDefaults(ctx, r.client, req, resp)
+}
+
 func (r *serverResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan serverResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -366,6 +372,7 @@ func (r *serverResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	zone := common.GetZone(plan.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
@@ -383,13 +390,18 @@ func (r *serverResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 	result, err := builder.Build(ctx, zone)
 	if err != nil {
-		resp.Diagnostics.AddError("Build Server Error", err.Error())
+		common.AddAPIError(ctx, &resp.Diagnostics, "Build Server Error", err)
+		return
+	}
+	resp.Diagnostics.Append(common.SetCreatedID(ctx, &resp.State, result.ServerID.String())...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	server := getServer(ctx, r.client, zone, result.ServerID, &resp.State, &resp.Diagnostics)
+	// 作成したサーバーはAPI上に残っているため、参照できない場合もstateから削除せずエラーとする
+	server, err := iaas.NewServerOp(r.client).Read(ctx, zone, result.ServerID)
 	if err != nil {
-		resp.Diagnostics.AddError("Get Server Error", err.Error())
+		common.AddAPIError(ctx, &resp.Diagnostics, "Get Server Error", fmt.Errorf("could not read SakuraCloud Server[%s] after creation: %w", result.ServerID, err))
 		return
 	}
 
@@ -404,6 +416,8 @@ func (r *serverResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	zone := common.GetZone(state.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -433,6 +447,7 @@ func (r *serverResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	sid := state.ID.ValueString()
 	common.SakuraMutexKV.Lock(sid)
@@ -449,7 +464,7 @@ func (r *serverResource) Update(ctx context.Context, req resource.UpdateRequest,
 	}
 	result, err := builder.Update(ctx, zone)
 	if err != nil {
-		resp.Diagnostics.AddError("Update Server Error", fmt.Sprintf("updating SakuraCloud Server[%s] is failed: %s", sid, err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Server Error", fmt.Errorf("updating SakuraCloud Server[%s] is failed: %w", sid, err))
 		return
 
 	}
@@ -476,6 +491,7 @@ func (r *serverResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout20min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	sid := state.ID.ValueString()
 	common.SakuraMutexKV.Lock(sid)
@@ -483,15 +499,18 @@ func (r *serverResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 	serverOp := iaas.NewServerOp(r.client)
 	server := getServer(ctx, r.client, zone, common.SakuraCloudID(sid), &resp.State, &resp.Diagnostics)
+	if server == nil {
+		return
+	}
 	if server.InstanceStatus.IsUp() {
 		if err := power.ShutdownServer(ctx, serverOp, zone, server.ID, state.ForceShutdown.ValueBool()); err != nil {
-			resp.Diagnostics.AddError("Shutdown Error", fmt.Sprintf("stopping SakuraCloud Server[%s] is failed: %s", server.ID, err))
+			common.AddAPIError(ctx, &resp.Diagnostics, "Shutdown Error", fmt.Errorf("stopping SakuraCloud Server[%s] is failed: %w", server.ID, err))
 			return
 		}
 	}
 
 	if err := serverOp.Delete(ctx, zone, server.ID); err != nil {
-		resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("deleting SakuraCloud Server[%s] is failed: %s", server.ID, err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Delete Error", fmt.Errorf("deleting SakuraCloud Server[%s] is failed: %w", server.ID, err))
 		return
 	}
 }
@@ -500,11 +519,10 @@ func getServer(ctx context.Context, client *common.APIClient, zone string, id ia
 	serverOp := iaas.NewServerOp(client)
 	server, err := serverOp.Read(ctx, zone, id)
 	if err != nil {
-		if iaas.IsNotFoundError(err) {
-			state.RemoveResource(ctx)
+		if common.HandleNotFoundOnRead(ctx, err, state, "Server", id.String()) {
 			return nil
 		}
-		diags.AddError("Get Server Error", fmt.Sprintf("could not read SakuraCloud Server[%s]: %s", id, err))
+		common.AddAPIError(ctx, diags, "Get Server Error", fmt.Errorf("could not read SakuraCloud Server[%s]: %w", id, err))
 	}
 
 	return server
@@ -0,0 +1,140 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test"
+)
+
+func TestAccSakuraResourceServer_basic(t *testing.T) {
+	test.ParallelTest(t)
+
+	resourceName := "sakura_server.foobar"
+	rand := test.RandomName(t, "server")
+	var server iaas.Server
+	var createdID string
+	basicConfig := test.BuildConfigWithMap(t, testAccSakuraServer_basic, map[string]any{"name": rand})
+	updateConfig := test.BuildConfigWithMap(t, testAccSakuraServer_update, map[string]any{"name": rand})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { test.AccPreCheck(t) },
+		ProtoV6ProviderFactories: test.AccProtoV6ProviderFactories,
+		CheckDestroy:             testCheckSakuraServerDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: basicConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraServerExists(resourceName, &server),
+					test.CheckFetched(&server, func(v *iaas.Server) error {
+						createdID = v.ID.String()
+						if !v.InstanceStatus.IsUp() {
+							return fmt.Errorf("server is not running: %s", v.InstanceStatus)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttr(resourceName, "name", rand),
+					resource.TestCheckResourceAttr(resourceName, "description", "description"),
+					resource.TestCheckResourceAttr(resourceName, "tags.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "core", "1"),
+					resource.TestCheckResourceAttr(resourceName, "memory", "1"),
+					resource.TestCheckResourceAttr(resourceName, "commitment", "standard"),
+					resource.TestCheckResourceAttr(resourceName, "network_interface.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "network_interface.0.upstream", "shared"),
+					resource.TestCheckResourceAttrSet(resourceName, "zone"),
+				),
+			},
+			test.StablePlanStep(basicConfig, resourceName, "id", "zone"),
+			{
+				// core/memoryの変更はforce_shutdownでシャットダウンしてから反映され、サーバーは再作成されない
+				Config: updateConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckSakuraServerExists(resourceName, &server),
+					test.CheckFetched(&server, func(v *iaas.Server) error {
+						if v.ID.String() != createdID {
+							return fmt.Errorf("server is recreated: %s -> %s", createdID, v.ID)
+						}
+						if !v.InstanceStatus.IsUp() {
+							return fmt.Errorf("server is not running after update: %s", v.InstanceStatus)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttr(resourceName, "description", "description-updated"),
+					resource.TestCheckResourceAttr(resourceName, "tags.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "core", "2"),
+					resource.TestCheckResourceAttr(resourceName, "memory", "4"),
+				),
+			},
+			test.StablePlanStep(updateConfig, resourceName, "id", "zone"),
+			// zoneはimport時にプロバイダーの設定から決まる
+			test.ImportStep(resourceName, "force_shutdown", "timeouts"),
+		},
+	})
+}
+
+func testCheckSakuraServerExists(n string, server *iaas.Server) resource.TestCheckFunc {
+	return test.CheckExists(n, test.ExistsCheck[iaas.Server]{
+		Kind: "Server",
+		Read: func(ctx context.Context, rs *terraform.ResourceState) (*iaas.Server, error) {
+			return iaas.NewServerOp(test.AccClientGetter()).Read(ctx, rs.Primary.Attributes["zone"], common.SakuraCloudID(rs.Primary.ID))
+		},
+		ID: func(v *iaas.Server) string { return v.ID.String() },
+	}, server)
+}
+
+var testCheckSakuraServerDestroy = test.CheckDestroy("sakura_server", func(ctx context.Context, rs *terraform.ResourceState) error {
+	zone := rs.Primary.Attributes["zone"]
+	if zone == "" {
+		return errors.New("zone is not set")
+	}
+	_, err := iaas.NewServerOp(test.AccClientGetter()).Read(ctx, zone, common.SakuraCloudID(rs.Primary.ID))
+	return err
+})
+
+var testAccSakuraServer_basic = `
+resource "sakura_server" "foobar" {
+  name        = "{{ .name }}"
+  description = "description"
+  tags        = ["tag1", "tag2"]
+  core        = 1
+  memory      = 1
+
+  network_interface = [{
+    upstream = "shared"
+  }]
+  force_shutdown = true
+}`
+
+var testAccSakuraServer_update = `
+resource "sakura_server" "foobar" {
+  name        = "{{ .name }}"
+  description = "description-updated"
+  tags        = ["tag1-upd"]
+  core        = 2
+  memory      = 4
+
+  network_interface = [{
+    upstream = "shared"
+  }]
+  force_shutdown = true
+}`
@@ -25,10 +25,11 @@ import (
 	"github.com/sacloud/simplemq-api-go/apis/v1/queue"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/desc"
+	sacloudvalidator "github.com/sacloud/terraform-provider-sakuracloud/internal/validator"
 )
 
 type simpleMQDataSource struct {
-	client *queue.Client
+	client *common.APIClient
 }
 
 var (
@@ -49,7 +50,7 @@ func (d *simpleMQDataSource) Configure(ctx context.Context, req datasource.Confi
 	if apiclient == nil {
 		return
 	}
-	d.client = apiclient.SimpleMqClient
+	d.client = apiclient
 }
 
 type simpleMQDataSourceModel struct {
@@ -68,6 +69,7 @@ func (d *simpleMQDataSource) Schema(_ context.Context, _ datasource.SchemaReques
 				Optional:    true,
 				Computed:    true,
 				Description: desc.Sprintf("The tags of the SimpleMQ."),
+				Validators:  sacloudvalidator.Tags(),
 			},
 			"visibility_timeout_seconds": schema.Int64Attribute{
 				Computed:    true,
@@ -88,6 +90,8 @@ func (d *simpleMQDataSource) Read(ctx context.Context, req datasource.ReadReques
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	name := data.Name.ValueString()
 	tags := common.TsetToStrings(data.Tags)
 	if name == "" && len(tags) == 0 {
@@ -95,10 +99,16 @@ func (d *simpleMQDataSource) Read(ctx context.Context, req datasource.ReadReques
 		return
 	}
 
-	queueOp := simplemq.NewQueueOp(d.client)
+	// クライアントは最初に利用する操作で生成する
+	client, err := d.client.SimpleMQClient()
+	if err != nil {
+		resp.Diagnostics.AddError("SimpleMQ Client Error", err.Error())
+		return
+	}
+	queueOp := simplemq.NewQueueOp(client)
 	qs, err := queueOp.List(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError("API Error", fmt.Sprintf("could not find SakuraCloud SimpleMQ resource: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "API Error", fmt.Errorf("could not find SakuraCloud SimpleMQ resource: %w", err))
 		return
 	}
 
@@ -31,12 +31,14 @@ type simpleMqBaseModel struct {
 }
 
 func (model *simpleMqBaseModel) updateState(data *queue.CommonServiceItem) {
-	model.ID = types.StringValue(simplemq.GetQueueID(data))
+	model.ID = common.NewSakuraIDValue(simplemq.GetQueueID(data))
 	model.Name = types.StringValue(simplemq.GetQueueName(data))
 	model.VisibilityTimeoutSeconds = types.Int64Value(int64(data.Settings.VisibilityTimeoutSeconds))
 	model.ExpireSeconds = types.Int64Value(int64(data.Settings.ExpireSeconds))
 	if v, ok := data.Description.Value.GetString(); ok {
 		model.Description = types.StringValue(v)
+	} else {
+		model.Description = types.StringValue("")
 	}
 	if iconID, ok := data.Icon.Value.Icon1.ID.Get(); ok {
 		id, ok := iconID.GetString()
@@ -47,5 +49,5 @@ func (model *simpleMqBaseModel) updateState(data *queue.CommonServiceItem) {
 	} else {
 		model.IconID = types.StringValue("")
 	}
-	model.Tags = common.StringsToTset(data.Tags)
+	model.Tags = common.FlattenTags(data.Tags)
 }
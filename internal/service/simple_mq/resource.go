@@ -28,7 +28,6 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	validator "github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
-	api "github.com/sacloud/api-client-go"
 	"github.com/sacloud/simplemq-api-go"
 	"github.com/sacloud/simplemq-api-go/apis/v1/queue"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
@@ -36,13 +35,14 @@ import (
 )
 
 type simpleMQResource struct {
-	client *queue.Client
+	client *common.APIClient
 }
 
 var (
 	_ resource.Resource                = &simpleMQResource{}
 	_ resource.ResourceWithConfigure   = &simpleMQResource{}
 	_ resource.ResourceWithImportState = &simpleMQResource{}
+	_ resource.ResourceWithModifyPlan  = &simpleMQResource{}
 )
 
 func NewSimpleMQResource() resource.Resource {
@@ -58,7 +58,18 @@ func (r *simpleMQResource) Configure(ctx context.Context, req resource.Configure
 	if apiclient == nil {
 		return
 	}
-	r.client = apiclient.SimpleMqClient
+	r.client = apiclient
+}
+
+// queueClient はシンプルMQのAPIクライアントを返す。クライアントは最初に利用する操作で生成するため、
+// シンプルMQを利用しない場合にエンドポイントなどの設定の誤りでConfigureが失敗することはない
+func (r *simpleMQResource) queueClient(diags *diag.Diagnostics) *queue.Client {
+	client, err := r.client.SimpleMQClient()
+	if err != nil {
+		diags.AddError("SimpleMQ Client Error", err.Error())
+		return nil
+	}
+	return client
 }
 
 type simpleMQResourceModel struct {
@@ -107,7 +118,7 @@ func (r *simpleMQResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 					}),
 				},
 			},
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
 				Create: true, Update: true, Delete: true,
 			}),
 		},
@@ -118,6 +129,10 @@ func (r *simpleMQResource) ImportState(ctx context.Context, req resource.ImportS
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *simpleMQResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanResourceDefaults(ctx, r.client, req, resp)
+}
+
 func (r *simpleMQResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan simpleMQResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -127,23 +142,32 @@ func (r *simpleMQResource) Create(ctx context.Context, req resource.CreateReques
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
-	queueOp := simplemq.NewQueueOp(r.client)
+	client := r.queueClient(&resp.Diagnostics)
+	if client == nil {
+		return
+	}
+	queueOp := simplemq.NewQueueOp(client)
 	mq, err := queueOp.Create(ctx, expandSimpleMQCreateRequest(&plan))
 	if err != nil {
-		resp.Diagnostics.AddError("Create Error", fmt.Sprintf("create SimpleMQ queue failed: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", fmt.Errorf("create SimpleMQ queue failed: %w", err))
 		return
 	}
 	qid := simplemq.GetQueueID(mq)
+	resp.Diagnostics.Append(common.SetCreatedID(ctx, &resp.State, qid)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
 	// SDK v2ではUpdateを呼び出して更新していたが、Frameworkではアクション間での状態の共有が難しいためメソッドに括り出して処理を共通化
-	err = r.callUpdateRequest(ctx, qid, &plan, mq)
+	err = callUpdateRequest(ctx, client, qid, &plan, mq)
 	if err != nil {
-		resp.Diagnostics.AddError("Create Error", err.Error())
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", err)
 		return
 	}
 
-	q := getMessageQueue(ctx, r.client, qid, &resp.State, &resp.Diagnostics)
+	q := getMessageQueue(ctx, client, qid, &resp.State, &resp.Diagnostics)
 	if q == nil {
 		return
 	}
@@ -159,7 +183,13 @@ func (r *simpleMQResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 
-	mq := getMessageQueue(ctx, r.client, state.ID.ValueString(), &resp.State, &resp.Diagnostics)
+	ctx = common.WithAPIErrorCapture(ctx)
+
+	client := r.queueClient(&resp.Diagnostics)
+	if client == nil {
+		return
+	}
+	mq := getMessageQueue(ctx, client, state.ID.ValueString(), &resp.State, &resp.Diagnostics)
 	if mq == nil {
 		return
 	}
@@ -177,14 +207,19 @@ func (r *simpleMQResource) Update(ctx context.Context, req resource.UpdateReques
 
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
-	err := r.callUpdateRequest(ctx, plan.ID.ValueString(), &plan, nil)
+	client := r.queueClient(&resp.Diagnostics)
+	if client == nil {
+		return
+	}
+	err := callUpdateRequest(ctx, client, plan.ID.ValueString(), &plan, nil)
 	if err != nil {
-		resp.Diagnostics.AddError("Update Error", err.Error())
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", err)
 		return
 	}
 
-	q := getMessageQueue(ctx, r.client, plan.ID.ValueString(), &resp.State, &resp.Diagnostics)
+	q := getMessageQueue(ctx, client, plan.ID.ValueString(), &resp.State, &resp.Diagnostics)
 	if q == nil {
 		return
 	}
@@ -202,22 +237,30 @@ func (r *simpleMQResource) Delete(ctx context.Context, req resource.DeleteReques
 
 	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
-	queueOp := simplemq.NewQueueOp(r.client)
-	mq := getMessageQueue(ctx, r.client, state.ID.ValueString(), &resp.State, &resp.Diagnostics)
+	client := r.queueClient(&resp.Diagnostics)
+	if client == nil {
+		return
+	}
+	queueOp := simplemq.NewQueueOp(client)
+	mq := getMessageQueue(ctx, client, state.ID.ValueString(), &resp.State, &resp.Diagnostics)
 	if mq == nil {
 		return
 	}
 
-	if err := queueOp.Delete(ctx, simplemq.GetQueueID(mq)); err != nil {
-		resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("delete SimpleMQ[%s] queue failed: %s", state.ID.ValueString(), err))
+	err := common.RetryOnConflict(ctx, func() error {
+		return queueOp.Delete(ctx, simplemq.GetQueueID(mq))
+	})
+	if err != nil {
+		common.AddAPIError(ctx, &resp.Diagnostics, "Delete Error", fmt.Errorf("delete SimpleMQ[%s] queue failed: %w", state.ID.ValueString(), err))
 		return
 	}
 }
 
-func (r *simpleMQResource) callUpdateRequest(ctx context.Context, id string, plan *simpleMQResourceModel, mq *queue.CommonServiceItem) error {
+func callUpdateRequest(ctx context.Context, client *queue.Client, id string, plan *simpleMQResourceModel, mq *queue.CommonServiceItem) error {
 	var err error
-	queueOp := simplemq.NewQueueOp(r.client)
+	queueOp := simplemq.NewQueueOp(client)
 
 	if mq == nil {
 		mq, err = queueOp.Read(ctx, id)
@@ -238,11 +281,10 @@ func getMessageQueue(ctx context.Context, client *queue.Client, id string, state
 	queueOp := simplemq.NewQueueOp(client)
 	mq, err := queueOp.Read(ctx, id)
 	if err != nil {
-		if api.IsNotFoundError(err) {
-			state.RemoveResource(ctx)
+		if common.HandleNotFoundOnRead(ctx, err, state, "SimpleMQ queue", id) {
 			return nil
 		}
-		diags.AddError("Get Queue Error", fmt.Sprintf("could not read SimpleMQ[%s] queue: %s", id, err))
+		common.AddAPIError(ctx, diags, "Get Queue Error", fmt.Errorf("could not read SimpleMQ[%s] queue: %w", id, err))
 		return nil
 	}
 
@@ -79,10 +79,12 @@ func (d *sshKeyDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	searcher := iaas.NewSSHKeyOp(d.client)
 	res, err := searcher.Find(ctx, common.CreateFindCondition(data.ID, data.Name, types.SetNull(types.StringType)))
 	if err != nil {
-		resp.Diagnostics.AddError("Read Error", fmt.Sprintf("could not find SakuraCloud SSHKey resource: %s", err.Error()))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Read Error", fmt.Errorf("could not find SakuraCloud SSHKey resource: %w", err))
 		return
 	}
 	if res == nil || res.Count == 0 || len(res.SSHKeys) == 0 {
@@ -39,6 +39,7 @@ var (
 	_ resource.Resource                = &sshKeyResource{}
 	_ resource.ResourceWithConfigure   = &sshKeyResource{}
 	_ resource.ResourceWithImportState = &sshKeyResource{}
+	_ resource.ResourceWithModifyPlan  = &sshKeyResource{}
 )
 
 func NewSSHKeyResource() resource.Resource {
@@ -79,7 +80,7 @@ func (r *sshKeyResource) Schema(ctx context.Context, _ resource.SchemaRequest, r
 				Computed:    true,
 				Description: "The fingerprint of the public key.",
 			},
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
 				Create: true, Update: true, Delete: true,
 			}),
 		},
@@ -90,6 +91,10 @@ func (r *sshKeyResource) ImportState(ctx context.Context, req resource.ImportSta
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *sshKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanResourceDefaults(ctx, r.client, req, resp)
+}
+
 func (r *sshKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan sshKeyResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -99,6 +104,7 @@ func (r *sshKeyResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	sshKeyOp := iaas.NewSSHKeyOp(r.client)
 	key, err := sshKeyOp.Create(ctx, &iaas.SSHKeyCreateRequest{
@@ -107,7 +113,7 @@ func (r *sshKeyResource) Create(ctx context.Context, req resource.CreateRequest,
 		PublicKey:   plan.PublicKey.ValueString(),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("Create Error", fmt.Sprintf("creating SSHKey is failed: %s", err.Error()))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", fmt.Errorf("creating SSHKey is failed: %w", err))
 		return
 	}
 
@@ -122,6 +128,8 @@ func (r *sshKeyResource) Read(ctx context.Context, req resource.ReadRequest, res
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	key := getSSHKey(ctx, r.client, common.ExpandSakuraCloudID(state.ID), &resp.State, &resp.Diagnostics)
 	if key == nil {
 		return
@@ -140,11 +148,12 @@ func (r *sshKeyResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	sshKeyOp := iaas.NewSSHKeyOp(r.client)
 	key, err := sshKeyOp.Read(ctx, common.ExpandSakuraCloudID(plan.ID))
 	if err != nil {
-		resp.Diagnostics.AddError("Update Error", fmt.Sprintf("could not read SSHKey[%s]: %s", plan.ID.ValueString(), err.Error()))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", fmt.Errorf("could not read SSHKey[%s]: %w", plan.ID.ValueString(), err))
 		return
 	}
 
@@ -153,7 +162,7 @@ func (r *sshKeyResource) Update(ctx context.Context, req resource.UpdateRequest,
 		Description: plan.Description.ValueString(),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("Update Error", fmt.Sprintf("updating SSHKey[%s] is failed: %s", plan.ID.ValueString(), err.Error()))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", fmt.Errorf("updating SSHKey[%s] is failed: %w", plan.ID.ValueString(), err))
 		return
 	}
 
@@ -175,6 +184,7 @@ func (r *sshKeyResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	sshKeyOp := iaas.NewSSHKeyOp(r.client)
 	key := getSSHKey(ctx, r.client, common.ExpandSakuraCloudID(state.ID), &resp.State, &resp.Diagnostics)
@@ -183,7 +193,7 @@ func (r *sshKeyResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	}
 
 	if err := sshKeyOp.Delete(ctx, key.ID); err != nil {
-		resp.Diagnostics.AddError("Delete Error", fmt.Sprintf("deleting SSHKey[%s] is failed: %s", state.ID.ValueString(), err.Error()))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Delete Error", fmt.Errorf("deleting SSHKey[%s] is failed: %w", state.ID.ValueString(), err))
 		return
 	}
 }
@@ -192,11 +202,10 @@ func getSSHKey(ctx context.Context, client *common.APIClient, id iaastypes.ID, s
 	sshKeyOp := iaas.NewSSHKeyOp(client)
 	sshKey, err := sshKeyOp.Read(ctx, id)
 	if err != nil {
-		if iaas.IsNotFoundError(err) {
-			state.RemoveResource(ctx)
+		if common.HandleNotFoundOnRead(ctx, err, state, "SSHKey", id.String()) {
 			return nil
 		}
-		diags.AddError("Read Error", fmt.Sprintf("could not read SSHKey[%d]: %s", id, err))
+		common.AddAPIError(ctx, diags, "Read Error", fmt.Errorf("could not read SSHKey[%d]: %w", id, err))
 		return nil
 	}
 
@@ -82,6 +82,8 @@ func (d *switchDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
+	ctx = common.WithAPIErrorCapture(ctx)
+
 	zone := common.GetZone(data.Zone, d.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -90,7 +92,7 @@ func (d *switchDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 	searcher := iaas.NewSwitchOp(d.client)
 	res, err := searcher.Find(ctx, zone, common.CreateFindCondition(data.ID, data.Name, data.Tags))
 	if err != nil {
-		resp.Diagnostics.AddError("Read Error", err.Error())
+		common.AddAPIError(ctx, &resp.Diagnostics, "Read Error", err)
 		return
 	}
 	if res == nil || res.Count == 0 || len(res.Switches) == 0 {
@@ -100,7 +102,7 @@ func (d *switchDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 
 	sw := res.Switches[0]
 	if err := data.updateState(ctx, d.client, sw, zone); err != nil {
-		resp.Diagnostics.AddError("Read Error", err.Error())
+		common.AddAPIError(ctx, &resp.Diagnostics, "Read Error", err)
 		return
 	}
 	data.IconID = types.StringValue(sw.IconID.String())
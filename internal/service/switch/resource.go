@@ -42,6 +42,7 @@ var (
 	_ resource.Resource                = &switchResource{}
 	_ resource.ResourceWithConfigure   = &switchResource{}
 	_ resource.ResourceWithImportState = &switchResource{}
+	_ resource.ResourceWithModifyPlan  = &switchResource{}
 )
 
 func NewSwitchResource() resource.Resource {
@@ -90,7 +91,7 @@ func (r *switchResource) Schema(ctx context.Context, _ resource.SchemaRequest, r
 					setvalidator.ValueStringsAre(sacloudvalidator.SakuraIDValidator()),
 				},
 			},
-			"timeouts": timeouts.Attributes(ctx, timeouts.Opts{
+			"timeouts": common.TimeoutsAttributes(ctx, timeouts.Opts{
 				Create: true, Update: true, Delete: true,
 			}),
 		},
@@ -101,6 +102,11 @@ func (r *switchResource) ImportState(ctx context.Context, req resource.ImportSta
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
 
+func (r *switchResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	common.PlanZone(ctx, r.client, req, resp)
+	common.PlanResourceDefaults(ctx, r.client, req, resp)
+}
+
 func (r *switchResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan switchResourceModel
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
@@ -114,6 +120,7 @@ func (r *switchResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	ctx, cancel := common.SetupTimeoutCreate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	swOp := iaas.NewSwitchOp(r.client)
 	sw, err := swOp.Create(ctx, zone, &iaas.SwitchCreateRequest{
@@ -123,7 +130,7 @@ func (r *switchResource) Create(ctx context.Context, req resource.CreateRequest,
 		IconID:      common.ExpandSakuraCloudID(plan.IconID),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("Create Error", fmt.Sprintf("creating SakuraCloud Switch is failed: %s", err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Create Error", fmt.Errorf("creating SakuraCloud Switch is failed: %w", err))
 		return
 	}
 
@@ -150,6 +157,8 @@ func (r *switchResource) Read(ctx context.Context, req resource.ReadRequest, res
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	ctx = common.WithAPIErrorCapture(ctx)
 	zone := common.GetZone(state.Zone, r.client, &resp.Diagnostics)
 	if resp.Diagnostics.HasError() {
 		return
@@ -161,7 +170,7 @@ func (r *switchResource) Read(ctx context.Context, req resource.ReadRequest, res
 	}
 
 	if err := state.updateState(ctx, r.client, sw, zone); err != nil {
-		resp.Diagnostics.AddError("Read Error", err.Error())
+		common.AddAPIError(ctx, &resp.Diagnostics, "Read Error", err)
 		return
 	}
 
@@ -183,6 +192,7 @@ func (r *switchResource) Update(ctx context.Context, req resource.UpdateRequest,
 
 	ctx, cancel := common.SetupTimeoutUpdate(ctx, plan.Timeouts, common.Timeout5min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	sid := state.ID.ValueString()
 	common.SakuraMutexKV.Lock(sid)
@@ -196,7 +206,7 @@ func (r *switchResource) Update(ctx context.Context, req resource.UpdateRequest,
 		IconID:      common.ExpandSakuraCloudID(plan.IconID),
 	})
 	if err != nil {
-		resp.Diagnostics.AddError("Update Error", fmt.Sprintf("updating SakuraCloud Switch[%s] is failed : %s", sw.ID, err))
+		common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", fmt.Errorf("updating SakuraCloud Switch[%s] is failed : %w", sw.ID, err))
 		return
 	}
 
@@ -212,7 +222,7 @@ func (r *switchResource) Update(ctx context.Context, req resource.UpdateRequest,
 				}
 			} else {
 				if err := swOp.ConnectToBridge(ctx, zone, sw.ID, common.SakuraCloudID(brId)); err != nil {
-					resp.Diagnostics.AddError("Update Error", fmt.Sprintf("connecting to Bridge[%s] is failed: %s", brId, err))
+					common.AddAPIError(ctx, &resp.Diagnostics, "Update Error", fmt.Errorf("connecting to Bridge[%s] is failed: %w", brId, err))
 					return
 				}
 			}
@@ -240,6 +250,7 @@ func (r *switchResource) Delete(ctx context.Context, req resource.DeleteRequest,
 
 	ctx, cancel := common.SetupTimeoutDelete(ctx, state.Timeouts, common.Timeout20min)
 	defer cancel()
+	ctx = common.WithAPIErrorCapture(ctx)
 
 	sid := state.ID.ValueString()
 	common.SakuraMutexKV.Lock(sid)
@@ -270,11 +281,10 @@ func getSwitch(ctx context.Context, client *common.APIClient, id iaastypes.ID, z
 	swOp := iaas.NewSwitchOp(client)
 	sw, err := swOp.Read(ctx, zone, id)
 	if err != nil {
-		if iaas.IsNotFoundError(err) {
-			state.RemoveResource(ctx)
+		if common.HandleNotFoundOnRead(ctx, err, state, "Switch", id.String()) {
 			return nil
 		}
-		diags.AddError("API Read Error", fmt.Sprintf("could not read SakuraCloud Switch[%s] : %s", id, err))
+		common.AddAPIError(ctx, diags, "API Read Error", fmt.Errorf("could not read SakuraCloud Switch[%s] : %w", id, err))
 		return nil
 	}
 	return sw
@@ -287,7 +297,7 @@ func updateModelByRead(ctx context.Context, model *switchResourceModel, client *
 	}
 
 	if err := model.updateState(ctx, client, sw, zone); err != nil {
-		diags.AddError("Update State Error", err.Error())
+		common.AddAPIError(ctx, diags, "Update State Error", err)
 		return true
 	}
 
@@ -17,64 +17,183 @@ package test
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	client "github.com/sacloud/api-client-go"
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
 )
 
-func CheckSakuraDataSourceExists(n string) resource.TestCheckFunc {
+const (
+	destroyCheckTimeout  = 3 * time.Minute
+	destroyCheckInterval = 5 * time.Second
+)
+
+// ReadFunc はstateに記録されたリソースをAPIから取得する。削除済みの場合はnot foundエラーを返すこと
+type ReadFunc func(ctx context.Context, rs *terraform.ResourceState) error
+
+// CheckDestroy は指定したタイプのリソースが全て削除されているかを検証するTestCheckFuncを返す。
+// 削除が非同期に反映される場合を考慮し、not foundが返るまで一定時間ポーリングする
+func CheckDestroy(resourceType string, read ReadFunc) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		rs, ok := s.RootModule().Resources[n]
-		if !ok {
-			return fmt.Errorf("resource is not exists: %s", n)
+		for _, rs := range s.RootModule().Resources {
+			if rs.Type != resourceType {
+				continue
+			}
+			if rs.Primary.ID == "" {
+				continue
+			}
+
+			if err := waitForDestroy(rs, read, destroyCheckTimeout, destroyCheckInterval); err != nil {
+				return fmt.Errorf("resource %s[%s]: %w", resourceType, rs.Primary.ID, err)
+			}
 		}
+		return nil
+	}
+}
 
-		if rs.Primary.ID == "" {
-			return fmt.Errorf("id is not set: %s", n)
+// ExistsFunc はstateに記録されたリソースがAPI上に存在するかを返す。
+// CheckDestroyのポーリングで大きなレスポンスを繰り返し取得しないよう、APIClientのKMSKeyExistsなどを利用する
+type ExistsFunc func(ctx context.Context, rs *terraform.ResourceState) (bool, error)
+
+// ReadByExists はExistsFuncを、存在しない場合にnot foundエラーを返すReadFuncに変換する
+func ReadByExists(exists ExistsFunc) ReadFunc {
+	return func(ctx context.Context, rs *terraform.ResourceState) error {
+		found, err := exists(ctx, rs)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return client.NewAPIError(http.StatusNotFound, "", fmt.Errorf("%s is not found", rs.Primary.ID))
 		}
 		return nil
 	}
 }
 
-func CheckSakuraSwitchDestroy(s *terraform.State) error {
-	swOp := iaas.NewSwitchOp(AccClientGetter())
+func waitForDestroy(rs *terraform.ResourceState, read ReadFunc, timeout, interval time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-	for _, rs := range s.RootModule().Resources {
-		if rs.Type != "sakura_switch" {
-			continue
+	for {
+		err := read(ctx, rs)
+		if common.IsNotFound(err) {
+			return nil
 		}
-		if rs.Primary.ID == "" {
-			continue
+		// not found以外のエラーは削除済みとみなさない
+		if err != nil {
+			return err
 		}
 
-		zone := rs.Primary.Attributes["zone"]
-		_, err := swOp.Read(context.Background(), zone, common.SakuraCloudID(rs.Primary.ID))
-		if err == nil {
-			return fmt.Errorf("resource Switch[%s] still exists", rs.Primary.ID)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("still exists after %s", timeout)
+		case <-time.After(interval):
 		}
 	}
+}
 
-	return nil
+// ExistsCheck はCheckExistsで検証するリソースの取得方法を表す
+type ExistsCheck[T any] struct {
+	// Kind はエラーメッセージに表示するリソースの種類
+	Kind string
+	// Read はstateに記録されたリソースをAPIから取得する
+	Read func(ctx context.Context, rs *terraform.ResourceState) (*T, error)
+	// ID は取得したリソースの識別子を返す
+	ID func(v *T) string
+	// IDAttribute は取得したリソースの識別子と比較するstate上の属性名。省略時はidを利用する
+	IDAttribute string
 }
 
-func CheckSakuraIconDestroy(s *terraform.State) error {
-	iconOp := iaas.NewIconOp(AccClientGetter())
+// CheckExists はstateに記録されたリソースがAPIから取得でき、識別子がstateと一致することを検証する。
+// 取得したリソースはvに格納されるため、後続のCheckFetchedなどで参照できる
+func CheckExists[T any](n string, c ExistsCheck[T], v *T) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
 
-	for _, rs := range s.RootModule().Resources {
-		if rs.Type != "sakura_icon" {
-			continue
+		idAttr := c.IDAttribute
+		if idAttr == "" {
+			idAttr = "id"
 		}
-		if rs.Primary.ID == "" {
-			continue
+		want := rs.Primary.Attributes[idAttr]
+		if want == "" {
+			return fmt.Errorf("no %s %s is set: %s", c.Kind, idAttr, n)
 		}
 
-		_, err := iconOp.Read(context.Background(), common.SakuraCloudID(rs.Primary.ID))
-		if err == nil {
-			return fmt.Errorf("still exists Icon: %s", rs.Primary.ID)
+		found, err := c.Read(context.Background(), rs)
+		if err != nil {
+			return fmt.Errorf("reading %s[%s] is failed: %w", c.Kind, want, err)
 		}
+		if found == nil {
+			return fmt.Errorf("not found %s: %s", c.Kind, want)
+		}
+		if got := c.ID(found); got != want {
+			return fmt.Errorf("%s %s mismatch: state has %q, but API returned %q", c.Kind, idAttr, want, got)
+		}
+
+		*v = *found
+		return nil
 	}
+}
 
-	return nil
+// CheckFetched はCheckExistsで取得したリソースに対する任意の検証を行う。
+// vはチェックの実行時に参照されるため、CheckExistsより後に指定すること
+func CheckFetched[T any](v *T, check func(v *T) error) resource.TestCheckFunc {
+	return func(_ *terraform.State) error {
+		return check(v)
+	}
 }
+
+// CheckFetchedAttrPair はCheckExistsで取得したリソースのフィールドが、別のリソースのstate上の属性値と一致することを検証する。
+// 他のリソースを参照するIDなど、事前に値が決まらないフィールドの検証に利用する
+func CheckFetchedAttrPair[T any](v *T, field string, get func(v *T) string, n, attr string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+		want, ok := rs.Primary.Attributes[attr]
+		if !ok {
+			return fmt.Errorf("%s: attribute %q is not set", n, attr)
+		}
+		if got := get(v); got != want {
+			return fmt.Errorf("%s: want %q (%s.%s), got %q", field, want, n, attr, got)
+		}
+		return nil
+	}
+}
+
+func CheckSakuraDataSourceExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("resource is not exists: %s", n)
+		}
+
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("id is not set: %s", n)
+		}
+		return nil
+	}
+}
+
+var CheckSakuraSwitchDestroy = CheckDestroy("sakura_switch", ReadByExists(func(ctx context.Context, rs *terraform.ResourceState) (bool, error) {
+	res, err := iaas.NewSwitchOp(AccClientGetter()).Find(ctx, rs.Primary.Attributes["zone"], common.IaaSExistsCondition(common.SakuraCloudID(rs.Primary.ID)))
+	if err != nil {
+		return false, err
+	}
+	return res.Count > 0, nil
+}))
+
+var CheckSakuraIconDestroy = CheckDestroy("sakura_icon", ReadByExists(func(ctx context.Context, rs *terraform.ResourceState) (bool, error) {
+	res, err := iaas.NewIconOp(AccClientGetter()).Find(ctx, common.IaaSExistsCondition(common.SakuraCloudID(rs.Primary.ID)))
+	if err != nil {
+		return false, err
+	}
+	return res.Count > 0, nil
+}))
@@ -0,0 +1,189 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	api "github.com/sacloud/api-client-go"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+)
+
+func TestWaitForDestroy(t *testing.T) {
+	notFound := api.NewAPIError(http.StatusNotFound, "not found", nil)
+	rs := &terraform.ResourceState{Primary: &terraform.InstanceState{ID: "110000000000"}}
+
+	expects := []struct {
+		name    string
+		results []error // n回目の呼び出しで返すエラー。最後の要素は以降も返し続ける
+		wantErr bool
+	}{
+		{
+			name:    "deleted",
+			results: []error{notFound},
+		},
+		{
+			name:    "deleted after polling",
+			results: []error{nil, nil, notFound},
+		},
+		{
+			name:    "still exists",
+			results: []error{nil},
+			wantErr: true,
+		},
+		{
+			name:    "unexpected error",
+			results: []error{errors.New("internal server error")},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range expects {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			read := func(_ context.Context, _ *terraform.ResourceState) error {
+				err := tc.results[min(calls, len(tc.results)-1)]
+				calls++
+				return err
+			}
+
+			err := waitForDestroy(rs, read, 100*time.Millisecond, time.Millisecond)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestReadByExists(t *testing.T) {
+	rs := &terraform.ResourceState{Primary: &terraform.InstanceState{ID: "110000000000"}}
+	exists := func(found bool, err error) ExistsFunc {
+		return func(context.Context, *terraform.ResourceState) (bool, error) {
+			return found, err
+		}
+	}
+
+	assert.NoError(t, ReadByExists(exists(true, nil))(context.Background(), rs))
+	assert.True(t, common.IsNotFound(ReadByExists(exists(false, nil))(context.Background(), rs)))
+
+	err := ReadByExists(exists(false, errors.New("internal server error")))(context.Background(), rs)
+	assert.Error(t, err)
+	assert.False(t, common.IsNotFound(err))
+}
+
+type testExistsItem struct {
+	ID   string
+	Name string
+}
+
+func testExistsState(attrs map[string]string) *terraform.State {
+	s := terraform.NewState()
+	s.RootModule().Resources["sakura_test.foobar"] = &terraform.ResourceState{
+		Type:    "sakura_test",
+		Primary: &terraform.InstanceState{ID: attrs["id"], Attributes: attrs},
+	}
+	return s
+}
+
+func TestCheckExists(t *testing.T) {
+	read := func(found *testExistsItem, err error) func(context.Context, *terraform.ResourceState) (*testExistsItem, error) {
+		return func(context.Context, *terraform.ResourceState) (*testExistsItem, error) {
+			return found, err
+		}
+	}
+
+	expects := []struct {
+		name    string
+		check   ExistsCheck[testExistsItem]
+		attrs   map[string]string
+		want    *testExistsItem
+		wantErr string
+	}{
+		{
+			name:  "found",
+			check: ExistsCheck[testExistsItem]{Read: read(&testExistsItem{ID: "1", Name: "foo"}, nil)},
+			attrs: map[string]string{"id": "1"},
+			want:  &testExistsItem{ID: "1", Name: "foo"},
+		},
+		{
+			name:    "id is not set",
+			check:   ExistsCheck[testExistsItem]{Read: read(&testExistsItem{ID: "1"}, nil)},
+			attrs:   map[string]string{},
+			wantErr: "no test id is set",
+		},
+		{
+			name:    "api error",
+			check:   ExistsCheck[testExistsItem]{Read: read(nil, errors.New("internal server error"))},
+			attrs:   map[string]string{"id": "1"},
+			wantErr: "internal server error",
+		},
+		{
+			name:    "id mismatch",
+			check:   ExistsCheck[testExistsItem]{Read: read(&testExistsItem{ID: "2"}, nil)},
+			attrs:   map[string]string{"id": "1"},
+			wantErr: "id mismatch",
+		},
+		{
+			name: "custom id attribute",
+			check: ExistsCheck[testExistsItem]{
+				Read:        read(&testExistsItem{Name: "foo"}, nil),
+				ID:          func(v *testExistsItem) string { return v.Name },
+				IDAttribute: "name",
+			},
+			attrs: map[string]string{"name": "foo"},
+			want:  &testExistsItem{Name: "foo"},
+		},
+	}
+
+	for _, tc := range expects {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.check.Kind = "test"
+			if tc.check.ID == nil {
+				tc.check.ID = func(v *testExistsItem) string { return v.ID }
+			}
+
+			var got testExistsItem
+			err := CheckExists("sakura_test.foobar", tc.check, &got)(testExistsState(tc.attrs))
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, *tc.want, got)
+		})
+	}
+}
+
+func TestCheckFetchedAttrPair(t *testing.T) {
+	s := testExistsState(map[string]string{"id": "1"})
+	get := func(v *testExistsItem) string { return v.ID }
+
+	v := &testExistsItem{}
+	check := CheckFetchedAttrPair(v, "ref_id", get, "sakura_test.foobar", "id")
+
+	// 取得前のvは空のため一致しない
+	assert.ErrorContains(t, check(s), `ref_id: want "1"`)
+
+	*v = testExistsItem{ID: "1"}
+	assert.NoError(t, check(s))
+
+	assert.Error(t, CheckFetchedAttrPair(v, "ref_id", get, "sakura_test.missing", "id")(s))
+	assert.Error(t, CheckFetchedAttrPair(v, "ref_id", get, "sakura_test.foobar", "missing")(s))
+}
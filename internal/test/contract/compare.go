@@ -0,0 +1,247 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package contract はVCRで記録した実APIのカセットをフェイクAPIサーバーに対して再生し、
+// フェイクが実APIと構造的に同等なレスポンスを返すかを検証するためのコントラクトテストの仕組みを提供する
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test/vcr"
+)
+
+var (
+	// 実APIのリソースIDは12桁の数値
+	idPattern = regexp.MustCompile(`^\d{12}$`)
+	// VCRが記録時にランダムなリソース名を置き換えたプレースホルダー
+	namePlaceholderPattern = regexp.MustCompile(`__vcr_name_\d+__`)
+)
+
+// isVolatile は実行ごとに値が変わるため、型のみを比較する文字列かを返す
+func isVolatile(v string) bool {
+	if v == vcr.Redacted || idPattern.MatchString(v) || namePlaceholderPattern.MatchString(v) {
+		return true
+	}
+	if _, err := time.Parse(time.RFC3339Nano, v); err == nil {
+		return true
+	}
+	return false
+}
+
+// Diff は記録されたレスポンスボディとフェイクのレスポンスボディを構造的に比較し、差異の一覧を返す。
+//
+//   - オブジェクトはキーの集合と各値を再帰的に比較する
+//   - 数値、ID、タイムスタンプ、プレースホルダー、マスクされた値は型のみを比較する
+//   - オブジェクトの配列は件数や内容が実行環境によって変わるため、各要素が記録された先頭要素と同じキーと型を持つかのみを比較する
+//   - それ以外の値(enumなどの文字列、真偽値、文字列の配列)は値を比較する
+func Diff(recorded, actual string) []string {
+	if recorded == "" || actual == "" {
+		if recorded != actual {
+			return []string{fmt.Sprintf("body: want %s, got %s", describeBody(recorded), describeBody(actual))}
+		}
+		return nil
+	}
+
+	want, err := decode(recorded)
+	if err != nil {
+		return []string{fmt.Sprintf("recorded body is not JSON: %s", err)}
+	}
+	got, err := decode(actual)
+	if err != nil {
+		return []string{fmt.Sprintf("actual body is not JSON: %s", err)}
+	}
+
+	var diffs []string
+	compare("$", want, got, false, &diffs)
+	return diffs
+}
+
+func describeBody(body string) string {
+	if body == "" {
+		return "empty body"
+	}
+	return fmt.Sprintf("%q", body)
+}
+
+func decode(body string) (interface{}, error) {
+	var v interface{}
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// compareはshapeOnlyがtrueの場合、キーの集合と型のみを比較する
+func compare(path string, want, got interface{}, shapeOnly bool, diffs *[]string) {
+	if typeName(want) != typeName(got) {
+		*diffs = append(*diffs, fmt.Sprintf("%s: type mismatch: want %s, got %s", path, typeName(want), typeName(got)))
+		return
+	}
+
+	switch want := want.(type) {
+	case map[string]interface{}:
+		got := got.(map[string]interface{})
+		for _, key := range unionKeys(want, got) {
+			w, inWant := want[key]
+			g, inGot := got[key]
+			switch {
+			case !inGot:
+				*diffs = append(*diffs, fmt.Sprintf("%s.%s: missing in fake response", path, key))
+			case !inWant:
+				*diffs = append(*diffs, fmt.Sprintf("%s.%s: unexpected in fake response", path, key))
+			default:
+				compare(path+"."+key, w, g, shapeOnly, diffs)
+			}
+		}
+	case []interface{}:
+		got := got.([]interface{})
+		if len(want) > 0 && typeName(want[0]) == "object" {
+			for i, g := range got {
+				compare(fmt.Sprintf("%s[%d]", path, i), want[0], g, true, diffs)
+			}
+			return
+		}
+		if shapeOnly {
+			return
+		}
+		if len(want) != len(got) {
+			*diffs = append(*diffs, fmt.Sprintf("%s: length mismatch: want %d, got %d", path, len(want), len(got)))
+			return
+		}
+		for i := range want {
+			compare(fmt.Sprintf("%s[%d]", path, i), want[i], got[i], shapeOnly, diffs)
+		}
+	case string:
+		got := got.(string)
+		if shapeOnly || isVolatile(want) && isVolatile(got) {
+			return
+		}
+		if want != got {
+			*diffs = append(*diffs, fmt.Sprintf("%s: want %q, got %q", path, want, got))
+		}
+	case json.Number:
+		// 件数などは実行環境によって変わるため比較しない
+	default:
+		if !shapeOnly && want != got {
+			*diffs = append(*diffs, fmt.Sprintf("%s: want %v, got %v", path, want, got))
+		}
+	}
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "bool"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func unionKeys(a, b map[string]interface{}) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var keys []string
+	for _, m := range []map[string]interface{}{a, b} {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// collectIDs はrecordedとactualの同じ位置にあるIDの対応をidsに追加する。
+// 記録時に払い出されたIDを、以降のリクエストでフェイクが払い出したIDに置き換えるために利用する
+func collectIDs(recorded, actual string, ids map[string]string) {
+	want, err := decode(recorded)
+	if err != nil {
+		return
+	}
+	got, err := decode(actual)
+	if err != nil {
+		return
+	}
+	walkIDs(want, got, ids)
+}
+
+func walkIDs(want, got interface{}, ids map[string]string) {
+	switch want := want.(type) {
+	case map[string]interface{}:
+		got, ok := got.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for key, w := range want {
+			walkIDs(w, got[key], ids)
+		}
+	case []interface{}:
+		got, ok := got.([]interface{})
+		if !ok || len(want) != len(got) {
+			return
+		}
+		for i := range want {
+			walkIDs(want[i], got[i], ids)
+		}
+	case string:
+		got, ok := got.(string)
+		if !ok || !idPattern.MatchString(want) || !idPattern.MatchString(got) {
+			return
+		}
+		if _, exists := ids[want]; !exists {
+			ids[want] = got
+		}
+	}
+}
+
+// replaceIDs はs中の記録時のIDをフェイクのIDに置き換える
+func replaceIDs(s string, ids map[string]string) string {
+	if len(ids) == 0 || s == "" {
+		return s
+	}
+	pairs := make([]string, 0, len(ids)*2)
+	for recorded, actual := range ids {
+		pairs = append(pairs, recorded, actual)
+	}
+	return strings.NewReplacer(pairs...).Replace(s)
+}
+
+// indentJSON は差異の表示用にJSONを整形する。JSONでない場合はそのまま返す
+func indentJSON(s string) string {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(s), "", "  "); err != nil {
+		return s
+	}
+	return buf.String()
+}
@@ -0,0 +1,146 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contract
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test/vcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	expects := []struct {
+		name     string
+		recorded string
+		actual   string
+		want     []string
+	}{
+		{
+			name:     "ids, timestamps and names are tolerated",
+			recorded: `{"Key":{"ID":"113700000001","CreatedAt":"2025-01-01T00:00:00+09:00","Name":"__vcr_name_0__","KeyOrigin":"generated"}}`,
+			actual:   `{"Key":{"ID":"110000000001","CreatedAt":"2026-10-15T12:34:56.789+09:00","Name":"__vcr_name_0__","KeyOrigin":"generated"}}`,
+		},
+		{
+			name:     "value mismatch",
+			recorded: `{"Key":{"KeyOrigin":"generated"}}`,
+			actual:   `{"Key":{"KeyOrigin":"imported"}}`,
+			want:     []string{`$.Key.KeyOrigin: want "generated", got "imported"`},
+		},
+		{
+			name:     "missing and unexpected keys",
+			recorded: `{"Key":{"ID":"113700000001","Tags":[]}}`,
+			actual:   `{"Key":{"ID":"110000000001","Status":"active"}}`,
+			want: []string{
+				"$.Key.Status: unexpected in fake response",
+				"$.Key.Tags: missing in fake response",
+			},
+		},
+		{
+			name:     "type mismatch",
+			recorded: `{"Count":1}`,
+			actual:   `{"Count":"1"}`,
+			want:     []string{"$.Count: type mismatch: want number, got string"},
+		},
+		{
+			name:     "object arrays are compared by shape",
+			recorded: `{"Count":3,"Keys":[{"ID":"113700000001","Name":"other"},{"ID":"113700000002","Name":"other2"},{"ID":"113700000003","Name":"x"}]}`,
+			actual:   `{"Count":1,"Keys":[{"ID":"110000000001","Name":"__vcr_name_0__"}]}`,
+		},
+		{
+			name:     "object array element shape mismatch",
+			recorded: `{"Keys":[{"ID":"113700000001"}]}`,
+			actual:   `{"Keys":[{"ID":"110000000001","Extra":true}]}`,
+			want:     []string{"$.Keys[0].Extra: unexpected in fake response"},
+		},
+		{
+			name:     "scalar arrays are compared by value",
+			recorded: `{"Tags":["tag1","tag2"]}`,
+			actual:   `{"Tags":["tag1"]}`,
+			want:     []string{"$.Tags: length mismatch: want 2, got 1"},
+		},
+		{
+			name:     "empty body",
+			recorded: ``,
+			actual:   `{}`,
+			want:     []string{`body: want empty body, got "{}"`},
+		},
+	}
+
+	for _, tc := range expects {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Diff(tc.recorded, tc.actual))
+		})
+	}
+}
+
+func TestReplay(t *testing.T) {
+	// 作成したリソースのIDで参照できるかのみを実装した最小限のサーバー
+	var created string
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/cloud/1.1/keys", func(w http.ResponseWriter, _ *http.Request) {
+		created = "110000000001"
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"Key":{"ID":%q,"Name":"__vcr_name_0__"}}`, created)
+	})
+	mux.HandleFunc("GET /api/cloud/1.1/keys/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != created {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"Key":{"ID":%q,"Name":"__vcr_name_0__","KeyOrigin":"imported"}}`, created)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := &vcr.Cassette{Interactions: []*vcr.Interaction{
+		{
+			Request:  vcr.Request{Method: http.MethodGet, URL: "/cloud/zone/tk1a/api/cloud/1.1/unsupported"},
+			Response: vcr.Response{StatusCode: http.StatusOK},
+		},
+		{
+			Request:  vcr.Request{Method: http.MethodPost, URL: "/cloud/zone/tk1a/api/cloud/1.1/keys", Body: `{"Key":{"Name":"__vcr_name_0__"}}`},
+			Response: vcr.Response{StatusCode: http.StatusCreated, Body: `{"Key":{"ID":"113700000001","Name":"__vcr_name_0__"}}`},
+		},
+		{
+			Request:  vcr.Request{Method: http.MethodGet, URL: "/cloud/zone/tk1a/api/cloud/1.1/keys/113700000001"},
+			Response: vcr.Response{StatusCode: http.StatusOK, Body: `{"Key":{"ID":"113700000001","Name":"__vcr_name_0__","KeyOrigin":"generated"}}`},
+		},
+		{
+			Request:  vcr.Request{Method: http.MethodGet, URL: "/iaas/zone"},
+			Response: vcr.Response{StatusCode: http.StatusOK},
+		},
+	}}
+
+	mismatches, err := Replay(context.Background(), c, Target{
+		ServiceRootURL: server.URL + serviceAPIPath,
+		Supports: func(_, path string) bool {
+			return path != "/unsupported"
+		},
+	})
+	require.NoError(t, err)
+
+	// 記録時のIDはフェイクのIDに置き換えて再生され、値の差異のみが検出される
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, 2, mismatches[0].Index)
+	assert.Equal(t, "/keys/110000000001", mismatches[0].URL)
+	assert.Equal(t, []string{`$.Key.KeyOrigin: want "generated", got "imported"`}, mismatches[0].Diffs)
+	assert.Contains(t, mismatches[0].String(), `"KeyOrigin": "generated"`)
+}
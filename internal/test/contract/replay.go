@@ -0,0 +1,150 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package contract
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test/vcr"
+)
+
+// serviceAPIPath はKMSなどのサービスのエンドポイントに共通するパス。
+// 記録時のAPIルートURLによってこれより前のパスが異なるため、以降のパスのみで再生先を決定する
+const serviceAPIPath = "/api/cloud/1.1"
+
+// Target はカセットを再生する先のフェイクAPIサーバー
+type Target struct {
+	// ServiceRootURL はサービスのエンドポイントのルートURL。serviceAPIPathまでを含める
+	ServiceRootURL string
+	// Client はリクエストに利用するHTTPクライアント。nilの場合はhttp.DefaultClientを利用する
+	Client *http.Client
+	// Supports はフェイクが実装しているリクエストかを返す。実装していないリクエストは再生せずに読み飛ばす
+	Supports func(method, path string) bool
+	// Authorize はリクエストに認証情報を設定する
+	Authorize func(req *http.Request)
+}
+
+// Mismatch はカセット中のやり取りに対するフェイクのレスポンスの差異
+type Mismatch struct {
+	Index    int
+	Method   string
+	URL      string
+	Diffs    []string
+	Recorded vcr.Response
+	Actual   vcr.Response
+}
+
+func (m *Mismatch) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "interaction #%d %s %s:\n", m.Index, m.Method, m.URL)
+	for _, d := range m.Diffs {
+		fmt.Fprintf(&sb, "  - %s\n", d)
+	}
+	fmt.Fprintf(&sb, "recorded (%d):\n%s\n", m.Recorded.StatusCode, indentJSON(m.Recorded.Body))
+	fmt.Fprintf(&sb, "fake (%d):\n%s", m.Actual.StatusCode, indentJSON(m.Actual.Body))
+	return sb.String()
+}
+
+// Replay はカセットのリクエストを記録順にtargetへ送信し、記録されたレスポンスと構造的に異なるものを返す。
+// 記録時に払い出されたIDは、フェイクが払い出したIDに置き換えて以降のリクエストを送信する
+func Replay(ctx context.Context, c *vcr.Cassette, target Target) ([]*Mismatch, error) {
+	client := target.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	ids := make(map[string]string)
+	var mismatches []*Mismatch
+	for i, interaction := range c.Interactions {
+		path, ok := servicePath(interaction.Request.URL)
+		if !ok || (target.Supports != nil && !target.Supports(interaction.Request.Method, stripQuery(path))) {
+			continue
+		}
+		path = replaceIDs(path, ids)
+		body := replaceIDs(interaction.Request.Body, ids)
+
+		actual, err := send(ctx, client, target, interaction.Request.Method, path, body)
+		if err != nil {
+			return nil, fmt.Errorf("interaction #%d %s %s: %w", i, interaction.Request.Method, path, err)
+		}
+
+		recorded := interaction.Response
+		collectIDs(recorded.Body, actual.Body, ids)
+
+		var diffs []string
+		if recorded.StatusCode != actual.StatusCode {
+			diffs = append(diffs, fmt.Sprintf("status code: want %d, got %d", recorded.StatusCode, actual.StatusCode))
+		}
+		diffs = append(diffs, Diff(recorded.Body, actual.Body)...)
+		if len(diffs) > 0 {
+			mismatches = append(mismatches, &Mismatch{
+				Index:    i,
+				Method:   interaction.Request.Method,
+				URL:      path,
+				Diffs:    diffs,
+				Recorded: recorded,
+				Actual:   *actual,
+			})
+		}
+	}
+	return mismatches, nil
+}
+
+func servicePath(recordedURL string) (string, bool) {
+	i := strings.Index(recordedURL, serviceAPIPath+"/")
+	if i < 0 {
+		return "", false
+	}
+	return recordedURL[i+len(serviceAPIPath):], true
+}
+
+func stripQuery(path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+func send(ctx context.Context, client *http.Client, target Target, method, path, body string) (*vcr.Response, error) {
+	var reqBody io.Reader
+	if body != "" {
+		reqBody = strings.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(target.ServiceRootURL, "/")+path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if target.Authorize != nil {
+		target.Authorize(req)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &vcr.Response{StatusCode: resp.StatusCode, Body: string(respBody)}, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test/contract"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test/vcr"
+)
+
+// 各サービスのacceptance testで記録したカセット
+const cassetteGlob = "../../service/*/" + vcr.CassetteDir + "/*.json"
+
+// フェイクが実装しているサービスのパス
+var contractServicePaths = []string{"/kms/", "/secretmanager/"}
+
+// TestContract は実APIを記録したカセットをフェイクに対して再生し、フェイクが実APIと構造的に同等なレスポンスを返すことを検証する
+func TestContract(t *testing.T) {
+	cassettes, err := filepath.Glob(cassetteGlob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cassettes) == 0 {
+		t.Skipf("no cassettes are found in %s. Record them with SAKURACLOUD_RECORD=1", cassetteGlob)
+	}
+
+	for _, path := range cassettes {
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		t.Run(name, func(t *testing.T) {
+			c, err := vcr.LoadCassette(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			server := NewServer()
+			defer server.Close()
+
+			mismatches, err := contract.Replay(context.Background(), c, contract.Target{
+				ServiceRootURL: server.URL + servicePathPrefix,
+				Client:         server.Client(),
+				Supports: func(_, path string) bool {
+					for _, p := range contractServicePaths {
+						if strings.HasPrefix(path+"/", p) {
+							return true
+						}
+					}
+					return false
+				},
+				Authorize: func(req *http.Request) {
+					req.SetBasicAuth(AccessToken, AccessTokenSecret)
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, m := range mismatches {
+				t.Errorf("fake response differs from the recorded one in %s\n%s", path, m)
+			}
+		})
+	}
+}
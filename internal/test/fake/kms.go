@@ -0,0 +1,220 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+
+	v1 "github.com/sacloud/kms-api-go/apis/v1"
+)
+
+const kmsNameMaxLength = 255
+
+// KMSBackend はKMS APIのインメモリ実装
+type KMSBackend struct {
+	mu    sync.Mutex
+	ids   *idGenerator
+	keys  map[string]v1.Key
+	order []string
+
+	// inUse はキーを利用しているリソースがあるかを返す。SecretManagerBackendのロックを取得するため、muを保持したまま呼び出さないこと
+	inUse func(id string) bool
+}
+
+func newKMSBackend(ids *idGenerator) *KMSBackend {
+	return &KMSBackend{ids: ids, keys: make(map[string]v1.Key)}
+}
+
+// Key はIDに対応するキーを返す。テストからフェイクの状態を検証するために利用する
+func (b *KMSBackend) Key(id string) (v1.Key, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	key, ok := b.keys[id]
+	return key, ok
+}
+
+// Put はテストの事前データとしてキーを登録する。IDが重複している場合はエラーを返す
+func (b *KMSBackend) Put(key v1.Key) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if key.ID == "" {
+		key.ID = b.ids.generate()
+	}
+	if _, ok := b.keys[key.ID]; ok {
+		return fmt.Errorf("KMS key %q already exists", key.ID)
+	}
+	if err := validateKMSKey(key.Name, key.KeyOrigin); err != nil {
+		return err
+	}
+	b.put(key)
+	return nil
+}
+
+func (b *KMSBackend) put(key v1.Key) {
+	if _, ok := b.keys[key.ID]; !ok {
+		b.order = append(b.order, key.ID)
+	}
+	b.keys[key.ID] = key
+}
+
+func (b *KMSBackend) register(mux *http.ServeMux, prefix string) {
+	mux.HandleFunc("GET "+prefix+"/kms/keys", b.list)
+	mux.HandleFunc("POST "+prefix+"/kms/keys", b.create)
+	mux.HandleFunc("GET "+prefix+"/kms/keys/{id}", b.read)
+	mux.HandleFunc("PUT "+prefix+"/kms/keys/{id}", b.update)
+	mux.HandleFunc("DELETE "+prefix+"/kms/keys/{id}", b.delete)
+}
+
+func (b *KMSBackend) list(w http.ResponseWriter, _ *http.Request) {
+	b.mu.Lock()
+	keys := make([]v1.Key, 0, len(b.order))
+	for _, id := range b.order {
+		keys = append(keys, b.keys[id])
+	}
+	b.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, &v1.PaginatedKeyList{
+		Count: len(keys),
+		From:  v1.NewOptInt(0),
+		Total: v1.NewOptInt(len(keys)),
+		Keys:  keys,
+	})
+}
+
+func (b *KMSBackend) create(w http.ResponseWriter, r *http.Request) {
+	var req v1.WrappedCreateKey
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	if err := validateKMSKey(req.Key.Name, req.Key.KeyOrigin); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	switch {
+	case req.Key.KeyOrigin == v1.KeyOriginEnumImported && req.Key.PlainKey.Value == "":
+		writeError(w, http.StatusBadRequest, "bad_request", "PlainKey is required when KeyOrigin is imported")
+		return
+	case req.Key.KeyOrigin == v1.KeyOriginEnumGenerated && req.Key.PlainKey.IsSet():
+		writeError(w, http.StatusBadRequest, "bad_request", "PlainKey cannot be specified when KeyOrigin is generated")
+		return
+	}
+
+	createdAt := now()
+	key := v1.Key{
+		ID:          b.ids.generate(),
+		CreatedAt:   v1.DateTime(createdAt),
+		ModifiedAt:  v1.DateTime(createdAt),
+		Name:        req.Key.Name,
+		Description: req.Key.Description,
+		KeyOrigin:   req.Key.KeyOrigin,
+		Tags:        slices.Clone(req.Key.Tags),
+	}
+
+	b.mu.Lock()
+	b.put(key)
+	b.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, &v1.WrappedCreateKey{Key: v1.CreateKey{
+		ID:          key.ID,
+		CreatedAt:   key.CreatedAt,
+		ModifiedAt:  key.ModifiedAt,
+		Name:        key.Name,
+		Description: key.Description,
+		KeyOrigin:   key.KeyOrigin,
+		Tags:        key.Tags,
+	}})
+}
+
+func (b *KMSBackend) read(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	key, ok := b.Key(id)
+	if !ok {
+		writeNotFound(w, "KMS key", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, &v1.WrappedKey{Key: key})
+}
+
+func (b *KMSBackend) update(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req v1.WrappedKey
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key, ok := b.keys[id]
+	if !ok {
+		writeNotFound(w, "KMS key", id)
+		return
+	}
+	if err := validateKMSKey(req.Key.Name, req.Key.KeyOrigin); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+	if req.Key.KeyOrigin != key.KeyOrigin {
+		writeError(w, http.StatusBadRequest, "bad_request", "KeyOrigin cannot be changed")
+		return
+	}
+
+	key.Name = req.Key.Name
+	key.Description = req.Key.Description
+	key.Tags = slices.Clone(req.Key.Tags)
+	key.ModifiedAt = v1.DateTime(now())
+	b.put(key)
+
+	writeJSON(w, http.StatusOK, &v1.WrappedKey{Key: key})
+}
+
+func (b *KMSBackend) delete(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if b.inUse != nil && b.inUse(id) {
+		writeError(w, http.StatusConflict, "conflict", fmt.Sprintf("KMS key %q is in use", id))
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.keys[id]; !ok {
+		writeNotFound(w, "KMS key", id)
+		return
+	}
+	delete(b.keys, id)
+	b.order = slices.DeleteFunc(b.order, func(v string) bool { return v == id })
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func validateKMSKey(name string, origin v1.KeyOriginEnum) error {
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if len([]rune(name)) > kmsNameMaxLength {
+		return fmt.Errorf("name must be %d characters or less", kmsNameMaxLength)
+	}
+	if err := origin.Validate(); err != nil {
+		return fmt.Errorf("invalid KeyOrigin: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"context"
+	"testing"
+
+	client "github.com/sacloud/api-client-go"
+	"github.com/sacloud/kms-api-go"
+	v1 "github.com/sacloud/kms-api-go/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKeyOp(t *testing.T, server *Server, token string) kms.KeyAPI {
+	t.Helper()
+
+	c, err := kms.NewClientWithApiUrl(server.URL+servicePathPrefix, client.WithApiKeys(token, AccessTokenSecret))
+	require.NoError(t, err)
+	return kms.NewKeyOp(c)
+}
+
+func TestKMS_CRUD(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	keyOp := testKeyOp(t, server, AccessToken)
+
+	created, err := keyOp.Create(ctx, v1.CreateKey{
+		Name:        "foobar",
+		Description: v1.NewOptString("description"),
+		KeyOrigin:   v1.KeyOriginEnumGenerated,
+		Tags:        []string{"tag1"},
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+
+	read, err := keyOp.Read(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "foobar", read.Name)
+	assert.Equal(t, "description", read.Description.Value)
+	assert.Equal(t, []string{"tag1"}, read.Tags)
+
+	_, err = keyOp.Update(ctx, created.ID, v1.Key{
+		Name:      "foobar-upd",
+		KeyOrigin: v1.KeyOriginEnumGenerated,
+	})
+	require.NoError(t, err)
+	stored, ok := server.KMS.Key(created.ID)
+	require.True(t, ok)
+	assert.Equal(t, "foobar-upd", stored.Name)
+	assert.False(t, stored.Description.IsSet())
+
+	keys, err := keyOp.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+
+	require.NoError(t, keyOp.Delete(ctx, created.ID))
+	_, ok = server.KMS.Key(created.ID)
+	assert.False(t, ok)
+}
+
+func TestKMS_nonASCII(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	keyOp := testKeyOp(t, server, AccessToken)
+
+	// HTMLエスケープ対象の文字やサロゲートペアになる絵文字も含めて、送った値がそのまま返ることを確認する
+	const (
+		name        = "本番用の鍵🔑<&>"
+		description = "日本語の説明😀"
+	)
+	created, err := keyOp.Create(ctx, v1.CreateKey{
+		Name:        name,
+		Description: v1.NewOptString(description),
+		KeyOrigin:   v1.KeyOriginEnumGenerated,
+		Tags:        []string{"タグ"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, name, created.Name)
+
+	_, err = keyOp.Update(ctx, created.ID, v1.Key{
+		Name:        name + "（更新）",
+		Description: v1.NewOptString(description),
+		KeyOrigin:   v1.KeyOriginEnumGenerated,
+		Tags:        []string{"タグ"},
+	})
+	require.NoError(t, err)
+
+	read, err := keyOp.Read(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, name+"（更新）", read.Name)
+	assert.Equal(t, description, read.Description.Value)
+	assert.Equal(t, []string{"タグ"}, read.Tags)
+}
+
+func TestKMS_errors(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	keyOp := testKeyOp(t, server, AccessToken)
+
+	_, err := keyOp.Read(ctx, "110000000000")
+	assert.True(t, client.IsNotFoundError(err), err)
+	_, err = keyOp.Update(ctx, "110000000000", v1.Key{Name: "foobar", KeyOrigin: v1.KeyOriginEnumGenerated})
+	assert.True(t, client.IsNotFoundError(err), err)
+	assert.True(t, client.IsNotFoundError(keyOp.Delete(ctx, "110000000000")))
+
+	_, err = keyOp.Create(ctx, v1.CreateKey{Name: "foobar", KeyOrigin: v1.KeyOriginEnumImported})
+	assert.Error(t, err, "imported key without PlainKey")
+	_, err = keyOp.Create(ctx, v1.CreateKey{KeyOrigin: v1.KeyOriginEnumGenerated})
+	assert.Error(t, err, "key without Name")
+
+	_, err = testKeyOp(t, server, "invalid").List(ctx)
+	assert.Error(t, err, "invalid credentials")
+
+	require.NoError(t, server.KMS.Put(v1.Key{ID: "110000000001", Name: "seed", KeyOrigin: v1.KeyOriginEnumGenerated}))
+	assert.Error(t, server.KMS.Put(v1.Key{ID: "110000000001", Name: "seed", KeyOrigin: v1.KeyOriginEnumGenerated}))
+}
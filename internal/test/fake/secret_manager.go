@@ -0,0 +1,400 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+
+	v1 "github.com/sacloud/secretmanager-api-go/apis/v1"
+)
+
+// fakeSecret はシークレットの全バージョンの値を保持する。versions[i]がバージョンi+1の値
+type fakeSecret struct {
+	name     string
+	versions []string
+}
+
+func (s *fakeSecret) latestVersion() int {
+	return len(s.versions)
+}
+
+// SecretManagerBackend はSecret Manager APIのインメモリ実装。
+// 実APIと同様に、シークレットの値は書き込めるがunveil以外では読み出せない
+type SecretManagerBackend struct {
+	mu      sync.Mutex
+	ids     *idGenerator
+	kms     *KMSBackend
+	vaults  map[string]v1.Vault
+	order   []string
+	secrets map[string][]*fakeSecret // vault ID -> secrets
+}
+
+func newSecretManagerBackend(ids *idGenerator, kms *KMSBackend) *SecretManagerBackend {
+	return &SecretManagerBackend{
+		ids:     ids,
+		kms:     kms,
+		vaults:  make(map[string]v1.Vault),
+		secrets: make(map[string][]*fakeSecret),
+	}
+}
+
+// Vault はIDに対応するボールトを返す。テストからフェイクの状態を検証するために利用する
+func (b *SecretManagerBackend) Vault(id string) (v1.Vault, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	vault, ok := b.vaults[id]
+	return vault, ok
+}
+
+// SecretValue はシークレットの指定バージョンの値を返す。versionが0の場合は最新バージョンを返す
+func (b *SecretManagerBackend) SecretValue(vaultID, name string, version int) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	secret := b.findSecret(vaultID, name)
+	if secret == nil {
+		return "", false
+	}
+	return secret.value(version)
+}
+
+// Put はテストの事前データとしてボールトを登録する。IDが重複している場合はエラーを返す
+func (b *SecretManagerBackend) Put(vault v1.Vault) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if vault.ID == "" {
+		vault.ID = b.ids.generate()
+	}
+	if _, ok := b.vaults[vault.ID]; ok {
+		return fmt.Errorf("SecretManager vault %q already exists", vault.ID)
+	}
+	if err := b.validateVault(vault.Name, vault.KmsKeyID); err != nil {
+		return err
+	}
+	b.put(vault)
+	return nil
+}
+
+// PutSecret はテストの事前データとしてシークレットの新しいバージョンを登録する
+func (b *SecretManagerBackend) PutSecret(vaultID, name, value string) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.vaults[vaultID]; !ok {
+		return 0, fmt.Errorf("SecretManager vault %q is not found", vaultID)
+	}
+	if err := validateSecret(name, value); err != nil {
+		return 0, err
+	}
+	return b.putSecret(vaultID, name, value), nil
+}
+
+func (b *SecretManagerBackend) put(vault v1.Vault) {
+	if _, ok := b.vaults[vault.ID]; !ok {
+		b.order = append(b.order, vault.ID)
+	}
+	b.vaults[vault.ID] = vault
+}
+
+func (b *SecretManagerBackend) putSecret(vaultID, name, value string) int {
+	secret := b.findSecret(vaultID, name)
+	if secret == nil {
+		secret = &fakeSecret{name: name}
+		b.secrets[vaultID] = append(b.secrets[vaultID], secret)
+	}
+	secret.versions = append(secret.versions, value)
+	return secret.latestVersion()
+}
+
+func (b *SecretManagerBackend) findSecret(vaultID, name string) *fakeSecret {
+	for _, s := range b.secrets[vaultID] {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func (s *fakeSecret) value(version int) (string, bool) {
+	if version == 0 {
+		version = s.latestVersion()
+	}
+	if version < 1 || version > s.latestVersion() {
+		return "", false
+	}
+	return s.versions[version-1], true
+}
+
+func (b *SecretManagerBackend) register(mux *http.ServeMux, prefix string) {
+	mux.HandleFunc("GET "+prefix+"/secretmanager/vaults", b.listVaults)
+	mux.HandleFunc("POST "+prefix+"/secretmanager/vaults", b.createVault)
+	mux.HandleFunc("GET "+prefix+"/secretmanager/vaults/{id}", b.readVault)
+	mux.HandleFunc("PUT "+prefix+"/secretmanager/vaults/{id}", b.updateVault)
+	mux.HandleFunc("DELETE "+prefix+"/secretmanager/vaults/{id}", b.deleteVault)
+
+	mux.HandleFunc("GET "+prefix+"/secretmanager/vaults/{id}/secrets", b.listSecrets)
+	mux.HandleFunc("POST "+prefix+"/secretmanager/vaults/{id}/secrets", b.createSecret)
+	mux.HandleFunc("DELETE "+prefix+"/secretmanager/vaults/{id}/secrets", b.deleteSecret)
+	mux.HandleFunc("POST "+prefix+"/secretmanager/vaults/{id}/secrets/unveil", b.unveilSecret)
+}
+
+func (b *SecretManagerBackend) listVaults(w http.ResponseWriter, _ *http.Request) {
+	b.mu.Lock()
+	vaults := make([]v1.Vault, 0, len(b.order))
+	for _, id := range b.order {
+		vaults = append(vaults, b.vaults[id])
+	}
+	b.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, &v1.PaginatedVaultList{
+		Count:  len(vaults),
+		From:   v1.NewOptInt(0),
+		Total:  v1.NewOptInt(len(vaults)),
+		Vaults: vaults,
+	})
+}
+
+func (b *SecretManagerBackend) createVault(w http.ResponseWriter, r *http.Request) {
+	var req v1.WrappedCreateVault
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if err := b.validateVault(req.Vault.Name, req.Vault.KmsKeyID); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	createdAt := now()
+	vault := v1.Vault{
+		ID:          b.ids.generate(),
+		CreatedAt:   v1.DateTime(createdAt),
+		ModifiedAt:  v1.DateTime(createdAt),
+		Name:        req.Vault.Name,
+		Description: req.Vault.Description,
+		KmsKeyID:    req.Vault.KmsKeyID,
+		Tags:        slices.Clone(req.Vault.Tags),
+	}
+
+	b.mu.Lock()
+	b.put(vault)
+	b.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, &v1.WrappedCreateVault{Vault: v1.CreateVault(vault)})
+}
+
+func (b *SecretManagerBackend) readVault(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	vault, ok := b.Vault(id)
+	if !ok {
+		writeNotFound(w, "SecretManager vault", id)
+		return
+	}
+	writeJSON(w, http.StatusOK, &v1.WrappedVault{Vault: vault})
+}
+
+func (b *SecretManagerBackend) updateVault(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req v1.WrappedVault
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	vault, ok := b.vaults[id]
+	if !ok {
+		writeNotFound(w, "SecretManager vault", id)
+		return
+	}
+	if err := b.validateVault(req.Vault.Name, req.Vault.KmsKeyID); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	vault.Name = req.Vault.Name
+	vault.Description = req.Vault.Description
+	vault.KmsKeyID = req.Vault.KmsKeyID
+	vault.Tags = slices.Clone(req.Vault.Tags)
+	vault.ModifiedAt = v1.DateTime(now())
+	b.put(vault)
+
+	writeJSON(w, http.StatusOK, &v1.WrappedVault{Vault: vault})
+}
+
+func (b *SecretManagerBackend) deleteVault(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.vaults[id]; !ok {
+		writeNotFound(w, "SecretManager vault", id)
+		return
+	}
+	delete(b.vaults, id)
+	delete(b.secrets, id)
+	b.order = slices.DeleteFunc(b.order, func(v string) bool { return v == id })
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (b *SecretManagerBackend) listSecrets(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	b.mu.Lock()
+	if _, ok := b.vaults[id]; !ok {
+		b.mu.Unlock()
+		writeNotFound(w, "SecretManager vault", id)
+		return
+	}
+	// 一覧では値を返さない
+	secrets := make([]v1.Secret, 0, len(b.secrets[id]))
+	for _, s := range b.secrets[id] {
+		secrets = append(secrets, v1.Secret{Name: s.name, LatestVersion: s.latestVersion()})
+	}
+	b.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, &v1.PaginatedSecretList{
+		Count:   len(secrets),
+		From:    v1.NewOptInt(0),
+		Total:   v1.NewOptInt(len(secrets)),
+		Secrets: secrets,
+	})
+}
+
+func (b *SecretManagerBackend) createSecret(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req v1.WrappedCreateSecret
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.vaults[id]; !ok {
+		writeNotFound(w, "SecretManager vault", id)
+		return
+	}
+	if err := validateSecret(req.Secret.Name, req.Secret.Value); err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", err.Error())
+		return
+	}
+
+	// 既存のシークレットへの書き込みは新しいバージョンの作成になる
+	version := b.putSecret(id, req.Secret.Name, req.Secret.Value)
+	writeJSON(w, http.StatusCreated, &v1.WrappedSecret{Secret: v1.Secret{
+		Name:          req.Secret.Name,
+		LatestVersion: version,
+	}})
+}
+
+func (b *SecretManagerBackend) deleteSecret(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req v1.WrappedDeleteSecret
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.vaults[id]; !ok {
+		writeNotFound(w, "SecretManager vault", id)
+		return
+	}
+	if b.findSecret(id, req.Secret.Name) == nil {
+		writeNotFound(w, "SecretManager secret", req.Secret.Name)
+		return
+	}
+	b.secrets[id] = slices.DeleteFunc(b.secrets[id], func(s *fakeSecret) bool { return s.name == req.Secret.Name })
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (b *SecretManagerBackend) unveilSecret(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var req v1.WrappedUnveil
+	if !readJSON(w, r, &req) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.vaults[id]; !ok {
+		writeNotFound(w, "SecretManager vault", id)
+		return
+	}
+	secret := b.findSecret(id, req.Secret.Name)
+	if secret == nil {
+		writeNotFound(w, "SecretManager secret", req.Secret.Name)
+		return
+	}
+
+	version := secret.latestVersion()
+	if v, ok := req.Secret.Version.Get(); ok {
+		version = v
+	}
+	value, ok := secret.value(version)
+	if !ok {
+		writeNotFound(w, "SecretManager secret version", fmt.Sprintf("%s:%d", req.Secret.Name, version))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &v1.WrappedUnveil{Secret: v1.Unveil{
+		Name:    secret.name,
+		Version: v1.NewOptNilInt(version),
+		Value:   value,
+	}})
+}
+
+// usesKey はkmsKeyIDのキーを利用しているボールトがあるかを返す
+func (b *SecretManagerBackend) usesKey(kmsKeyID string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, vault := range b.vaults {
+		if vault.KmsKeyID == kmsKeyID {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *SecretManagerBackend) validateVault(name, kmsKeyID string) error {
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if kmsKeyID == "" {
+		return errors.New("KmsKeyID is required")
+	}
+	if _, ok := b.kms.Key(kmsKeyID); !ok {
+		return fmt.Errorf("KMS key %q is not found", kmsKeyID)
+	}
+	return nil
+}
+
+func validateSecret(name, value string) error {
+	if name == "" {
+		return errors.New("name is required")
+	}
+	if value == "" {
+		return errors.New("value is required")
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	client "github.com/sacloud/api-client-go"
+	kmsapi "github.com/sacloud/kms-api-go/apis/v1"
+	sm "github.com/sacloud/secretmanager-api-go"
+	v1 "github.com/sacloud/secretmanager-api-go/apis/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSecretManagerClient(t *testing.T, server *Server) *v1.Client {
+	t.Helper()
+
+	c, err := sm.NewClientWithApiUrl(server.URL+servicePathPrefix, client.WithApiKeys(AccessToken, AccessTokenSecret))
+	require.NoError(t, err)
+	return c
+}
+
+func TestSecretManager_vault(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	vaultOp := sm.NewVaultOp(testSecretManagerClient(t, server))
+
+	_, err := vaultOp.Create(ctx, v1.CreateVault{Name: "foobar", KmsKeyID: "110000000000"})
+	assert.Error(t, err, "vault with missing KMS key")
+
+	require.NoError(t, server.KMS.Put(kmsapi.Key{ID: "110000000000", Name: "key", KeyOrigin: kmsapi.KeyOriginEnumGenerated}))
+	created, err := vaultOp.Create(ctx, v1.CreateVault{
+		Name:     "foobar",
+		KmsKeyID: "110000000000",
+		Tags:     []string{"tag1"},
+	})
+	require.NoError(t, err)
+
+	_, err = vaultOp.Update(ctx, created.ID, v1.Vault{
+		Name:        "foobar-upd",
+		Description: v1.NewOptString("description"),
+		KmsKeyID:    "110000000000",
+	})
+	require.NoError(t, err)
+
+	read, err := vaultOp.Read(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "foobar-upd", read.Name)
+	assert.Equal(t, "description", read.Description.Value)
+
+	// ボールトから利用されているキーは削除できない
+	keyOp := testKeyOp(t, server, AccessToken)
+	var apiErr *client.APIError
+	require.ErrorAs(t, keyOp.Delete(ctx, "110000000000"), &apiErr)
+	assert.Equal(t, http.StatusConflict, apiErr.Code)
+
+	require.NoError(t, vaultOp.Delete(ctx, created.ID))
+	_, err = vaultOp.Read(ctx, created.ID)
+	assert.True(t, client.IsNotFoundError(err), err)
+	require.NoError(t, keyOp.Delete(ctx, "110000000000"))
+}
+
+func TestSecretManager_secret(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	ctx := context.Background()
+	require.NoError(t, server.KMS.Put(kmsapi.Key{ID: "110000000000", Name: "key", KeyOrigin: kmsapi.KeyOriginEnumGenerated}))
+	require.NoError(t, server.SecretManager.Put(v1.Vault{ID: "110000000001", Name: "vault", KmsKeyID: "110000000000"}))
+
+	secretOp := sm.NewSecretOp(testSecretManagerClient(t, server), "110000000001")
+
+	created, err := secretOp.Create(ctx, v1.CreateSecret{Name: "foobar", Value: "value1"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, created.LatestVersion)
+
+	updated, err := secretOp.Update(ctx, v1.CreateSecret{Name: "foobar", Value: "value2"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated.LatestVersion)
+
+	secrets, err := secretOp.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, secrets, 1)
+	assert.Equal(t, v1.Secret{Name: "foobar", LatestVersion: 2}, secrets[0])
+
+	latest, err := secretOp.Unveil(ctx, v1.Unveil{Name: "foobar"})
+	require.NoError(t, err)
+	assert.Equal(t, "value2", latest.Value)
+	assert.Equal(t, 2, latest.Version.Value)
+
+	first, err := secretOp.Unveil(ctx, v1.Unveil{Name: "foobar", Version: v1.NewOptNilInt(1)})
+	require.NoError(t, err)
+	assert.Equal(t, "value1", first.Value)
+
+	_, err = secretOp.Unveil(ctx, v1.Unveil{Name: "foobar", Version: v1.NewOptNilInt(3)})
+	assert.True(t, client.IsNotFoundError(err), err)
+
+	require.NoError(t, secretOp.Delete(ctx, v1.DeleteSecret{Name: "foobar"}))
+	_, ok := server.SecretManager.SecretValue("110000000001", "foobar", 0)
+	assert.False(t, ok)
+	assert.True(t, client.IsNotFoundError(secretOp.Delete(ctx, v1.DeleteSecret{Name: "foobar"})))
+
+	_, err = sm.NewSecretOp(testSecretManagerClient(t, server), "110000000009").List(ctx)
+	assert.True(t, client.IsNotFoundError(err), err)
+}
@@ -0,0 +1,158 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fake はacceptance testを実アカウントなしで実行するための、さくらのクラウドAPIのインメモリなフェイク実装を提供する
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	AccessToken       = "fake-access-token"
+	AccessTokenSecret = "fake-access-token-secret"
+)
+
+// KMSなどのゾーンに依存しないサービスはtk1aゾーン配下のパスで提供される
+const servicePathPrefix = "/tk1a/api/cloud/1.1"
+
+// Server はフェイクAPIサーバー。プロバイダーのapi_root_urlにURLを指定して利用する
+type Server struct {
+	*httptest.Server
+
+	KMS           *KMSBackend
+	SecretManager *SecretManagerBackend
+}
+
+// backend はServerにホストされる各サービスのフェイク実装
+type backend interface {
+	register(mux *http.ServeMux, prefix string)
+}
+
+// NewServer はフェイクAPIサーバーを起動する。利用後はCloseを呼ぶこと
+func NewServer() *Server {
+	// 実APIと同様に、サービスをまたいでIDが重複しないようにする
+	ids := &idGenerator{}
+	s := &Server{
+		KMS: newKMSBackend(ids),
+	}
+	s.SecretManager = newSecretManagerBackend(ids, s.KMS)
+	s.KMS.inUse = s.SecretManager.usesKey
+
+	mux := http.NewServeMux()
+	for _, b := range []backend{s.KMS, s.SecretManager} {
+		b.register(mux, servicePathPrefix)
+	}
+
+	s.Server = httptest.NewServer(authenticate(mux))
+	return s
+}
+
+// ProviderConfig はフェイクAPIサーバーを利用するためのproviderブロックを返す。blocksはproviderブロックに追加する設定
+func (s *Server) ProviderConfig(blocks ...string) string {
+	return fmt.Sprintf(`
+provider "sakura" {
+  token        = %q
+  secret       = %q
+  api_root_url = %q
+  retry_max    = 0
+%s
+}
+`, AccessToken, AccessTokenSecret, s.URL, strings.Join(blocks, "\n"))
+}
+
+func authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, secret, ok := r.BasicAuth()
+		if !ok || token != AccessToken || secret != AccessTokenSecret {
+			writeError(w, http.StatusUnauthorized, "unauthorized", "invalid access token or secret")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// idGenerator は実APIと同様の12桁の数値IDを払い出す
+type idGenerator struct {
+	mu   sync.Mutex
+	next int64
+}
+
+func (g *idGenerator) generate() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.next == 0 {
+		g.next = 110000000000
+	}
+	g.next++
+	return strconv.FormatInt(g.next, 10)
+}
+
+func now() string {
+	return time.Now().Format(time.RFC3339Nano)
+}
+
+type apiError struct {
+	IsFatal   bool   `json:"is_fatal"`
+	Status    string `json:"status"`
+	ErrorCode string `json:"error_code"`
+	ErrorMsg  string `json:"error_msg"`
+}
+
+func writeError(w http.ResponseWriter, status int, code, msg string) {
+	body, _ := json.Marshal(&apiError{ //nolint:errchkjson
+		IsFatal:   true,
+		Status:    fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		ErrorCode: code,
+		ErrorMsg:  msg,
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body) //nolint:errcheck,gosec
+}
+
+func writeNotFound(w http.ResponseWriter, kind, id string) {
+	writeError(w, http.StatusNotFound, "not_found", fmt.Sprintf("%s %q is not found", kind, id))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v json.Marshaler) {
+	body, err := v.MarshalJSON()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "internal_error", err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body) //nolint:errcheck,gosec
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v json.Unmarshaler) bool {
+	body, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = v.UnmarshalJSON(body)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "bad_request", fmt.Sprintf("invalid request body: %s", err))
+		return false
+	}
+	return true
+}
@@ -18,6 +18,12 @@ import (
 	"bytes"
 	"fmt"
 	"log"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
 	"text/template"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/acctest"
@@ -31,16 +37,105 @@ func BuildConfigWithArgs(config string, args ...string) string {
 	}
 
 	buf := bytes.NewBufferString("")
-	err := template.Must(template.New("tmpl").Parse(config)).Execute(buf, data)
+	err := template.Must(template.New("tmpl").Option("missingkey=error").Parse(config)).Execute(buf, data)
 	if err != nil {
 		log.Fatal(err)
 	}
 	return buf.String()
 }
 
-func RandomName() string {
-	rand := acctest.RandStringFromCharSet(10, acctest.CharSetAlpha)
-	return fmt.Sprintf("terraform-acctest-%s", rand)
+// RawHCL はBuildConfigWithMapでエスケープせずにそのまま埋め込む値
+type RawHCL string
+
+// BuildConfigWithMap はテンプレート中の{{ .name }}をvaluesの値で置き換えたconfigを返す。
+//
+// 文字列の値はHCLの文字列リテラル内に埋め込めるようエスケープされるため、"{{ .name }}"のようにダブルクォートで囲んで利用する。
+// テンプレートがvaluesに存在しないキーを参照した場合はテストを失敗させる
+func BuildConfigWithMap(t testing.TB, config string, values map[string]any) string {
+	t.Helper()
+
+	data := make(map[string]any, len(values))
+	for k, v := range values {
+		switch v := v.(type) {
+		case string:
+			data[k] = EscapeHCLString(v)
+		case RawHCL:
+			data[k] = string(v)
+		default:
+			data[k] = v
+		}
+	}
+
+	tmpl, err := template.New("config").Option("missingkey=error").Parse(config)
+	if err != nil {
+		t.Fatalf("parsing test config template is failed: %s", err)
+	}
+	buf := bytes.NewBufferString("")
+	if err := tmpl.Execute(buf, data); err != nil {
+		keys := slices.Sorted(maps.Keys(values))
+		t.Fatalf("rendering test config template is failed: %s (available keys: %s)", err, strings.Join(keys, ", "))
+	}
+	return buf.String()
+}
+
+// EscapeHCLString はHCLの文字列リテラル内に埋め込めるよう、クォートや改行、テンプレートシーケンスをエスケープする
+func EscapeHCLString(v string) string {
+	return hclStringEscaper.Replace(v)
+}
+
+var hclStringEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+	"${", "$${",
+	"%{", "%%{",
+)
+
+// RandomNamePrefix はacceptance testで作成するリソース名のプレフィックス。sweeperはこのプレフィックスを持つリソースのみを削除する
+const RandomNamePrefix = "tf-acc-"
+
+// RandomNamePattern はRandomNameが生成する名前に一致する正規表現。"-upd"のように名前に付け足した接尾辞も含めて一致する
+var RandomNamePattern = regexp.MustCompile(regexp.QuoteMeta(RandomNamePrefix) + `[a-z0-9-]*[a-z0-9]`)
+
+const (
+	randomNameLength  = 8
+	randomNameCharSet = "abcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+// 生成済みの名前と生成したテスト名。同一プロセス内での名前の重複を避けるために利用する
+var randomNames = struct {
+	mu   sync.Mutex
+	used map[string]string
+}{used: make(map[string]string)}
+
+// RandomName はacceptance testで作成するリソース名を生成する。
+// resourceTypeを指定すると"tf-acc-kms-x7f3a9ab"のように名前にリソースの種類を含め、リークしたリソースの作成元を追えるようにする。
+// 生成した名前はテスト終了時にログへ出力する
+func RandomName(t testing.TB, resourceType ...string) string {
+	t.Helper()
+
+	prefix := RandomNamePrefix
+	for _, v := range resourceType {
+		prefix += v + "-"
+	}
+
+	randomNames.mu.Lock()
+	var name string
+	for {
+		name = prefix + acctest.RandStringFromCharSet(randomNameLength, randomNameCharSet)
+		if _, exists := randomNames.used[name]; !exists {
+			break
+		}
+	}
+	randomNames.used[name] = t.Name()
+	randomNames.mu.Unlock()
+
+	t.Cleanup(func() {
+		t.Logf("[INFO] random name %q was generated by %s", name, t.Name())
+	})
+	return name
 }
 
 func RandomPassword() string {
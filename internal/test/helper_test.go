@@ -0,0 +1,108 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildConfigWithMap(t *testing.T) {
+	config := `
+resource "sakura_secret_manager_secret" "foobar" {
+  name             = "{{ .name }}"
+  value_wo         = "{{ .value }}"
+  value_wo_version = {{ .version }}
+  {{ .extra }}
+}`
+
+	got := BuildConfigWithMap(t, config, map[string]any{
+		"name":    "tf-acc-secret-foobar",
+		"value":   "p\"a\\ss\n${var.foo}%{ if true }",
+		"version": 2,
+		"extra":   RawHCL(`tags = ["tag1"]`),
+	})
+	assert.Equal(t, `
+resource "sakura_secret_manager_secret" "foobar" {
+  name             = "tf-acc-secret-foobar"
+  value_wo         = "p\"a\\ss\n$${var.foo}%%{ if true }"
+  value_wo_version = 2
+  tags = ["tag1"]
+}`, got)
+}
+
+func TestBuildConfigWithMap_missingKey(t *testing.T) {
+	mock := &mockT{TB: t}
+	func() {
+		defer func() { recover() }() //nolint:errcheck
+		BuildConfigWithMap(mock, `name = "{{ .name }}"`, map[string]any{"nmae": "foo"})
+	}()
+
+	assert.True(t, mock.failed)
+	assert.Contains(t, mock.message, `"name"`)
+	assert.Contains(t, mock.message, "available keys: nmae")
+}
+
+// mockT はt.Fatalfの呼び出しを記録するtesting.TB
+type mockT struct {
+	testing.TB
+	failed  bool
+	message string
+}
+
+func (m *mockT) Helper() {}
+
+func (m *mockT) Fatalf(format string, args ...any) {
+	m.failed = true
+	m.message = fmt.Sprintf(format, args...)
+	panic("Fatalf")
+}
+
+func TestRandomName(t *testing.T) {
+	name := RandomName(t)
+	assert.Regexp(t, `^tf-acc-[a-z0-9]{8}$`, name)
+	assert.Equal(t, name, RandomNamePattern.FindString(name))
+
+	typed := RandomName(t, "kms")
+	assert.Regexp(t, `^tf-acc-kms-[a-z0-9]{8}$`, typed)
+	assert.Equal(t, typed+"-upd", RandomNamePattern.FindString(`name = "`+typed+`-upd"`))
+}
+
+func TestRandomName_parallel(t *testing.T) {
+	const workers, perWorker = 16, 200
+
+	names := make(chan string, workers*perWorker)
+	t.Run("generate", func(t *testing.T) {
+		for i := range workers {
+			t.Run(fmt.Sprintf("worker%d", i), func(t *testing.T) {
+				t.Parallel()
+				for range perWorker {
+					names <- RandomName(t, "unit")
+				}
+			})
+		}
+	})
+	close(names)
+
+	seen := make(map[string]bool)
+	for name := range names {
+		require.False(t, seen[name], "duplicated name: %s", name)
+		seen[name] = true
+	}
+	assert.Len(t, seen, workers*perWorker)
+}
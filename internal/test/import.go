@@ -0,0 +1,72 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+// ImportStep はresourceNameのリソースをIDでimportし、importしたstateが直前のstateと一致することを確認するTestStepを返す。
+// ignoreにはAPIから読み出せないためimportでは復元できない属性(write-onlyな属性やシークレットの値など)を指定する
+func ImportStep(resourceName string, ignore ...string) resource.TestStep {
+	return resource.TestStep{
+		ResourceName:            resourceName,
+		ImportState:             true,
+		ImportStateVerify:       true,
+		ImportStateVerifyIgnore: ignore,
+	}
+}
+
+// ImportStepByAttributes は"id"属性を持たないリソース向けのImportStep。
+// import IDはattributesの値を"/"で連結したものとし、stateの照合にはattributesの最後の属性を利用する
+func ImportStepByAttributes(resourceName string, attributes []string, ignore ...string) resource.TestStep {
+	step := ImportStep(resourceName, ignore...)
+	step.ImportStateIdFunc = func(s *terraform.State) (string, error) {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return "", fmt.Errorf("not found: %s", resourceName)
+		}
+		var values []string
+		for _, attr := range attributes {
+			v := rs.Primary.Attributes[attr]
+			if v == "" {
+				return "", fmt.Errorf("%s: attribute %q is not set", resourceName, attr)
+			}
+			values = append(values, v)
+		}
+		return strings.Join(values, "/"), nil
+	}
+	step.ImportStateVerifyIdentifierAttribute = attributes[len(attributes)-1]
+	return step
+}
+
+// ImportPlanStep はimportブロックを使って直前のステップのconfigでresourceNameのリソースをimportし、
+// import後のplanに差分がないことを確認するTestStepを返す。
+// import後のReadがstateを完全に埋めていない場合、configと実リソースが一致していても差分が出るためこれで検出する
+func ImportPlanStep(resourceName string) resource.TestStep {
+	return resource.TestStep{
+		ResourceName:    resourceName,
+		ImportState:     true,
+		ImportStateKind: resource.ImportBlockWithID,
+		ImportPlanChecks: resource.ImportPlanChecks{
+			PreApply: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+		},
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportStepByAttributes(t *testing.T) {
+	state := terraform.NewState()
+	state.RootModule().Resources["sakura_secret_manager_secret.foobar"] = &terraform.ResourceState{
+		Type: "sakura_secret_manager_secret",
+		Primary: &terraform.InstanceState{
+			ID:         "id-attribute-not-set",
+			Attributes: map[string]string{"vault_id": "110000000001", "name": "foobar"},
+		},
+	}
+
+	step := ImportStepByAttributes("sakura_secret_manager_secret.foobar", []string{"vault_id", "name"}, "value")
+	assert.True(t, step.ImportState)
+	assert.True(t, step.ImportStateVerify)
+	assert.Equal(t, "name", step.ImportStateVerifyIdentifierAttribute)
+	assert.Equal(t, []string{"value"}, step.ImportStateVerifyIgnore)
+
+	id, err := step.ImportStateIdFunc(state)
+	require.NoError(t, err)
+	assert.Equal(t, "110000000001/foobar", id)
+
+	_, err = ImportStepByAttributes("sakura_secret_manager_secret.foobar", []string{"vault_id", "version"}).ImportStateIdFunc(state)
+	assert.Error(t, err)
+	_, err = ImportStepByAttributes("sakura_secret_manager_secret.other", []string{"name"}).ImportStateIdFunc(state)
+	assert.Error(t, err)
+}
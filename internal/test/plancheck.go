@@ -0,0 +1,93 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+)
+
+// StablePlanStep は直前のステップと同じconfigを再度planし、差分がないことを確認するTestStepを返す。
+// knownAttributesにはresourceAddressのリソースでplan時に値が確定している(unknownにならない)べき属性を指定する
+func StablePlanStep(config, resourceAddress string, knownAttributes ...string) resource.TestStep {
+	checks := make([]plancheck.PlanCheck, 0, len(knownAttributes)+1)
+	for _, attr := range knownAttributes {
+		checks = append(checks, ExpectNotUnknownValue(resourceAddress, tfjsonpath.New(attr)))
+	}
+	checks = append(checks, plancheck.ExpectEmptyPlan())
+
+	return resource.TestStep{
+		Config: config,
+		ConfigPlanChecks: resource.ConfigPlanChecks{
+			PreApply:             checks,
+			PostApplyPostRefresh: []plancheck.PlanCheck{plancheck.ExpectEmptyPlan()},
+		},
+	}
+}
+
+type expectNotUnknownValue struct {
+	resourceAddress string
+	attributePath   tfjsonpath.Path
+}
+
+// ExpectNotUnknownValue は指定した属性のplan後の値がunknownでないことを確認するPlanCheckを返す。
+// ネストした属性の場合は、いずれかの要素がunknownであればエラーとする
+func ExpectNotUnknownValue(resourceAddress string, attributePath tfjsonpath.Path) plancheck.PlanCheck {
+	return expectNotUnknownValue{resourceAddress: resourceAddress, attributePath: attributePath}
+}
+
+func (e expectNotUnknownValue) CheckPlan(_ context.Context, req plancheck.CheckPlanRequest, resp *plancheck.CheckPlanResponse) {
+	for _, rc := range req.Plan.ResourceChanges {
+		if rc.Address != e.resourceAddress || rc.Change == nil {
+			continue
+		}
+
+		// AfterUnknownには値が確定していない属性のみが含まれる
+		result, err := tfjsonpath.Traverse(rc.Change.AfterUnknown, e.attributePath)
+		if err != nil {
+			return
+		}
+		if containsUnknown(result) {
+			resp.Error = fmt.Errorf("%s: expected known value at %q, but found unknown value", e.resourceAddress, e.attributePath.String())
+		}
+		return
+	}
+
+	resp.Error = fmt.Errorf("%s - Resource not found in plan ResourceChanges", e.resourceAddress)
+}
+
+func containsUnknown(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case map[string]interface{}:
+		for _, e := range v {
+			if containsUnknown(e) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, e := range v {
+			if containsUnknown(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpectNotUnknownValue(t *testing.T) {
+	plan := &tfjson.Plan{
+		ResourceChanges: []*tfjson.ResourceChange{
+			{
+				Address: "sakura_secret_manager_secret.foobar",
+				Change: &tfjson.Change{
+					After: map[string]interface{}{"name": "foobar"},
+					AfterUnknown: map[string]interface{}{
+						"version": true,
+						"tags":    []interface{}{false, true},
+						"name":    false,
+					},
+				},
+			},
+		},
+	}
+
+	expects := []struct {
+		address string
+		path    tfjsonpath.Path
+		wantErr bool
+	}{
+		{address: "sakura_secret_manager_secret.foobar", path: tfjsonpath.New("name")},
+		{address: "sakura_secret_manager_secret.foobar", path: tfjsonpath.New("id")},
+		{address: "sakura_secret_manager_secret.foobar", path: tfjsonpath.New("version"), wantErr: true},
+		{address: "sakura_secret_manager_secret.foobar", path: tfjsonpath.New("tags"), wantErr: true},
+		{address: "sakura_secret_manager_secret.other", path: tfjsonpath.New("name"), wantErr: true},
+	}
+
+	for _, tc := range expects {
+		var resp plancheck.CheckPlanResponse
+		ExpectNotUnknownValue(tc.address, tc.path).CheckPlan(context.Background(), plancheck.CheckPlanRequest{Plan: plan}, &resp)
+		if tc.wantErr {
+			assert.Error(t, resp.Error, "%s %s", tc.address, tc.path)
+		} else {
+			assert.NoError(t, resp.Error, "%s %s", tc.address, tc.path)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test/vcr"
+)
+
+const preflightTimeout = time.Minute
+
+var accRequiredEnvs = []string{
+	"SAKURACLOUD_ACCESS_TOKEN",
+	"SAKURACLOUD_ACCESS_TOKEN_SECRET",
+}
+
+// preflightSettings はacceptance testで利用される実効的な設定値
+type preflightSettings struct {
+	Zone       string
+	APIRootURL string
+	RetryMax   int
+	RateLimit  int
+	Sandbox    bool
+}
+
+func (s *preflightSettings) String() string {
+	return fmt.Sprintf("zone=%s sandbox=%t retry_max=%d api_request_rate_limit=%d api_root_url=%q", s.Zone, s.Sandbox, s.RetryMax, s.RateLimit, s.APIRootURL)
+}
+
+// preflightAPI は事前チェックでAPIを呼び出す処理。テストではダブルに差し替える
+type preflightAPI interface {
+	AuthStatus(ctx context.Context) error
+	Zones(ctx context.Context) ([]string, error)
+}
+
+type iaasPreflightAPI struct {
+	caller iaas.APICaller
+}
+
+func (a *iaasPreflightAPI) AuthStatus(ctx context.Context) error {
+	_, err := iaas.NewAuthStatusOp(a.caller).Read(ctx)
+	return err
+}
+
+func (a *iaasPreflightAPI) Zones(ctx context.Context) ([]string, error) {
+	found, err := iaas.NewZoneOp(a.caller).Find(ctx, &iaas.FindCondition{})
+	if err != nil {
+		return nil, err
+	}
+	var zones []string
+	for _, z := range found.Zones {
+		zones = append(zones, z.Name)
+	}
+	return zones, nil
+}
+
+// preflight はacceptance testの実行環境を確認し、見つかった問題を全てまとめて返す。
+// apiがnilの場合はAPIを呼び出すチェックを行わない
+func preflight(ctx context.Context, lookupEnv func(string) (string, bool), api func(*preflightSettings) (preflightAPI, error)) (*preflightSettings, []string) {
+	var problems []string
+
+	for _, env := range accRequiredEnvs {
+		if v, _ := lookupEnv(env); v == "" {
+			problems = append(problems, fmt.Sprintf("%s must be set for acceptance tests", env))
+		}
+	}
+
+	settings := &preflightSettings{
+		Zone:       envOrDefault(lookupEnv, "SAKURACLOUD_ZONE", testDefaultTargetZone),
+		APIRootURL: envOrDefault(lookupEnv, "SAKURACLOUD_API_ROOT_URL", ""),
+		Sandbox:    isSandbox(lookupEnv),
+	}
+	if settings.Sandbox {
+		settings.Zone = SandboxZone
+	}
+	var err error
+	if settings.RetryMax, err = strconv.Atoi(envOrDefault(lookupEnv, "SAKURACLOUD_RETRY_MAX", testDefaultAPIRetryMax)); err != nil {
+		problems = append(problems, fmt.Sprintf("SAKURACLOUD_RETRY_MAX is invalid: %s", err))
+	}
+	if settings.RateLimit, err = strconv.Atoi(envOrDefault(lookupEnv, "SAKURACLOUD_RATE_LIMIT", testDefaultAPIRateLimit)); err != nil {
+		problems = append(problems, fmt.Sprintf("SAKURACLOUD_RATE_LIMIT is invalid: %s", err))
+	}
+
+	// 認証情報が不足している状態でAPIを呼び出しても同じ問題が報告されるだけなので、ここで打ち切る
+	if len(problems) > 0 || api == nil {
+		return settings, problems
+	}
+
+	client, err := api(settings)
+	if err != nil {
+		return settings, append(problems, fmt.Sprintf("creating API client is failed: %s", err))
+	}
+	if err := client.AuthStatus(ctx); err != nil {
+		return settings, append(problems, fmt.Sprintf("SAKURACLOUD_ACCESS_TOKEN/SAKURACLOUD_ACCESS_TOKEN_SECRET are not valid: %s", err))
+	}
+	zones, err := client.Zones(ctx)
+	if err != nil {
+		return settings, append(problems, fmt.Sprintf("listing zones is failed: %s", err))
+	}
+	if !slices.Contains(zones, settings.Zone) {
+		problems = append(problems, fmt.Sprintf("SAKURACLOUD_ZONE %q is not found. available zones: %s", settings.Zone, strings.Join(zones, ", ")))
+	}
+	return settings, problems
+}
+
+func envOrDefault(lookupEnv func(string) (string, bool), key, defaultValue string) string {
+	if v, ok := lookupEnv(key); ok && v != "" {
+		return v
+	}
+	return defaultValue
+}
+
+func newPreflightAPI(settings *preflightSettings) (preflightAPI, error) {
+	client, err := newAccConfig(settings).NewClient()
+	if err != nil {
+		return nil, err
+	}
+	return &iaasPreflightAPI{caller: client}, nil
+}
+
+// 事前チェックはAPIを呼び出すため、プロセス内で一度だけ実行する
+var accPreflight struct {
+	once     sync.Once
+	settings atomic.Pointer[preflightSettings]
+	problems []string
+}
+
+// accSettings はacceptance testの設定値を返す。事前チェックを実行していない場合は環境変数から決定する
+func accSettings() *preflightSettings {
+	if settings := accPreflight.settings.Load(); settings != nil {
+		return settings
+	}
+	settings, _ := preflight(context.Background(), accLookupEnv, nil)
+	return settings
+}
+
+func runAccPreflight(t *testing.T) {
+	t.Helper()
+
+	accPreflight.once.Do(func() {
+		api := newPreflightAPI
+		if accVCRMode == vcr.ModeReplay {
+			// 再生時はAPIにアクセスしない
+			api = nil
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), preflightTimeout)
+		defer cancel()
+		settings, problems := preflight(ctx, accLookupEnv, api)
+		accPreflight.settings.Store(settings)
+		accPreflight.problems = problems
+	})
+
+	if len(accPreflight.problems) > 0 {
+		t.Fatalf("acceptance test preflight failed:\n  - %s", strings.Join(accPreflight.problems, "\n  - "))
+	}
+	t.Logf("[INFO] acceptance test settings: %s", accPreflight.settings.Load())
+}
@@ -0,0 +1,121 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubPreflightAPI struct {
+	authErr error
+	zones   []string
+}
+
+func (s *stubPreflightAPI) AuthStatus(context.Context) error {
+	return s.authErr
+}
+
+func (s *stubPreflightAPI) Zones(context.Context) ([]string, error) {
+	return s.zones, nil
+}
+
+func stubPreflight(api *stubPreflightAPI) func(*preflightSettings) (preflightAPI, error) {
+	return func(*preflightSettings) (preflightAPI, error) {
+		return api, nil
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	validEnvs := map[string]string{
+		"SAKURACLOUD_ACCESS_TOKEN":        "token",
+		"SAKURACLOUD_ACCESS_TOKEN_SECRET": "secret",
+	}
+
+	t.Run("all problems are reported at once", func(t *testing.T) {
+		called := false
+		_, problems := preflight(context.Background(), testEnvLookup(map[string]string{
+			"SAKURACLOUD_RETRY_MAX": "ten",
+		}), func(*preflightSettings) (preflightAPI, error) {
+			called = true
+			return &stubPreflightAPI{}, nil
+		})
+
+		require.Len(t, problems, 3)
+		assert.Contains(t, problems[0], "SAKURACLOUD_ACCESS_TOKEN must be set")
+		assert.Contains(t, problems[1], "SAKURACLOUD_ACCESS_TOKEN_SECRET must be set")
+		assert.Contains(t, problems[2], "SAKURACLOUD_RETRY_MAX is invalid")
+		assert.False(t, called, "API should not be called when environment variables are missing")
+	})
+
+	t.Run("settings", func(t *testing.T) {
+		settings, problems := preflight(context.Background(), testEnvLookup(validEnvs), stubPreflight(&stubPreflightAPI{zones: []string{"is1a", "is1b"}}))
+		require.Empty(t, problems)
+		assert.Equal(t, &preflightSettings{Zone: "is1b", RetryMax: 30, RateLimit: 5}, settings)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, problems := preflight(context.Background(), testEnvLookup(validEnvs), stubPreflight(&stubPreflightAPI{authErr: errors.New("401 Unauthorized")}))
+		require.Len(t, problems, 1)
+		assert.Contains(t, problems[0], "401 Unauthorized")
+	})
+
+	t.Run("unknown zone", func(t *testing.T) {
+		envs := map[string]string{"SAKURACLOUD_ZONE": "xx1a"}
+		for k, v := range validEnvs {
+			envs[k] = v
+		}
+		_, problems := preflight(context.Background(), testEnvLookup(envs), stubPreflight(&stubPreflightAPI{zones: []string{"is1a", "tk1a"}}))
+		require.Len(t, problems, 1)
+		assert.Contains(t, problems[0], `"xx1a" is not found`)
+		assert.Contains(t, problems[0], "is1a, tk1a")
+	})
+
+	t.Run("api checks are skipped", func(t *testing.T) {
+		_, problems := preflight(context.Background(), testEnvLookup(validEnvs), nil)
+		assert.Empty(t, problems)
+	})
+}
+
+func testEnvLookup(envs map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := envs[key]
+		return v, ok
+	}
+}
+
+func TestPreflight_sandbox(t *testing.T) {
+	envs := map[string]string{
+		"SAKURACLOUD_ACCESS_TOKEN":        "token",
+		"SAKURACLOUD_ACCESS_TOKEN_SECRET": "secret",
+		"SAKURACLOUD_ZONE":                "is1a",
+		SandboxEnvVar:                     "1",
+	}
+
+	settings, problems := preflight(context.Background(), testEnvLookup(envs), stubPreflight(&stubPreflightAPI{zones: []string{"is1a", "tk1v"}}))
+	require.Empty(t, problems)
+	assert.True(t, settings.Sandbox)
+	assert.Equal(t, SandboxZone, settings.Zone)
+
+	envs[SandboxEnvVar] = "0"
+	settings, problems = preflight(context.Background(), testEnvLookup(envs), stubPreflight(&stubPreflightAPI{zones: []string{"is1a", "tk1v"}}))
+	require.Empty(t, problems)
+	assert.False(t, settings.Sandbox)
+	assert.Equal(t, "is1a", settings.Zone)
+}
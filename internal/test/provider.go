@@ -15,16 +15,20 @@
 package test
 
 import (
+	"fmt"
+	"net/http"
 	"os"
-	"reflect"
+	"os/exec"
+	"strconv"
+	"sync"
 	"testing"
-	"unsafe"
 
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
 	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
 	sakura "github.com/sacloud/terraform-provider-sakuracloud/internal/provider"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test/vcr"
 )
 
 var (
@@ -34,45 +38,139 @@ var (
 )
 
 var (
-	AccProvider                 provider.Provider
-	AccProtoV6ProviderFactories map[string]func() (tfprotov6.ProviderServer, error)
-	AccClientGetter             func() *common.APIClient
+	accVCRMode, accVCRModeErr = vcr.ModeFromEnv(os.LookupEnv)
+	accRecorder               = vcr.NewRecorder(accVCRMode, http.DefaultTransport, RandomNamePattern)
 )
 
-func init() {
-	// APIClientをテストで利用するためのAccProvider
-	AccProvider = sakura.New("test")()
-	AccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
-		"sakura": providerserver.NewProtocol6WithError(AccProvider),
+// AccProtoV6ProviderFactories はacceptance test用のprovider factory。
+// 呼び出しごとに新しいプロバイダーを生成するため、並列に実行されるテスト間でプロバイダーの状態を共有しない
+var AccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"sakura": func() (tfprotov6.ProviderServer, error) {
+		return providerserver.NewProtocol6WithError(newAccProvider())()
+	},
+}
+
+// AccClientGetter はテストのチェック処理でAPIを呼び出すためのクライアントを返す。
+// プロバイダーとは別に、プロバイダーと同じ設定値で生成する
+var AccClientGetter = sync.OnceValue(func() *common.APIClient {
+	client, err := newAccConfig(accSettings()).NewClient()
+	if err != nil {
+		panic(fmt.Sprintf("creating API client for acceptance tests is failed: %s", err))
+	}
+	return client
+})
+
+func newAccProvider() provider.Provider {
+	opts := []sakura.Option{sakura.WithEnvLookup(accProviderLookupEnv)}
+	if accVCRMode != vcr.ModeDisabled {
+		opts = append(opts, sakura.WithHTTPTransport(accRecorder))
+	}
+	return sakura.New("test", opts...)()
+}
+
+// accLookupEnv はacceptance testで参照する環境変数を返す。
+// 再生時はAPIにアクセスしないため、認証情報が未設定であればダミーの値を返す
+func accLookupEnv(key string) (string, bool) {
+	if v, ok := os.LookupEnv(key); ok && v != "" {
+		return v, true
+	}
+	if accVCRMode == vcr.ModeReplay {
+		switch key {
+		case "SAKURACLOUD_ACCESS_TOKEN", "SAKURACLOUD_ACCESS_TOKEN_SECRET":
+			return "replay", true
+		}
+	}
+	return "", false
+}
+
+// accProviderLookupEnv はプロバイダーに渡す環境変数を返す。
+// プロセスの環境変数を書き換えずに、事前チェックで決定したゾーンなどの設定値をプロバイダーに渡す
+func accProviderLookupEnv(key string) (string, bool) {
+	settings := accSettings()
+	switch key {
+	case "SAKURACLOUD_ZONE":
+		return settings.Zone, true
+	case "SAKURACLOUD_RETRY_MAX":
+		return strconv.Itoa(settings.RetryMax), true
+	case "SAKURACLOUD_RATE_LIMIT":
+		return strconv.Itoa(settings.RateLimit), true
+	}
+	return accLookupEnv(key)
+}
+
+func newAccConfig(settings *preflightSettings) *common.Config {
+	env := func(key string) string {
+		v, _ := accLookupEnv(key)
+		return v
+	}
+	cfg := &common.Config{
+		Profile:             env("SAKURACLOUD_PROFILE"),
+		AccessToken:         env("SAKURACLOUD_ACCESS_TOKEN"),
+		AccessTokenSecret:   env("SAKURACLOUD_ACCESS_TOKEN_SECRET"),
+		Zone:                settings.Zone,
+		APIRootURL:          settings.APIRootURL,
+		RetryMax:            settings.RetryMax,
+		APIRequestTimeout:   common.APIRequestTimeout,
+		APIRequestRateLimit: settings.RateLimit,
 	}
-	AccClientGetter = func() *common.APIClient {
-		var v reflect.Value = reflect.ValueOf(AccProvider).Elem()
-		var c reflect.Value = v.FieldByName("client").Elem()
-		return (*common.APIClient)(unsafe.Pointer(c.UnsafeAddr()))
+	if accVCRMode != vcr.ModeDisabled {
+		cfg.HTTPTransport = accRecorder
 	}
+	return cfg
 }
 
 func AccPreCheck(t *testing.T) {
-	requiredEnvs := []string{
-		"SAKURACLOUD_ACCESS_TOKEN",
-		"SAKURACLOUD_ACCESS_TOKEN_SECRET",
+	if accVCRModeErr != nil {
+		t.Fatal(accVCRModeErr)
 	}
 
-	for _, env := range requiredEnvs {
-		if v := os.Getenv(env); v == "" {
-			t.Fatalf("%s must be set for acceptance tests", env)
-		}
+	runAccPreflight(t)
+	startCassette(t)
+}
+
+// ParallelTest はacceptance testを他のテストと並列に実行する。
+// カセットはプロセスで一つのため、記録/再生時は並列に実行しない
+func ParallelTest(t *testing.T) {
+	t.Helper()
+
+	if accVCRMode == vcr.ModeDisabled {
+		t.Parallel()
+	}
+}
+
+// startCassette はSAKURACLOUD_RECORD/SAKURACLOUD_REPLAYが指定されている場合にテストに対応するカセットを開始する
+func startCassette(t *testing.T) {
+	if accVCRMode == vcr.ModeDisabled {
+		return
 	}
 
-	if v := os.Getenv("SAKURACLOUD_ZONE"); v == "" {
-		os.Setenv("SAKURACLOUD_ZONE", testDefaultTargetZone) //nolint:errcheck,gosec
+	if err := accRecorder.Start(vcr.CassettePath(t.Name())); err != nil {
+		t.Fatal(err)
 	}
+	t.Cleanup(func() {
+		if err := accRecorder.Stop(); err != nil {
+			t.Errorf("saving cassette is failed: %s", err)
+		}
+	})
+}
 
-	if v := os.Getenv("SAKURACLOUD_RETRY_MAX"); v == "" {
-		os.Setenv("SAKURACLOUD_RETRY_MAX", testDefaultAPIRetryMax) //nolint:errcheck,gosec
+// SensitiveValue はカセットの再生時に、APIから取得した機密情報の期待値を記録時に置き換えられた値にする
+func SensitiveValue(v string) string {
+	if accVCRMode == vcr.ModeReplay {
+		return vcr.Redacted
 	}
+	return v
+}
+
+// FakePreCheck はフェイクAPIサーバーを利用するテストの事前チェック。
+// TF_ACCなしで通常のgo testとして実行されるため、terraformコマンドが利用できない環境ではスキップする
+func FakePreCheck(t *testing.T) {
+	t.Helper()
 
-	if v := os.Getenv("SAKURACLOUD_RATE_LIMIT"); v == "" {
-		os.Setenv("SAKURACLOUD_RATE_LIMIT", testDefaultAPIRateLimit) //nolint:errcheck,gosec
+	if os.Getenv("TF_ACC_TERRAFORM_PATH") != "" {
+		return
+	}
+	if _, err := exec.LookPath("terraform"); err != nil {
+		t.Skip("terraform command is not found, skipping test against the fake API server")
 	}
 }
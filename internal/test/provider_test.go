@@ -0,0 +1,101 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testProviderConfig(t *testing.T, server tfprotov6.ProviderServer, values map[string]tftypes.Value) *tfprotov6.DynamicValue {
+	t.Helper()
+
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov6.GetProviderSchemaRequest{})
+	require.NoError(t, err)
+
+	typ := resp.Provider.ValueType().(tftypes.Object)
+	attrs := make(map[string]tftypes.Value)
+	for name, attrType := range typ.AttributeTypes {
+		if v, ok := values[name]; ok {
+			attrs[name] = v
+			continue
+		}
+		attrs[name] = tftypes.NewValue(attrType, nil)
+	}
+	config, err := tfprotov6.NewDynamicValue(typ, tftypes.NewValue(typ, attrs))
+	require.NoError(t, err)
+	return &config
+}
+
+// プロバイダーごとに状態を持つため、並列に設定してもデータ競合が発生しないこと(-raceで確認する)
+func TestAccProtoV6ProviderFactories_parallel(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+
+	const parallelism = 8
+	var wg sync.WaitGroup
+	for range parallelism {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			providerServer, err := AccProtoV6ProviderFactories["sakura"]()
+			if !assert.NoError(t, err) {
+				return
+			}
+			config := testProviderConfig(t, providerServer, map[string]tftypes.Value{
+				"token":        tftypes.NewValue(tftypes.String, fake.AccessToken),
+				"secret":       tftypes.NewValue(tftypes.String, fake.AccessTokenSecret),
+				"api_root_url": tftypes.NewValue(tftypes.String, server.URL),
+			})
+			resp, err := providerServer.ConfigureProvider(context.Background(), &tfprotov6.ConfigureProviderRequest{Config: config})
+			if assert.NoError(t, err) {
+				for _, d := range resp.Diagnostics {
+					assert.NotEqual(t, tfprotov6.DiagnosticSeverityError, d.Severity, "%s: %s", d.Summary, d.Detail)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestAccProviderLookupEnv(t *testing.T) {
+	t.Setenv("SAKURACLOUD_ZONE", "")
+	t.Setenv("SAKURACLOUD_RETRY_MAX", "")
+	t.Setenv("SAKURACLOUD_RATE_LIMIT", "3")
+	t.Setenv(SandboxEnvVar, "")
+
+	// プロセスの環境変数を書き換えずに、テスト用のデフォルト値をプロバイダーに渡す
+	zone, ok := accProviderLookupEnv("SAKURACLOUD_ZONE")
+	assert.True(t, ok)
+	assert.Equal(t, testDefaultTargetZone, zone)
+
+	retryMax, _ := accProviderLookupEnv("SAKURACLOUD_RETRY_MAX")
+	assert.Equal(t, testDefaultAPIRetryMax, retryMax)
+
+	rateLimit, _ := accProviderLookupEnv("SAKURACLOUD_RATE_LIMIT")
+	assert.Equal(t, "3", rateLimit)
+
+	t.Setenv(SandboxEnvVar, "1")
+	zone, _ = accProviderLookupEnv("SAKURACLOUD_ZONE")
+	assert.Equal(t, SandboxZone, zone)
+}
@@ -0,0 +1,50 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"os"
+	"testing"
+)
+
+const (
+	// SandboxEnvVar に1が設定されている場合、acceptance testを無償のサンドボックスゾーンで実行する
+	SandboxEnvVar = "SAKURACLOUD_USE_SANDBOX"
+	// SandboxZone はサンドボックスのゾーン
+	SandboxZone = "tk1v"
+	// SandboxTestPrefix はサンドボックスで実行できるacceptance testの名前のプレフィックス。
+	// `make testacc-sandbox`はこのプレフィックスを持つテストのみを実行する
+	SandboxTestPrefix = "TestAccSandbox"
+)
+
+// IsSandbox はacceptance testをサンドボックスで実行するかを返す
+func IsSandbox() bool {
+	return isSandbox(os.LookupEnv)
+}
+
+func isSandbox(lookupEnv func(string) (string, bool)) bool {
+	v, ok := lookupEnv(SandboxEnvVar)
+	return ok && v != "" && v != "0"
+}
+
+// SkipInSandbox はサンドボックスで利用できないリソースを扱うテストをスキップする。
+// サンドボックスで実行できないテストはAccPreCheckより前にこれを呼び出す
+func SkipInSandbox(t *testing.T, reason string) {
+	t.Helper()
+
+	if IsSandbox() {
+		t.Skipf("skipping in the sandbox zone(%s): %s", SandboxZone, reason)
+	}
+}
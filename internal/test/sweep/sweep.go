@@ -0,0 +1,57 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sweep は失敗したacceptance testが残したリソースを削除するsweeperを提供する。
+//
+//	go test ./internal/test/sweep -v -sweep=tk1a
+package sweep
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/test"
+)
+
+// MinAge 作成からこの時間が経過していないリソースは実行中のテストのものである可能性があるため削除しない
+const MinAge = time.Hour
+
+// SharedClient は環境変数からsweeper用のAPIクライアントを生成する
+func SharedClient() (*common.APIClient, error) {
+	cfg := &common.Config{
+		Profile:             os.Getenv("SAKURACLOUD_PROFILE"),
+		AccessToken:         os.Getenv("SAKURACLOUD_ACCESS_TOKEN"),
+		AccessTokenSecret:   os.Getenv("SAKURACLOUD_ACCESS_TOKEN_SECRET"),
+		Zone:                common.Zone,
+		APIRootURL:          os.Getenv("SAKURACLOUD_API_ROOT_URL"),
+		RetryMax:            common.RetryMax,
+		APIRequestTimeout:   common.APIRequestTimeout,
+		APIRequestRateLimit: common.APIRequestRateLimit,
+	}
+	return cfg.NewClient()
+}
+
+// ShouldSweep はacceptance testが作成したリソースで、かつMinAge以上経過しているかを返す
+func ShouldSweep(name, createdAt string, now time.Time) bool {
+	if !strings.HasPrefix(name, test.RandomNamePrefix) {
+		return false
+	}
+	created, err := time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return false
+	}
+	return now.Sub(created) >= MinAge
+}
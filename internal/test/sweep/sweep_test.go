@@ -0,0 +1,163 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sweep
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	sm "github.com/sacloud/secretmanager-api-go"
+	smapi "github.com/sacloud/secretmanager-api-go/apis/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+func init() {
+	// シークレットはボールトのsweeperで削除してからボールトを削除する
+	resource.AddTestSweepers("sakura_secret_manager", &resource.Sweeper{
+		Name: "sakura_secret_manager",
+		F:    sweepSecretManagerVaults,
+	})
+	resource.AddTestSweepers("sakura_kms", &resource.Sweeper{
+		Name:         "sakura_kms",
+		Dependencies: []string{"sakura_secret_manager"},
+		F:            sweepKMSKeys,
+	})
+}
+
+func sweepSecretManagerVaults(_ string) error {
+	client, err := SharedClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	now := time.Now()
+
+	smClient, err := client.SecretManagerClient()
+	if err != nil {
+		return err
+	}
+	vaultOp := sm.NewVaultOp(smClient)
+	vaults, err := vaultOp.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, vault := range vaults {
+		if !ShouldSweep(vault.Name, string(vault.CreatedAt), now) {
+			continue
+		}
+		if err := sweepSecrets(ctx, smClient, vault.ID); err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		log.Printf("[INFO] deleting SecretManager vault: %s (%s)", vault.Name, vault.ID)
+		if err := vaultOp.Delete(ctx, vault.ID); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("deleting SecretManager vault[%s] is failed: %w", vault.ID, err))
+		}
+	}
+	return errs
+}
+
+func sweepSecrets(ctx context.Context, client *smapi.Client, vaultID string) error {
+	secretOp := sm.NewSecretOp(client, vaultID)
+	secrets, err := secretOp.List(ctx)
+	if err != nil {
+		return fmt.Errorf("listing secrets in SecretManager vault[%s] is failed: %w", vaultID, err)
+	}
+
+	var errs error
+	for _, secret := range secrets {
+		log.Printf("[INFO] deleting SecretManager secret: %s/%s", vaultID, secret.Name)
+		if err := secretOp.Delete(ctx, smapi.DeleteSecret{Name: secret.Name}); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("deleting SecretManager secret[%s/%s] is failed: %w", vaultID, secret.Name, err))
+		}
+	}
+	return errs
+}
+
+func sweepKMSKeys(_ string) error {
+	client, err := SharedClient()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	now := time.Now()
+
+	keyOp, err := client.KMSKeyOp()
+	if err != nil {
+		return err
+	}
+	keys, err := keyOp.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, key := range keys {
+		if !ShouldSweep(key.Name, string(key.CreatedAt), now) {
+			continue
+		}
+		log.Printf("[INFO] deleting KMS key: %s (%s)", key.Name, key.ID)
+		if err := keyOp.Delete(ctx, key.ID); err != nil {
+			errs = multierror.Append(errs, fmt.Errorf("deleting KMS key[%s] is failed: %w", key.ID, err))
+		}
+	}
+	return errs
+}
+
+func TestShouldSweep(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	expects := []struct {
+		name      string
+		createdAt string
+		want      bool
+	}{
+		{
+			name:      "tf-acc-kms-abcdefgh",
+			createdAt: "2025-01-01T10:00:00.123456+00:00",
+			want:      true,
+		},
+		{
+			name:      "tf-acc-kms-abcdefgh",
+			createdAt: "2025-01-01T11:30:00+00:00",
+			want:      false,
+		},
+		{
+			name:      "production-key",
+			createdAt: "2025-01-01T10:00:00+00:00",
+			want:      false,
+		},
+		{
+			name:      "tf-acc-kms-abcdefgh",
+			createdAt: "invalid",
+			want:      false,
+		},
+	}
+
+	for _, tc := range expects {
+		assert.Equal(t, tc.want, ShouldSweep(tc.name, tc.createdAt, now), "%s created at %s", tc.name, tc.createdAt)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Redacted はカセットに記録する際に機密情報を置き換える値
+const Redacted = "[REDACTED]"
+
+// カセットに記録するヘッダー。Authorizationなどの認証情報を含むヘッダーは記録しない
+var recordedHeaders = []string{"Content-Type"}
+
+// 値を記録しないJSONのキー(大文字小文字は区別しない)
+var sensitiveKeys = map[string]bool{
+	"value":             true,
+	"plainkey":          true,
+	"password":          true,
+	"accesstoken":       true,
+	"accesstokensecret": true,
+	"token":             true,
+	"secret":            true,
+}
+
+// 値を記録しないクエリパラメータ(大文字小文字は区別しない)
+var sensitiveQueryParams = map[string]bool{
+	"token":  true,
+	"secret": true,
+}
+
+// sanitizer はリクエスト/レスポンスから機密情報を取り除き、実行ごとに変わるランダムなリソース名をプレースホルダーに置き換える
+type sanitizer struct {
+	namePattern *regexp.Regexp
+	names       map[string]string // 実際の名前 -> プレースホルダー
+	order       []string
+}
+
+func newSanitizer(namePattern *regexp.Regexp) *sanitizer {
+	return &sanitizer{namePattern: namePattern, names: make(map[string]string)}
+}
+
+var placeholderPattern = regexp.MustCompile(`__vcr_name_(\d+)__`)
+
+func placeholder(i int) string {
+	return fmt.Sprintf("__vcr_name_%d__", i)
+}
+
+// replaceNames はランダムなリソース名を出現順にプレースホルダーへ置き換える。
+// 記録時と再生時で名前が異なっていても、出現順が同じであれば同じプレースホルダーになる
+func (s *sanitizer) replaceNames(v string) string {
+	if s.namePattern == nil {
+		return v
+	}
+	return s.namePattern.ReplaceAllStringFunc(v, func(name string) string {
+		if p, ok := s.names[name]; ok {
+			return p
+		}
+		p := placeholder(len(s.order))
+		s.names[name] = p
+		s.order = append(s.order, name)
+		return p
+	})
+}
+
+// restoreNames はプレースホルダーを今回の実行での名前に戻す。
+// 記録時にレスポンスで初めて現れた名前(他のテストが作成したリソースなど)は今回の実行に対応する名前がないため、
+// プレースホルダーのまま返しつつ採番だけを進めて以降のプレースホルダーとの対応を保つ
+func (s *sanitizer) restoreNames(v string) string {
+	return placeholderPattern.ReplaceAllStringFunc(v, func(p string) string {
+		i, err := strconv.Atoi(placeholderPattern.FindStringSubmatch(p)[1])
+		if err != nil {
+			return p
+		}
+		for len(s.order) <= i {
+			unknown := placeholder(len(s.order))
+			s.names[unknown] = unknown
+			s.order = append(s.order, unknown)
+		}
+		return s.order[i]
+	})
+}
+
+func (s *sanitizer) url(u *url.URL) string {
+	// ホストはAPIルートURLの設定によって変わるため記録しない
+	sanitized := &url.URL{Path: u.Path}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			if sensitiveQueryParams[strings.ToLower(key)] {
+				query.Set(key, Redacted)
+			}
+		}
+		sanitized.RawQuery = query.Encode()
+	}
+	return s.replaceNames(sanitized.String())
+}
+
+func (s *sanitizer) header(h http.Header) http.Header {
+	sanitized := http.Header{}
+	for _, key := range recordedHeaders {
+		if v := h.Values(key); len(v) > 0 {
+			sanitized[key] = v
+		}
+	}
+	return sanitized
+}
+
+func (s *sanitizer) body(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		// JSON以外のボディはそのまま記録する
+		return s.replaceNames(string(body))
+	}
+
+	redacted, err := json.Marshal(redact(v))
+	if err != nil {
+		return s.replaceNames(string(body))
+	}
+	return s.replaceNames(string(redacted))
+}
+
+func redact(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, ok := value.(string); ok && sensitiveKeys[strings.ToLower(key)] {
+				v[key] = Redacted
+				continue
+			}
+			v[key] = redact(value)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = redact(v[i])
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vcr はacceptance testのHTTPリクエスト/レスポンスをカセットに記録し、ネットワークなしで再生するための仕組みを提供する。
+//
+// SAKURACLOUD_RECORD=1で記録、SAKURACLOUD_REPLAY=1で再生を行う。
+// 記録時は認証情報やシークレットの値を取り除いた上でtestdata/cassettes配下に保存する。
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Mode は記録/再生のモード
+type Mode int
+
+const (
+	ModeDisabled Mode = iota
+	ModeRecord
+	ModeReplay
+)
+
+const (
+	RecordEnvVar = "SAKURACLOUD_RECORD"
+	ReplayEnvVar = "SAKURACLOUD_REPLAY"
+)
+
+// CassetteDir はカセットを保存するディレクトリ。go testはパッケージのディレクトリで実行されるため、各パッケージのtestdata配下となる
+const CassetteDir = "testdata/cassettes"
+
+// ModeFromEnv は環境変数から記録/再生のモードを決定する
+func ModeFromEnv(lookupEnv func(string) (string, bool)) (Mode, error) {
+	record := isEnabled(lookupEnv, RecordEnvVar)
+	replay := isEnabled(lookupEnv, ReplayEnvVar)
+	switch {
+	case record && replay:
+		return ModeDisabled, fmt.Errorf("%s and %s cannot be enabled at the same time", RecordEnvVar, ReplayEnvVar)
+	case record:
+		return ModeRecord, nil
+	case replay:
+		return ModeReplay, nil
+	default:
+		return ModeDisabled, nil
+	}
+}
+
+func isEnabled(lookupEnv func(string) (string, bool), key string) bool {
+	v, ok := lookupEnv(key)
+	return ok && v != "" && v != "0"
+}
+
+// CassettePath はテスト名に対応するカセットのパスを返す
+func CassettePath(testName string) string {
+	return filepath.Join(CassetteDir, strings.ReplaceAll(testName, "/", "_")+".json")
+}
+
+// Cassette は記録されたリクエスト/レスポンスの組の一覧
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+	used     bool
+}
+
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// LoadCassette はpathに保存されたカセットを読み込む
+func LoadCassette(path string) (*Cassette, error) {
+	return loadCassette(path)
+}
+
+func loadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("loading cassette %q is failed: %w", path, err)
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parsing cassette %q is failed: %w", path, err)
+	}
+	return &c, nil
+}
+
+func (c *Cassette) save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// Recorder はカセットへの記録/カセットからの再生を行うhttp.RoundTripper。
+// Startでカセットを開始するまではtransportへリクエストをそのまま渡す
+type Recorder struct {
+	mode        Mode
+	transport   http.RoundTripper
+	namePattern *regexp.Regexp
+
+	mu        sync.Mutex
+	path      string
+	cassette  *Cassette
+	sanitizer *sanitizer
+}
+
+var _ http.RoundTripper = (*Recorder)(nil)
+
+// NewRecorder はRecorderを生成する。namePatternに一致する文字列はテストごとにランダムなリソース名として扱い、プレースホルダーに置き換えて記録する
+func NewRecorder(mode Mode, transport http.RoundTripper, namePattern *regexp.Regexp) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{mode: mode, transport: transport, namePattern: namePattern}
+}
+
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Start はカセットを開始する。記録モードでは空のカセットを、再生モードではpathのカセットを読み込む
+func (r *Recorder) Start(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch r.mode {
+	case ModeRecord:
+		r.cassette = &Cassette{}
+	case ModeReplay:
+		c, err := loadCassette(path)
+		if err != nil {
+			return err
+		}
+		r.cassette = c
+	default:
+		return nil
+	}
+	r.path = path
+	r.sanitizer = newSanitizer(r.namePattern)
+	return nil
+}
+
+// Stop はカセットを終了する。記録モードではカセットをファイルに保存する
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cassette == nil {
+		return nil
+	}
+	var err error
+	if r.mode == ModeRecord {
+		err = r.cassette.save(r.path)
+	}
+	r.cassette = nil
+	r.sanitizer = nil
+	return err
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	active := r.cassette != nil
+	r.mu.Unlock()
+	if !active {
+		return r.transport.RoundTrip(req)
+	}
+
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	outReq := req.Clone(req.Context())
+	if body != nil {
+		outReq.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	resp, err := r.transport.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close() //nolint:errcheck,gosec
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cassette != nil {
+		r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+			Request: Request{
+				Method: req.Method,
+				URL:    r.sanitizer.url(req.URL),
+				Body:   r.sanitizer.body(body),
+			},
+			Response: Response{
+				StatusCode: resp.StatusCode,
+				Header:     r.sanitizer.header(resp.Header),
+				Body:       r.sanitizer.body(respBody),
+			},
+		})
+	}
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cassette == nil {
+		return nil, errors.New("cassette is not started")
+	}
+	url := r.sanitizer.url(req.URL)
+	reqBody := r.sanitizer.body(body)
+
+	for _, i := range r.cassette.Interactions {
+		if i.used || i.Request.Method != req.Method || i.Request.URL != url || i.Request.Body != reqBody {
+			continue
+		}
+		i.used = true
+
+		header := i.Response.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		respBody := r.sanitizer.restoreNames(i.Response.Body)
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", i.Response.StatusCode, http.StatusText(i.Response.StatusCode)),
+			StatusCode:    i.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(strings.NewReader(respBody)),
+			ContentLength: int64(len(respBody)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded interaction is found in %q: %s %s", r.path, req.Method, url)
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close() //nolint:errcheck
+	return io.ReadAll(req.Body)
+}
@@ -0,0 +1,115 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vcr
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModeFromEnv(t *testing.T) {
+	expects := []struct {
+		envs    map[string]string
+		want    Mode
+		wantErr bool
+	}{
+		{envs: map[string]string{}, want: ModeDisabled},
+		{envs: map[string]string{RecordEnvVar: "1"}, want: ModeRecord},
+		{envs: map[string]string{ReplayEnvVar: "1"}, want: ModeReplay},
+		{envs: map[string]string{ReplayEnvVar: "0"}, want: ModeDisabled},
+		{envs: map[string]string{RecordEnvVar: "1", ReplayEnvVar: "1"}, wantErr: true},
+	}
+
+	for _, tc := range expects {
+		got, err := ModeFromEnv(func(key string) (string, bool) {
+			v, ok := tc.envs[key]
+			return v, ok
+		})
+		if tc.wantErr {
+			assert.Error(t, err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, got)
+	}
+}
+
+func doRequest(t *testing.T, rt http.RoundTripper, method, url, body string) (int, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.SetBasicAuth("token", "secret")
+
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close() //nolint:errcheck
+
+	respBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(respBody)
+}
+
+func TestRecorder_recordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		// リクエストされた名前とシークレットの値をそのまま返す
+		w.Write([]byte(strings.Replace(string(body), "Secret", "Created", 1))) //nolint:errcheck
+	}))
+
+	path := filepath.Join(t.TempDir(), CassettePath("TestFoo/bar"))
+
+	recorder := NewRecorder(ModeRecord, http.DefaultTransport, testNamePattern)
+	require.NoError(t, recorder.Start(path))
+	status, body := doRequest(t, recorder, http.MethodPost, server.URL+"/secrets", `{"Secret":{"Name":"terraform-acctest-abc","Value":"p@ssw0rd"}}`)
+	require.NoError(t, recorder.Stop())
+	server.Close()
+
+	// 記録中のレスポンスは加工しない
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, `{"Created":{"Name":"terraform-acctest-abc","Value":"p@ssw0rd"}}`, body)
+
+	data, err := os.ReadFile(path) //nolint:gosec
+	require.NoError(t, err)
+	cassette := string(data)
+	assert.NotContains(t, cassette, "p@ssw0rd")
+	assert.NotContains(t, cassette, "dG9rZW46c2VjcmV0") // Basic認証のヘッダー
+	assert.NotContains(t, cassette, "terraform-acctest-abc")
+	assert.NotContains(t, cassette, strings.TrimPrefix(server.URL, "http://"))
+
+	// サーバーを停止した状態で、別の名前・値のリクエストを再生できる
+	replayer := NewRecorder(ModeReplay, http.DefaultTransport, testNamePattern)
+	require.NoError(t, replayer.Start(path))
+	status, body = doRequest(t, replayer, http.MethodPost, server.URL+"/secrets", `{"Secret":{"Name":"terraform-acctest-xyz","Value":"other"}}`)
+	assert.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, `{"Created":{"Name":"terraform-acctest-xyz","Value":"[REDACTED]"}}`, body)
+
+	// 記録された回数を超えたリクエストはエラーになる
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/secrets", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	_, err = replayer.RoundTrip(req) //nolint:bodyclose
+	assert.Error(t, err)
+	require.NoError(t, replayer.Stop())
+}
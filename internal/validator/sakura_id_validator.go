@@ -16,7 +16,9 @@ package validator
 
 import (
 	"context"
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 )
@@ -39,9 +41,9 @@ func (v stringSakuraIDTypeValidator) ValidateString(ctx context.Context, req val
 	}
 
 	value := req.ConfigValue.ValueString()
-	_, err := strconv.ParseInt(value, 10, 64)
-	if err != nil {
-		resp.Diagnostics.AddAttributeError(req.Path, v.Description(ctx), value)
+	if !IsSakuraID(value) {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid SakuraCloud resource ID",
+			fmt.Sprintf("expected a SakuraCloud resource ID (numeric), got %q — did you mean to use the name attribute?", value))
 		return
 	}
 }
@@ -49,3 +51,13 @@ func (v stringSakuraIDTypeValidator) ValidateString(ctx context.Context, req val
 func SakuraIDValidator() stringSakuraIDTypeValidator {
 	return stringSakuraIDTypeValidator{}
 }
+
+// IsSakuraID はvalueがさくらのクラウドのリソースIDの形式(数字のみ)かを返す。
+// 符号や空白はstrconv.ParseIntで受け付けられてしまうため、数字以外を含む場合は不正とする
+func IsSakuraID(value string) bool {
+	if value == "" || strings.TrimLeft(value, "0123456789") != "" {
+		return false
+	}
+	_, err := strconv.ParseInt(value, 10, 64)
+	return err == nil
+}
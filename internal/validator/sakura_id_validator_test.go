@@ -0,0 +1,72 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSakuraIDValidator(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name    string
+		value   types.String
+		wantErr bool
+	}{
+		{name: "numeric", value: types.StringValue("110000000001")},
+		{name: "leading zero", value: types.StringValue("0123")},
+		{name: "null", value: types.StringNull()},
+		{name: "unknown", value: types.StringUnknown()},
+		{name: "name", value: types.StringValue("my-key"), wantErr: true},
+		{name: "empty", value: types.StringValue(""), wantErr: true},
+		{name: "signed", value: types.StringValue("-1"), wantErr: true},
+		{name: "plus sign", value: types.StringValue("+110000000001"), wantErr: true},
+		{name: "whitespace", value: types.StringValue(" 110000000001"), wantErr: true},
+		{name: "overflow", value: types.StringValue("99999999999999999999"), wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp := &validator.StringResponse{}
+			SakuraIDValidator().ValidateString(context.Background(), validator.StringRequest{
+				Path:        path.Root("id"),
+				ConfigValue: tc.value,
+			}, resp)
+			assert.Equal(t, tc.wantErr, resp.Diagnostics.HasError(), resp.Diagnostics)
+		})
+	}
+
+	t.Run("message", func(t *testing.T) {
+		t.Parallel()
+
+		resp := &validator.StringResponse{}
+		SakuraIDValidator().ValidateString(context.Background(), validator.StringRequest{
+			Path:        path.Root("id"),
+			ConfigValue: types.StringValue("my-key"),
+		}, resp)
+		require.Len(t, resp.Diagnostics, 1)
+		assert.Equal(t, `expected a SakuraCloud resource ID (numeric), got "my-key" — did you mean to use the name attribute?`, resp.Diagnostics[0].Detail())
+	})
+}
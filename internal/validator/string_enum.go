@@ -0,0 +1,44 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// StringEnum は大文字小文字を区別せずに受け付ける列挙値の一覧。APIに送信する際はCanonicalで正規の表記に変換する
+type StringEnum []string
+
+func NewStringEnum(values ...string) StringEnum {
+	return StringEnum(values)
+}
+
+// OneOf は値がいずれかの列挙値と大文字小文字を区別せずに一致するかを検証するvalidatorを返す
+func (e StringEnum) OneOf() validator.String {
+	return stringvalidator.OneOfCaseInsensitive(e...)
+}
+
+// Canonical はvと大文字小文字を区別せずに一致する列挙値を返す。一致するものがない場合はvをそのまま返す
+func (e StringEnum) Canonical(v string) string {
+	for _, value := range e {
+		if strings.EqualFold(v, value) {
+			return value
+		}
+	}
+	return v
+}
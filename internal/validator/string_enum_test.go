@@ -0,0 +1,52 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringEnum(t *testing.T) {
+	enum := NewStringEnum("generated", "imported")
+
+	expects := []struct {
+		value     string
+		canonical string
+		valid     bool
+	}{
+		{value: "generated", canonical: "generated", valid: true},
+		{value: "Generated", canonical: "generated", valid: true},
+		{value: "IMPORTED", canonical: "imported", valid: true},
+		{value: "unknown", canonical: "unknown", valid: false},
+	}
+	for _, tc := range expects {
+		t.Run(tc.value, func(t *testing.T) {
+			assert.Equal(t, tc.canonical, enum.Canonical(tc.value))
+
+			var resp validator.StringResponse
+			enum.OneOf().ValidateString(context.Background(), validator.StringRequest{
+				Path:        path.Root("key_origin"),
+				ConfigValue: types.StringValue(tc.value),
+			}, &resp)
+			assert.Equal(t, !tc.valid, resp.Diagnostics.HasError(), resp.Diagnostics)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Tags はtags属性に設定するバリデータを返す。
+// 空のタグや制御文字を含むタグはapply時に詳細の無い400エラーとなるため、plan時に検出する
+func Tags() []validator.Set {
+	return []validator.Set{
+		setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1), noControlCharactersValidator{}),
+		tagsCaseConflictValidator{},
+	}
+}
+
+// TagList はプロバイダーのdefault_tagsなど、リストで指定するタグに設定するバリデータを返す
+func TagList() []validator.List {
+	return []validator.List{
+		listvalidator.UniqueValues(),
+		listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1), noControlCharactersValidator{}),
+	}
+}
+
+// noControlCharactersValidator は文字列に制御文字が含まれていないことを検証する
+type noControlCharactersValidator struct{}
+
+var _ validator.String = noControlCharactersValidator{}
+
+func (v noControlCharactersValidator) Description(_ context.Context) string {
+	return "string must not contain control characters"
+}
+
+func (v noControlCharactersValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v noControlCharactersValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+	if i := strings.IndexFunc(value, unicode.IsControl); i >= 0 {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Attribute Value",
+			fmt.Sprintf("Attribute %s %s, got control character %U at byte offset %d", req.Path, v.Description(ctx), []rune(value[i:])[0], i))
+	}
+}
+
+// tagsCaseConflictValidator は大文字小文字のみが異なるタグが含まれている場合に警告する
+type tagsCaseConflictValidator struct{}
+
+var _ validator.Set = tagsCaseConflictValidator{}
+
+func (v tagsCaseConflictValidator) Description(_ context.Context) string {
+	return "tags should not differ only by case"
+}
+
+func (v tagsCaseConflictValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v tagsCaseConflictValidator) ValidateSet(_ context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	seen := make(map[string]string)
+	for _, element := range req.ConfigValue.Elements() {
+		s, ok := element.(types.String)
+		if !ok || s.IsUnknown() || s.IsNull() {
+			continue
+		}
+		tag := s.ValueString()
+		folded := strings.ToLower(tag)
+		if other, ok := seen[folded]; ok {
+			resp.Diagnostics.AddAttributeWarning(req.Path.AtSetValue(s), "Tags differ only by case",
+				fmt.Sprintf("Tag %q differs only by case from %q in the same list. Check that both tags are intended.", tag, other))
+			continue
+		}
+		seen[folded] = tag
+	}
+}
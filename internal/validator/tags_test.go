@@ -0,0 +1,82 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTags(t *testing.T) {
+	many := make([]string, 20)
+	for i := range many {
+		many[i] = strings.Repeat("a", i+1)
+	}
+
+	expects := []struct {
+		name     string
+		tags     []string
+		errors   int
+		warnings int
+	}{
+		{name: "valid", tags: []string{"tag1", "タグ", "@auto-reboot"}},
+		{name: "empty", tags: []string{}},
+		{name: "long", tags: []string{strings.Repeat("あ", 64)}},
+		{name: "empty tag", tags: []string{""}, errors: 1},
+		{name: "many", tags: many},
+		{name: "control character", tags: []string{"tag\n1", "tag\t2"}, errors: 2},
+		{name: "differ only by case", tags: []string{"Tag1", "tag1", "TAG1", "tag2"}, warnings: 2},
+	}
+	for _, tc := range expects {
+		t.Run(tc.name, func(t *testing.T) {
+			elements := make([]attr.Value, len(tc.tags))
+			for i, tag := range tc.tags {
+				elements[i] = types.StringValue(tag)
+			}
+			req := validator.SetRequest{
+				Path:        path.Root("tags"),
+				ConfigValue: types.SetValueMust(types.StringType, elements),
+			}
+
+			var diags diag.Diagnostics
+			for _, v := range Tags() {
+				var resp validator.SetResponse
+				v.ValidateSet(context.Background(), req, &resp)
+				diags.Append(resp.Diagnostics...)
+			}
+			assert.Equal(t, tc.errors, diags.ErrorsCount(), diags)
+			assert.Equal(t, tc.warnings, diags.WarningsCount(), diags)
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		for _, v := range Tags() {
+			var resp validator.SetResponse
+			v.ValidateSet(context.Background(), validator.SetRequest{
+				Path:        path.Root("tags"),
+				ConfigValue: types.SetUnknown(types.StringType),
+			}, &resp)
+			assert.Empty(t, resp.Diagnostics)
+		}
+	})
+}
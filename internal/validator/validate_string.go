@@ -0,0 +1,36 @@
+// Copyright 2016-2025 terraform-provider-sakuracloud authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ValidateString はvalueに対してvalidatorsをすべて実行し、最初のエラーで中断せずに診断結果をまとめて返す。
+// planの時点で値が未知だったため検証できなかった値を、Create/Updateで改めて検証する場合に利用する
+func ValidateString(ctx context.Context, p path.Path, value types.String, validators ...validator.String) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, v := range validators {
+		resp := &validator.StringResponse{}
+		v.ValidateString(ctx, validator.StringRequest{Path: p, PathExpression: p.Expression(), ConfigValue: value}, resp)
+		diags.Append(resp.Diagnostics...)
+	}
+	return diags
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 
+	"github.com/sacloud/terraform-provider-sakuracloud/internal/common"
 	sakura "github.com/sacloud/terraform-provider-sakuracloud/internal/provider"
 	ver "github.com/sacloud/terraform-provider-sakuracloud/version"
 )
@@ -22,6 +23,7 @@ func main() {
 		Debug:   debug,
 	}
 	err := providerserver.Serve(context.Background(), sakura.New(ver.Version), opts)
+	common.LogAPIMetrics()
 
 	if err != nil {
 		log.Fatal(err.Error())